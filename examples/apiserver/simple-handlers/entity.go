@@ -45,6 +45,10 @@ func (*MyEntityRepository) Delete(ctx context.Context, value db_repo.ModelBased)
 	return nil
 }
 
+func (*MyEntityRepository) Restore(ctx context.Context, value db_repo.ModelBased) error {
+	return nil
+}
+
 func (*MyEntityRepository) Query(ctx context.Context, qb *db_repo.QueryBuilder, result interface{}) error {
 	r := result.(*[]*MyEntity)
 
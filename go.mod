@@ -15,6 +15,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression v1.2.0
 	github.com/aws/aws-sdk-go-v2/service/cloudwatch v1.2.0
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.4.2
+	github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.3.2
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.18.0
 	github.com/aws/aws-sdk-go-v2/service/ecs v1.9.1
 	github.com/aws/aws-sdk-go-v2/service/rds v1.9.0
@@ -85,7 +86,6 @@ require (
 	github.com/aws/aws-sdk-go-v2/feature/ec2/imds v1.0.4 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/configsources v1.0.2 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/ini v1.2.3 // indirect
-	github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.3.2 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.3.0 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/endpoint-discovery v1.0.2 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/presigned-url v1.3.1 // indirect
@@ -162,4 +162,4 @@ require (
 	gotest.tools/v3 v3.0.3 // indirect
 )
 
-go 1.17
+go 1.18
@@ -2,6 +2,7 @@ package crud_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -11,6 +12,8 @@ import (
 	"github.com/justtrackio/gosoline/pkg/apiserver/crud"
 	"github.com/justtrackio/gosoline/pkg/apiserver/crud/mocks"
 	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/es"
+	esMocks "github.com/justtrackio/gosoline/pkg/es/mocks"
 	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
 	"github.com/justtrackio/gosoline/pkg/mdl"
 	"github.com/justtrackio/gosoline/pkg/validation"
@@ -270,6 +273,41 @@ func TestUpdateHandler_Handle_ValidationError(t *testing.T) {
 	transformer.Repo.AssertExpectations(t)
 }
 
+func TestUpdateHandler_Handle_VersionConflict(t *testing.T) {
+	readModel := &Model{}
+	updateModel := &Model{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+			Timestamps: db_repo.Timestamps{
+				UpdatedAt: &time.Time{},
+				CreatedAt: &time.Time{},
+			},
+		},
+		Name: mdl.String("updated"),
+	}
+
+	logger := logMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	transformer.Repo.On("Update", mock.Anything, updateModel).Return(fmt.Errorf("expected version 1 but found 2: %w", db_repo.ErrVersionConflict))
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), readModel).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*Model)
+		model.Id = mdl.Uint(1)
+		model.Name = mdl.String("original")
+		model.UpdatedAt = &time.Time{}
+		model.CreatedAt = &time.Time{}
+	}).Return(nil)
+
+	handler := crud.NewUpdateHandler(logger, transformer)
+
+	body := `{"name": "updated"}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	transformer.Repo.AssertExpectations(t)
+}
+
 func TestDeleteHandler_Handle(t *testing.T) {
 	model := &Model{}
 	deleteModel := &Model{
@@ -370,3 +408,91 @@ func TestListHandler_Handle(t *testing.T) {
 
 	transformer.Repo.AssertExpectations(t)
 }
+
+func TestSearchListHandler_Handle(t *testing.T) {
+	logger := logMocks.NewLoggerMockedAll()
+	transformer := new(mocks.SearchListHandler)
+	repo := new(esMocks.Repository)
+	handler := crud.NewSearchListHandler(logger, transformer)
+
+	metadata, err := es.ReadMetadata("items", &Output{})
+	assert.NoError(t, err)
+
+	expectedQuery := `{
+		"track_total_hits": true,
+		"query": {"bool": {"must": [{"bool": {"should": [{"terms": {"name": ["foobar"]}}], "minimum_should_match": 1}}]}},
+		"sort": [{"name": {"order": "asc"}}],
+		"from": 0,
+		"size": 2
+	}`
+	sb := mock.MatchedBy(func(sb *es.SearchBuilder) bool {
+		var expected, actual interface{}
+		body, _ := json.Marshal(sb.Build())
+
+		_ = json.Unmarshal([]byte(expectedQuery), &expected)
+		_ = json.Unmarshal(body, &actual)
+
+		return assert.ObjectsAreEqual(expected, actual)
+	})
+
+	transformer.On("GetSearchRepository").Return(repo)
+	transformer.On("Search", mock.Anything, sb, crud.DefaultApiView).Return([]Output{{Id: id1, Name: mdl.String("foobar")}}, 1, nil)
+	repo.On("GetMetadata").Return(metadata)
+
+	body := `{"filter":{"matches":[{"values":["foobar"],"dimension":"name","operator":"="}],"bool":"and"},"order":[{"field":"name","direction":"ASC"}],"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("POST", "/", "/", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"total":1,"results":[{"id":1,"name":"foobar","updatedAt":null,"createdAt":null}]}`, response.Body.String())
+
+	transformer.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestRestoreHandler_Handle(t *testing.T) {
+	model := &Model{}
+	restoreModel := &Model{
+		Model: db_repo.Model{
+			Id: id1,
+			Timestamps: db_repo.Timestamps{
+				UpdatedAt: &time.Time{},
+				CreatedAt: &time.Time{},
+			},
+		},
+		Name: mdl.String("foobar"),
+	}
+
+	logger := logMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	transformer.Repo.On("Read", mock.MatchedBy(db_repo.IsWithDeleted), mock.AnythingOfType("*uint"), model).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*Model)
+		model.Id = id1
+		model.Name = mdl.String("foobar")
+		model.UpdatedAt = &time.Time{}
+		model.CreatedAt = &time.Time{}
+	}).Return(nil)
+	transformer.Repo.On("Restore", mock.Anything, restoreModel).Return(nil)
+
+	handler := crud.NewRestoreHandler(logger, transformer)
+
+	response := apiserver.HttpTest("POST", "/:id/restore", "/1/restore", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"id":1,"updatedAt":"0001-01-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z","name":"foobar"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestRestoreHandler_Handle_NotFound(t *testing.T) {
+	logger := logMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	transformer.Repo.On("Read", mock.Anything, mock.AnythingOfType("*uint"), &Model{}).Return(db_repo.NewRecordNotFoundError(1, "model", fmt.Errorf("not found")))
+
+	handler := crud.NewRestoreHandler(logger, transformer)
+
+	response := apiserver.HttpTest("POST", "/:id/restore", "/1/restore", "", handler)
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	transformer.Repo.AssertExpectations(t)
+}
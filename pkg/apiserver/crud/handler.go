@@ -8,6 +8,7 @@ import (
 	"github.com/jinzhu/inflection"
 	"github.com/justtrackio/gosoline/pkg/apiserver"
 	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/es"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
@@ -19,6 +20,7 @@ type Repository interface {
 	Read(ctx context.Context, id *uint, out db_repo.ModelBased) error
 	Update(ctx context.Context, value db_repo.ModelBased) error
 	Delete(ctx context.Context, value db_repo.ModelBased) error
+	Restore(ctx context.Context, value db_repo.ModelBased) error
 	Query(ctx context.Context, qb *db_repo.QueryBuilder, result interface{}) error
 	Count(ctx context.Context, qb *db_repo.QueryBuilder, model db_repo.ModelBased) (int, error)
 	GetMetadata() db_repo.Metadata
@@ -66,6 +68,15 @@ type ListHandler interface {
 	BaseListHandler
 }
 
+// SearchListHandler serves the list input from an elasticsearch index, see sql.EsQueryBuilder for the supported
+// filters. Search returns the total number of matching documents as well, which is the es.SearchResult.Total of the
+// search.
+//go:generate mockery --name SearchListHandler
+type SearchListHandler interface {
+	GetSearchRepository() es.Repository
+	Search(ctx context.Context, sb *es.SearchBuilder, apiView string) (out interface{}, total int, err error)
+}
+
 //go:generate mockery --name Handler
 type Handler interface {
 	BaseHandler
@@ -80,6 +91,10 @@ func AddCrudHandlers(logger log.Logger, d *apiserver.Definitions, version int, b
 	AddUpdateHandler(logger, d, version, basePath, handler)
 	AddDeleteHandler(logger, d, version, basePath, handler)
 	AddListHandler(logger, d, version, basePath, handler)
+
+	if _, ok := handler.GetModel().(db_repo.SoftDeletable); ok {
+		AddRestoreHandler(logger, d, version, basePath, handler)
+	}
 }
 
 func AddCreateHandler(logger log.Logger, d *apiserver.Definitions, version int, basePath string, handler CreateHandler) {
@@ -106,12 +121,24 @@ func AddDeleteHandler(logger log.Logger, d *apiserver.Definitions, version int,
 	d.DELETE(idPath, NewDeleteHandler(logger, handler))
 }
 
+func AddRestoreHandler(logger log.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
+	_, idPath := getHandlerPaths(version, basePath)
+
+	d.POST(idPath+"/restore", NewRestoreHandler(logger, handler))
+}
+
 func AddListHandler(logger log.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
 	plural := inflection.Plural(basePath)
 	path := fmt.Sprintf("/v%d/%s", version, plural)
 	d.POST(path, NewListHandler(logger, handler))
 }
 
+func AddSearchListHandler(logger log.Logger, d *apiserver.Definitions, version int, basePath string, handler SearchListHandler) {
+	plural := inflection.Plural(basePath)
+	path := fmt.Sprintf("/v%d/%s", version, plural)
+	d.POST(path, NewSearchListHandler(logger, handler))
+}
+
 func getHandlerPaths(version int, basePath string) (path string, idPath string) {
 	path = fmt.Sprintf("/v%d/%s", version, basePath)
 	idPath = fmt.Sprintf("%s/:id", path)
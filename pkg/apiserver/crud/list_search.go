@@ -0,0 +1,56 @@
+package crud
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/justtrackio/gosoline/pkg/apiserver"
+	"github.com/justtrackio/gosoline/pkg/apiserver/sql"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+type searchListHandler struct {
+	transformer SearchListHandler
+	logger      log.Logger
+}
+
+func NewSearchListHandler(logger log.Logger, transformer SearchListHandler) gin.HandlerFunc {
+	lh := searchListHandler{
+		transformer: transformer,
+		logger:      logger,
+	}
+
+	return apiserver.CreateJsonHandler(lh)
+}
+
+func (lh searchListHandler) GetInput() interface{} {
+	return sql.NewInput()
+}
+
+func (lh searchListHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	inp := request.Body.(*sql.Input)
+
+	metadata := lh.transformer.GetSearchRepository().GetMetadata()
+
+	eqb := sql.NewEsQueryBuilder(metadata)
+	sb, err := eqb.Build(inp)
+	if err != nil {
+		return nil, err
+	}
+
+	apiView := GetApiViewFromHeader(request.Header)
+	results, total, err := lh.transformer.Search(ctx, sb, apiView)
+	if err != nil {
+		return nil, err
+	}
+
+	out := Output{
+		Total:   total,
+		Results: results,
+	}
+
+	resp := apiserver.NewJsonResponse(out)
+	resp.AddHeader(apiserver.ApiViewKey, apiView)
+
+	return resp, nil
+}
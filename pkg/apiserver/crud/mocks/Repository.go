@@ -106,6 +106,20 @@ func (_m *Repository) Read(ctx context.Context, id *uint, out db_repo.ModelBased
 	return r0
 }
 
+// Restore provides a mock function with given fields: ctx, value
+func (_m *Repository) Restore(ctx context.Context, value db_repo.ModelBased) error {
+	ret := _m.Called(ctx, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db_repo.ModelBased) error); ok {
+		r0 = rf(ctx, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, value
 func (_m *Repository) Update(ctx context.Context, value db_repo.ModelBased) error {
 	ret := _m.Called(ctx, value)
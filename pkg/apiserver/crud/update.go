@@ -68,6 +68,11 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return apiserver.NewStatusResponse(http.StatusConflict), nil
 	}
 
+	if errors.Is(err, db_repo.ErrVersionConflict) {
+		uh.logger.WithContext(ctx).Warn("failed to update model: %s", err)
+		return apiserver.NewStatusResponse(http.StatusConflict), nil
+	}
+
 	if errors.Is(err, &validation.Error{}) {
 		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
 	}
@@ -92,7 +92,7 @@ func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) {
 	d.Handle("PUT", relativePath, handlers...)
 }
 
-func buildRouter(definitions *Definitions, router gin.IRouter) {
+func BuildRouter(definitions *Definitions, router gin.IRouter) {
 	grp := router
 
 	if definitions.parent != nil {
@@ -113,7 +113,7 @@ func buildRouter(definitions *Definitions, router gin.IRouter) {
 	}
 
 	for _, c := range definitions.children {
-		buildRouter(c, grp)
+		BuildRouter(c, grp)
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/justtrackio/gosoline/pkg/cfg"
@@ -17,8 +18,13 @@ func init() {
 }
 
 type ApiHealthCheckSettings struct {
-	Port int    `cfg:"port" default:"8090"`
+	Port int `cfg:"port" default:"8090"`
+	// Path serves the liveness of the application. It only fails if the kernel or one of its modules failed.
 	Path string `cfg:"path" default:"/health"`
+	// ReadinessPath serves the readiness of the application. It fails until all kernel stages are running and
+	// as long as any health check of a module or dependency fails.
+	ReadinessPath string        `cfg:"readiness_path" default:"/health/ready"`
+	Timeout       time.Duration `cfg:"timeout" default:"5s"`
 }
 
 type ApiHealthCheck struct {
@@ -34,20 +40,24 @@ func NewApiHealthCheck() kernel.ModuleFactory {
 		settings := &ApiHealthCheckSettings{}
 		config.UnmarshalKey("api.health", settings)
 
+		reporter, err := kernel.ProvideHealthReporter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can not provide health reporter: %w", err)
+		}
+
 		gin.SetMode(gin.ReleaseMode)
 		router := gin.New()
 
-		healthCheck := NewApiHealthCheckWithInterfaces(logger, router, settings)
+		healthCheck := NewApiHealthCheckWithInterfaces(logger, router, reporter, settings)
 
 		return healthCheck, nil
 	}
 }
 
-func NewApiHealthCheckWithInterfaces(logger log.Logger, router *gin.Engine, settings *ApiHealthCheckSettings) *ApiHealthCheck {
+func NewApiHealthCheckWithInterfaces(logger log.Logger, router *gin.Engine, reporter kernel.HealthReporter, settings *ApiHealthCheckSettings) *ApiHealthCheck {
 	router.Use(LoggingMiddleware(logger))
-	router.GET(settings.Path, func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{})
-	})
+	router.GET(settings.Path, healthReportHandler(reporter.Liveness, settings.Timeout))
+	router.GET(settings.ReadinessPath, healthReportHandler(reporter.Readiness, settings.Timeout))
 
 	addr := fmt.Sprintf(":%d", settings.Port)
 
@@ -81,3 +91,24 @@ func (a *ApiHealthCheck) waitForStop(ctx context.Context) {
 		a.logger.Error("api health check close: %w", err)
 	}
 }
+
+func healthReportHandler(report func(ctx context.Context) *kernel.HealthReport, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		result := report(ctx)
+
+		if !result.Healthy {
+			c.JSON(http.StatusServiceUnavailable, result)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -7,7 +7,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/justtrackio/gosoline/pkg/apiserver"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	kernelMocks "github.com/justtrackio/gosoline/pkg/kernel/mocks"
 	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewApiHealthCheck(t *testing.T) {
@@ -15,10 +19,28 @@ func TestNewApiHealthCheck(t *testing.T) {
 	ginEngine := gin.New()
 	logger := logMocks.NewLoggerMockedAll()
 
-	apiserver.NewApiHealthCheckWithInterfaces(logger, ginEngine, &apiserver.ApiHealthCheckSettings{
-		Path: "/health",
+	reporter := new(kernelMocks.HealthReporter)
+	reporter.On("Liveness", mock.Anything).Return(&kernel.HealthReport{
+		Healthy: true,
+		Kernel:  kernel.HealthStateBooting,
+	})
+	reporter.On("Readiness", mock.Anything).Return(&kernel.HealthReport{
+		Healthy: false,
+		Kernel:  kernel.HealthStateBooting,
+	})
+
+	apiserver.NewApiHealthCheckWithInterfaces(logger, ginEngine, reporter, &apiserver.ApiHealthCheckSettings{
+		Path:          "/health",
+		ReadinessPath: "/health/ready",
 	})
 
 	httpRecorder := httptest.NewRecorder()
 	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health", http.StatusOK)
+	assert.JSONEq(t, `{"healthy":true,"kernel":"booting","stages":null,"modules":null}`, httpRecorder.Body.String())
+
+	httpRecorder = httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health/ready", http.StatusServiceUnavailable)
+	assert.JSONEq(t, `{"healthy":false,"kernel":"booting","stages":null,"modules":null}`, httpRecorder.Body.String())
+
+	reporter.AssertExpectations(t)
 }
@@ -87,7 +87,7 @@ func New(definer Definer) kernel.ModuleFactory {
 			return nil, fmt.Errorf("can not access appctx metadata: %w", err)
 		}
 
-		buildRouter(definitions, router)
+		BuildRouter(definitions, router)
 
 		for _, route := range router.Routes() {
 			err = metadata.Append("apiserver.routes", HandlerMetadata{
@@ -0,0 +1,230 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/justtrackio/gosoline/pkg/es"
+)
+
+// EsQueryBuilder translates the list input into a search on an elasticsearch index. The dimensions are the fields of
+// the documents as described by the metadata of the index. Grouping is not supported.
+type EsQueryBuilder struct {
+	metadata es.Metadata
+}
+
+func NewEsQueryBuilder(metadata es.Metadata) *EsQueryBuilder {
+	return &EsQueryBuilder{
+		metadata: metadata,
+	}
+}
+
+func (qb EsQueryBuilder) Build(inp *Input) (*es.SearchBuilder, error) {
+	sb := es.NewSearchBuilder()
+
+	if len(inp.GroupBy) > 0 {
+		return nil, fmt.Errorf("group by is not supported on index %s", qb.metadata.Index)
+	}
+
+	if len(inp.Filter.Matches) > 0 || len(inp.Filter.Groups) > 0 {
+		query, err := qb.buildFilter(inp.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.Query(query)
+	}
+
+	for _, o := range inp.Order {
+		if !qb.metadata.HasField(o.Field) {
+			return nil, fmt.Errorf("no list mapping found for order field %s", o.Field)
+		}
+
+		sb.OrderBy(o.Field, o.Direction)
+	}
+
+	if inp.Page != nil {
+		sb.Page(inp.Page.Offset, inp.Page.Limit)
+	}
+
+	return sb, nil
+}
+
+func (qb EsQueryBuilder) buildFilter(filter Filter) (es.Query, error) {
+	queries := make([]es.Query, 0, len(filter.Matches)+len(filter.Groups))
+
+	for _, m := range filter.Matches {
+		if len(m.Dimension) == 0 {
+			continue
+		}
+
+		query, err := qb.buildFilterMatch(m)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, query)
+	}
+
+	for _, g := range filter.Groups {
+		query, err := qb.buildFilter(g)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, query)
+	}
+
+	switch strings.ToLower(filter.Bool) {
+	case "", "and":
+		return es.Bool().Must(queries...), nil
+	case "or":
+		return es.Bool().Should(queries...).MinimumShouldMatch(1), nil
+	default:
+		return nil, fmt.Errorf("unknown bool operator %s", filter.Bool)
+	}
+}
+
+func (qb EsQueryBuilder) buildFilterMatch(match FilterMatch) (es.Query, error) {
+	field, ok := qb.metadata.Fields[match.Dimension]
+	if !ok {
+		return nil, fmt.Errorf("no list mapping found for dimension %s", match.Dimension)
+	}
+
+	if len(match.Values) == 0 {
+		return es.Bool().MustNot(es.MatchAll()), nil
+	}
+
+	switch {
+	case match.Operator == OpEq:
+		return qb.buildEq(field, match.Values), nil
+
+	case match.Operator == OpNeq:
+		return es.Bool().MustNot(qb.buildEq(field, match.Values)), nil
+
+	case strings.EqualFold(OpIs, match.Operator), strings.EqualFold(OpIsNot, match.Operator):
+		return qb.buildIs(field.Name, match)
+
+	case match.Operator == OPMemberOf:
+		return qb.buildEq(field, match.Values), nil
+
+	case match.Operator == OPNotMemberOf:
+		return es.Bool().MustNot(qb.buildEq(field, match.Values)), nil
+	}
+
+	queries := make([]es.Query, 0, len(match.Values))
+
+	for _, v := range match.Values {
+		switch match.Operator {
+		case OpLike:
+			queries = append(queries, qb.buildLike(field, v))
+		case ">":
+			queries = append(queries, es.Range(field.Name).Gt(v))
+		case ">=":
+			queries = append(queries, es.Range(field.Name).Gte(v))
+		case "<":
+			queries = append(queries, es.Range(field.Name).Lt(v))
+		case "<=":
+			queries = append(queries, es.Range(field.Name).Lte(v))
+		default:
+			return nil, fmt.Errorf("unsupported operator %s for dimension %s", match.Operator, match.Dimension)
+		}
+	}
+
+	return es.Bool().Should(queries...).MinimumShouldMatch(1), nil
+}
+
+// buildEq matches documents with any of the values, a nil value matches documents without the field. As the terms of
+// a text field are analyzed, its values are matched as a phrase instead, so all terms of a value have to be present.
+func (qb EsQueryBuilder) buildEq(field es.Field, values []interface{}) es.Query {
+	hasNull := false
+	filteredValues := make([]interface{}, 0, len(values))
+
+	for _, v := range values {
+		if v == nil {
+			hasNull = true
+			continue
+		}
+
+		filteredValues = append(filteredValues, v)
+	}
+
+	queries := make([]es.Query, 0, len(filteredValues)+1)
+
+	switch {
+	case len(filteredValues) == 0:
+	case field.Type == es.FieldTypeText:
+		for _, v := range filteredValues {
+			queries = append(queries, es.MatchPhrase(field.Name, fmt.Sprint(v)))
+		}
+	default:
+		queries = append(queries, es.Terms(field.Name, filteredValues...))
+	}
+
+	if hasNull {
+		queries = append(queries, es.Bool().MustNot(es.Exists(field.Name)))
+	}
+
+	return es.Bool().Should(queries...).MinimumShouldMatch(1)
+}
+
+func (qb EsQueryBuilder) buildIs(field string, match FilterMatch) (es.Query, error) {
+	for _, v := range match.Values {
+		if !strings.EqualFold(fmt.Sprint(v), "null") {
+			return nil, fmt.Errorf("operator %s of dimension %s only supports null, but got %v", match.Operator, match.Dimension, v)
+		}
+	}
+
+	if strings.EqualFold(OpIs, match.Operator) {
+		return es.Bool().MustNot(es.Exists(field)), nil
+	}
+
+	return es.Exists(field), nil
+}
+
+// buildLike uses a full-text match on text fields and a wildcard on all other fields, like the LIKE of sql does.
+func (qb EsQueryBuilder) buildLike(field es.Field, value interface{}) es.Query {
+	if field.Type == es.FieldTypeText {
+		return es.Match(field.Name, fmt.Sprint(value))
+	}
+
+	return es.Wildcard(field.Name, likeToWildcard(fmt.Sprint(value)))
+}
+
+// likeToWildcard translates the wildcards % and _ of a LIKE pattern to * and ? and escapes the characters having a
+// special meaning in a wildcard pattern only. A wildcard escaped by a backslash is matched literally.
+func likeToWildcard(pattern string) string {
+	wildcard := strings.Builder{}
+	escaped := false
+
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			writeWildcardLiteral(&wildcard, c)
+		case c == '\\':
+			escaped = true
+		case c == '%':
+			wildcard.WriteRune('*')
+		case c == '_':
+			wildcard.WriteRune('?')
+		default:
+			writeWildcardLiteral(&wildcard, c)
+		}
+	}
+
+	// a trailing backslash has nothing to escape and is matched literally
+	if escaped {
+		writeWildcardLiteral(&wildcard, '\\')
+	}
+
+	return wildcard.String()
+}
+
+func writeWildcardLiteral(wildcard *strings.Builder, c rune) {
+	if c == '*' || c == '?' || c == '\\' {
+		wildcard.WriteRune('\\')
+	}
+
+	wildcard.WriteRune(c)
+}
@@ -0,0 +1,211 @@
+package sql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/apiserver/sql"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/stretchr/testify/assert"
+)
+
+type esItem struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description" es:"type=text"`
+	Amount      int    `json:"amount"`
+}
+
+func buildEsQuery(t *testing.T, inp *sql.Input) (string, error) {
+	metadata, err := es.ReadMetadata("items", &esItem{})
+	assert.NoError(t, err)
+
+	sb, err := sql.NewEsQueryBuilder(metadata).Build(inp)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(sb.Build())
+	assert.NoError(t, err)
+
+	return string(body), nil
+}
+
+func TestEsQueryBuilder_Build_DimensionMissing(t *testing.T) {
+	_, err := buildEsQuery(t, &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "bla",
+					Operator:  "=",
+					Values:    []interface{}{"blub"},
+				},
+			},
+		},
+	})
+
+	assert.EqualError(t, err, "no list mapping found for dimension bla")
+}
+
+func TestEsQueryBuilder_Build_GroupBy(t *testing.T) {
+	_, err := buildEsQuery(t, &sql.Input{
+		GroupBy: []string{"name"},
+	})
+
+	assert.EqualError(t, err, "group by is not supported on index items")
+}
+
+func TestEsQueryBuilder_Build(t *testing.T) {
+	body, err := buildEsQuery(t, &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "name",
+					Operator:  "=",
+					Values:    []interface{}{"a", nil},
+				},
+				{
+					Dimension: "amount",
+					Operator:  ">=",
+					Values:    []interface{}{3},
+				},
+			},
+			Groups: []sql.Filter{
+				{
+					Matches: []sql.FilterMatch{
+						{
+							Dimension: "description",
+							Operator:  "~",
+							Values:    []interface{}{"foo"},
+						},
+						{
+							Dimension: "name",
+							Operator:  "~",
+							Values:    []interface{}{"%bar%"},
+						},
+					},
+					Bool: "or",
+				},
+			},
+			Bool: "and",
+		},
+		Order: []sql.Order{
+			{
+				Field:     "amount",
+				Direction: "DESC",
+			},
+		},
+		Page: &sql.Page{
+			Offset: 10,
+			Limit:  5,
+		},
+	})
+
+	expected := `{
+		"track_total_hits": true,
+		"query": {"bool": {"must": [
+			{"bool": {"should": [
+				{"terms": {"name": ["a"]}},
+				{"bool": {"must_not": [{"exists": {"field": "name"}}]}}
+			], "minimum_should_match": 1}},
+			{"bool": {"should": [{"range": {"amount": {"gte": 3}}}], "minimum_should_match": 1}},
+			{"bool": {"should": [
+				{"bool": {"should": [{"match": {"description": "foo"}}], "minimum_should_match": 1}},
+				{"bool": {"should": [{"wildcard": {"name": "*bar*"}}], "minimum_should_match": 1}}
+			], "minimum_should_match": 1}}
+		]}},
+		"sort": [{"amount": {"order": "desc"}}],
+		"from": 10,
+		"size": 5
+	}`
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, body)
+}
+
+func TestEsQueryBuilder_Build_IsNot(t *testing.T) {
+	body, err := buildEsQuery(t, &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "name",
+					Operator:  "is not",
+					Values:    []interface{}{"NULL"},
+				},
+				{
+					Dimension: "amount",
+					Operator:  "!=",
+					Values:    []interface{}{1, 2},
+				},
+			},
+		},
+	})
+
+	expected := `{
+		"track_total_hits": true,
+		"query": {"bool": {"must": [
+			{"exists": {"field": "name"}},
+			{"bool": {"must_not": [
+				{"bool": {"should": [{"terms": {"amount": [1, 2]}}], "minimum_should_match": 1}}
+			]}}
+		]}}
+	}`
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, body)
+}
+
+func TestEsQueryBuilder_Build_Like(t *testing.T) {
+	body, err := buildEsQuery(t, &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "name",
+					Operator:  "~",
+					Values:    []interface{}{"a_b%", "what?*", `100\%`},
+				},
+			},
+		},
+	})
+
+	expected := `{
+		"track_total_hits": true,
+		"query": {"bool": {"must": [
+			{"bool": {"should": [
+				{"wildcard": {"name": "a?b*"}},
+				{"wildcard": {"name": "what\\?\\*"}},
+				{"wildcard": {"name": "100%"}}
+			], "minimum_should_match": 1}}
+		]}}
+	}`
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, body)
+}
+
+func TestEsQueryBuilder_Build_EqText(t *testing.T) {
+	body, err := buildEsQuery(t, &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "description",
+					Operator:  "=",
+					Values:    []interface{}{"foo", "bar"},
+				},
+			},
+		},
+	})
+
+	expected := `{
+		"track_total_hits": true,
+		"query": {"bool": {"must": [
+			{"bool": {"should": [
+				{"match_phrase": {"description": "foo"}},
+				{"match_phrase": {"description": "bar"}}
+			], "minimum_should_match": 1}}
+		]}}
+	}`
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, body)
+}
@@ -53,7 +53,8 @@ func Provide(ctx context.Context, key interface{}, factory func() (interface{},
 		return nil, err
 	}
 
-	cont.items.Store(key, val)
+	// another routine might have provided the item in the meantime, so we keep the item stored first
+	val, _ = cont.items.LoadOrStore(key, val)
 
 	return val, nil
 }
@@ -2,6 +2,7 @@ package appctx_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/justtrackio/gosoline/pkg/appctx"
@@ -24,3 +25,34 @@ func TestProvide(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "bar", val)
 }
+
+func TestProvide_Concurrent(t *testing.T) {
+	ctx := appctx.WithContainer(context.Background())
+
+	entered := sync.WaitGroup{}
+	entered.Add(2)
+
+	provided := make([]interface{}, 2)
+	done := sync.WaitGroup{}
+	done.Add(2)
+
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			defer done.Done()
+
+			val, err := appctx.Provide(ctx, "foo", func() (interface{}, error) {
+				entered.Done()
+				entered.Wait()
+
+				return &i, nil
+			})
+
+			assert.NoError(t, err)
+			provided[i] = val
+		}(i)
+	}
+
+	done.Wait()
+
+	assert.Same(t, provided[0], provided[1], "both routines should get the item stored first")
+}
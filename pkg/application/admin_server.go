@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/justtrackio/gosoline/pkg/apiserver"
+	"github.com/justtrackio/gosoline/pkg/apiserver/auth"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/dx"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+func init() {
+	dx.RegisterRandomizablePortSetting("admin.server.port")
+}
+
+type AdminServerSettings struct {
+	Port int `cfg:"port" default:"8071"`
+	// Authenticators lists the apiserver/auth authenticators protecting the admin server. A request has to
+	// be accepted by any of them. Available are apiKey (header X-API-KEY) and basicAuth.
+	Authenticators []string `cfg:"authenticators" default:"apiKey"`
+}
+
+type AdminLogLevel struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// AdminServer provides runtime control over the kernel. It lists the modules with their state, restarts
+// restartable modules, changes log levels per channel and triggers a dump of the config.
+type AdminServer struct {
+	kernel.BackgroundModule
+	kernel.ServiceStage
+
+	config cfg.Config
+	logger log.Logger
+	admin  kernel.ModuleAdmin
+	server *http.Server
+}
+
+func NewAdminServer() kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		var err error
+		var admin kernel.ModuleAdmin
+
+		settings := &AdminServerSettings{}
+		config.UnmarshalKey("admin.server", settings)
+
+		logger = logger.WithChannel("admin-server")
+
+		if admin, err = kernel.ProvideModuleAdmin(ctx); err != nil {
+			return nil, fmt.Errorf("can not provide module admin: %w", err)
+		}
+
+		authenticators := make(map[string]auth.Authenticator, len(settings.Authenticators))
+
+		for _, name := range settings.Authenticators {
+			switch name {
+			case auth.ByApiKey:
+				authenticators[name] = auth.NewConfigKeyAuthenticator(config, logger, auth.ProvideValueFromHeader(auth.HeaderApiKey))
+			case auth.ByBasicAuth:
+				if authenticators[name], err = auth.NewBasicAuthAuthenticator(config, logger); err != nil {
+					return nil, fmt.Errorf("can not create basic auth authenticator: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("there is no authenticator %s available for the admin server", name)
+			}
+		}
+
+		if len(authenticators) == 0 {
+			return nil, fmt.Errorf("the admin server needs at least one authenticator")
+		}
+
+		gin.SetMode(gin.ReleaseMode)
+		router := gin.New()
+
+		return NewAdminServerWithInterfaces(config, logger, router, admin, authenticators, settings), nil
+	}
+}
+
+func NewAdminServerWithInterfaces(config cfg.Config, logger log.Logger, router *gin.Engine, admin kernel.ModuleAdmin, authenticators map[string]auth.Authenticator, settings *AdminServerSettings) *AdminServer {
+	s := &AdminServer{
+		config: config,
+		logger: logger,
+		admin:  admin,
+	}
+
+	router.Use(apiserver.LoggingMiddleware(logger))
+	router.Use(auth.NewChainHandler(authenticators))
+
+	router.GET("/modules", s.handleModules)
+	router.POST("/modules/:name/restart", s.handleRestart)
+	router.GET("/log/levels", s.handleLogLevels)
+	router.PUT("/log/levels/:channel", s.handleSetLogLevel)
+	router.DELETE("/log/levels/:channel", s.handleResetLogLevel)
+	router.POST("/config/debug", s.handleDebugConfig)
+
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", settings.Port),
+		Handler: router,
+	}
+
+	return s
+}
+
+func (s *AdminServer) Run(ctx context.Context) error {
+	go s.waitForStop(ctx)
+
+	s.logger.Info("serving admin endpoints on address %s", s.server.Addr)
+
+	if err := s.server.ListenAndServe(); err != http.ErrServerClosed {
+		return fmt.Errorf("admin server closed unexpected: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AdminServer) handleModules(ginCtx *gin.Context) {
+	ginCtx.JSON(http.StatusOK, s.admin.GetModules())
+}
+
+func (s *AdminServer) handleRestart(ginCtx *gin.Context) {
+	name := ginCtx.Param("name")
+	err := s.admin.RestartModule(name)
+
+	switch {
+	case err == nil:
+		s.logger.Info("restart of module %s requested", name)
+		ginCtx.Status(http.StatusAccepted)
+	case errors.Is(err, kernel.ErrModuleNotFound):
+		ginCtx.JSON(http.StatusNotFound, gin.H{"err": err.Error()})
+	case errors.Is(err, kernel.ErrModuleNotRestartable), errors.Is(err, kernel.ErrModuleNotRunning):
+		ginCtx.JSON(http.StatusConflict, gin.H{"err": err.Error()})
+	default:
+		ginCtx.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+	}
+}
+
+func (s *AdminServer) handleLogLevels(ginCtx *gin.Context) {
+	ginCtx.JSON(http.StatusOK, log.GetChannelLevels())
+}
+
+func (s *AdminServer) handleSetLogLevel(ginCtx *gin.Context) {
+	channel := ginCtx.Param("channel")
+	body := &AdminLogLevel{}
+
+	if err := ginCtx.ShouldBindJSON(body); err != nil {
+		ginCtx.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	if err := log.SetChannelLevel(channel, body.Level); err != nil {
+		ginCtx.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	s.logger.Info("set log level of channel %s to %s", channel, body.Level)
+	ginCtx.JSON(http.StatusOK, log.GetChannelLevels())
+}
+
+func (s *AdminServer) handleResetLogLevel(ginCtx *gin.Context) {
+	channel := ginCtx.Param("channel")
+	log.ResetChannelLevel(channel)
+
+	s.logger.Info("reset log level of channel %s", channel)
+	ginCtx.JSON(http.StatusOK, log.GetChannelLevels())
+}
+
+func (s *AdminServer) handleDebugConfig(ginCtx *gin.Context) {
+	if err := cfg.DebugConfig(s.config, s.logger); err != nil {
+		ginCtx.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+
+	ginCtx.Status(http.StatusNoContent)
+}
+
+func (s *AdminServer) waitForStop(ctx context.Context) {
+	<-ctx.Done()
+
+	if err := s.server.Close(); err != nil {
+		s.logger.Error("could not close admin server: %w", err)
+	}
+}
@@ -0,0 +1,129 @@
+package application_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/justtrackio/gosoline/pkg/application"
+	"github.com/justtrackio/gosoline/pkg/apiserver/auth"
+	authMocks "github.com/justtrackio/gosoline/pkg/apiserver/auth/mocks"
+	cfgMocks "github.com/justtrackio/gosoline/pkg/cfg/mocks"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	kernelMocks "github.com/justtrackio/gosoline/pkg/kernel/mocks"
+	"github.com/justtrackio/gosoline/pkg/log"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAdminServerRouter(valid bool) (*gin.Engine, *kernelMocks.ModuleAdmin, *cfgMocks.Config) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	config := new(cfgMocks.Config)
+	logger := logMocks.NewLoggerMockedAll()
+	admin := new(kernelMocks.ModuleAdmin)
+
+	authenticator := new(authMocks.Authenticator)
+	authenticator.On("IsValid", mock.Anything).Return(valid, nil)
+
+	application.NewAdminServerWithInterfaces(config, logger, router, admin, map[string]auth.Authenticator{
+		auth.ByApiKey: authenticator,
+	}, &application.AdminServerSettings{})
+
+	return router, admin, config
+}
+
+func serveAdminRequest(router *gin.Engine, method string, path string, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestAdminServer_Unauthorized(t *testing.T) {
+	router, admin, _ := newAdminServerRouter(false)
+
+	recorder := serveAdminRequest(router, http.MethodGet, "/modules", "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	admin.AssertExpectations(t)
+}
+
+func TestAdminServer_Modules(t *testing.T) {
+	router, admin, _ := newAdminServerRouter(true)
+
+	admin.On("GetModules").Return([]kernel.ModuleStatus{
+		{
+			Name:        "consumer",
+			Stage:       kernel.StageApplication,
+			Type:        "background",
+			IsRunning:   true,
+			Restartable: true,
+			Uptime:      "1m0s",
+		},
+	})
+
+	recorder := serveAdminRequest(router, http.MethodGet, "/modules", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `[{"name":"consumer","stage":2048,"type":"background","is_running":true,"restartable":true,"restarts":0,"uptime":"1m0s"}]`, recorder.Body.String())
+
+	admin.AssertExpectations(t)
+}
+
+func TestAdminServer_Restart(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code int
+	}{
+		"success":         {err: nil, code: http.StatusAccepted},
+		"not found":       {err: fmt.Errorf("can not restart: %w", kernel.ErrModuleNotFound), code: http.StatusNotFound},
+		"not restartable": {err: fmt.Errorf("can not restart: %w", kernel.ErrModuleNotRestartable), code: http.StatusConflict},
+		"not running":     {err: fmt.Errorf("can not restart: %w", kernel.ErrModuleNotRunning), code: http.StatusConflict},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router, admin, _ := newAdminServerRouter(true)
+			admin.On("RestartModule", "consumer").Return(test.err)
+
+			recorder := serveAdminRequest(router, http.MethodPost, "/modules/consumer/restart", "")
+			assert.Equal(t, test.code, recorder.Code)
+
+			admin.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminServer_LogLevels(t *testing.T) {
+	router, _, _ := newAdminServerRouter(true)
+	defer log.ResetChannelLevel("admin-test")
+
+	recorder := serveAdminRequest(router, http.MethodPut, "/log/levels/admin-test", `{"level":"verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = serveAdminRequest(router, http.MethodPut, "/log/levels/admin-test", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, log.LevelDebug, log.GetChannelLevels()["admin-test"])
+
+	recorder = serveAdminRequest(router, http.MethodDelete, "/log/levels/admin-test", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, log.GetChannelLevels(), "admin-test")
+}
+
+func TestAdminServer_DebugConfig(t *testing.T) {
+	router, _, config := newAdminServerRouter(true)
+	config.On("AllSettings").Return(map[string]interface{}{
+		"app_name": "test",
+	})
+
+	recorder := serveAdminRequest(router, http.MethodPost, "/config/debug", "")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+	config.AssertExpectations(t)
+}
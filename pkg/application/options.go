@@ -34,6 +34,12 @@ type kernelSettings struct {
 	KillTimeout time.Duration `cfg:"killTimeout" default:"10s"`
 }
 
+// WithAdminServer adds the admin server providing runtime control over the kernel and its modules.
+// It is not part of the default options as it has to be protected by an authenticator.
+func WithAdminServer(app *App) {
+	WithModule("admin-server", NewAdminServer())(app)
+}
+
 func WithApiHealthCheck(app *App) {
 	WithModule("api-health-check", apiserver.NewApiHealthCheck())(app)
 }
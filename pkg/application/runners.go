@@ -42,7 +42,11 @@ func RunConsumers(consumers stream.ConsumerCallbackMap, options ...Option) {
 }
 
 func RunMdlSubscriber(transformers mdlsub.TransformerMapTypeVersionFactories, options ...Option) {
-	subs := mdlsub.NewSubscriberFactory(transformers)
+	RunMdlSubscriberWithUpcasters(transformers, nil, options...)
+}
+
+func RunMdlSubscriberWithUpcasters(transformers mdlsub.TransformerMapTypeVersionFactories, upcasters mdlsub.UpcasterMapTypeVersionFactories, options ...Option) {
+	subs := mdlsub.NewSubscriberFactoryWithUpcasters(transformers, upcasters)
 
 	options = append(options, WithExecBackoffInfinite)
 
@@ -0,0 +1,260 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/parquet"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+const (
+	// AttributeArchivedAt holds the time a message was archived at in jsonl files. Use it as timestamp attribute of a
+	// replay source to replay a time range.
+	AttributeArchivedAt = "archivedAt"
+	// AttributeModelId holds the model of a message as written by the mdlsub publisher. Messages are archived in the
+	// dataset of their model.
+	AttributeModelId = "modelId"
+
+	PartitioningDay  = "day"
+	PartitioningHour = "hour"
+)
+
+// Settings of an archive are read from archive.<name>. The consumer of an archive is configured like every other
+// batch consumer at stream.consumer.archive-<name>: its batch_size and idle_timeout control how many messages are
+// written to a file at most and how long messages are buffered before they are written.
+type Settings struct {
+	// Format of the files, either jsonl or parquet
+	Format string `cfg:"format" default:"jsonl" validate:"oneof=jsonl parquet"`
+	// The files are stored in the bucket and at the prefix of the model of a message. Messages without the
+	// AttributeModelId attribute are stored for this model, whose name defaults to the name of the archive.
+	ModelId mdl.ModelId `cfg:"model_id"`
+	// Message attribute holding the time a message was created at, formatted as RFC3339. Messages without it are
+	// partitioned by the time they are archived at.
+	TimestampAttribute string `cfg:"timestamp_attribute"`
+	// Partition the files by day or hour
+	Partitioning string `cfg:"partitioning" default:"day" validate:"oneof=day hour"`
+	// Maximum number of messages per file
+	MaxFileSize int `cfg:"max_file_size" default:"10000" validate:"min=1"`
+}
+
+func (s *Settings) GetNamingStrategy() string {
+	if s.Partitioning == PartitioningHour {
+		return parquet.NamingStrategyDtHourSeparated
+	}
+
+	return parquet.NamingStrategyDtSeparated
+}
+
+func (s *Settings) GetPartitionInterval() time.Duration {
+	if s.Partitioning == PartitioningHour {
+		return time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+func ConfigurableArchiveKey(name string) string {
+	return fmt.Sprintf("archive.%s", name)
+}
+
+func ConsumerName(name string) string {
+	return fmt.Sprintf("archive-%s", name)
+}
+
+func ReadSettings(config cfg.Config, name string) *Settings {
+	settings := &Settings{}
+	config.UnmarshalKey(ConfigurableArchiveKey(name), settings)
+
+	if settings.ModelId.Name == "" {
+		settings.ModelId.Name = name
+	}
+
+	settings.ModelId.PadFromConfig(config)
+
+	return settings
+}
+
+// NewModule creates a batch consumer writing all messages of its input to the archive.
+func NewModule(name string) kernel.ModuleFactory {
+	return stream.NewBatchConsumer(ConsumerName(name), NewCallbackFactory(name))
+}
+
+func NewCallbackFactory(name string) stream.BatchConsumerCallbackFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (stream.BatchConsumerCallback, error) {
+		settings := ReadSettings(config, name)
+
+		writer, err := NewWriter(ctx, config, logger, settings)
+		if err != nil {
+			return nil, fmt.Errorf("can not create writer of archive %s: %w", name, err)
+		}
+
+		return NewCallbackWithInterfaces(logger, clock.NewRealClock(), writer, settings), nil
+	}
+}
+
+// Callback writes every batch of messages partitioned by their model and time. A message is only acknowledged after
+// the file containing it has been written, so messages of failed files are delivered again.
+type Callback struct {
+	logger   log.Logger
+	clock    clock.Clock
+	writer   Writer
+	settings *Settings
+}
+
+func NewCallbackWithInterfaces(logger log.Logger, clock clock.Clock, writer Writer, settings *Settings) *Callback {
+	return &Callback{
+		logger:   logger,
+		clock:    clock,
+		writer:   writer,
+		settings: settings,
+	}
+}
+
+func (c *Callback) GetModel(_ map[string]interface{}) interface{} {
+	return &RawBody{}
+}
+
+func (c *Callback) Consume(ctx context.Context, models []interface{}, attributes []map[string]interface{}) ([]bool, error) {
+	acks := make([]bool, len(models))
+	archived := make(map[mdl.ModelId][]*ParquetMessage)
+	modelIds := make([]mdl.ModelId, 0)
+	positions := make(map[*ParquetMessage]int, len(models))
+
+	for i, model := range models {
+		body, ok := model.(*RawBody)
+		if !ok {
+			c.logger.WithContext(ctx).Error("can not archive message: expected a model of type %T but got %T", body, model)
+			continue
+		}
+
+		attributes[i][stream.AttributeEncoding] = body.GetEncoding()
+
+		modelId, err := c.getModelId(attributes[i])
+		if err != nil {
+			c.logger.WithContext(ctx).Error("can not archive message: %w", err)
+			continue
+		}
+
+		timestamp, err := c.getTimestamp(attributes[i])
+		if err != nil {
+			c.logger.WithContext(ctx).Error("can not archive message: %w", err)
+			continue
+		}
+
+		msg, err := NewParquetMessage(timestamp, &stream.Message{
+			Attributes: attributes[i],
+			Body:       body.String(),
+		})
+		if err != nil {
+			c.logger.WithContext(ctx).Error("can not archive message: %w", err)
+			continue
+		}
+
+		if _, ok = archived[modelId]; !ok {
+			modelIds = append(modelIds, modelId)
+		}
+
+		archived[modelId] = append(archived[modelId], msg)
+		positions[msg] = i
+	}
+
+	var errs error
+
+	for _, modelId := range modelIds {
+		if err := c.write(ctx, modelId, archived[modelId], positions, acks); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return acks, errs
+}
+
+func (c *Callback) write(ctx context.Context, modelId mdl.ModelId, archived []*ParquetMessage, positions map[*ParquetMessage]int, acks []bool) error {
+	partitioner := parquet.NewPartitionerWithInterfaces(c.clock, &parquet.PartitionerSettings{
+		PartitionInterval: c.settings.GetPartitionInterval(),
+		BufferInterval:    c.settings.GetPartitionInterval(),
+		MaxPartitionSize:  c.settings.MaxFileSize,
+	})
+
+	go func() {
+		for _, msg := range archived {
+			partitioner.Ingest(msg)
+		}
+
+		partitioner.Stop()
+	}()
+
+	var errs error
+
+	for partition := range partitioner.Out() {
+		messages := make([]ParquetMessage, len(partition.Elements))
+
+		for i, element := range partition.Elements {
+			messages[i] = *element.(*ParquetMessage)
+		}
+
+		if err := c.writer.Write(ctx, modelId, partition.Timestamp, messages); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("can not write %d messages of model %s to partition %s: %w", len(messages), modelId.String(), partition.Timestamp.Format(time.RFC3339), err))
+			continue
+		}
+
+		for _, element := range partition.Elements {
+			acks[positions[element.(*ParquetMessage)]] = true
+		}
+	}
+
+	return errs
+}
+
+func (c *Callback) getModelId(attributes map[string]interface{}) (mdl.ModelId, error) {
+	value, ok := attributes[AttributeModelId]
+	if !ok {
+		return c.settings.ModelId, nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return mdl.ModelId{}, fmt.Errorf("the attribute %s should be a string but is %T", AttributeModelId, value)
+	}
+
+	modelId, err := mdl.ModelIdFromString(str)
+	if err != nil {
+		return mdl.ModelId{}, fmt.Errorf("can not parse the attribute %s: %w", AttributeModelId, err)
+	}
+
+	// the environment is not part of the attribute
+	modelId.Environment = c.settings.ModelId.Environment
+
+	return modelId, nil
+}
+
+func (c *Callback) getTimestamp(attributes map[string]interface{}) (time.Time, error) {
+	if c.settings.TimestampAttribute == "" {
+		return c.clock.Now(), nil
+	}
+
+	value, ok := attributes[c.settings.TimestampAttribute]
+	if !ok {
+		return c.clock.Now(), nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("the attribute %s should be a string but is %T", c.settings.TimestampAttribute, value)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can not parse the attribute %s: %w", c.settings.TimestampAttribute, err)
+	}
+
+	return timestamp, nil
+}
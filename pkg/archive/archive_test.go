@@ -0,0 +1,257 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/justtrackio/gosoline/pkg/archive"
+	archiveMocks "github.com/justtrackio/gosoline/pkg/archive/mocks"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	s3Mocks "github.com/justtrackio/gosoline/pkg/cloud/aws/s3/mocks"
+	"github.com/justtrackio/gosoline/pkg/encoding/base64"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/parquet"
+	"github.com/justtrackio/gosoline/pkg/replay"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var (
+	archivedAt = time.Date(2021, 5, 2, 10, 30, 0, 0, time.UTC)
+	modelId    = mdl.ModelId{
+		Project:     "justtrack",
+		Environment: "test",
+		Family:      "gosoline",
+		Application: "archive",
+		Name:        "orders",
+	}
+)
+
+func newArchiveCallback(writer archive.Writer, partitioning string, maxFileSize int) *archive.Callback {
+	return archive.NewCallbackWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewFakeClockAt(archivedAt), writer, &archive.Settings{
+		ModelId:            modelId,
+		Partitioning:       partitioning,
+		MaxFileSize:        maxFileSize,
+		TimestampAttribute: "createdAt",
+	})
+}
+
+func decodeRawBodies(t *testing.T, callback *archive.Callback, bodies ...string) ([]interface{}, []map[string]interface{}) {
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: stream.EncodingJson,
+	})
+
+	models := make([]interface{}, len(bodies))
+	attributes := make([]map[string]interface{}, len(bodies))
+
+	for i, body := range bodies {
+		models[i] = callback.GetModel(nil)
+
+		_, attrs, err := encoder.Decode(context.Background(), stream.NewJsonMessage(body), models[i])
+		assert.NoError(t, err)
+
+		attributes[i] = attrs
+	}
+
+	return models, attributes
+}
+
+func archivedMessage(t *testing.T, body string) archive.ParquetMessage {
+	msg, err := archive.NewParquetMessage(archivedAt, stream.NewJsonMessage(body))
+	assert.NoError(t, err)
+
+	return *msg
+}
+
+func TestCallback_Consume(t *testing.T) {
+	writer := new(archiveMocks.Writer)
+	callback := newArchiveCallback(writer, archive.PartitioningHour, 2)
+	models, attributes := decodeRawBodies(t, callback, `{"id":1}`, `{"id": 2}`, `{"id":3}`)
+
+	partition := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	writer.On("Write", mock.Anything, modelId, partition, []archive.ParquetMessage{
+		archivedMessage(t, `{"id":1}`),
+		archivedMessage(t, `{"id": 2}`),
+	}).Return(nil).Once()
+	writer.On("Write", mock.Anything, modelId, partition, []archive.ParquetMessage{
+		archivedMessage(t, `{"id":3}`),
+	}).Return(nil).Once()
+
+	acks, err := callback.Consume(context.Background(), models, attributes)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true}, acks)
+
+	writer.AssertExpectations(t)
+}
+
+func TestCallback_ConsumeWriteFails(t *testing.T) {
+	writer := new(archiveMocks.Writer)
+	callback := newArchiveCallback(writer, archive.PartitioningDay, 2)
+	models, attributes := decodeRawBodies(t, callback, `{"id":1}`, `{"id":2}`, `{"id":3}`)
+
+	partition := time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	writer.On("Write", mock.Anything, modelId, partition, []archive.ParquetMessage{
+		archivedMessage(t, `{"id":1}`),
+		archivedMessage(t, `{"id":2}`),
+	}).Return(fmt.Errorf("boom")).Once()
+	writer.On("Write", mock.Anything, modelId, partition, []archive.ParquetMessage{
+		archivedMessage(t, `{"id":3}`),
+	}).Return(nil).Once()
+
+	acks, err := callback.Consume(context.Background(), models, attributes)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can not write 2 messages of model justtrack.gosoline.archive.orders to partition 2021-05-02T00:00:00Z: boom")
+	assert.Equal(t, []bool{false, false, true}, acks, "only messages of written files should be acknowledged")
+
+	writer.AssertExpectations(t)
+}
+
+func TestCallback_ConsumePartitionsByModelAndTimestamp(t *testing.T) {
+	writer := new(archiveMocks.Writer)
+	callback := newArchiveCallback(writer, archive.PartitioningHour, 10)
+	models, attributes := decodeRawBodies(t, callback, `{"id":1}`, `{"id":2}`, `{"id":3}`)
+
+	attributes[0]["createdAt"] = "2021-05-01T08:15:00Z"
+	attributes[1][archive.AttributeModelId] = "justtrack.gosoline.shop.items"
+	attributes[1]["createdAt"] = "2021-05-01T08:20:00Z"
+	attributes[2]["createdAt"] = "2021-05-01T09:00:00Z"
+
+	createdAt := func(body string, timestamp time.Time, attributes map[string]interface{}) archive.ParquetMessage {
+		msg, err := archive.NewParquetMessage(timestamp, stream.NewJsonMessage(body, attributes))
+		assert.NoError(t, err)
+
+		return *msg
+	}
+
+	itemsModelId := mdl.ModelId{
+		Project:     "justtrack",
+		Environment: "test",
+		Family:      "gosoline",
+		Application: "shop",
+		Name:        "items",
+	}
+
+	writer.On("Write", mock.Anything, modelId, time.Date(2021, 5, 1, 8, 0, 0, 0, time.UTC), []archive.ParquetMessage{
+		createdAt(`{"id":1}`, time.Date(2021, 5, 1, 8, 15, 0, 0, time.UTC), attributes[0]),
+	}).Return(nil).Once()
+	writer.On("Write", mock.Anything, modelId, time.Date(2021, 5, 1, 9, 0, 0, 0, time.UTC), []archive.ParquetMessage{
+		createdAt(`{"id":3}`, time.Date(2021, 5, 1, 9, 0, 0, 0, time.UTC), attributes[2]),
+	}).Return(nil).Once()
+	writer.On("Write", mock.Anything, itemsModelId, time.Date(2021, 5, 1, 8, 0, 0, 0, time.UTC), []archive.ParquetMessage{
+		createdAt(`{"id":2}`, time.Date(2021, 5, 1, 8, 20, 0, 0, time.UTC), attributes[1]),
+	}).Return(nil).Once()
+
+	acks, err := callback.Consume(context.Background(), models, attributes)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true}, acks)
+
+	writer.AssertExpectations(t)
+}
+
+func TestParquetMessage(t *testing.T) {
+	timestamp := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+	msg := stream.NewJsonMessage(`{"id":1}`, map[string]interface{}{
+		"type": "order",
+	})
+
+	record, err := archive.NewParquetMessage(timestamp, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, timestamp, record.GetPartitionTimestamp())
+
+	decoded, err := record.GetMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, stream.NewMessage(`{"id":1}`, map[string]interface{}{
+		"encoding": "application/json",
+		"type":     "order",
+	}), decoded)
+}
+
+func TestRawBody_Protobuf(t *testing.T) {
+	data, err := proto.Marshal(wrapperspb.String("archived"))
+	assert.NoError(t, err)
+
+	encoded := base64.Encode(data)
+	body := &archive.RawBody{}
+
+	err = stream.DecodeMessage(stream.EncodingProtobuf, encoded, body)
+	assert.NoError(t, err)
+	assert.Equal(t, string(encoded), body.String())
+	assert.Equal(t, stream.EncodingProtobuf, body.GetEncoding())
+}
+
+func TestJsonlWriter(t *testing.T) {
+	client := new(s3Mocks.Client)
+
+	prefixNaming, ok := parquet.GetS3PrefixNamingStrategy(parquet.NamingStrategyDtHourSeparated)
+	assert.True(t, ok)
+
+	client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		body, err := ioutil.ReadAll(input.Body)
+		expected := `{"attributes":{"archivedAt":"2021-05-02T10:30:00Z","encoding":"application/json"},"body":"{\"id\":1}"}` + "\n"
+
+		return err == nil &&
+			*input.Bucket == "justtrack-test-gosoline" &&
+			strings.HasPrefix(*input.Key, "datalake/orders/year=2021/month=05/day=02/hour=10/") &&
+			strings.HasSuffix(*input.Key, ".jsonl") &&
+			string(body) == expected
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	writer := archive.NewJsonlWriterWithInterfaces(client, prefixNaming)
+	err := writer.Write(context.Background(), modelId, time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC), []archive.ParquetMessage{
+		archivedMessage(t, `{"id":1}`),
+	})
+	assert.NoError(t, err)
+
+	client.AssertExpectations(t)
+}
+
+func TestJsonlWriter_ReadableByReplay(t *testing.T) {
+	client := new(s3Mocks.Client)
+	uploaded := &bytes.Buffer{}
+
+	prefixNaming, _ := parquet.GetS3PrefixNamingStrategy(parquet.NamingStrategyDtSeparated)
+
+	client.On("PutObject", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		_, _ = uploaded.ReadFrom(args.Get(1).(*s3.PutObjectInput).Body)
+	}).Return(&s3.PutObjectOutput{}, nil).Once()
+	client.On("GetObject", mock.Anything, mock.Anything).Return(func(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(uploaded.Bytes()))}
+	}, nil).Once()
+
+	writer := archive.NewJsonlWriterWithInterfaces(client, prefixNaming)
+	err := writer.Write(context.Background(), mdl.ModelId{Name: "orders"}, archivedAt, []archive.ParquetMessage{
+		archivedMessage(t, `{"id":1}`),
+	})
+	assert.NoError(t, err)
+
+	source := replay.NewS3SourceWithInterfaces(client, "bucket", "datalake/orders/", archive.AttributeArchivedAt)
+	records, err := source.ReadFile(context.Background(), "file")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []replay.Record{
+		{
+			Timestamp: archivedAt,
+			Message: &stream.Message{
+				Attributes: map[string]interface{}{
+					archive.AttributeArchivedAt: "2021-05-02T10:30:00Z",
+					stream.AttributeEncoding:    stream.EncodingJson.String(),
+				},
+				Body: `{"id":1}`,
+			},
+		},
+	}, records)
+
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+// ParquetMessage is the record of a stream.Message in a parquet dataset. The attributes are stored json encoded, as
+// parquet needs a fixed schema.
+type ParquetMessage struct {
+	Timestamp  time.Time `json:"timestamp" parquet:"timestamp"`
+	Attributes string    `json:"attributes" parquet:"attributes"`
+	Body       string    `json:"body" parquet:"body"`
+}
+
+func NewParquetMessage(timestamp time.Time, msg *stream.Message) (*ParquetMessage, error) {
+	attributes, err := json.Marshal(msg.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal message attributes: %w", err)
+	}
+
+	return &ParquetMessage{
+		Timestamp:  timestamp,
+		Attributes: string(attributes),
+		Body:       msg.Body,
+	}, nil
+}
+
+func (m *ParquetMessage) GetPartitionTimestamp() time.Time {
+	return m.Timestamp
+}
+
+func (m *ParquetMessage) GetMessage() (*stream.Message, error) {
+	msg := &stream.Message{
+		Attributes: map[string]interface{}{},
+		Body:       m.Body,
+	}
+
+	if m.Attributes == "" {
+		return msg, nil
+	}
+
+	if err := json.Unmarshal([]byte(m.Attributes), &msg.Attributes); err != nil {
+		return nil, fmt.Errorf("can not unmarshal message attributes: %w", err)
+	}
+
+	return msg, nil
+}
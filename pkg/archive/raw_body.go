@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/encoding/base64"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// RawBody is the model of the archive consumer. It keeps the body of a message as it was encoded, so json and
+// protobuf messages can be archived without knowing their models. As the consumer removes the encoding attribute
+// while decoding, the body remembers its encoding.
+type RawBody struct {
+	data     []byte
+	encoding stream.EncodingType
+}
+
+func (b *RawBody) UnmarshalJSON(data []byte) error {
+	b.data = append([]byte{}, data...)
+	b.encoding = stream.EncodingJson
+
+	return nil
+}
+
+// EmptyMessage returns a message without any fields, so all fields of the decoded message end up as unknown fields.
+func (b *RawBody) EmptyMessage() proto.Message {
+	return &emptypb.Empty{}
+}
+
+func (b *RawBody) FromMessage(message proto.Message) error {
+	b.data = base64.Encode(message.ProtoReflect().GetUnknown())
+	b.encoding = stream.EncodingProtobuf
+
+	return nil
+}
+
+func (b *RawBody) ToMessage() (proto.Message, error) {
+	return nil, fmt.Errorf("a raw body can't be encoded as protobuf message")
+}
+
+func (b *RawBody) GetEncoding() stream.EncodingType {
+	return b.encoding
+}
+
+func (b *RawBody) String() string {
+	return string(b.data)
+}
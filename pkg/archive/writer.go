@@ -0,0 +1,157 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoS3 "github.com/justtrackio/gosoline/pkg/cloud/aws/s3"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/parquet"
+)
+
+const (
+	FormatJsonl   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// A Writer writes the messages of a partition of a model to a new file. It returns after the file has been stored
+// durably.
+//go:generate mockery --name Writer
+type Writer interface {
+	Write(ctx context.Context, modelId mdl.ModelId, partition time.Time, messages []ParquetMessage) error
+}
+
+// A ParquetWriterFactory creates the parquet.Writer of a model.
+type ParquetWriterFactory func(modelId mdl.ModelId) (parquet.Writer, error)
+
+func NewWriter(ctx context.Context, config cfg.Config, logger log.Logger, settings *Settings) (Writer, error) {
+	namingStrategy := settings.GetNamingStrategy()
+
+	switch settings.Format {
+	case FormatJsonl:
+		return newJsonlWriter(ctx, config, logger, namingStrategy)
+	case FormatParquet:
+		return NewParquetWriterWithInterfaces(func(modelId mdl.ModelId) (parquet.Writer, error) {
+			return parquet.NewWriter(ctx, config, logger, &parquet.WriterSettings{
+				ModelId:        modelId,
+				NamingStrategy: namingStrategy,
+			})
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown archive format %s", settings.Format)
+	}
+}
+
+type parquetWriter struct {
+	factory ParquetWriterFactory
+
+	lck     sync.Mutex
+	writers map[mdl.ModelId]parquet.Writer
+}
+
+// NewParquetWriterWithInterfaces writes the messages as parquet files, which can be read by a parquet.Reader into a
+// slice of ParquetMessage. The writer of a model is created by the factory on its first write.
+func NewParquetWriterWithInterfaces(factory ParquetWriterFactory) Writer {
+	return &parquetWriter{
+		factory: factory,
+		writers: make(map[mdl.ModelId]parquet.Writer),
+	}
+}
+
+func (w *parquetWriter) Write(ctx context.Context, modelId mdl.ModelId, partition time.Time, messages []ParquetMessage) error {
+	writer, err := w.getWriter(modelId)
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, partition, messages)
+}
+
+func (w *parquetWriter) getWriter(modelId mdl.ModelId) (parquet.Writer, error) {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	if writer, ok := w.writers[modelId]; ok {
+		return writer, nil
+	}
+
+	writer, err := w.factory(modelId)
+	if err != nil {
+		return nil, fmt.Errorf("can not create parquet writer for model %s: %w", modelId.String(), err)
+	}
+
+	w.writers[modelId] = writer
+
+	return writer, nil
+}
+
+type jsonlWriter struct {
+	client       gosoS3.Client
+	prefixNaming parquet.S3PrefixNamingStrategy
+}
+
+func newJsonlWriter(ctx context.Context, config cfg.Config, logger log.Logger, namingStrategy string) (Writer, error) {
+	prefixNaming, ok := parquet.GetS3PrefixNamingStrategy(namingStrategy)
+	if !ok {
+		return nil, fmt.Errorf("unknown prefix naming strategy: %s", namingStrategy)
+	}
+
+	client, err := gosoS3.ProvideClient(ctx, config, logger, "default")
+	if err != nil {
+		return nil, fmt.Errorf("can not create s3 client default: %w", err)
+	}
+
+	return NewJsonlWriterWithInterfaces(client, prefixNaming), nil
+}
+
+// NewJsonlWriterWithInterfaces writes the messages in the format of the stream.FileOutput to the bucket of their
+// model. The keys are the same as for parquet files, but with a .jsonl extension. The time a message was archived
+// at is stored in the attribute AttributeArchivedAt.
+func NewJsonlWriterWithInterfaces(client gosoS3.Client, prefixNaming parquet.S3PrefixNamingStrategy) Writer {
+	return &jsonlWriter{
+		client:       client,
+		prefixNaming: prefixNaming,
+	}
+}
+
+func (w *jsonlWriter) Write(ctx context.Context, modelId mdl.ModelId, partition time.Time, messages []ParquetMessage) error {
+	body := &bytes.Buffer{}
+
+	for i := range messages {
+		msg, err := messages[i].GetMessage()
+		if err != nil {
+			return err
+		}
+
+		msg.Attributes[AttributeArchivedAt] = messages[i].Timestamp.Format(time.RFC3339Nano)
+
+		data, err := msg.MarshalToBytes()
+		if err != nil {
+			return fmt.Errorf("can not marshal message: %w", err)
+		}
+
+		body.Write(data)
+		body.WriteByte('\n')
+	}
+
+	bucket := parquet.GetS3BucketName(modelId)
+	key := fmt.Sprintf("%s.jsonl", strings.TrimSuffix(parquet.GetS3Key(modelId, partition, w.prefixNaming), ".parquet"))
+
+	_, err := w.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: mdl.String(bucket),
+		Key:    mdl.String(key),
+		Body:   bytes.NewReader(body.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("can not put object s3://%s/%s: %w", bucket, key, err)
+	}
+
+	return nil
+}
@@ -30,16 +30,7 @@ func Run(module kernel.ModuleFactory, otherModuleMaps ...map[string]kernel.Modul
 		defaultErrorHandler("can not initialize the logger: %w", err)
 	}
 
-	settings := &kernelSettings{}
-	config.UnmarshalKey("kernel", settings)
-
-	ctx := appctx.WithContainer(context.Background())
-
-	k, err := kernel.New(ctx, config, logger, kernel.KillTimeout(settings.KillTimeout))
-	if err != nil {
-		defaultErrorHandler("can not initialize the kernel: %w", err)
-	}
-
+	k := newKernel(appctx.WithContainer(context.Background()), config, logger)
 	k.Add("cli", module, kernel.ModuleType(kernel.TypeEssential), kernel.ModuleStage(kernel.StageApplication))
 	for _, otherModuleMap := range otherModuleMaps {
 		for name, otherModule := range otherModuleMap {
@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+const (
+	ExitCodeOk    = 0
+	ExitCodeError = 1
+	ExitCodeUsage = 2
+
+	configFlagName    = "config"
+	defaultConfigFile = "./config.dist.yml"
+)
+
+type CommandOption func(cmd *Command)
+
+// A Command is a named sub command of a cli application. The command runs as an essential kernel module,
+// so the kernel shuts down as soon as the command is done. Flags of the command are declared with a struct
+// using the cfg, default and usage tags. Flags provided on the command line are written to the config at
+// the key cli.<command name> and can be read with ReadFlags inside the module factory.
+type Command struct {
+	Name        string
+	Description string
+	Module      kernel.ModuleFactory
+	Flags       interface{}
+	Stage       int
+}
+
+func NewCommand(name string, description string, module kernel.ModuleFactory, options ...CommandOption) *Command {
+	cmd := &Command{
+		Name:        name,
+		Description: description,
+		Module:      module,
+		Stage:       kernel.StageApplication,
+	}
+
+	for _, opt := range options {
+		opt(cmd)
+	}
+
+	return cmd
+}
+
+// WithFlags declares the flags of a command by a struct (or a pointer to a struct).
+func WithFlags(flags interface{}) CommandOption {
+	return func(cmd *Command) {
+		cmd.Flags = flags
+	}
+}
+
+// WithStage overwrites the kernel stage the command module runs in.
+func WithStage(stage int) CommandOption {
+	return func(cmd *Command) {
+		cmd.Stage = stage
+	}
+}
+
+func FlagsKey(command string) string {
+	return fmt.Sprintf("cli.%s", command)
+}
+
+// ReadFlags unmarshals the flags of a command into the given struct. Values which were not provided on
+// the command line are read from the config or fall back to the default of the field.
+func ReadFlags(config cfg.Config, command string, flags interface{}) {
+	config.UnmarshalKey(FlagsKey(command), flags)
+}
+
+type commandRunner struct {
+	name     string
+	commands map[string]*Command
+	output   io.Writer
+}
+
+// RunCommands parses the command line, selects the command named by the first argument and runs it
+// inside a kernel. It exits the process with ExitCodeOk if the command succeeded, with ExitCodeError if
+// the command or the kernel failed and with ExitCodeUsage if the command line could not be parsed.
+func RunCommands(commands []*Command, otherModuleMaps ...map[string]kernel.ModuleFactory) {
+	runner := newCommandRunner(filepath.Base(os.Args[0]), commands, os.Stderr)
+	code := runner.run(os.Args[1:], otherModuleMaps)
+
+	os.Exit(code)
+}
+
+func newCommandRunner(name string, commands []*Command, output io.Writer) *commandRunner {
+	runner := &commandRunner{
+		name:     name,
+		commands: make(map[string]*Command),
+		output:   output,
+	}
+
+	for _, cmd := range commands {
+		runner.commands[cmd.Name] = cmd
+	}
+
+	return runner
+}
+
+func (r *commandRunner) run(args []string, otherModuleMaps []map[string]kernel.ModuleFactory) int {
+	cmd, configOptions, code := r.parse(args)
+
+	if cmd == nil {
+		return code
+	}
+
+	config := cfg.New()
+	if err := config.Option(configOptions...); err != nil {
+		defaultErrorHandler("can not initialize the config: %w", err)
+	}
+
+	logger, err := newCliLogger()
+	if err != nil {
+		defaultErrorHandler("can not initialize the logger: %w", err)
+	}
+
+	var cmdErr error
+	module := r.wrapModule(cmd, &cmdErr)
+
+	ctx := appctx.WithContainer(context.Background())
+	k := newKernel(ctx, config, logger)
+	k.AddFactory(stream.ProducerDaemonFactory)
+	k.Add(cmd.Name, module, kernel.ModuleType(kernel.TypeEssential), kernel.ModuleStage(cmd.Stage))
+
+	for _, otherModuleMap := range otherModuleMaps {
+		for name, otherModule := range otherModuleMap {
+			k.Add(name, otherModule)
+		}
+	}
+
+	k.Run()
+
+	return r.exitCode(ctx, cmd, cmdErr)
+}
+
+// exitCode reports the command as failed if the command module failed or the kernel did not run it
+// successfully, e.g. because another module could not be created or failed.
+func (r *commandRunner) exitCode(ctx context.Context, cmd *Command, cmdErr error) int {
+	if cmdErr != nil {
+		fmt.Fprintf(r.output, "command %s failed: %s\n", cmd.Name, cmdErr)
+
+		return ExitCodeError
+	}
+
+	reporter, err := kernel.ProvideHealthReporter(ctx)
+	if err != nil {
+		fmt.Fprintf(r.output, "command %s failed: %s\n", cmd.Name, err)
+
+		return ExitCodeError
+	}
+
+	report := reporter.Liveness(ctx)
+
+	if report.Healthy {
+		return ExitCodeOk
+	}
+
+	fmt.Fprintf(r.output, "command %s failed: the kernel ended in state %s\n", cmd.Name, report.Kernel)
+
+	names := make([]string, 0, len(report.Modules))
+	for name := range report.Modules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if module := report.Modules[name]; !module.Healthy {
+			fmt.Fprintf(r.output, "  module %s failed: %s\n", name, module.Error)
+		}
+	}
+
+	return ExitCodeError
+}
+
+// parse selects the command from the arguments and converts its flags to config options. If no command
+// could be selected, the returned command is nil and the exit code reports whether help was requested.
+func (r *commandRunner) parse(args []string) (*Command, []cfg.Option, int) {
+	if len(args) == 0 {
+		r.printUsage()
+		return nil, nil, ExitCodeUsage
+	}
+
+	name := args[0]
+
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) > 1 {
+			if cmd, ok := r.commands[args[1]]; ok {
+				flags, _, _ := r.newFlagSet(cmd)
+				flags.Usage()
+
+				return nil, nil, ExitCodeOk
+			}
+		}
+
+		r.printUsage()
+
+		return nil, nil, ExitCodeOk
+	}
+
+	cmd, ok := r.commands[name]
+
+	if !ok {
+		fmt.Fprintf(r.output, "unknown command %s\n\n", name)
+		r.printUsage()
+
+		return nil, nil, ExitCodeUsage
+	}
+
+	flags, bindings, err := r.newFlagSet(cmd)
+	if err != nil {
+		fmt.Fprintf(r.output, "can not create flags for command %s: %s\n", cmd.Name, err)
+
+		return nil, nil, ExitCodeError
+	}
+
+	configFile := flags.String(configFlagName, "", fmt.Sprintf("path to a config file (default %s if it exists)", defaultConfigFile))
+
+	if err = flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return nil, nil, ExitCodeOk
+		}
+
+		return nil, nil, ExitCodeUsage
+	}
+
+	configOptions := []cfg.Option{
+		cfg.WithErrorHandlers(defaultErrorHandler),
+	}
+
+	switch {
+	case *configFile != "":
+		configOptions = append(configOptions, cfg.WithConfigFile(*configFile, "yml"))
+	case fileExists(defaultConfigFile):
+		configOptions = append(configOptions, cfg.WithConfigFile(defaultConfigFile, "yml"))
+	}
+
+	configOptions = append(configOptions, cfg.WithConfigSetting(FlagsKey(cmd.Name), setFlagValues(flags, bindings)))
+
+	return cmd, configOptions, ExitCodeOk
+}
+
+func (r *commandRunner) newFlagSet(cmd *Command) (*flag.FlagSet, map[string]flagBinding, error) {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	flags.SetOutput(r.output)
+	flags.Usage = func() {
+		fmt.Fprintf(r.output, "usage: %s %s [flags]\n\n%s\n\nflags:\n", r.name, cmd.Name, cmd.Description)
+		flags.PrintDefaults()
+	}
+
+	bindings, err := bindFlags(flags, cmd.Flags)
+
+	return flags, bindings, err
+}
+
+func (r *commandRunner) printUsage() {
+	names := make([]string, 0, len(r.commands))
+	width := 0
+
+	for name := range r.commands {
+		names = append(names, name)
+
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintf(r.output, "usage: %s <command> [flags]\n\ncommands:\n", r.name)
+
+	for _, name := range names {
+		fmt.Fprintf(r.output, "  %-*s  %s\n", width, name, r.commands[name].Description)
+	}
+
+	fmt.Fprintf(r.output, "\nuse \"%s help <command>\" for more information about a command\n", r.name)
+}
+
+// wrapModule records the error of the command module (or its factory) so the exit code can be derived from it.
+func (r *commandRunner) wrapModule(cmd *Command, cmdErr *error) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		module, err := cmd.Module(ctx, config, logger)
+		if err != nil {
+			*cmdErr = err
+
+			return nil, err
+		}
+
+		return &commandModule{
+			Module: module,
+			err:    cmdErr,
+		}, nil
+	}
+}
+
+type commandModule struct {
+	kernel.Module
+	err *error
+}
+
+func (m *commandModule) Run(ctx context.Context) error {
+	err := m.Module.Run(ctx)
+	*m.err = err
+
+	return err
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
+
+func newKernel(ctx context.Context, config cfg.Config, logger log.Logger) kernel.Kernel {
+	settings := &kernelSettings{}
+	config.UnmarshalKey("kernel", settings)
+
+	k, err := kernel.New(ctx, config, logger, kernel.KillTimeout(settings.KillTimeout))
+	if err != nil {
+		defaultErrorHandler("can not initialize the kernel: %w", err)
+	}
+
+	return k
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type backfillFlags struct {
+	From    time.Duration `cfg:"from" default:"24h" usage:"how far to go back"`
+	Limit   int           `cfg:"limit" default:"100" usage:"max number of items"`
+	DryRun  bool          `cfg:"dry_run" usage:"only print what would be done"`
+	Models  []string      `cfg:"models" usage:"models to backfill"`
+	Target  string        `cfg:"target" default:"queue"`
+	Options struct {
+		Rate float64 `cfg:"rate" default:"1.5"`
+	} `cfg:"options"`
+}
+
+func newTestRunner(output *bytes.Buffer) *commandRunner {
+	commands := []*Command{
+		NewCommand("backfill", "backfill all models", nil, WithFlags(&backfillFlags{})),
+		NewCommand("purge-cache", "purge the cache", nil),
+	}
+
+	return newCommandRunner("ops", commands, output)
+}
+
+func TestCommandRunner_ParseFlags(t *testing.T) {
+	output := &bytes.Buffer{}
+	runner := newTestRunner(output)
+
+	cmd, configOptions, code := runner.parse([]string{"backfill", "--limit=5", "--dry_run", "--models=a,b", "--options.rate=3"})
+
+	assert.Equal(t, ExitCodeOk, code)
+	assert.Equal(t, "backfill", cmd.Name)
+
+	// there is no config file in the working directory of the test, so only the flags are set
+	assert.Len(t, configOptions, 2)
+
+	config := cfg.New()
+	err := config.Option(append(configOptions, cfg.WithConfigSetting("cli.backfill.target", "stream"))...)
+	assert.NoError(t, err)
+
+	flags := &backfillFlags{}
+	ReadFlags(config, "backfill", flags)
+
+	assert.Equal(t, 24*time.Hour, flags.From)
+	assert.Equal(t, 5, flags.Limit)
+	assert.True(t, flags.DryRun)
+	assert.Equal(t, []string{"a", "b"}, flags.Models)
+	assert.Equal(t, "stream", flags.Target, "flags which were not set should not overwrite config values")
+	assert.Equal(t, 3.0, flags.Options.Rate)
+}
+
+func TestCommandRunner_ParseUnknownFlag(t *testing.T) {
+	output := &bytes.Buffer{}
+	runner := newTestRunner(output)
+
+	cmd, _, code := runner.parse([]string{"backfill", "--unknown=1"})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, ExitCodeUsage, code)
+	assert.Contains(t, output.String(), "flag provided but not defined: -unknown")
+}
+
+func TestCommandRunner_ParseUnknownCommand(t *testing.T) {
+	output := &bytes.Buffer{}
+	runner := newTestRunner(output)
+
+	cmd, _, code := runner.parse([]string{"reindex"})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, ExitCodeUsage, code)
+	assert.Equal(t, `unknown command reindex
+
+usage: ops <command> [flags]
+
+commands:
+  backfill     backfill all models
+  purge-cache  purge the cache
+
+use "ops help <command>" for more information about a command
+`, output.String())
+}
+
+func TestCommandRunner_HelpCommand(t *testing.T) {
+	output := &bytes.Buffer{}
+	runner := newTestRunner(output)
+
+	cmd, _, code := runner.parse([]string{"help", "backfill"})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, ExitCodeOk, code)
+	assert.Contains(t, output.String(), "usage: ops backfill [flags]")
+	assert.Contains(t, output.String(), "how far to go back (default 24h0m0s)")
+	assert.Contains(t, output.String(), "models to backfill (comma separated)")
+}
+
+func TestBindFlags_Unsupported(t *testing.T) {
+	type invalidFlags struct {
+		Values map[string]string `cfg:"values"`
+	}
+
+	output := &bytes.Buffer{}
+	runner := newCommandRunner("ops", []*Command{NewCommand("invalid", "", nil, WithFlags(invalidFlags{}))}, output)
+
+	cmd, _, code := runner.parse([]string{"invalid"})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, ExitCodeError, code)
+	assert.Contains(t, output.String(), "flags of type map[string]string are not supported")
+}
+
+func TestBindFlags_ReservedConfig(t *testing.T) {
+	type configFlags struct {
+		Config string `cfg:"config"`
+	}
+
+	output := &bytes.Buffer{}
+	runner := newCommandRunner("ops", []*Command{NewCommand("invalid", "", nil, WithFlags(configFlags{}))}, output)
+
+	cmd, _, code := runner.parse([]string{"invalid"})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, ExitCodeError, code)
+	assert.Contains(t, output.String(), "the flag config is reserved for the path of the config file")
+}
+
+type noopModule struct{}
+
+func (m noopModule) Run(_ context.Context) error {
+	return nil
+}
+
+func TestCommandRunner_RunFailedKernel(t *testing.T) {
+	output := &bytes.Buffer{}
+	module := func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return noopModule{}, nil
+	}
+	failing := func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return nil, fmt.Errorf("no connection")
+	}
+
+	runner := newCommandRunner("ops", []*Command{NewCommand("noop", "", module)}, output)
+	code := runner.run([]string{"noop"}, []map[string]kernel.ModuleFactory{{"failing": failing}})
+
+	assert.Equal(t, ExitCodeError, code)
+	assert.Contains(t, output.String(), "command noop failed: the kernel ended in state failed")
+}
+
+func TestCommandRunner_Run(t *testing.T) {
+	output := &bytes.Buffer{}
+	module := func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return noopModule{}, nil
+	}
+
+	runner := newCommandRunner("ops", []*Command{NewCommand("noop", "", module)}, output)
+	code := runner.run([]string{"noop"}, nil)
+
+	assert.Equal(t, ExitCodeOk, code)
+	assert.Empty(t, output.String())
+}
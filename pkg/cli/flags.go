@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// flagBinding connects a flag of a command flag set with the cfg key it is written to.
+type flagBinding struct {
+	key   string
+	value func() interface{}
+}
+
+// bindFlags registers a flag for every field of the given flag struct. The flag name is taken from the
+// cfg tag of the field, the default value from the default tag and the help text from the usage tag.
+// Nested structs are flattened using a dot as separator, e.g. a field "limit" in a struct "batch"
+// becomes the flag --batch.limit.
+func bindFlags(flags *flag.FlagSet, flagStruct interface{}) (map[string]flagBinding, error) {
+	bindings := make(map[string]flagBinding)
+
+	if flagStruct == nil {
+		return bindings, nil
+	}
+
+	typ := reflect.TypeOf(flagStruct)
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the flags of a command have to be a struct, but %T was provided", flagStruct)
+	}
+
+	if err := bindStructFlags(flags, bindings, typ, ""); err != nil {
+		return nil, err
+	}
+
+	return bindings, nil
+}
+
+func bindStructFlags(flags *flag.FlagSet, bindings map[string]flagBinding, typ reflect.Type, prefix string) error {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		key, ok := field.Tag.Lookup("cfg")
+
+		if !ok || key == "" || key == "-" {
+			continue
+		}
+
+		name := prefix + key
+
+		if name == configFlagName {
+			return fmt.Errorf("the flag %s is reserved for the path of the config file", name)
+		}
+
+		def := field.Tag.Get("default")
+		usage := field.Tag.Get("usage")
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := bindStructFlags(flags, bindings, field.Type, name+"."); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		binding, err := bindFlag(flags, field.Type, name, def, usage)
+		if err != nil {
+			return fmt.Errorf("can not bind flag %s: %w", name, err)
+		}
+
+		bindings[name] = binding
+	}
+
+	return nil
+}
+
+func bindFlag(flags *flag.FlagSet, typ reflect.Type, name string, def string, usage string) (flagBinding, error) {
+	var err error
+	var value func() interface{}
+
+	switch {
+	case typ == durationType:
+		var d time.Duration
+
+		if def != "" {
+			if d, err = time.ParseDuration(def); err != nil {
+				return flagBinding{}, err
+			}
+		}
+
+		ptr := flags.Duration(name, d, usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() == reflect.String:
+		ptr := flags.String(name, def, usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() == reflect.Bool:
+		ptr := flags.Bool(name, def == "true", usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Int64:
+		var i int64
+
+		if def != "" {
+			if _, err = fmt.Sscan(def, &i); err != nil {
+				return flagBinding{}, err
+			}
+		}
+
+		ptr := flags.Int64(name, i, usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() >= reflect.Uint && typ.Kind() <= reflect.Uint64:
+		var u uint64
+
+		if def != "" {
+			if _, err = fmt.Sscan(def, &u); err != nil {
+				return flagBinding{}, err
+			}
+		}
+
+		ptr := flags.Uint64(name, u, usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		var f float64
+
+		if def != "" {
+			if _, err = fmt.Sscan(def, &f); err != nil {
+				return flagBinding{}, err
+			}
+		}
+
+		ptr := flags.Float64(name, f, usage)
+		value = func() interface{} { return *ptr }
+
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String:
+		ptr := flags.String(name, def, usage+" (comma separated)")
+		value = func() interface{} {
+			if *ptr == "" {
+				return []string{}
+			}
+
+			return strings.Split(*ptr, ",")
+		}
+
+	default:
+		return flagBinding{}, fmt.Errorf("flags of type %s are not supported", typ.String())
+	}
+
+	return flagBinding{
+		key:   name,
+		value: value,
+	}, nil
+}
+
+// setFlagValues collects the values of all flags which were explicitly set on the command line. Flags
+// which were not provided are left out, so values from config files or the environment are not overwritten
+// by the defaults of the flags.
+func setFlagValues(flags *flag.FlagSet, bindings map[string]flagBinding) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	flags.Visit(func(f *flag.Flag) {
+		binding, ok := bindings[f.Name]
+
+		if !ok {
+			return
+		}
+
+		setNestedValue(values, strings.Split(binding.key, "."), binding.value())
+	})
+
+	return values
+}
+
+func setNestedValue(values map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 1 {
+		values[path[0]] = value
+		return
+	}
+
+	child, ok := values[path[0]].(map[string]interface{})
+
+	if !ok {
+		child = make(map[string]interface{})
+		values[path[0]] = child
+	}
+
+	setNestedValue(child, path[1:], value)
+}
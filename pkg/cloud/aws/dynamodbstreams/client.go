@@ -0,0 +1,66 @@
+package dynamodbstreams
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsCfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoAws "github.com/justtrackio/gosoline/pkg/cloud/aws"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+//go:generate mockery --name Client
+type Client interface {
+	DescribeStream(ctx context.Context, params *dynamodbstreams.DescribeStreamInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.DescribeStreamOutput, error)
+	GetRecords(ctx context.Context, params *dynamodbstreams.GetRecordsInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetRecordsOutput, error)
+	GetShardIterator(ctx context.Context, params *dynamodbstreams.GetShardIteratorInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetShardIteratorOutput, error)
+	ListStreams(ctx context.Context, params *dynamodbstreams.ListStreamsInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.ListStreamsOutput, error)
+}
+
+type ClientSettings struct {
+	gosoAws.ClientSettings
+}
+
+type ClientConfig struct {
+	Settings    ClientSettings
+	LoadOptions []func(options *awsCfg.LoadOptions) error
+}
+
+type ClientOption func(cfg *ClientConfig)
+
+type clientAppCtxKey string
+
+func ProvideClient(ctx context.Context, config cfg.Config, logger log.Logger, name string, optFns ...ClientOption) (*dynamodbstreams.Client, error) {
+	client, err := appctx.Provide(ctx, clientAppCtxKey(name), func() (interface{}, error) {
+		return NewClient(ctx, config, logger, name, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client.(*dynamodbstreams.Client), nil
+}
+
+func NewClient(ctx context.Context, config cfg.Config, logger log.Logger, name string, optFns ...ClientOption) (*dynamodbstreams.Client, error) {
+	clientCfg := &ClientConfig{}
+	gosoAws.UnmarshalClientSettings(config, &clientCfg.Settings, "dynamodbstreams", name)
+
+	for _, opt := range optFns {
+		opt(clientCfg)
+	}
+
+	var err error
+	var awsConfig aws.Config
+
+	if awsConfig, err = gosoAws.DefaultClientConfig(ctx, config, logger, clientCfg.Settings.ClientSettings, clientCfg.LoadOptions...); err != nil {
+		return nil, fmt.Errorf("can not initialize config: %w", err)
+	}
+
+	client := dynamodbstreams.NewFromConfig(awsConfig)
+
+	return client, nil
+}
@@ -16,6 +16,20 @@ type Queue struct {
 	mock.Mock
 }
 
+// ChangeMessageVisibility provides a mock function with given fields: ctx, receiptHandle, visibilityTimeout
+func (_m *Queue) ChangeMessageVisibility(ctx context.Context, receiptHandle string, visibilityTimeout int32) error {
+	ret := _m.Called(ctx, receiptHandle, visibilityTimeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) error); ok {
+		r0 = rf(ctx, receiptHandle, visibilityTimeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteMessage provides a mock function with given fields: ctx, receiptHandle
 func (_m *Queue) DeleteMessage(ctx context.Context, receiptHandle string) error {
 	ret := _m.Called(ctx, receiptHandle)
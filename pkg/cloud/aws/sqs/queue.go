@@ -29,6 +29,7 @@ type Queue interface {
 	GetUrl() string
 	GetArn() string
 
+	ChangeMessageVisibility(ctx context.Context, receiptHandle string, visibilityTimeout int32) error
 	DeleteMessage(ctx context.Context, receiptHandle string) error
 	DeleteMessageBatch(ctx context.Context, receiptHandles []string) error
 	Receive(ctx context.Context, maxNumberOfMessages int32, waitTime int32) ([]types.Message, error)
@@ -185,6 +186,10 @@ func (q *queue) SendBatch(ctx context.Context, messages []*Message) error {
 
 func (q *queue) Receive(ctx context.Context, maxNumberOfMessages int32, waitTime int32) ([]types.Message, error) {
 	input := &sqs.ReceiveMessageInput{
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeName(types.MessageSystemAttributeNameApproximateReceiveCount),
+			types.QueueAttributeName(types.MessageSystemAttributeNameMessageGroupId),
+		},
 		MessageAttributeNames: []string{"ALL"},
 		MaxNumberOfMessages:   maxNumberOfMessages,
 		QueueUrl:              aws.String(q.properties.Url),
@@ -205,6 +210,18 @@ func (q *queue) Receive(ctx context.Context, maxNumberOfMessages int32, waitTime
 	return out.Messages, nil
 }
 
+func (q *queue) ChangeMessageVisibility(ctx context.Context, receiptHandle string, visibilityTimeout int32) error {
+	input := &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(q.properties.Url),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: visibilityTimeout,
+	}
+
+	_, err := q.client.ChangeMessageVisibility(ctx, input)
+
+	return err
+}
+
 func (q *queue) DeleteMessage(ctx context.Context, receiptHandle string) error {
 	input := &sqs.DeleteMessageInput{
 		QueueUrl:      aws.String(q.properties.Url),
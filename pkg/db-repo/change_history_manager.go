@@ -142,20 +142,20 @@ func (c *ChangeHistoryManager) createHistoryTriggers(originalTable *tableMetadat
 		fmt.Sprintf(`CREATE TRIGGER %s_ai AFTER INSERT ON %s FOR EACH ROW %s WHERE %s`,
 			originalTable.tableName,
 			originalTable.tableNameQuoted,
-			c.insertHistoryEntry(originalTable, historyTable, "insert", true),
+			c.insertHistoryEntry(originalTable, historyTable, "'insert'", true),
 			c.primaryKeysMatchCondition(originalTable, NewRecord),
 		),
 		fmt.Sprintf(`CREATE TRIGGER %s_au AFTER UPDATE ON %s FOR EACH ROW %s WHERE %s AND (%s)`,
 			originalTable.tableName,
 			originalTable.tableNameQuoted,
-			c.insertHistoryEntry(originalTable, historyTable, "update", true),
+			c.insertHistoryEntry(originalTable, historyTable, c.updateAction(originalTable), true),
 			c.primaryKeysMatchCondition(originalTable, NewRecord),
 			c.rowUpdatedCondition(originalTable),
 		),
 		fmt.Sprintf(`CREATE TRIGGER %s_bd BEFORE DELETE ON %s FOR EACH ROW %s WHERE %s`,
 			originalTable.tableName,
 			originalTable.tableNameQuoted,
-			c.insertHistoryEntry(originalTable, historyTable, "delete", false),
+			c.insertHistoryEntry(originalTable, historyTable, "'delete'", false),
 			c.primaryKeysMatchCondition(originalTable, OldRecord),
 		),
 		fmt.Sprintf(`CREATE TRIGGER %s_revai BEFORE INSERT ON %s FOR EACH ROW %s`,
@@ -179,7 +179,7 @@ func (c *ChangeHistoryManager) insertHistoryEntry(originalTable *tableMetadata,
 
 	return fmt.Sprintf(`
 		INSERT INTO %s (change_history_action,change_history_revision,change_history_action_at,%s) 
-			SELECT '%s', NULL, NOW(), %s 
+			SELECT %s, NULL, NOW(), %s 
 			FROM %s AS d`,
 		historyTable.tableNameQuoted,
 		columns,
@@ -188,6 +188,21 @@ func (c *ChangeHistoryManager) insertHistoryEntry(originalTable *tableMetadata,
 		originalTable.tableNameQuoted)
 }
 
+// updateAction records soft deletes and restores of soft deletable models as their own actions instead of updates.
+func (c *ChangeHistoryManager) updateAction(originalTable *tableMetadata) string {
+	column, ok := originalTable.columnNameQuoted(ColumnDeletedAt)
+	if !ok {
+		return "'update'"
+	}
+
+	return fmt.Sprintf(`CASE
+				WHEN OLD.%s IS NULL AND NEW.%s IS NOT NULL THEN 'softdel'
+				WHEN OLD.%s IS NOT NULL AND NEW.%s IS NULL THEN 'restore'
+				ELSE 'update'
+			END`,
+		column, column, column, column)
+}
+
 func (c *ChangeHistoryManager) incrementRevision(originalTable *tableMetadata, historyTable *tableMetadata) string {
 	return fmt.Sprintf(`
 		BEGIN 
@@ -131,3 +131,13 @@ func (m *tableMetadata) columnNamesQuotedExcludingValue(excluded ...string) []st
 		return !funk.ContainsString(excluded, item.name)
 	}).([]columnMetadata))
 }
+
+func (m *tableMetadata) columnNameQuoted(name string) (string, bool) {
+	for _, column := range m.columns {
+		if column.name == name {
+			return column.nameQuoted, true
+		}
+	}
+
+	return "", false
+}
@@ -5,6 +5,12 @@ import (
 	"fmt"
 )
 
+// ErrVersionConflict is returned when updating a versioned model which has been modified since it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrSoftDeleteNotSupported is returned when restoring a model which doesn't implement SoftDeletable.
+var ErrSoftDeleteNotSupported = errors.New("soft deletes are not supported by the model")
+
 type RecordNotFoundError struct {
 	id      uint
 	modelId string
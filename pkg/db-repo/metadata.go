@@ -19,6 +19,9 @@ type Metadata struct {
 	TableName  string
 	PrimaryKey string
 	Mappings   FieldMappings
+	// VersionColumn enables optimistic locking on the given integer column.
+	// Alternatively, the field can be marked with an orm:"version" tag.
+	VersionColumn string
 }
 
 type FieldMappings map[string]FieldMapping
@@ -63,6 +63,22 @@ func (r metricRepository) Delete(ctx context.Context, value ModelBased) error {
 	return err
 }
 
+func (r metricRepository) Restore(ctx context.Context, value ModelBased) error {
+	start := time.Now()
+	err := r.Repository.Restore(ctx, value)
+	r.writeMetric(Restore, err, start)
+
+	return err
+}
+
+func (r metricRepository) Purge(ctx context.Context, value ModelBased) error {
+	start := time.Now()
+	err := r.Repository.Purge(ctx, value)
+	r.writeMetric(Purge, err, start)
+
+	return err
+}
+
 func (r metricRepository) Query(ctx context.Context, qb *QueryBuilder, result interface{}) error {
 	start := time.Now()
 	err := r.Repository.Query(ctx, qb, result)
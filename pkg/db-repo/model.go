@@ -6,7 +6,10 @@ import (
 	"github.com/justtrackio/gosoline/pkg/mdl"
 )
 
-const ColumnUpdatedAt = "updated_at"
+const (
+	ColumnUpdatedAt = "updated_at"
+	ColumnDeletedAt = "deleted_at"
+)
 
 type ModelBased interface {
 	mdl.Identifiable
@@ -53,6 +56,25 @@ func (m *Timestamps) GetCreatedAt() *time.Time {
 	return m.CreatedAt
 }
 
+// SoftDeletable models are only marked as deleted by the repository and can be restored until they are purged.
+type SoftDeletable interface {
+	GetDeletedAt() *time.Time
+	SetDeletedAt(deletedAt *time.Time)
+}
+
+// SoftDeletes opts a model into soft deletes when embedded next to the Model.
+type SoftDeletes struct {
+	DeletedAt *time.Time `sql:"index"`
+}
+
+func (m *SoftDeletes) GetDeletedAt() *time.Time {
+	return m.DeletedAt
+}
+
+func (m *SoftDeletes) SetDeletedAt(deletedAt *time.Time) {
+	m.DeletedAt = deletedAt
+}
+
 func EmptyTimestamps() Timestamps {
 	return Timestamps{
 		UpdatedAt: &time.Time{},
@@ -17,6 +17,11 @@ const (
 	metricNameNotifyFailure = "ModelEventNotifyFailure"
 )
 
+const (
+	DeleteTypeSoft = "soft"
+	DeleteTypeHard = "hard"
+)
+
 var NotificationTypes = []string{Create, Update, Delete}
 
 type (
@@ -61,11 +66,17 @@ func (n baseNotifier) Send(ctx context.Context, notificationType string, value M
 
 	out := n.transformer("api", n.version, value)
 
-	msg, err := n.encoder.Encode(ctx, out, map[string]interface{}{
+	attributes := map[string]interface{}{
 		"type":    notificationType,
 		"version": n.version,
 		"modelId": modelId,
-	})
+	}
+
+	if notificationType == Delete {
+		attributes["deleteType"] = getDeleteType(value)
+	}
+
+	msg, err := n.encoder.Encode(ctx, out, attributes)
 	if err != nil {
 		return fmt.Errorf("can not encode notification message: %w", err)
 	}
@@ -110,6 +121,15 @@ func (n baseNotifier) writeMetric(err error) {
 	})
 }
 
+// getDeleteType tells subscribers whether the deleted model can still be restored.
+func getDeleteType(value ModelBased) string {
+	if model, ok := value.(SoftDeletable); ok && model.GetDeletedAt() != nil {
+		return DeleteTypeSoft
+	}
+
+	return DeleteTypeHard
+}
+
 func getDefaultNotifierMetrics(modelId mdl.ModelId) []*metric.Datum {
 	return []*metric.Datum{
 		{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/justtrackio/gosoline/pkg/db"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
@@ -61,11 +62,56 @@ func (r *notifyingRepository) Delete(ctx context.Context, value ModelBased) erro
 	return r.doCallback(ctx, Delete, value)
 }
 
+// Restore notifies about a create, as the model reappears for everyone who processed its delete notification.
+func (r *notifyingRepository) Restore(ctx context.Context, value ModelBased) error {
+	if err := r.Repository.Restore(ctx, value); err != nil {
+		return err
+	}
+
+	return r.doCallback(ctx, Create, value)
+}
+
+// Purge notifies about a hard delete. Purging a soft deleted model is not notified again, as the delete notification
+// has already been sent for the soft delete.
+func (r *notifyingRepository) Purge(ctx context.Context, value ModelBased) error {
+	softDeleted := false
+	if model, ok := value.(SoftDeletable); ok {
+		softDeleted = model.GetDeletedAt() != nil
+	}
+
+	if err := r.Repository.Purge(ctx, value); err != nil {
+		return err
+	}
+
+	if softDeleted {
+		return nil
+	}
+
+	return r.doCallback(ctx, Delete, value)
+}
+
+// doCallback sends the notifications right away or, inside of a transaction, once the transaction has been committed.
+// Errors of deferred notifications can't be returned anymore and are logged instead.
 func (r *notifyingRepository) doCallback(ctx context.Context, callbackType string, value ModelBased) error {
 	if _, ok := r.notifiers[callbackType]; !ok {
 		return nil
 	}
 
+	if _, ok := db.TransactionFromContext(ctx); !ok {
+		return r.notify(ctx, callbackType, value)
+	}
+
+	db.OnCommit(ctx, func(ctx context.Context) {
+		if err := r.notify(ctx, callbackType, value); err != nil {
+			r.logger.WithContext(ctx).Error("can not send notifications after commit: %w", err)
+		}
+	})
+
+	return nil
+}
+
+func (r *notifyingRepository) notify(ctx context.Context, callbackType string, value ModelBased) error {
+
 	logger := r.logger.WithContext(ctx)
 	var errors error
 
@@ -8,16 +8,21 @@ import (
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/db"
 	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/spf13/cast"
 )
 
 type OrmMigrationSetting struct {
 	TablePrefixed bool `cfg:"table_prefixed" default:"true"`
 }
 
+const ormConnectionNameKey = "gosoline:connection_name"
+
 type OrmSettings struct {
 	Migrations  OrmMigrationSetting `cfg:"migrations"`
 	Driver      string              `cfg:"driver" validation:"required"`
 	Application string              `cfg:"application" default:"{app_name}"`
+	// ConnectionName is the name of the db connection, an orm only joins transactions running on the same connection
+	ConnectionName string
 }
 
 func NewOrm(config cfg.Config, logger log.Logger) (*gorm.DB, error) {
@@ -28,6 +33,7 @@ func NewOrm(config cfg.Config, logger log.Logger) (*gorm.DB, error) {
 
 	settings := OrmSettings{}
 	config.UnmarshalKey("db.default", &settings)
+	settings.ConnectionName = db.ConnectionNameFromConfig(config, "default")
 
 	return NewOrmWithInterfaces(dbClient, settings)
 }
@@ -42,8 +48,9 @@ func NewOrmWithDbSettings(logger log.Logger, dbSettings db.Settings, application
 		Migrations: OrmMigrationSetting{
 			TablePrefixed: dbSettings.Migrations.PrefixedTables,
 		},
-		Driver:      dbSettings.Driver,
-		Application: application,
+		Driver:         dbSettings.Driver,
+		Application:    application,
+		ConnectionName: db.ConnectionName(dbSettings),
 	}
 
 	return NewOrmWithInterfaces(dbClient, ormSettings)
@@ -55,10 +62,8 @@ func NewOrmWithInterfaces(dbClient gorm.SQLCommon, settings OrmSettings) (*gorm.
 		return nil, fmt.Errorf("could not create gorm: %w", err)
 	}
 
-	orm.LogMode(false)
-	orm.SetLogger(&noopLogger{})
-	orm = orm.Set("gorm:auto_preload", true)
-	orm = orm.Set("gorm:save_associations", false)
+	orm = configureOrm(orm)
+	orm = orm.Set(ormConnectionNameKey, settings.ConnectionName)
 
 	if !settings.Migrations.TablePrefixed {
 		return orm, nil
@@ -72,3 +77,38 @@ func NewOrmWithInterfaces(dbClient gorm.SQLCommon, settings OrmSettings) (*gorm.
 
 	return orm, nil
 }
+
+// NewOrmForTransaction creates an orm which runs all statements in the given transaction. The transaction has to run
+// on the same connection as the orm.
+func NewOrmForTransaction(orm *gorm.DB, tx *db.Transaction) (*gorm.DB, error) {
+	connectionName, _ := orm.Get(ormConnectionNameKey)
+
+	if err := tx.CheckConnection(cast.ToString(connectionName)); err != nil {
+		return nil, err
+	}
+
+	txOrm, err := gorm.Open(orm.Dialect().GetName(), tx.Tx().Tx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create gorm: %w", err)
+	}
+
+	registerOrmCallbacks(txOrm)
+
+	return configureOrm(txOrm), nil
+}
+
+func configureOrm(orm *gorm.DB) *gorm.DB {
+	orm.LogMode(false)
+	orm.SetLogger(&noopLogger{})
+	orm = orm.Set("gorm:auto_preload", true)
+	orm = orm.Set("gorm:save_associations", false)
+
+	return orm
+}
+
+func registerOrmCallbacks(orm *gorm.DB) {
+	orm.Callback().
+		Update().
+		After("gorm:update_time_stamp").
+		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+}
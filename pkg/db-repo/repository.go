@@ -2,6 +2,8 @@ package db_repo
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -20,15 +22,17 @@ import (
 )
 
 const (
-	Create = "create"
-	Read   = "read"
-	Update = "update"
-	Delete = "delete"
-	Query  = "query"
+	Create  = "create"
+	Read    = "read"
+	Update  = "update"
+	Delete  = "delete"
+	Query   = "query"
+	Restore = "restore"
+	Purge   = "purge"
 )
 
 var (
-	operations     = []string{Create, Read, Update, Delete, Query}
+	operations     = []string{Create, Read, Update, Delete, Query, Restore, Purge}
 	ErrCrossQuery  = fmt.Errorf("cross querying wrong model from repo")
 	ErrCrossCreate = fmt.Errorf("cross creating wrong model from repo")
 	ErrCrossRead   = fmt.Errorf("cross reading wrong model from repo")
@@ -47,6 +51,8 @@ type Repository interface {
 	Read(ctx context.Context, id *uint, out ModelBased) error
 	Update(ctx context.Context, value ModelBased) error
 	Delete(ctx context.Context, value ModelBased) error
+	Restore(ctx context.Context, value ModelBased) error
+	Purge(ctx context.Context, value ModelBased) error
 	Query(ctx context.Context, qb *QueryBuilder, result interface{}) error
 	Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error)
 
@@ -74,10 +80,7 @@ func New(config cfg.Config, logger log.Logger, s Settings) (*repository, error)
 		return nil, fmt.Errorf("can not create orm: %w", err)
 	}
 
-	orm.Callback().
-		Update().
-		After("gorm:update_time_stamp").
-		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+	registerOrmCallbacks(orm)
 	clk := clock.NewRealClock()
 
 	return NewWithInterfaces(logger, tracer, orm, clk, s.Metadata), nil
@@ -94,10 +97,7 @@ func NewWithDbSettings(config cfg.Config, logger log.Logger, dbSettings db.Setti
 		return nil, fmt.Errorf("can not create orm: %w", err)
 	}
 
-	orm.Callback().
-		Update().
-		After("gorm:update_time_stamp").
-		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+	registerOrmCallbacks(orm)
 
 	clk := clock.NewRealClock()
 
@@ -129,7 +129,17 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 	value.SetUpdatedAt(&now)
 	value.SetCreatedAt(&now)
 
-	err := r.orm.Create(value).Error
+	if err := r.initVersion(value); err != nil {
+		logger.Error("could not initialize version of model type %v: %w", modelId, err)
+		return err
+	}
+
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = orm.Create(value).Error
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warn("could not create model of type %s due to duplicate entry error: %s", modelId, err.Error())
@@ -143,7 +153,7 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 		return err
 	}
 
-	err = r.refreshAssociations(value, Create)
+	err = r.refreshAssociations(orm, value, Create)
 
 	if err != nil {
 		logger.Error("could not update associations of model type %v: %w", modelId, err)
@@ -164,7 +174,16 @@ func (r *repository) Read(ctx context.Context, id *uint, out ModelBased) error {
 	_, span := r.startSubSpan(ctx, "Get")
 	defer span.Finish()
 
-	err := r.orm.First(out, *id).Error
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if IsWithDeleted(ctx) {
+		orm = orm.Unscoped()
+	}
+
+	err = orm.First(out, *id).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewRecordNotFoundError(*id, modelId, err)
@@ -187,7 +206,12 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 	now := r.clock.Now()
 	value.SetUpdatedAt(&now)
 
-	err := r.orm.Save(value).Error
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.save(orm, value)
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warn("could not update model of type %s with id %d due to duplicate entry error: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
@@ -196,12 +220,17 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 		}
 	}
 
+	if errors.Is(err, ErrVersionConflict) {
+		logger.Warn("could not update model of type %s with id %d due to a version conflict: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
+		return err
+	}
+
 	if err != nil {
 		logger.Error("could not update model of type %s with id %d: %w", modelId, mdl.EmptyUintIfNil(value.GetId()), err)
 		return err
 	}
 
-	err = r.refreshAssociations(value, Update)
+	err = r.refreshAssociations(orm, value, Update)
 
 	if err != nil {
 		logger.Error("could not update associations of model type %s with id %d: %w", modelId, *value.GetId(), err)
@@ -213,24 +242,127 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 	return r.Read(ctx, value.GetId(), value)
 }
 
+// Delete marks SoftDeletable models as deleted and removes all other models from the database.
 func (r *repository) Delete(ctx context.Context, value ModelBased) error {
 	if !r.isQueryableModel(value) {
 		return ErrCrossDelete
 	}
 
+	_, span := r.startSubSpan(ctx, "Delete")
+	defer span.Finish()
+
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if model, ok := value.(SoftDeletable); ok {
+		return r.softDelete(ctx, orm, value, model)
+	}
+
+	return r.hardDelete(ctx, orm, value)
+}
+
+// Restore reverts the soft delete of a model and reads the restored model into value.
+func (r *repository) Restore(ctx context.Context, value ModelBased) error {
+	if !r.isQueryableModel(value) {
+		return ErrCrossUpdate
+	}
+
+	model, ok := value.(SoftDeletable)
+	if !ok {
+		return ErrSoftDeleteNotSupported
+	}
+
 	modelId := r.GetModelId()
 	logger := r.logger.WithContext(ctx)
 
-	_, span := r.startSubSpan(ctx, "Delete")
+	ctx, span := r.startSubSpan(ctx, "Restore")
+	defer span.Finish()
+
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	result := orm.Unscoped().Model(value).UpdateColumns(map[string]interface{}{
+		ColumnDeletedAt: nil,
+		ColumnUpdatedAt: now,
+	})
+
+	if result.Error != nil {
+		logger.Error("could not restore model of type %s with id %d: %w", modelId, mdl.EmptyUintIfNil(value.GetId()), result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return NewRecordNotFoundError(mdl.EmptyUintIfNil(value.GetId()), modelId, gorm.ErrRecordNotFound)
+	}
+
+	model.SetDeletedAt(nil)
+	value.SetUpdatedAt(&now)
+
+	logger.Info("restored model of type %s with id %d", modelId, *value.GetId())
+
+	return r.Read(ctx, value.GetId(), value)
+}
+
+// Purge removes a model from the database, regardless of whether it has been soft deleted before.
+func (r *repository) Purge(ctx context.Context, value ModelBased) error {
+	if !r.isQueryableModel(value) {
+		return ErrCrossDelete
+	}
+
+	_, span := r.startSubSpan(ctx, "Purge")
 	defer span.Finish()
 
-	err := r.refreshAssociations(value, Delete)
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.hardDelete(ctx, orm.Unscoped(), value)
+}
+
+func (r *repository) softDelete(ctx context.Context, orm *gorm.DB, value ModelBased, model SoftDeletable) error {
+	modelId := r.GetModelId()
+	logger := r.logger.WithContext(ctx)
+
+	now := r.clock.Now()
+	result := orm.Model(value).UpdateColumns(map[string]interface{}{
+		ColumnDeletedAt: now,
+		ColumnUpdatedAt: now,
+	})
+
+	if result.Error != nil {
+		logger.Error("could not soft delete model of type %s with id %d: %w", modelId, mdl.EmptyUintIfNil(value.GetId()), result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return NewRecordNotFoundError(mdl.EmptyUintIfNil(value.GetId()), modelId, gorm.ErrRecordNotFound)
+	}
+
+	model.SetDeletedAt(&now)
+	value.SetUpdatedAt(&now)
+
+	logger.Info("soft deleted model of type %s with id %d", modelId, *value.GetId())
+
+	return nil
+}
+
+func (r *repository) hardDelete(ctx context.Context, orm *gorm.DB, value ModelBased) error {
+	modelId := r.GetModelId()
+	logger := r.logger.WithContext(ctx)
+
+	err := r.refreshAssociations(orm, value, Delete)
 	if err != nil {
 		logger.Error("could not delete associations of model type %s with id %d: %w", modelId, *value.GetId(), err)
 		return err
 	}
 
-	err = r.orm.Delete(value).Error
+	err = orm.Delete(value).Error
 
 	if err != nil {
 		logger.Error("could not delete model of type %s with id %d: %w", modelId, *value.GetId(), err)
@@ -272,7 +404,16 @@ func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interfa
 	_, span := r.startSubSpan(ctx, "Query")
 	defer span.Finish()
 
-	db := r.orm.New()
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	db := orm.New()
+
+	if IsWithDeleted(ctx) {
+		db = db.Unscoped()
+	}
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -323,7 +464,12 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 		Count int
 	}{}
 
-	db := r.orm.New()
+	orm, err := r.ormFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db := orm.New()
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -333,17 +479,22 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 		db = db.Where(qb.where[i], qb.args[i]...)
 	}
 
-	scope := r.orm.NewScope(model)
+	scope := orm.NewScope(model)
 	tableName := scope.TableName()
 	key := scope.PrimaryKey()
 	sel := fmt.Sprintf("COUNT(DISTINCT %s.%s) AS count", tableName, key)
 
-	err := db.Table(tableName).Select(sel).Scan(&result).Error
+	// the count is selected from the table instead of the model, so gorm doesn't exclude soft deleted models by itself
+	if isSoftDeletable(model) && !IsWithDeleted(ctx) {
+		db = db.Where(fmt.Sprintf("%s.%s IS NULL", tableName, ColumnDeletedAt))
+	}
+
+	err = db.Table(tableName).Select(sel).Scan(&result).Error
 
 	return result.Count, err
 }
 
-func (r *repository) refreshAssociations(model interface{}, op string) error {
+func (r *repository) refreshAssociations(orm *gorm.DB, model interface{}, op string) error {
 	typeReflection := reflect.TypeOf(model).Elem()
 	valueReflection := reflect.ValueOf(model).Elem()
 
@@ -355,17 +506,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 			continue
 		}
 
-		tags := make(map[string]string)
-		for _, tag := range strings.Split(tag, ",") {
-			parts := strings.Split(tag, ":")
-
-			value := ""
-			if len(parts) == 2 {
-				value = parts[1]
-			}
-
-			tags[parts[0]] = value
-		}
+		tags := readOrmTags(tag)
 
 		if _, ok := tags["assoc_update"]; !ok {
 			continue
@@ -374,7 +515,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		var err error
 
 		values := valueReflection.Field(i)
-		scope := r.orm.NewScope(model)
+		scope := orm.NewScope(model)
 		scopeField, _ := scope.FieldByName(field.Name)
 
 		switch op {
@@ -384,7 +525,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		case Update:
 			switch scopeField.Relationship.Kind {
 			case "many_to_many":
-				err = r.orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
+				err = orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
 
 			default:
 				assocIds := readIdsFromReflectValue(values)
@@ -401,7 +542,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 					qry = qry + fmt.Sprintf(" AND %s NOT IN (%s)", "id", strings.Join(assocIds, ","))
 				}
 
-				err = r.orm.Exec(qry).Error
+				err = orm.Exec(qry).Error
 			}
 
 		case Delete:
@@ -415,10 +556,10 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 				}
 
 				qry := fmt.Sprintf("DELETE FROM %s WHERE %s = %d", tableName, scopeField.Relationship.ForeignDBNames[0], id)
-				err = r.orm.Exec(qry).Error
+				err = orm.Exec(qry).Error
 
 			default:
-				err = r.orm.Model(model).Association(field.Name).Clear().Error
+				err = orm.Model(model).Association(field.Name).Clear().Error
 			}
 
 		default:
@@ -433,6 +574,113 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 	return nil
 }
 
+// save updates the model. Versioned models are only written if the stored version still matches the
+// version of the model, in which case the version is incremented.
+func (r *repository) save(orm *gorm.DB, value ModelBased) error {
+	field, err := r.versionField(value)
+	if err != nil {
+		return err
+	}
+
+	if field == nil {
+		return orm.Save(value).Error
+	}
+
+	version, err := readVersion(field)
+	if err != nil {
+		return err
+	}
+
+	return orm.Transaction(func(tx *gorm.DB) error {
+		var stored int64
+
+		scope := tx.NewScope(value)
+		err := tx.Table(scope.TableName()).
+			Set("gorm:query_option", "FOR UPDATE").
+			Select(scope.Quote(field.DBName)).
+			Where(fmt.Sprintf("%s = ?", scope.Quote(scope.PrimaryKey())), scope.PrimaryKeyValue()).
+			Row().
+			Scan(&stored)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("expected version %d but the model does not exist: %w", version, ErrVersionConflict)
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not read the current version: %w", err)
+		}
+
+		if stored != version {
+			return fmt.Errorf("expected version %d but found %d: %w", version, stored, ErrVersionConflict)
+		}
+
+		if err = field.Set(version + 1); err != nil {
+			return fmt.Errorf("could not increment the version: %w", err)
+		}
+
+		if err = tx.Save(value).Error; err != nil {
+			_ = field.Set(version)
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) initVersion(value ModelBased) error {
+	field, err := r.versionField(value)
+	if err != nil || field == nil {
+		return err
+	}
+
+	version, err := readVersion(field)
+	if err != nil || version != 0 {
+		return err
+	}
+
+	return field.Set(1)
+}
+
+func (r *repository) versionField(value ModelBased) (*gorm.Field, error) {
+	scope := r.orm.NewScope(value)
+
+	if r.metadata.VersionColumn != "" {
+		field, ok := scope.FieldByName(r.metadata.VersionColumn)
+
+		if !ok {
+			return nil, fmt.Errorf("the model %T has no version column %s", value, r.metadata.VersionColumn)
+		}
+
+		return field, nil
+	}
+
+	for _, field := range scope.Fields() {
+		if _, ok := readOrmTags(field.Tag.Get("orm"))["version"]; ok {
+			return field, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ormFor returns the orm to use for the context, which is bound to the transaction of the context, if any.
+func (r *repository) ormFor(ctx context.Context) (*gorm.DB, error) {
+	tx, ok := db.TransactionFromContext(ctx)
+	if !ok {
+		return r.orm, nil
+	}
+
+	orm, err := tx.Value(r.orm, func() (interface{}, error) {
+		return NewOrmForTransaction(r.orm, tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create orm for transaction: %w", err)
+	}
+
+	return orm.(*gorm.DB), nil
+}
+
 func (r *repository) GetModelId() string {
 	return r.metadata.ModelId.String()
 }
@@ -455,6 +703,48 @@ func (r *repository) startSubSpan(ctx context.Context, action string) (context.C
 	return ctx, span
 }
 
+func readOrmTags(tag string) map[string]string {
+	tags := make(map[string]string)
+
+	if tag == "" {
+		return tags
+	}
+
+	for _, tag := range strings.Split(tag, ",") {
+		parts := strings.Split(tag, ":")
+
+		value := ""
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+
+		tags[parts[0]] = value
+	}
+
+	return tags
+}
+
+func readVersion(field *gorm.Field) (int64, error) {
+	value := field.Field
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return 0, nil
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("the version field %s has to be an integer but is %s", field.Name, value.Type())
+}
+
 func readIdsFromReflectValue(values reflect.Value) []string {
 	ids := make([]string, 0)
 
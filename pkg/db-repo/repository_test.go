@@ -9,10 +9,12 @@ import (
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/jonboulle/clockwork"
 	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/db-repo/mocks"
 	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
 	"github.com/justtrackio/gosoline/pkg/mdl"
 	"github.com/justtrackio/gosoline/pkg/tracing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type MyTestModel struct {
@@ -24,6 +26,8 @@ const (
 	manyToMany  = "manyToMany"
 	oneOfMany   = "oneOfMany"
 	hasMany     = "hasMany"
+	versioned   = "versioned"
+	softDeleted = "softDeleted"
 )
 
 var MyTestModelMetadata = db_repo.Metadata{
@@ -97,11 +101,47 @@ type Ones struct {
 	HasManyId *uint
 }
 
+type VersionedModel struct {
+	db_repo.Model
+	Version int `orm:"version"`
+}
+
+var VersionedModelMetadata = db_repo.Metadata{
+	ModelId: mdl.ModelId{
+		Application: "application",
+		Name:        "versioned",
+	},
+	TableName:  "versioned_models",
+	PrimaryKey: "versioned_models.id",
+	Mappings: db_repo.FieldMappings{
+		"versioned.id": db_repo.NewFieldMapping("versioned_models.id"),
+	},
+}
+
+type SoftDeletedModel struct {
+	db_repo.Model
+	db_repo.SoftDeletes
+}
+
+var SoftDeletedModelMetadata = db_repo.Metadata{
+	ModelId: mdl.ModelId{
+		Application: "application",
+		Name:        "softDeleted",
+	},
+	TableName:  "soft_deleted_models",
+	PrimaryKey: "soft_deleted_models.id",
+	Mappings: db_repo.FieldMappings{
+		"softDeleted.id": db_repo.NewFieldMapping("soft_deleted_models.id"),
+	},
+}
+
 var metadatas = map[string]db_repo.Metadata{
 	"myTestModel": MyTestModelMetadata,
 	"manyToMany":  ManyToManyMetadata,
 	"oneOfMany":   OneOfManyMetadata,
 	"hasMany":     HasManyMetadata,
+	"versioned":   VersionedModelMetadata,
+	"softDeleted": SoftDeletedModelMetadata,
 }
 
 type idMatcher struct{}
@@ -391,6 +431,89 @@ func TestRepository_Update(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRepository_CreateVersioned(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now, versioned)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `versioned_models` \\(`id`,`updated_at`,`created_at`,`version`\\) VALUES \\(\\?,\\?,\\?,\\?\\)").WithArgs(id1, &now, &now, 1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "version"}).AddRow(id1, &now, &now, 1)
+	dbc.ExpectQuery("SELECT \\* FROM `versioned_models` WHERE `versioned_models`\\.`id` = \\? AND \\(\\(`versioned_models`\\.`id` = 1\\)\\) ORDER BY `versioned_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model := VersionedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Create(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, model.Version)
+}
+
+func TestRepository_UpdateVersioned(t *testing.T) {
+	dbc, repo := getMocks(t, versioned)
+	now := time.Unix(1549964818, 0)
+
+	result := goSqlMock.NewResult(0, 1)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT `version` FROM `versioned_models` WHERE \\(`id` = \\?\\) FOR UPDATE").WithArgs(id1).WillReturnRows(goSqlMock.NewRows([]string{"version"}).AddRow(2))
+	dbc.ExpectExec("UPDATE `versioned_models` SET `updated_at` = \\?, `version` = \\? WHERE `versioned_models`\\.`id` = \\?").WithArgs(goSqlMock.AnyArg(), 3, id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "version"}).AddRow(id1, &now, &now, 3)
+	dbc.ExpectQuery("SELECT \\* FROM `versioned_models` WHERE `versioned_models`\\.`id` = \\? AND \\(\\(`versioned_models`\\.`id` = 1\\)\\) ORDER BY `versioned_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model := VersionedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		Version: 2,
+	}
+
+	err := repo.Update(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, model.Version)
+}
+
+func TestRepository_UpdateVersionConflict(t *testing.T) {
+	dbc, repo := getMocks(t, versioned)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT `version` FROM `versioned_models` WHERE \\(`id` = \\?\\) FOR UPDATE").WithArgs(id1).WillReturnRows(goSqlMock.NewRows([]string{"version"}).AddRow(3))
+	dbc.ExpectRollback()
+
+	model := VersionedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		Version: 2,
+	}
+
+	err := repo.Update(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.ErrorIs(t, err, db_repo.ErrVersionConflict)
+	assert.Equal(t, 2, model.Version)
+}
+
 func TestRepository_UpdateManyToManyNoRelation(t *testing.T) {
 	dbc, repo := getMocks(t, manyToMany)
 	now := time.Unix(1549964818, 0)
@@ -795,6 +918,231 @@ func TestRepository_DeleteHasManyNoRelation(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRepository_ReadSoftDeleted(t *testing.T) {
+	dbc, repo := getMocks(t, softDeleted)
+	now := time.Unix(1549964818, 0)
+
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND \\(\\(`soft_deleted_models`\\.`id` = 1\\)\\) ORDER BY `soft_deleted_models`\\.`id` ASC LIMIT 1").WillReturnRows(goSqlMock.NewRows([]string{"id"}))
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "deleted_at"}).AddRow(id1, &now, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE \\(`soft_deleted_models`\\.`id` = 1\\) ORDER BY `soft_deleted_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model := SoftDeletedModel{}
+	err := repo.Read(context.Background(), id1, &model)
+	assert.ErrorAs(t, err, &db_repo.RecordNotFoundError{})
+
+	err = repo.Read(db_repo.WithDeleted(context.Background()), id1, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, now, *model.DeletedAt)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_DeleteSoftDeletes(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now, softDeleted)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?, `updated_at` = \\? WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND `soft_deleted_models`\\.`id` = \\?").WithArgs(now, now, id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	model := SoftDeletedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Delete(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, &now, model.DeletedAt)
+}
+
+func TestRepository_DeleteSoftDeletedNotFound(t *testing.T) {
+	dbc, repo := getMocks(t, softDeleted)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?, `updated_at` = \\? WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND `soft_deleted_models`\\.`id` = \\?").WillReturnResult(goSqlMock.NewResult(0, 0))
+	dbc.ExpectCommit()
+
+	model := SoftDeletedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Delete(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.ErrorAs(t, err, &db_repo.RecordNotFoundError{})
+}
+
+func TestRepository_Restore(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now, softDeleted)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?, `updated_at` = \\? WHERE `soft_deleted_models`\\.`id` = \\?").WithArgs(nil, now, id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "deleted_at"}).AddRow(id1, &now, &now, nil)
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND `soft_deleted_models`\\.`id` = \\? AND \\(\\(`soft_deleted_models`\\.`id` = 1\\)\\) ORDER BY `soft_deleted_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model := SoftDeletedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		SoftDeletes: db_repo.SoftDeletes{
+			DeletedAt: &now,
+		},
+	}
+
+	err := repo.Restore(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Nil(t, model.DeletedAt)
+}
+
+func TestRepository_RestoreNotSoftDeletable(t *testing.T) {
+	dbc, repo := getMocks(t, myTestModel)
+
+	model := MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Restore(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.ErrorIs(t, err, db_repo.ErrSoftDeleteNotSupported)
+}
+
+func TestRepository_Purge(t *testing.T) {
+	dbc, repo := getMocks(t, softDeleted)
+	now := time.Unix(1549964818, 0)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("DELETE FROM `soft_deleted_models`  WHERE `soft_deleted_models`\\.`id` = \\?").WithArgs(id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	model := SoftDeletedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		SoftDeletes: db_repo.SoftDeletes{
+			DeletedAt: &now,
+		},
+	}
+
+	err := repo.Purge(context.Background(), &model)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+}
+
+func TestRepository_QuerySoftDeleted(t *testing.T) {
+	dbc, repo := getMocks(t, softDeleted)
+
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND \\(\\(id > \\?\\)\\)").WithArgs(0).WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(id1))
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE \\(id > \\?\\)$").WithArgs(0).WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(id1).AddRow(id42))
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where("id > ?", 0)
+
+	result := make([]*SoftDeletedModel, 0)
+	err := repo.Query(context.Background(), qb, &result)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	result = make([]*SoftDeletedModel, 0)
+	err = repo.Query(db_repo.WithDeleted(context.Background()), qb, &result)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNotifyingRepository_PurgeSoftDeleted(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now, softDeleted)
+
+	notifier := new(mocks.Notifier)
+	notifying := db_repo.NewNotifyingRepository(logMocks.NewLoggerMockedAll(), repo)
+	notifying.AddNotifierAll(notifier)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?, `updated_at` = \\?").WithArgs(now, now, id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+	dbc.ExpectBegin()
+	dbc.ExpectExec("DELETE FROM `soft_deleted_models`").WithArgs(id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	model := &SoftDeletedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	notifier.On("Send", mock.Anything, db_repo.Delete, model).Return(nil).Once()
+
+	err := notifying.Delete(context.Background(), model)
+	assert.NoError(t, err)
+
+	err = notifying.Purge(context.Background(), model)
+	assert.NoError(t, err)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	notifier.AssertExpectations(t)
+}
+
+func TestRepository_CountSoftDeleted(t *testing.T) {
+	dbc, repo := getMocks(t, softDeleted)
+
+	dbc.ExpectQuery("SELECT COUNT\\(DISTINCT soft_deleted_models\\.id\\) AS count FROM `soft_deleted_models` WHERE \\(soft_deleted_models\\.deleted_at IS NULL\\)").WillReturnRows(goSqlMock.NewRows([]string{"count"}).AddRow(2))
+	dbc.ExpectQuery("SELECT COUNT\\(DISTINCT soft_deleted_models\\.id\\) AS count FROM `soft_deleted_models`$").WillReturnRows(goSqlMock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.Count(context.Background(), db_repo.NewQueryBuilder(), &SoftDeletedModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = repo.Count(db_repo.WithDeleted(context.Background()), db_repo.NewQueryBuilder(), &SoftDeletedModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func getMocks(t *testing.T, whichMetadata string) (goSqlMock.Sqlmock, db_repo.Repository) {
 	logger := logMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
@@ -0,0 +1,162 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/jonboulle/clockwork"
+	"github.com/justtrackio/gosoline/pkg/db"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/db-repo/mocks"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getTransactionMocks(t *testing.T, now time.Time, whichMetadata ...string) (goSqlMock.Sqlmock, db.TransactionManager, []db_repo.Repository) {
+	return getTransactionMocksWithConnection(t, now, "default", whichMetadata...)
+}
+
+func getTransactionMocksWithConnection(t *testing.T, now time.Time, connectionName string, whichMetadata ...string) (goSqlMock.Sqlmock, db.TransactionManager, []db_repo.Repository) {
+	logger := logMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	sqlDb, clientMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(sqlDb, db_repo.OrmSettings{
+		Driver:         "mysql",
+		ConnectionName: "default",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	repos := make([]db_repo.Repository, len(whichMetadata))
+	for i, name := range whichMetadata {
+		repos[i] = db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClockAt(now), metadatas[name])
+	}
+
+	manager := db.NewTransactionManagerWithInterfaces(logger, sqlx.NewDb(sqlDb, "mysql"), connectionName)
+
+	return clientMock, manager, repos
+}
+
+func TestRepository_Transaction(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, manager, repos := getTransactionMocks(t, now, myTestModel, versioned)
+
+	notifier := new(mocks.Notifier)
+	notifying := db_repo.NewNotifyingRepository(logMocks.NewLoggerMockedAll(), repos[0])
+	notifying.AddNotifier(db_repo.Create, notifier)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(result)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(id1))
+	dbc.ExpectExec("INSERT INTO `versioned_models`").WithArgs(id42, &now, &now, 1).WillReturnResult(result)
+	dbc.ExpectQuery("SELECT \\* FROM `versioned_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "version"}).AddRow(id42, 1))
+	dbc.ExpectCommit()
+
+	first := &MyTestModel{Model: db_repo.Model{Id: id1}}
+	second := &VersionedModel{Model: db_repo.Model{Id: id42}}
+
+	notifier.On("Send", mock.Anything, db_repo.Create, first).Return(nil).Once()
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := notifying.Create(ctx, first); err != nil {
+			return err
+		}
+
+		notifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+
+		return repos[1].Create(ctx, second)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	notifier.AssertExpectations(t)
+}
+
+func TestRepository_TransactionRollback(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, manager, repos := getTransactionMocks(t, now, myTestModel)
+
+	notifier := new(mocks.Notifier)
+	notifying := db_repo.NewNotifyingRepository(logMocks.NewLoggerMockedAll(), repos[0])
+	notifying.AddNotifier(db_repo.Create, notifier)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(id1))
+	dbc.ExpectRollback()
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := notifying.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}}); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("something went wrong")
+	})
+
+	assert.EqualError(t, err, "something went wrong")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	notifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRepository_TransactionOtherConnection(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, manager, repos := getTransactionMocksWithConnection(t, now, "other", myTestModel)
+
+	dbc.ExpectBegin()
+	dbc.ExpectRollback()
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return repos[0].Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}})
+	})
+
+	assert.ErrorIs(t, err, db.ErrForeignTransaction)
+	assert.Contains(t, err.Error(), `the transaction runs on connection "other", but "default" is used`)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_TransactionDbSettings(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	logger := logMocks.NewLoggerMockedAll()
+	dbSettings := db.Settings{
+		Driver: "mysql",
+		Uri: db.Uri{
+			Host:     "localhost",
+			Port:     3306,
+			User:     "gosoline",
+			Database: "gosoline",
+		},
+	}
+
+	sqlDb, dbc, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(sqlDb, db_repo.OrmSettings{
+		Driver:         dbSettings.Driver,
+		ConnectionName: db.ConnectionName(dbSettings),
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	repo := db_repo.NewWithInterfaces(logger, tracing.NewNoopTracer(), orm, clockwork.NewFakeClockAt(now), metadatas[myTestModel])
+	manager := db.NewTransactionManagerWithInterfaces(logger, sqlx.NewDb(sqlDb, "mysql"), db.ConnectionName(dbSettings))
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(id1))
+	dbc.ExpectCommit()
+
+	err = manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
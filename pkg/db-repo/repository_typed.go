@@ -0,0 +1,155 @@
+package db_repo
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// TypedPageCallback is called for every page read by QueryPages. Returning false stops the iteration.
+type TypedPageCallback[M ModelBased] func(ctx context.Context, models []M) (bool, error)
+
+// TypedRepository is a type safe wrapper around a Repository for the model type M, which has to be a pointer to a model struct.
+//
+//go:generate mockery --name TypedRepository
+type TypedRepository[M ModelBased] interface {
+	Create(ctx context.Context, value M) error
+	Read(ctx context.Context, id *uint) (M, error)
+	Update(ctx context.Context, value M) error
+	Delete(ctx context.Context, value M) error
+	Restore(ctx context.Context, value M) error
+	Purge(ctx context.Context, value M) error
+	Query(ctx context.Context, qb *QueryBuilder) ([]M, error)
+	QueryPages(ctx context.Context, qb *QueryBuilder, pageSize int, callback TypedPageCallback[M]) error
+	Count(ctx context.Context, qb *QueryBuilder) (int, error)
+
+	GetModelId() string
+	GetModelName() string
+	GetMetadata() Metadata
+}
+
+type typedRepository[M ModelBased] struct {
+	repo Repository
+}
+
+func NewTypedRepository[M ModelBased](config cfg.Config, logger log.Logger, s Settings) (TypedRepository[M], error) {
+	repo, err := New(config, logger, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTypedRepositoryWithInterfaces[M](repo), nil
+}
+
+func NewTypedRepositoryWithInterfaces[M ModelBased](repo Repository) TypedRepository[M] {
+	return &typedRepository[M]{
+		repo: repo,
+	}
+}
+
+func (r *typedRepository[M]) Create(ctx context.Context, value M) error {
+	return r.repo.Create(ctx, value)
+}
+
+func (r *typedRepository[M]) Read(ctx context.Context, id *uint) (M, error) {
+	model := newTypedModel[M]()
+
+	if err := r.repo.Read(ctx, id, model); err != nil {
+		var empty M
+		return empty, err
+	}
+
+	return model, nil
+}
+
+func (r *typedRepository[M]) Update(ctx context.Context, value M) error {
+	return r.repo.Update(ctx, value)
+}
+
+func (r *typedRepository[M]) Delete(ctx context.Context, value M) error {
+	return r.repo.Delete(ctx, value)
+}
+
+func (r *typedRepository[M]) Restore(ctx context.Context, value M) error {
+	return r.repo.Restore(ctx, value)
+}
+
+func (r *typedRepository[M]) Purge(ctx context.Context, value M) error {
+	return r.repo.Purge(ctx, value)
+}
+
+func (r *typedRepository[M]) Query(ctx context.Context, qb *QueryBuilder) ([]M, error) {
+	models := make([]M, 0)
+
+	if err := r.repo.Query(ctx, qb, &models); err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// QueryPages reads the result of the query in pages of the given size, starting at the offset of the query builder.
+// The query should define an order to get stable pages.
+func (r *typedRepository[M]) QueryPages(ctx context.Context, qb *QueryBuilder, pageSize int, callback TypedPageCallback[M]) error {
+	offset := 0
+	if qb.page != nil {
+		offset = qb.page.offset
+	}
+
+	for {
+		pageQb := *qb
+		pageQb.page = &page{
+			offset: offset,
+			limit:  pageSize,
+		}
+
+		models, err := r.Query(ctx, &pageQb)
+		if err != nil {
+			return err
+		}
+
+		if len(models) == 0 {
+			return nil
+		}
+
+		cont, err := callback(ctx, models)
+
+		if err != nil || !cont {
+			return err
+		}
+
+		if len(models) < pageSize {
+			return nil
+		}
+
+		offset += pageSize
+	}
+}
+
+func (r *typedRepository[M]) Count(ctx context.Context, qb *QueryBuilder) (int, error) {
+	return r.repo.Count(ctx, qb, newTypedModel[M]())
+}
+
+func (r *typedRepository[M]) GetModelId() string {
+	return r.repo.GetModelId()
+}
+
+func (r *typedRepository[M]) GetModelName() string {
+	return r.repo.GetModelName()
+}
+
+func (r *typedRepository[M]) GetMetadata() Metadata {
+	return r.repo.GetMetadata()
+}
+
+func newTypedModel[M ModelBased]() M {
+	var model M
+
+	if t := reflect.TypeOf(&model).Elem(); t.Kind() == reflect.Ptr {
+		model = reflect.New(t.Elem()).Interface().(M)
+	}
+
+	return model
+}
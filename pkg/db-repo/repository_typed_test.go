@@ -0,0 +1,62 @@
+package db_repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedRepository_Read(t *testing.T) {
+	dbc, repo := getMocks(t, myTestModel)
+	typed := db_repo.NewTypedRepositoryWithInterfaces[*MyTestModel](repo)
+	now := time.Unix(1549964818, 0)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` WHERE \\(`my_test_models`\\.`id` = 1\\) ORDER BY `my_test_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model, err := typed.Read(context.Background(), id1)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, id1, model.Id)
+	assert.Equal(t, &now, model.UpdatedAt)
+}
+
+func TestTypedRepository_QueryPages(t *testing.T) {
+	dbc, repo := getMocks(t, myTestModel)
+	typed := db_repo.NewTypedRepositoryWithInterfaces[*MyTestModel](repo)
+
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` ORDER BY id ASC LIMIT 2 OFFSET 0").
+		WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` ORDER BY id ASC LIMIT 2 OFFSET 2").
+		WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(3))
+
+	qb := db_repo.NewQueryBuilder()
+	qb.OrderBy("id", "ASC")
+
+	pages := make([][]uint, 0)
+	err := typed.QueryPages(context.Background(), qb, 2, func(ctx context.Context, models []*MyTestModel) (bool, error) {
+		ids := make([]uint, 0, len(models))
+		for _, model := range models {
+			ids = append(ids, *model.Id)
+		}
+
+		pages = append(pages, ids)
+
+		return true, nil
+	})
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]uint{{1, 2}, {3}}, pages)
+}
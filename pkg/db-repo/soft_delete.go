@@ -0,0 +1,25 @@
+package db_repo
+
+import (
+	"context"
+)
+
+type withDeletedCtxKey struct{}
+
+// WithDeleted returns a context for which Read, Query and Count include soft deleted models.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withDeletedCtxKey{}, true)
+}
+
+// IsWithDeleted reports whether soft deleted models should be included for the context.
+func IsWithDeleted(ctx context.Context) bool {
+	withDeleted, ok := ctx.Value(withDeletedCtxKey{}).(bool)
+
+	return ok && withDeleted
+}
+
+func isSoftDeletable(value interface{}) bool {
+	_, ok := value.(SoftDeletable)
+
+	return ok
+}
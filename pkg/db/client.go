@@ -39,11 +39,18 @@ type Client interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	Select(dest interface{}, query string, args ...interface{}) error
 	Get(dest interface{}, query string, args ...interface{}) error
+
+	// the context aware methods join the transaction of the context, if any
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
 type ClientSqlx struct {
-	logger log.Logger
-	db     *sqlx.DB
+	logger         log.Logger
+	db             *sqlx.DB
+	connectionName string
 }
 
 func NewClient(config cfg.Config, logger log.Logger, name string) (Client, error) {
@@ -52,7 +59,7 @@ func NewClient(config cfg.Config, logger log.Logger, name string) (Client, error
 		return nil, fmt.Errorf("can not connect to sql database: %w", err)
 	}
 
-	return NewClientWithInterfaces(logger, db), nil
+	return NewClientWithInterfaces(logger, db, ConnectionNameFromConfig(config, name)), nil
 }
 
 func NewClientWithSettings(logger log.Logger, settings Settings) (Client, error) {
@@ -61,13 +68,14 @@ func NewClientWithSettings(logger log.Logger, settings Settings) (Client, error)
 		return nil, fmt.Errorf("can not connect to sql database: %w", err)
 	}
 
-	return NewClientWithInterfaces(logger, db), nil
+	return NewClientWithInterfaces(logger, db, ConnectionName(settings)), nil
 }
 
-func NewClientWithInterfaces(logger log.Logger, db *sqlx.DB) Client {
+func NewClientWithInterfaces(logger log.Logger, db *sqlx.DB, connectionName string) Client {
 	return &ClientSqlx{
-		logger: logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
-		db:     db,
+		logger:         logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
+		db:             db,
+		connectionName: connectionName,
 	}
 }
 
@@ -175,3 +183,61 @@ func (c *ClientSqlx) Get(dest interface{}, query string, args ...interface{}) er
 
 	return c.db.Get(dest, query, args...)
 }
+
+func (c *ClientSqlx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.logger.Debug("> %s %q", query, args)
+
+	executor, err := c.executor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return executor.ExecContext(ctx, query, args...)
+}
+
+func (c *ClientSqlx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.logger.Debug("> %s %q", query, args)
+
+	executor, err := c.executor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return executor.QueryContext(ctx, query, args...)
+}
+
+func (c *ClientSqlx) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	c.logger.Debug("> %s %q", query, args)
+
+	executor, err := c.executor(ctx)
+	if err != nil {
+		return err
+	}
+
+	return sqlx.SelectContext(ctx, executor, dest, query, args...)
+}
+
+func (c *ClientSqlx) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	c.logger.Debug("> %s %q", query, args)
+
+	executor, err := c.executor(ctx)
+	if err != nil {
+		return err
+	}
+
+	return sqlx.GetContext(ctx, executor, dest, query, args...)
+}
+
+// executor returns the transaction of the context, if any. A transaction of another connection can't be joined.
+func (c *ClientSqlx) executor(ctx context.Context) (sqlx.ExtContext, error) {
+	tx, ok := TransactionFromContext(ctx)
+	if !ok {
+		return c.db, nil
+	}
+
+	if err := tx.CheckConnection(c.connectionName); err != nil {
+		return nil, err
+	}
+
+	return tx.Tx(), nil
+}
@@ -126,7 +126,7 @@ func getMocks() (db.Client, goSqlMock.Sqlmock) {
 	loggerMock := logMocks.NewLoggerMockedAll()
 	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
 
-	client := db.NewClientWithInterfaces(loggerMock, sqlxDB)
+	client := db.NewClientWithInterfaces(loggerMock, sqlxDB, "default")
 
 	return client, sqlMock
 }
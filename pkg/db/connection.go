@@ -9,6 +9,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
@@ -59,6 +60,10 @@ func ProvideConnection(config cfg.Config, logger log.Logger, configKey string) (
 	defaultConnections.instances[key] = instance
 	defaultConnections.errors[key] = err
 
+	if err == nil {
+		kernel.RegisterHealthCheck(fmt.Sprintf("db.%s", configKey), kernel.HealthCheckFunc(instance.PingContext))
+	}
+
 	return defaultConnections.instances[key], defaultConnections.errors[key]
 }
 
@@ -106,6 +111,17 @@ func NewConnectionWithInterfaces(settings Settings) (*sqlx.DB, error) {
 	return db, nil
 }
 
+// ConnectionName identifies the database of the settings. Clients, orms and transactions of the same database can
+// share a transaction, even if they don't share the connection pool.
+func ConnectionName(settings Settings) string {
+	return fmt.Sprintf("%s://%s@%s:%d/%s", settings.Driver, settings.Uri.User, settings.Uri.Host, settings.Uri.Port, settings.Uri.Database)
+}
+
+// ConnectionNameFromConfig returns the ConnectionName of the connection configured at db.<name>.
+func ConnectionNameFromConfig(config cfg.Config, name string) string {
+	return ConnectionName(createSettings(config, name))
+}
+
 func createSettings(config cfg.Config, key string) Settings {
 	settings := Settings{
 		Migrations: MigrationSettings{},
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	db "github.com/justtrackio/gosoline/pkg/db"
 	mock "github.com/stretchr/testify/mock"
 
@@ -42,6 +44,32 @@ func (_m *Client) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return r0, r1
 }
 
+// ExecContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 sql.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) sql.Result); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(sql.Result)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: dest, query, args
 func (_m *Client) Get(dest interface{}, query string, args ...interface{}) error {
 	var _ca []interface{}
@@ -59,6 +87,23 @@ func (_m *Client) Get(dest interface{}, query string, args ...interface{}) error
 	return r0
 }
 
+// GetContext provides a mock function with given fields: ctx, dest, query, args
+func (_m *Client) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, dest, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string, ...interface{}) error); ok {
+		r0 = rf(ctx, dest, query, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetResult provides a mock function with given fields: query, args
 func (_m *Client) GetResult(query string, args ...interface{}) (*db.Result, error) {
 	var _ca []interface{}
@@ -158,6 +203,32 @@ func (_m *Client) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	return r0, r1
 }
 
+// QueryContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 *sql.Rows
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Rows); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Rows)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueryRow provides a mock function with given fields: query, args
 func (_m *Client) QueryRow(query string, args ...interface{}) *sql.Row {
 	var _ca []interface{}
@@ -219,3 +290,20 @@ func (_m *Client) Select(dest interface{}, query string, args ...interface{}) er
 
 	return r0
 }
+
+// SelectContext provides a mock function with given fields: ctx, dest, query, args
+func (_m *Client) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, dest, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string, ...interface{}) error); ok {
+		r0 = rf(ctx, dest, query, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
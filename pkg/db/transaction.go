@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+type transactionContextKey struct{}
+
+var ErrForeignTransaction = fmt.Errorf("the transaction runs on another connection")
+
+// TransactionManager groups writes of several repositories and clients into a single unit of work.
+//
+//go:generate mockery --name TransactionManager
+type TransactionManager interface {
+	// WithTransaction runs fn inside a transaction which is carried by the context passed to fn.
+	// The transaction is committed if fn returns nil and rolled back otherwise. If the context
+	// already carries a transaction, fn runs inside a savepoint of it instead.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactionManager struct {
+	logger         log.Logger
+	db             *sqlx.DB
+	connectionName string
+}
+
+func NewTransactionManager(config cfg.Config, logger log.Logger, name string) (TransactionManager, error) {
+	db, err := ProvideConnection(config, logger, name)
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to sql database: %w", err)
+	}
+
+	return NewTransactionManagerWithInterfaces(logger, db, ConnectionNameFromConfig(config, name)), nil
+}
+
+func NewTransactionManagerWithInterfaces(logger log.Logger, db *sqlx.DB, connectionName string) TransactionManager {
+	return &transactionManager{
+		logger:         logger,
+		db:             db,
+		connectionName: connectionName,
+	}
+}
+
+func (m *transactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if tx, ok := TransactionFromContext(ctx); ok {
+		if err = tx.CheckConnection(m.connectionName); err != nil {
+			return err
+		}
+
+		return tx.withSavepoint(ctx, fn)
+	}
+
+	sqlTx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can not begin transaction: %w", err)
+	}
+
+	tx := &Transaction{
+		tx:             sqlTx,
+		connectionName: m.connectionName,
+		values:         make(map[interface{}]interface{}),
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, transactionContextKey{}, tx)); err != nil {
+		if rollbackErr := sqlTx.Rollback(); rollbackErr != nil {
+			m.logger.WithContext(ctx).Error("can not roll back transaction: %w", rollbackErr)
+		}
+
+		return err
+	}
+
+	if err = sqlTx.Commit(); err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	for _, callback := range tx.onCommit {
+		callback(ctx)
+	}
+
+	return nil
+}
+
+// Transaction is the transaction carried by the context inside of TransactionManager.WithTransaction.
+type Transaction struct {
+	lck            sync.Mutex
+	tx             *sqlx.Tx
+	connectionName string
+	savepoints     int
+	onCommit       []func(ctx context.Context)
+	values         map[interface{}]interface{}
+}
+
+// TransactionFromContext returns the active transaction of the context, if any.
+func TransactionFromContext(ctx context.Context) (*Transaction, bool) {
+	tx, ok := ctx.Value(transactionContextKey{}).(*Transaction)
+
+	return tx, ok
+}
+
+// OnCommit runs the callback after the transaction of the context has been committed. Callbacks of a transaction
+// or savepoint which is rolled back are discarded. Without an active transaction, the callback runs immediately.
+func OnCommit(ctx context.Context, callback func(ctx context.Context)) {
+	tx, ok := TransactionFromContext(ctx)
+
+	if !ok {
+		callback(ctx)
+		return
+	}
+
+	tx.lck.Lock()
+	defer tx.lck.Unlock()
+
+	tx.onCommit = append(tx.onCommit, callback)
+}
+
+func (t *Transaction) Tx() *sqlx.Tx {
+	return t.tx
+}
+
+// ConnectionName returns the name of the connection the transaction runs on.
+func (t *Transaction) ConnectionName() string {
+	return t.connectionName
+}
+
+// CheckConnection returns an error wrapping ErrForeignTransaction if the transaction runs on another connection.
+func (t *Transaction) CheckConnection(connectionName string) error {
+	if t.connectionName != connectionName {
+		return fmt.Errorf("%w: the transaction runs on connection %q, but %q is used", ErrForeignTransaction, t.connectionName, connectionName)
+	}
+
+	return nil
+}
+
+// Value returns the value stored for the key, creating it with the factory on first access.
+// It allows users of the transaction to cache handles bound to it.
+func (t *Transaction) Value(key interface{}, factory func() (interface{}, error)) (interface{}, error) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	if value, ok := t.values[key]; ok {
+		return value, nil
+	}
+
+	value, err := factory()
+	if err != nil {
+		return nil, err
+	}
+
+	t.values[key] = value
+
+	return value, nil
+}
+
+func (t *Transaction) withSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.lck.Lock()
+	t.savepoints++
+	name := fmt.Sprintf("sp_%d", t.savepoints)
+	callbacks := len(t.onCommit)
+	t.lck.Unlock()
+
+	if _, err := t.tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", name)); err != nil {
+		return fmt.Errorf("can not create savepoint %s: %w", name, err)
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rollbackErr := t.tx.ExecContext(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", name)); rollbackErr != nil {
+			return fmt.Errorf("can not roll back to savepoint %s: %s: %w", name, rollbackErr, err)
+		}
+
+		t.lck.Lock()
+		t.onCommit = t.onCommit[:callbacks]
+		t.lck.Unlock()
+
+		return err
+	}
+
+	if _, err := t.tx.ExecContext(ctx, fmt.Sprintf("RELEASE SAVEPOINT %s", name)); err != nil {
+		return fmt.Errorf("can not release savepoint %s: %w", name, err)
+	}
+
+	return nil
+}
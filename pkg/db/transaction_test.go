@@ -0,0 +1,165 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/db"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTransactionMocks() (db.TransactionManager, db.Client, goSqlMock.Sqlmock) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	logger := logMocks.NewLoggerMockedAll()
+	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
+
+	return db.NewTransactionManagerWithInterfaces(logger, sqlxDB, "default"), db.NewClientWithInterfaces(logger, sqlxDB, "default"), sqlMock
+}
+
+func TestTransactionManager_Commit(t *testing.T) {
+	manager, client, sqlMock := getTransactionMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO foo").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	committed := false
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO foo VALUES (1)"); err != nil {
+			return err
+		}
+
+		db.OnCommit(ctx, func(ctx context.Context) {
+			committed = true
+		})
+
+		assert.False(t, committed)
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_Rollback(t *testing.T) {
+	manager, client, sqlMock := getTransactionMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO foo").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectRollback()
+
+	committed := false
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO foo VALUES (1)"); err != nil {
+			return err
+		}
+
+		db.OnCommit(ctx, func(ctx context.Context) {
+			committed = true
+		})
+
+		return fmt.Errorf("something went wrong")
+	})
+
+	assert.EqualError(t, err, "something went wrong")
+	assert.False(t, committed)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_Savepoints(t *testing.T) {
+	manager, client, sqlMock := getTransactionMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("INSERT INTO foo").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("INSERT INTO bar").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectCommit()
+
+	notified := make([]string, 0)
+	insert := func(table string, fail bool) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			if _, err := client.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (1)", table)); err != nil {
+				return err
+			}
+
+			db.OnCommit(ctx, func(ctx context.Context) {
+				notified = append(notified, table)
+			})
+
+			if fail {
+				return fmt.Errorf("can not insert into %s", table)
+			}
+
+			return nil
+		}
+	}
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		err := manager.WithTransaction(ctx, insert("foo", true))
+		assert.EqualError(t, err, "can not insert into foo")
+
+		return manager.WithTransaction(ctx, insert("bar", false))
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bar"}, notified)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_Panic(t *testing.T) {
+	manager, _, sqlMock := getTransactionMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_ForeignClient(t *testing.T) {
+	manager, _, sqlMock := getTransactionMocks()
+	client := db.NewClientWithInterfaces(logMocks.NewLoggerMockedAll(), sqlx.NewDb(nil, "sqlmock"), "other")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := client.ExecContext(ctx, "INSERT INTO foo VALUES (1)")
+
+		return err
+	})
+
+	assert.ErrorIs(t, err, db.ErrForeignTransaction)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_ForeignManager(t *testing.T) {
+	manager, _, sqlMock := getTransactionMocks()
+	other := db.NewTransactionManagerWithInterfaces(logMocks.NewLoggerMockedAll(), sqlx.NewDb(nil, "sqlmock"), "other")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return other.WithTransaction(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	assert.ErrorIs(t, err, db.ErrForeignTransaction)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
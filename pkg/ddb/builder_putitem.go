@@ -53,10 +53,20 @@ func (b *putItemBuilder) Build(item interface{}) (*dynamodb.PutItemInput, error)
 	}
 
 	var err error
+	var version int64
 	expr := expression.Expression{}
+	condition := b.condition
 
-	if b.condition != nil {
-		expr, err = expression.NewBuilder().WithCondition(*b.condition).Build()
+	if b.metadata.Version.Enabled {
+		if version, err = b.metadata.Version.read(item); err != nil {
+			return nil, fmt.Errorf("could not read version: %w", err)
+		}
+
+		condition = b.metadata.Version.withCondition(version, condition)
+	}
+
+	if condition != nil {
+		expr, err = expression.NewBuilder().WithCondition(*condition).Build()
 	}
 
 	if err != nil {
@@ -76,6 +86,10 @@ func (b *putItemBuilder) Build(item interface{}) (*dynamodb.PutItemInput, error)
 		return nil, err
 	}
 
+	if b.metadata.Version.Enabled {
+		marshalled[b.metadata.Version.Field] = b.metadata.Version.attributeValue(version + 1)
+	}
+
 	input.Item = marshalled
 
 	return input, err
@@ -6,6 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/hashicorp/go-multierror"
 	"github.com/justtrackio/gosoline/pkg/clock"
 )
@@ -27,6 +28,7 @@ type ScanBuilder interface {
 	WithPageSize(size int) ScanBuilder
 	WithSegment(segment int, total int) ScanBuilder
 	WithConsistentRead(consistentRead bool) ScanBuilder
+	WithExclusiveStartKey(key map[string]types.AttributeValue) ScanBuilder
 	Build(result interface{}) (*ScanOperation, error)
 }
 
@@ -42,6 +44,7 @@ type scanBuilder struct {
 	segment        *int32
 	segmentTotal   *int32
 	consistentRead *bool
+	startKey       map[string]types.AttributeValue
 }
 
 func NewScanBuilder(metadata *Metadata, clock clock.Clock) ScanBuilder {
@@ -109,6 +112,13 @@ func (b *scanBuilder) WithConsistentRead(consistentRead bool) ScanBuilder {
 	return b
 }
 
+// WithExclusiveStartKey continues a scan after the key, which is the ScanResult.LastEvaluatedKey of a previous scan.
+func (b *scanBuilder) WithExclusiveStartKey(key map[string]types.AttributeValue) ScanBuilder {
+	b.startKey = key
+
+	return b
+}
+
 func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 	targetType := resolveTargetType(b.selected, b.projection, result)
 	expr, err := b.buildExpression(targetType)
@@ -121,6 +131,7 @@ func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 		TableName:                 aws.String(b.metadata.TableName),
 		IndexName:                 b.indexName,
 		ConsistentRead:            b.consistentRead,
+		ExclusiveStartKey:         b.startKey,
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		FilterExpression:          expr.Filter(),
@@ -35,6 +35,8 @@ type updateItemBuilder struct {
 	condition     *expression.ConditionBuilder
 	updateBuilder *expression.UpdateBuilder
 	returnType    types.ReturnValue
+
+	versionIncremented bool
 }
 
 func NewUpdateItemBuilder(metadata *Metadata) UpdateItemBuilder {
@@ -145,7 +147,19 @@ func (b *updateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 		return nil, fmt.Errorf("value for returning the updated item is not a pointer")
 	}
 
-	expr, err := b.buildExpression()
+	condition := b.condition
+
+	if b.metadata.Version.Enabled {
+		var version int64
+		if version, err = b.metadata.Version.read(item); err != nil {
+			return nil, fmt.Errorf("could not read version: %w", err)
+		}
+
+		b.incrementVersion()
+		condition = b.metadata.Version.withCondition(version, condition)
+	}
+
+	expr, err := b.buildExpression(b.updateBuilder, condition)
 	if err != nil {
 		return nil, err
 	}
@@ -163,24 +177,37 @@ func (b *updateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 	return input, err
 }
 
-func (b *updateItemBuilder) buildExpression() (expression.Expression, error) {
-	if b.updateBuilder == nil && b.condition == nil {
+func (b *updateItemBuilder) buildExpression(updateBuilder *expression.UpdateBuilder, condition *expression.ConditionBuilder) (expression.Expression, error) {
+	if updateBuilder == nil && condition == nil {
 		return expression.Expression{}, nil
 	}
 
 	exprBuilder := expression.NewBuilder()
 
-	if b.updateBuilder != nil {
-		exprBuilder = exprBuilder.WithUpdate(*b.updateBuilder)
+	if updateBuilder != nil {
+		exprBuilder = exprBuilder.WithUpdate(*updateBuilder)
 	}
 
-	if b.condition != nil {
-		exprBuilder = exprBuilder.WithCondition(*b.condition)
+	if condition != nil {
+		exprBuilder = exprBuilder.WithCondition(*condition)
 	}
 
 	return exprBuilder.Build()
 }
 
+func (b *updateItemBuilder) incrementVersion() {
+	if b.versionIncremented {
+		return
+	}
+
+	b.versionIncremented = true
+	name := expression.Name(b.metadata.Version.Field)
+
+	b.update(func() expression.UpdateBuilder {
+		return b.updateBuilder.Set(name, expression.Plus(name.IfNotExists(expression.Value(0)), expression.Value(1)))
+	})
+}
+
 func (b *updateItemBuilder) update(callback func() expression.UpdateBuilder) *updateItemBuilder {
 	if b.updateBuilder == nil {
 		ub := expression.UpdateBuilder{}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// ErrVersionConflict is returned by writes on a versioned table if the item has been modified since it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
 func IsTableNotFoundError(err error) bool {
 	return errors.As(err, &TableNotFoundError{})
 }
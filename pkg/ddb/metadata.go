@@ -26,6 +26,7 @@ type Metadata struct {
 	TableName  string
 	Attributes Attributes
 	TimeToLive metadataTtl
+	Version    metadataVersion
 	Main       metadataMain
 	Local      metaLocal
 	Global     metaGlobal
@@ -28,6 +28,11 @@ func (f *metadataFactory) GetMetadata(settings *Settings) (*Metadata, error) {
 		return nil, fmt.Errorf("can not get ttl for table %s: %w", tableName, err)
 	}
 
+	version, err := f.getVersion(settings)
+	if err != nil {
+		return nil, fmt.Errorf("can not get version attribute for table %s: %w", tableName, err)
+	}
+
 	mainFields, err := f.getFields(settings.Main.Model, tagKey, tagKey)
 	if err != nil {
 		return nil, fmt.Errorf("can not get fields for main table %s: %w", tableName, err)
@@ -47,6 +52,7 @@ func (f *metadataFactory) GetMetadata(settings *Settings) (*Metadata, error) {
 		TableName:  tableName,
 		Attributes: attributes,
 		TimeToLive: ttl,
+		Version:    version,
 		Main: metadataMain{
 			metadataFields: mainFields,
 			metadataCapacity: metadataCapacity{
@@ -217,6 +223,72 @@ func (f *metadataFactory) getTimeToLive(attributes Attributes) (metadataTtl, err
 	return data, nil
 }
 
+func (f *metadataFactory) getVersion(settings *Settings) (metadataVersion, error) {
+	data := metadataVersion{
+		Enabled: false,
+	}
+
+	attribute, err := f.getVersionAttribute(settings)
+	if err != nil {
+		return data, err
+	}
+
+	if attribute == nil {
+		return data, nil
+	}
+
+	field, _ := findBaseType(settings.Main.Model).FieldByName(attribute.FieldName)
+
+	if !isVersionType(field.Type) {
+		return data, fmt.Errorf("the version attribute %s has to be an integer but is %s", attribute.AttributeName, field.Type)
+	}
+
+	data.Enabled = true
+	data.Field = attribute.AttributeName
+	data.FieldName = attribute.FieldName
+
+	return data, nil
+}
+
+func (f *metadataFactory) getVersionAttribute(settings *Settings) (*Attribute, error) {
+	if settings.Main.VersionAttribute == "" {
+		attributes, err := ReadAttributes(settings.Main.Model)
+		if err != nil {
+			return nil, err
+		}
+
+		return attributes.GetByTag("version", "enabled")
+	}
+
+	t := findBaseType(settings.Main.Model)
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't read version attribute from model as it is not a struct but instead is %T", settings.Main.Model)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		attributeName, err := getAttributeName(field)
+		if err != nil {
+			return nil, err
+		}
+
+		if attributeName == nil || *attributeName != settings.Main.VersionAttribute {
+			continue
+		}
+
+		return &Attribute{
+			FieldName:     field.Name,
+			AttributeName: *attributeName,
+			Tags:          make(map[string]string),
+			Type:          types.ScalarAttributeTypeN,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("there is no field for the version attribute %s", settings.Main.VersionAttribute)
+}
+
 func ReadAttributes(model interface{}) (Attributes, error) {
 	t := findBaseType(model)
 	attributes := make(Attributes)
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/mdl"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,3 +96,37 @@ func TestMetadataReadFields(t *testing.T) {
 	_, err = ddb.MetadataReadFields(TestModelEmptyJSON{})
 	assert.Error(t, err)
 }
+
+func TestMetadataFactory_Version(t *testing.T) {
+	type versioned struct {
+		Id      int    `json:"id" ddb:"key=hash"`
+		Version uint64 `json:"version" ddb:"version=enabled"`
+		Name    string `json:"name"`
+	}
+
+	settings := &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "versioned",
+		},
+		Main: ddb.MainSettings{
+			Model: versioned{},
+		},
+	}
+
+	metadata, err := ddb.NewMetadataFactory().GetMetadata(settings)
+	assert.NoError(t, err)
+	assert.True(t, metadata.Version.Enabled)
+	assert.Equal(t, "version", metadata.Version.Field)
+
+	settings.Main.VersionAttribute = "name"
+	_, err = ddb.NewMetadataFactory().GetMetadata(settings)
+	assert.EqualError(t, err, "can not get version attribute for table applike-test-gosoline-ddb-versioned: the version attribute name has to be an integer but is string")
+
+	settings.Main.VersionAttribute = "missing"
+	_, err = ddb.NewMetadataFactory().GetMetadata(settings)
+	assert.EqualError(t, err, "can not get version attribute for table applike-test-gosoline-ddb-versioned: there is no field for the version attribute missing")
+}
@@ -6,6 +6,8 @@ import (
 	expression "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	ddb "github.com/justtrackio/gosoline/pkg/ddb"
 	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 // ScanBuilder is an autogenerated mock type for the ScanBuilder type
@@ -68,6 +70,22 @@ func (_m *ScanBuilder) WithConsistentRead(consistentRead bool) ddb.ScanBuilder {
 	return r0
 }
 
+// WithExclusiveStartKey provides a mock function with given fields: key
+func (_m *ScanBuilder) WithExclusiveStartKey(key map[string]types.AttributeValue) ddb.ScanBuilder {
+	ret := _m.Called(key)
+
+	var r0 ddb.ScanBuilder
+	if rf, ok := ret.Get(0).(func(map[string]types.AttributeValue) ddb.ScanBuilder); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithFilter provides a mock function with given fields: filter
 func (_m *ScanBuilder) WithFilter(filter expression.ConditionBuilder) ddb.ScanBuilder {
 	ret := _m.Called(filter)
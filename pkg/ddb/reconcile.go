@@ -0,0 +1,588 @@
+package ddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	ReconcileActionCreateTable       = "createTable"
+	ReconcileActionCreateGlobalIndex = "createGlobalIndex"
+	ReconcileActionDeleteGlobalIndex = "deleteGlobalIndex"
+	ReconcileActionUpdateBillingMode = "updateBillingMode"
+	ReconcileActionUpdateThroughput  = "updateThroughput"
+	ReconcileActionUpdateTimeToLive  = "updateTimeToLive"
+)
+
+var (
+	ErrReconcileDestructive = fmt.Errorf("the table requires destructive changes")
+	ErrReconcileUnsupported = fmt.Errorf("the table requires changes which can't be applied to an existing table")
+)
+
+type ReconcileOptions struct {
+	// DryRun only logs the changes required to reconcile the table
+	DryRun bool
+	// AllowDestructive allows changes losing data, e.g. deleting a global secondary index
+	AllowDestructive bool
+	// UpdateThroughput applies differences in the provisioned throughput of the table and its indices. It is disabled
+	// by default, as the throughput of autoscaled tables differs from the configured one.
+	UpdateThroughput bool
+}
+
+type ReconcileChange struct {
+	Action      string
+	Index       string
+	Description string
+	Destructive bool
+
+	apply func(ctx context.Context) error
+}
+
+// ReconcilePlan contains the changes to apply to an existing table to match the metadata derived from its settings.
+// Differences which can't be applied to an existing table, like a different key schema or different local secondary
+// indices, are listed as unsupported.
+type ReconcilePlan struct {
+	TableName   string
+	Changes     []*ReconcileChange
+	Unsupported []string
+}
+
+func (p *ReconcilePlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+func (p *ReconcilePlan) IsDestructive() bool {
+	for _, change := range p.Changes {
+		if change.Destructive {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *ReconcilePlan) String() string {
+	if !p.HasChanges() && len(p.Unsupported) == 0 {
+		return fmt.Sprintf("table %s is up to date", p.TableName)
+	}
+
+	lines := []string{fmt.Sprintf("table %s:", p.TableName)}
+
+	for _, change := range p.Changes {
+		prefix := ""
+		if change.Destructive {
+			prefix = "[destructive] "
+		}
+
+		lines = append(lines, fmt.Sprintf("  - %s%s", prefix, change.Description))
+	}
+
+	for _, unsupported := range p.Unsupported {
+		lines = append(lines, fmt.Sprintf("  - [unsupported] %s", unsupported))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Reconcile diffs the metadata derived from the settings against the existing table and applies the changes. Global
+// secondary indices are deleted first and created last, the service waits for every change (including the backfill
+// of new indices) to complete before applying the next one.
+func (s *Service) Reconcile(ctx context.Context, settings *Settings, options ReconcileOptions) (*ReconcilePlan, error) {
+	plan, err := s.PlanReconcile(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.UpdateThroughput {
+		plan.Changes = withoutThroughputChanges(plan.Changes)
+	}
+
+	if options.DryRun {
+		s.logger.Info("dry run of the reconciliation of ddb %s", plan.String())
+		return plan, nil
+	}
+
+	if len(plan.Unsupported) > 0 {
+		return plan, fmt.Errorf("can not reconcile ddb table %s: %w: %s", plan.TableName, ErrReconcileUnsupported, strings.Join(plan.Unsupported, ", "))
+	}
+
+	if plan.IsDestructive() && !options.AllowDestructive {
+		return plan, fmt.Errorf("can not reconcile ddb table %s: %w", plan.TableName, ErrReconcileDestructive)
+	}
+
+	if !plan.HasChanges() {
+		s.logger.Info("ddb %s", plan.String())
+		return plan, nil
+	}
+
+	s.logger.Info("reconciling ddb %s", plan.String())
+
+	for _, change := range plan.Changes {
+		if err = change.apply(ctx); err != nil {
+			return plan, fmt.Errorf("can not %s of ddb table %s: %w", change.Description, plan.TableName, err)
+		}
+
+		s.logger.Info("applied change to ddb table %s: %s", plan.TableName, change.Description)
+	}
+
+	return plan, nil
+}
+
+func withoutThroughputChanges(changes []*ReconcileChange) []*ReconcileChange {
+	kept := make([]*ReconcileChange, 0, len(changes))
+
+	for _, change := range changes {
+		if change.Action != ReconcileActionUpdateThroughput {
+			kept = append(kept, change)
+		}
+	}
+
+	return kept
+}
+
+func (s *Service) PlanReconcile(ctx context.Context, settings *Settings) (*ReconcilePlan, error) {
+	metadata, err := s.metadataFactory.GetMetadata(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ReconcilePlan{
+		TableName: metadata.TableName,
+	}
+
+	out, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(metadata.TableName),
+	})
+
+	var errResourceNotFoundException *types.ResourceNotFoundException
+	if errors.As(err, &errResourceNotFoundException) {
+		plan.Changes = append(plan.Changes, &ReconcileChange{
+			Action:      ReconcileActionCreateTable,
+			Description: "create table",
+			apply: func(ctx context.Context) error {
+				return s.createTable(ctx, settings, metadata)
+			},
+		})
+
+		return plan, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not describe ddb table %s: %w", metadata.TableName, err)
+	}
+
+	if err = s.planKeySchema(plan, metadata, out.Table); err != nil {
+		return nil, err
+	}
+
+	if err = s.planLocalIndices(plan, metadata, out.Table); err != nil {
+		return nil, err
+	}
+
+	if err = s.planGlobalIndices(plan, settings, metadata, out.Table); err != nil {
+		return nil, err
+	}
+
+	if err = s.planTimeToLive(ctx, plan, metadata); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *Service) planKeySchema(plan *ReconcilePlan, metadata *Metadata, table *types.TableDescription) error {
+	keySchema, err := s.getKeySchema(metadata.Main)
+	if err != nil {
+		return fmt.Errorf("can not create main key schema for table %s: %w", metadata.TableName, err)
+	}
+
+	if !isKeySchemaEqual(keySchema, table.KeySchema) {
+		plan.Unsupported = append(plan.Unsupported, "the key schema of the table differs")
+	}
+
+	return nil
+}
+
+func (s *Service) planLocalIndices(plan *ReconcilePlan, metadata *Metadata, table *types.TableDescription) error {
+	desired, err := s.getLocalSecondaryIndices(metadata)
+	if err != nil {
+		return fmt.Errorf("can not create definitions for local secondary indices on table %s: %w", metadata.TableName, err)
+	}
+
+	existing := make(map[string]types.LocalSecondaryIndexDescription, len(table.LocalSecondaryIndexes))
+	for _, index := range table.LocalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = index
+	}
+
+	for _, index := range desired {
+		name := aws.ToString(index.IndexName)
+		current, ok := existing[name]
+		delete(existing, name)
+
+		if !ok {
+			plan.Unsupported = append(plan.Unsupported, fmt.Sprintf("the local secondary index %s is missing", name))
+			continue
+		}
+
+		if !isKeySchemaEqual(index.KeySchema, current.KeySchema) || !isProjectionEqual(index.Projection, current.Projection) {
+			plan.Unsupported = append(plan.Unsupported, fmt.Sprintf("the local secondary index %s differs", name))
+		}
+	}
+
+	unknown := make([]string, 0, len(existing))
+	for name := range existing {
+		unknown = append(unknown, name)
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		plan.Unsupported = append(plan.Unsupported, fmt.Sprintf("the local secondary index %s is unknown", name))
+	}
+
+	return nil
+}
+
+// planGlobalIndices adds the deletions of global secondary indices, the capacity changes of the table and the kept
+// indices and the creations of global secondary indices in the order they have to be applied. An index with a
+// different key schema or projection is deleted and created again.
+func (s *Service) planGlobalIndices(plan *ReconcilePlan, settings *Settings, metadata *Metadata, table *types.TableDescription) error {
+	desired, err := s.getGlobalSecondaryIndices(settings, metadata)
+	if err != nil {
+		return fmt.Errorf("can not create definitions for global secondary indices on table %s: %w", metadata.TableName, err)
+	}
+
+	existing := make(map[string]types.GlobalSecondaryIndexDescription, len(table.GlobalSecondaryIndexes))
+	for _, index := range table.GlobalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = index
+	}
+
+	kept := make([]types.GlobalSecondaryIndex, 0)
+	deletions := make([]string, 0)
+	creations := make([]types.GlobalSecondaryIndex, 0)
+
+	for _, index := range desired {
+		name := aws.ToString(index.IndexName)
+		current, ok := existing[name]
+		delete(existing, name)
+
+		switch {
+		case !ok:
+			creations = append(creations, index)
+		case !isKeySchemaEqual(index.KeySchema, current.KeySchema) || !isProjectionEqual(index.Projection, current.Projection):
+			deletions = append(deletions, name)
+			creations = append(creations, index)
+		default:
+			kept = append(kept, index)
+		}
+	}
+
+	for name := range existing {
+		deletions = append(deletions, name)
+	}
+
+	sort.Strings(deletions)
+
+	for _, name := range deletions {
+		plan.Changes = append(plan.Changes, s.newGlobalIndexChange(metadata, ReconcileActionDeleteGlobalIndex, name, types.GlobalSecondaryIndexUpdate{
+			Delete: &types.DeleteGlobalSecondaryIndexAction{
+				IndexName: aws.String(name),
+			},
+		}))
+	}
+
+	s.planCapacity(plan, settings, metadata, table, kept)
+
+	for _, index := range creations {
+		plan.Changes = append(plan.Changes, s.newGlobalIndexChange(metadata, ReconcileActionCreateGlobalIndex, aws.ToString(index.IndexName), types.GlobalSecondaryIndexUpdate{
+			Create: &types.CreateGlobalSecondaryIndexAction{
+				IndexName:             index.IndexName,
+				KeySchema:             index.KeySchema,
+				Projection:            index.Projection,
+				ProvisionedThroughput: index.ProvisionedThroughput,
+			},
+		}))
+	}
+
+	return nil
+}
+
+func (s *Service) newGlobalIndexChange(metadata *Metadata, action string, name string, update types.GlobalSecondaryIndexUpdate) *ReconcileChange {
+	input := &dynamodb.UpdateTableInput{
+		TableName:                   aws.String(metadata.TableName),
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{update},
+	}
+
+	change := &ReconcileChange{
+		Action: action,
+		Index:  name,
+		apply: func(ctx context.Context) error {
+			return s.updateTable(ctx, input)
+		},
+	}
+
+	if action == ReconcileActionDeleteGlobalIndex {
+		change.Description = fmt.Sprintf("delete global secondary index %s", name)
+		change.Destructive = true
+	} else {
+		change.Description = fmt.Sprintf("create global secondary index %s", name)
+		input.AttributeDefinitions = s.getAttributeDefinitions(metadata)
+	}
+
+	return change
+}
+
+func (s *Service) planCapacity(plan *ReconcilePlan, settings *Settings, metadata *Metadata, table *types.TableDescription, kept []types.GlobalSecondaryIndex) {
+	currentBillingMode := types.BillingModeProvisioned
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		currentBillingMode = table.BillingModeSummary.BillingMode
+	}
+
+	desiredBillingMode := getBillingMode(settings)
+
+	if currentBillingMode != desiredBillingMode {
+		input := &dynamodb.UpdateTableInput{
+			TableName:             aws.String(metadata.TableName),
+			BillingMode:           desiredBillingMode,
+			ProvisionedThroughput: s.getProvisionedThroughput(settings, metadata.Main.metadataCapacity),
+		}
+
+		// switching to provisioned capacity requires the capacity of every index
+		for _, index := range kept {
+			if index.ProvisionedThroughput == nil {
+				continue
+			}
+
+			input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+				Update: &types.UpdateGlobalSecondaryIndexAction{
+					IndexName:             index.IndexName,
+					ProvisionedThroughput: index.ProvisionedThroughput,
+				},
+			})
+		}
+
+		plan.Changes = append(plan.Changes, &ReconcileChange{
+			Action:      ReconcileActionUpdateBillingMode,
+			Description: fmt.Sprintf("update billing mode from %s to %s", currentBillingMode, desiredBillingMode),
+			apply: func(ctx context.Context) error {
+				return s.updateTable(ctx, input)
+			},
+		})
+
+		return
+	}
+
+	if desiredBillingMode == types.BillingModePayPerRequest {
+		return
+	}
+
+	desired := s.getProvisionedThroughput(settings, metadata.Main.metadataCapacity)
+	if !isThroughputEqual(desired, table.ProvisionedThroughput) {
+		plan.Changes = append(plan.Changes, s.newThroughputChange(metadata, "", desired))
+	}
+
+	existing := make(map[string]types.GlobalSecondaryIndexDescription, len(table.GlobalSecondaryIndexes))
+	for _, index := range table.GlobalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = index
+	}
+
+	for _, index := range kept {
+		if !isThroughputEqual(index.ProvisionedThroughput, existing[aws.ToString(index.IndexName)].ProvisionedThroughput) {
+			plan.Changes = append(plan.Changes, s.newThroughputChange(metadata, aws.ToString(index.IndexName), index.ProvisionedThroughput))
+		}
+	}
+}
+
+func (s *Service) newThroughputChange(metadata *Metadata, indexName string, throughput *types.ProvisionedThroughput) *ReconcileChange {
+	input := &dynamodb.UpdateTableInput{
+		TableName: aws.String(metadata.TableName),
+	}
+
+	description := fmt.Sprintf("update throughput of the table to %d read and %d write capacity units", aws.ToInt64(throughput.ReadCapacityUnits), aws.ToInt64(throughput.WriteCapacityUnits))
+
+	if indexName == "" {
+		input.ProvisionedThroughput = throughput
+	} else {
+		description = fmt.Sprintf("update throughput of global secondary index %s to %d read and %d write capacity units", indexName, aws.ToInt64(throughput.ReadCapacityUnits), aws.ToInt64(throughput.WriteCapacityUnits))
+		input.GlobalSecondaryIndexUpdates = []types.GlobalSecondaryIndexUpdate{
+			{
+				Update: &types.UpdateGlobalSecondaryIndexAction{
+					IndexName:             aws.String(indexName),
+					ProvisionedThroughput: throughput,
+				},
+			},
+		}
+	}
+
+	return &ReconcileChange{
+		Action:      ReconcileActionUpdateThroughput,
+		Index:       indexName,
+		Description: description,
+		apply: func(ctx context.Context) error {
+			return s.updateTable(ctx, input)
+		},
+	}
+}
+
+func (s *Service) planTimeToLive(ctx context.Context, plan *ReconcilePlan, metadata *Metadata) error {
+	desired, err := s.getTimeToLiveSpecification(metadata)
+	if err != nil {
+		return fmt.Errorf("can not create ttl specification for table %s: %w", metadata.TableName, err)
+	}
+
+	out, err := s.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(metadata.TableName),
+	})
+	if err != nil {
+		return fmt.Errorf("can not describe the ttl of ddb table %s: %w", metadata.TableName, err)
+	}
+
+	var currentAttribute string
+	if description := out.TimeToLiveDescription; description != nil {
+		switch description.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			currentAttribute = aws.ToString(description.AttributeName)
+		}
+	}
+
+	var desiredAttribute string
+	if desired != nil {
+		desiredAttribute = aws.ToString(desired.AttributeName)
+	}
+
+	if currentAttribute == desiredAttribute {
+		return nil
+	}
+
+	specifications := make([]*types.TimeToLiveSpecification, 0, 2)
+	descriptions := make([]string, 0, 2)
+
+	if currentAttribute != "" {
+		specifications = append(specifications, &types.TimeToLiveSpecification{
+			Enabled:       aws.Bool(false),
+			AttributeName: aws.String(currentAttribute),
+		})
+		descriptions = append(descriptions, fmt.Sprintf("disable ttl on attribute %s", currentAttribute))
+	}
+
+	if desired != nil {
+		specifications = append(specifications, desired)
+		descriptions = append(descriptions, fmt.Sprintf("enable ttl on attribute %s", desiredAttribute))
+	}
+
+	plan.Changes = append(plan.Changes, &ReconcileChange{
+		Action:      ReconcileActionUpdateTimeToLive,
+		Description: strings.Join(descriptions, " and "),
+		apply: func(ctx context.Context) error {
+			for _, specification := range specifications {
+				_, err := s.client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+					TableName:               aws.String(metadata.TableName),
+					TimeToLiveSpecification: specification,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	})
+
+	return nil
+}
+
+func (s *Service) updateTable(ctx context.Context, input *dynamodb.UpdateTableInput) error {
+	if _, err := s.client.UpdateTable(ctx, input); err != nil {
+		return err
+	}
+
+	return s.waitForTableGettingActive(ctx, aws.ToString(input.TableName))
+}
+
+// waitForTableGettingActive waits until the table and all of its global secondary indices are active and the
+// backfill of new indices is done, which can take a long time for big tables.
+func (s *Service) waitForTableGettingActive(ctx context.Context, name string) error {
+	for {
+		out, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(name),
+		})
+		if err != nil {
+			return fmt.Errorf("can not describe ddb table %s: %w", name, err)
+		}
+
+		if isTableActive(out.Table) {
+			return nil
+		}
+
+		s.logger.Info("waiting for ddb table %s and its indices getting active", name)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func isTableActive(table *types.TableDescription) bool {
+	if table.TableStatus != types.TableStatusActive {
+		return false
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive || aws.ToBool(index.Backfilling) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isKeySchemaEqual(a []types.KeySchemaElement, b []types.KeySchemaElement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if aws.ToString(a[i].AttributeName) != aws.ToString(b[i].AttributeName) || a[i].KeyType != b[i].KeyType {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isProjectionEqual(a *types.Projection, b *types.Projection) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.ProjectionType != b.ProjectionType {
+		return false
+	}
+
+	attributesA := append([]string{}, a.NonKeyAttributes...)
+	attributesB := append([]string{}, b.NonKeyAttributes...)
+
+	sort.Strings(attributesA)
+	sort.Strings(attributesB)
+
+	return strings.Join(attributesA, ",") == strings.Join(attributesB, ",")
+}
+
+func isThroughputEqual(desired *types.ProvisionedThroughput, current *types.ProvisionedThroughputDescription) bool {
+	if desired == nil || current == nil {
+		return desired == nil
+	}
+
+	return aws.ToInt64(desired.ReadCapacityUnits) == aws.ToInt64(current.ReadCapacityUnits) &&
+		aws.ToInt64(desired.WriteCapacityUnits) == aws.ToInt64(current.WriteCapacityUnits)
+}
@@ -0,0 +1,126 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoDynamodb "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodb"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// ModuleName is the name to use when adding the module to the kernel, so other modules can depend on it:
+//
+// k.Add(reconcile.ModuleName, reconcile.NewModule())
+// k.Add("consumer", consumerFactory, kernel.ModuleDependsOn(reconcile.ModuleName))
+const ModuleName = "ddb-reconcile"
+
+type Settings struct {
+	DryRun           bool `cfg:"dry_run" default:"false"`
+	AllowDestructive bool `cfg:"allow_destructive" default:"false"`
+	UpdateThroughput bool `cfg:"update_throughput" default:"false"`
+}
+
+//go:generate mockery --name Reconciler
+type Reconciler interface {
+	Reconcile(ctx context.Context, settings *ddb.Settings, options ddb.ReconcileOptions) (*ddb.ReconcilePlan, error)
+}
+
+type ReconcilerFactory func(ctx context.Context, clientName string) (Reconciler, error)
+
+// Module reconciles the tables of all ddb repositories created by the application and of the additionally given
+// settings at startup. It becomes ready after all tables have been reconciled.
+type Module struct {
+	kernel.BackgroundModule
+	kernel.EssentialStage
+
+	logger            log.Logger
+	registry          *ddb.SettingsRegistry
+	tables            []*ddb.Settings
+	reconcilerFactory ReconcilerFactory
+	settings          *Settings
+	ready             chan struct{}
+}
+
+func NewModule(tables ...*ddb.Settings) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		logger = logger.WithChannel("ddb-reconcile")
+
+		settings := &Settings{}
+		config.UnmarshalKey("ddb.reconcile", settings)
+
+		registry, err := ddb.ProvideSettingsRegistry(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can not access the settings registry: %w", err)
+		}
+
+		for _, table := range tables {
+			table.ModelId.PadFromConfig(config)
+		}
+
+		reconcilerFactory := func(ctx context.Context, clientName string) (Reconciler, error) {
+			client, err := gosoDynamodb.ProvideClient(ctx, config, logger, clientName)
+			if err != nil {
+				return nil, fmt.Errorf("can not create dynamodb client: %w", err)
+			}
+
+			return ddb.NewServiceWithInterfaces(logger, client), nil
+		}
+
+		return NewModuleWithInterfaces(logger, registry, tables, reconcilerFactory, settings), nil
+	}
+}
+
+func NewModuleWithInterfaces(logger log.Logger, registry *ddb.SettingsRegistry, tables []*ddb.Settings, reconcilerFactory ReconcilerFactory, settings *Settings) *Module {
+	return &Module{
+		logger:            logger,
+		registry:          registry,
+		tables:            tables,
+		reconcilerFactory: reconcilerFactory,
+		settings:          settings,
+		ready:             make(chan struct{}),
+	}
+}
+
+func (m *Module) Ready() <-chan struct{} {
+	return m.ready
+}
+
+func (m *Module) Run(ctx context.Context) error {
+	options := ddb.ReconcileOptions{
+		DryRun:           m.settings.DryRun,
+		AllowDestructive: m.settings.AllowDestructive,
+		UpdateThroughput: m.settings.UpdateThroughput,
+	}
+
+	// the repositories register their settings while the modules are created, so they are complete at this point
+	tables := make([]*ddb.Settings, 0, len(m.tables))
+	seen := make(map[string]bool)
+
+	for _, table := range append(m.registry.All(), m.tables...) {
+		tableName := ddb.TableName(table)
+
+		if !seen[tableName] {
+			seen[tableName] = true
+			tables = append(tables, table)
+		}
+	}
+
+	for _, table := range tables {
+		reconciler, err := m.reconcilerFactory(ctx, table.ClientName)
+		if err != nil {
+			return err
+		}
+
+		if _, err = reconciler.Reconcile(ctx, table, options); err != nil {
+			return err
+		}
+	}
+
+	m.logger.Info("reconciled %d ddb tables", len(tables))
+	close(m.ready)
+
+	return nil
+}
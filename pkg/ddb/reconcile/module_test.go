@@ -0,0 +1,83 @@
+package reconcile_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/ddb/reconcile"
+	"github.com/justtrackio/gosoline/pkg/ddb/reconcile/mocks"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTable(name string) *ddb.Settings {
+	return &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "justtrack",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "reconcile",
+			Name:        name,
+		},
+	}
+}
+
+func newModule(t *testing.T, reconciler *mocks.Reconciler, settings *reconcile.Settings, tables ...*ddb.Settings) *reconcile.Module {
+	registry, err := ddb.ProvideSettingsRegistry(appctx.WithContainer(context.Background()))
+	assert.NoError(t, err)
+
+	registry.Add(newTable("items"))
+	registry.Add(newTable("orders"))
+
+	factory := func(ctx context.Context, clientName string) (reconcile.Reconciler, error) {
+		return reconciler, nil
+	}
+
+	return reconcile.NewModuleWithInterfaces(logMocks.NewLoggerMockedAll(), registry, tables, factory, settings)
+}
+
+func TestModule_Run(t *testing.T) {
+	ctx := context.Background()
+	options := ddb.ReconcileOptions{DryRun: true}
+
+	reconciler := new(mocks.Reconciler)
+	for _, name := range []string{"items", "orders", "users"} {
+		reconciler.On("Reconcile", ctx, newTable(name), options).Return(&ddb.ReconcilePlan{}, nil).Once()
+	}
+
+	module := newModule(t, reconciler, &reconcile.Settings{DryRun: true}, newTable("users"), newTable("items"))
+
+	assert.NoError(t, module.Run(ctx))
+
+	select {
+	case <-module.Ready():
+	default:
+		assert.Fail(t, "the module should be ready after all tables have been reconciled")
+	}
+
+	reconciler.AssertExpectations(t)
+}
+
+func TestModule_Run_Failure(t *testing.T) {
+	ctx := context.Background()
+
+	reconciler := new(mocks.Reconciler)
+	reconciler.On("Reconcile", ctx, newTable("items"), mock.Anything).Return(nil, fmt.Errorf("destructive")).Once()
+
+	module := newModule(t, reconciler, &reconcile.Settings{})
+
+	assert.EqualError(t, module.Run(ctx), "destructive")
+
+	select {
+	case <-module.Ready():
+		assert.Fail(t, "the module should not be ready after a failed reconciliation")
+	default:
+	}
+
+	reconciler.AssertExpectations(t)
+}
@@ -0,0 +1,251 @@
+package ddb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamodbMocks "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodb/mocks"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const reconcileTableName = "applike-test-gosoline-ddb-reconcileModel"
+
+type reconcileModel struct {
+	Id   int    `json:"id" ddb:"key=hash"`
+	Name string `json:"name" ddb:"global=hash"`
+	Ttl  int    `json:"ttl" ddb:"ttl=enabled"`
+}
+
+func reconcileSettings() *ddb.Settings {
+	return &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "reconcileModel",
+		},
+		Main: ddb.MainSettings{
+			Model:              reconcileModel{},
+			ReadCapacityUnits:  2,
+			WriteCapacityUnits: 3,
+		},
+		Global: []ddb.GlobalSettings{
+			{
+				Model:              reconcileModel{},
+				ReadCapacityUnits:  4,
+				WriteCapacityUnits: 5,
+			},
+		},
+	}
+}
+
+func setupReconcileClient(table *types.TableDescription) *dynamodbMocks.Client {
+	client := new(dynamodbMocks.Client)
+
+	client.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+		TableName: aws.String(reconcileTableName),
+	}).Return(&dynamodb.DescribeTableOutput{
+		Table: table,
+	}, nil)
+
+	client.On("DescribeTimeToLive", mock.Anything, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(reconcileTableName),
+	}).Return(&dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &types.TimeToLiveDescription{
+			TimeToLiveStatus: types.TimeToLiveStatusDisabled,
+		},
+	}, nil)
+
+	return client
+}
+
+func outdatedTable() *types.TableDescription {
+	return &types.TableDescription{
+		TableName:   aws.String(reconcileTableName),
+		TableStatus: types.TableStatusActive,
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		ProvisionedThroughput: &types.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(1),
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+			{
+				IndexName:   aws.String("global-obsolete"),
+				IndexStatus: types.IndexStatusActive,
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String("obsolete"),
+						KeyType:       types.KeyTypeHash,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeKeysOnly,
+				},
+			},
+		},
+	}
+}
+
+func TestService_PlanReconcile(t *testing.T) {
+	client := setupReconcileClient(outdatedTable())
+	svc := ddb.NewServiceWithInterfaces(logMocks.NewLoggerMockedAll(), client)
+
+	plan, err := svc.PlanReconcile(context.Background(), reconcileSettings())
+	assert.NoError(t, err)
+
+	actions := make([]string, len(plan.Changes))
+	for i, change := range plan.Changes {
+		actions[i] = change.Action
+	}
+
+	assert.Equal(t, []string{
+		ddb.ReconcileActionDeleteGlobalIndex,
+		ddb.ReconcileActionUpdateThroughput,
+		ddb.ReconcileActionCreateGlobalIndex,
+		ddb.ReconcileActionUpdateTimeToLive,
+	}, actions)
+	assert.True(t, plan.IsDestructive())
+	assert.Empty(t, plan.Unsupported)
+
+	expected := "table applike-test-gosoline-ddb-reconcileModel:\n" +
+		"  - [destructive] delete global secondary index global-obsolete\n" +
+		"  - update throughput of the table to 2 read and 3 write capacity units\n" +
+		"  - create global secondary index global-name\n" +
+		"  - enable ttl on attribute ttl"
+	assert.Equal(t, expected, plan.String())
+}
+
+func TestService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	client := setupReconcileClient(outdatedTable())
+
+	client.On("UpdateTable", ctx, &dynamodb.UpdateTableInput{
+		TableName: aws.String(reconcileTableName),
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Delete: &types.DeleteGlobalSecondaryIndexAction{
+					IndexName: aws.String("global-obsolete"),
+				},
+			},
+		},
+	}).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+
+	client.On("UpdateTable", ctx, &dynamodb.UpdateTableInput{
+		TableName: aws.String(reconcileTableName),
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(2),
+			WriteCapacityUnits: aws.Int64(3),
+		},
+	}).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+
+	client.On("UpdateTable", ctx, &dynamodb.UpdateTableInput{
+		TableName: aws.String(reconcileTableName),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: types.ScalarAttributeTypeN,
+			},
+			{
+				AttributeName: aws.String("name"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName: aws.String("global-name"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("name"),
+							KeyType:       types.KeyTypeHash,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(4),
+						WriteCapacityUnits: aws.Int64(5),
+					},
+				},
+			},
+		},
+	}).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+
+	client.On("UpdateTimeToLive", ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(reconcileTableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String("ttl"),
+			Enabled:       aws.Bool(true),
+		},
+	}).Return(&dynamodb.UpdateTimeToLiveOutput{}, nil).Once()
+
+	svc := ddb.NewServiceWithInterfaces(logMocks.NewLoggerMockedAll(), client)
+
+	_, err := svc.Reconcile(ctx, reconcileSettings(), ddb.ReconcileOptions{
+		AllowDestructive: true,
+		UpdateThroughput: true,
+	})
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestService_Reconcile_Guards(t *testing.T) {
+	ctx := context.Background()
+	client := setupReconcileClient(outdatedTable())
+	svc := ddb.NewServiceWithInterfaces(logMocks.NewLoggerMockedAll(), client)
+
+	plan, err := svc.Reconcile(ctx, reconcileSettings(), ddb.ReconcileOptions{})
+	assert.ErrorIs(t, err, ddb.ErrReconcileDestructive)
+	assert.Len(t, plan.Changes, 3)
+
+	plan, err = svc.Reconcile(ctx, reconcileSettings(), ddb.ReconcileOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Changes, 3)
+
+	plan, err = svc.Reconcile(ctx, reconcileSettings(), ddb.ReconcileOptions{DryRun: true, UpdateThroughput: true})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Changes, 4)
+
+	client.AssertNotCalled(t, "UpdateTable", mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "UpdateTimeToLive", mock.Anything, mock.Anything)
+}
+
+func TestService_Reconcile_Unsupported(t *testing.T) {
+	table := outdatedTable()
+	table.KeySchema = append(table.KeySchema, types.KeySchemaElement{
+		AttributeName: aws.String("rev"),
+		KeyType:       types.KeyTypeRange,
+	})
+	table.LocalSecondaryIndexes = []types.LocalSecondaryIndexDescription{
+		{
+			IndexName: aws.String("local-rev"),
+		},
+	}
+
+	client := setupReconcileClient(table)
+	svc := ddb.NewServiceWithInterfaces(logMocks.NewLoggerMockedAll(), client)
+
+	plan, err := svc.Reconcile(context.Background(), reconcileSettings(), ddb.ReconcileOptions{AllowDestructive: true})
+	assert.ErrorIs(t, err, ddb.ErrReconcileUnsupported)
+	assert.Equal(t, []string{
+		"the key schema of the table differs",
+		"the local secondary index local-rev is unknown",
+	}, plan.Unsupported)
+
+	client.AssertNotCalled(t, "UpdateTable", mock.Anything, mock.Anything)
+}
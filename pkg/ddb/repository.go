@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/cenkalti/backoff"
@@ -109,6 +111,13 @@ func NewRepository(ctx context.Context, config cfg.Config, logger log.Logger, se
 		return nil, fmt.Errorf("can not access the appctx metadata: %w", err)
 	}
 
+	var registry *SettingsRegistry
+	if registry, err = ProvideSettingsRegistry(ctx); err != nil {
+		return nil, fmt.Errorf("can not access the settings registry: %w", err)
+	}
+
+	registry.Add(settings)
+
 	return NewWithInterfaces(logger, tracer, client, settings)
 }
 
@@ -467,6 +476,11 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 		return nil, fmt.Errorf("could not build input and expr for PutItem operation on table %s: %w", r.metadata.TableName, err)
 	}
 
+	version, err := r.readVersion(item)
+	if err != nil {
+		return nil, fmt.Errorf("could not read version for PutItem operation on table %s: %w", r.metadata.TableName, err)
+	}
+
 	result := newPutItemResult()
 
 	out, err := r.client.PutItem(ctx, input)
@@ -487,6 +501,10 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 		return nil, fmt.Errorf("could not execute PutItem operation for table %s: %w", r.metadata.TableName, err)
 	}
 
+	if err = r.checkVersion(ctx, r.keyFromItem(input.Item), version, result.ConditionalCheckFailed, item); err != nil {
+		return nil, fmt.Errorf("could not execute PutItem operation for table %s: %w", r.metadata.TableName, err)
+	}
+
 	if out == nil {
 		return result, nil
 	}
@@ -580,6 +598,11 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 		return nil, fmt.Errorf("could not build input for UpdateItem operation on table %s: %w", r.metadata.TableName, err)
 	}
 
+	version, err := r.readVersion(item)
+	if err != nil {
+		return nil, fmt.Errorf("could not read version for UpdateItem operation on table %s: %w", r.metadata.TableName, err)
+	}
+
 	result := newUpdateItemResult()
 	out, err := r.client.UpdateItem(ctx, input)
 
@@ -599,6 +622,10 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 		return nil, fmt.Errorf("could not execute UpdateItem operation for table %s: %w", r.metadata.TableName, err)
 	}
 
+	if err = r.checkVersion(ctx, input.Key, version, result.ConditionalCheckFailed, item); err != nil {
+		return nil, fmt.Errorf("could not execute UpdateItem operation for table %s: %w", r.metadata.TableName, err)
+	}
+
 	if out == nil {
 		return result, nil
 	}
@@ -670,6 +697,7 @@ func (r *repository) doScan(ctx context.Context, op *ScanOperation) (*readResult
 	op.result.ItemCount += out.Count
 	op.result.ScannedCount += out.ScannedCount
 	op.result.ConsumedCapacity.add(out.ConsumedCapacity)
+	op.result.LastEvaluatedKey = out.LastEvaluatedKey
 
 	nextPageSize := op.iterator.advance(&out.Count)
 
@@ -711,6 +739,63 @@ func (r *repository) UpdateItemBuilder() UpdateItemBuilder {
 	return NewUpdateItemBuilder(r.metadata)
 }
 
+func (r *repository) readVersion(item interface{}) (int64, error) {
+	if !r.metadata.Version.Enabled {
+		return 0, nil
+	}
+
+	return r.metadata.Version.read(item)
+}
+
+// checkVersion reports a failed write on a versioned table as ErrVersionConflict if the stored version
+// differs from the expected one. Otherwise, the new version is written back to the item.
+func (r *repository) checkVersion(ctx context.Context, key map[string]types.AttributeValue, version int64, conditionalCheckFailed bool, item interface{}) error {
+	if !r.metadata.Version.Enabled {
+		return nil
+	}
+
+	if !conditionalCheckFailed {
+		return r.metadata.Version.write(item, version+1)
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:                aws.String(r.metadata.TableName),
+		Key:                      key,
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#version"),
+		ExpressionAttributeNames: map[string]string{"#version": r.metadata.Version.Field},
+	}
+
+	out, err := r.client.GetItem(ctx, input)
+	if err != nil {
+		return fmt.Errorf("could not read the current version: %w", err)
+	}
+
+	stored := int64(0)
+
+	if attribute, ok := out.Item[r.metadata.Version.Field].(*types.AttributeValueMemberN); ok {
+		if stored, err = strconv.ParseInt(attribute.Value, 10, 64); err != nil {
+			return fmt.Errorf("could not parse the current version: %w", err)
+		}
+	}
+
+	if stored != version {
+		return fmt.Errorf("expected version %d but found %d: %w", version, stored, ErrVersionConflict)
+	}
+
+	return nil
+}
+
+func (r *repository) keyFromItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	key := make(map[string]types.AttributeValue)
+
+	for _, field := range r.metadata.Main.GetKeyFields() {
+		key[field] = item[field]
+	}
+
+	return key
+}
+
 func (r *repository) readAll(items interface{}, read func() (*readResult, error)) error {
 	unmarshaller, err := NewUnmarshallerFromPtrSlice(items)
 	if err != nil {
@@ -0,0 +1,166 @@
+package ddb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoDynamodb "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodb"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+// TypedResultCallback is called for every page read by QueryPages and ScanPages. Returning false stops the iteration.
+type TypedResultCallback[T any] func(ctx context.Context, items []T, progress Progress) (bool, error)
+
+// TypedRepository is a type safe wrapper around a Repository for the model type T.
+//
+//go:generate mockery --name TypedRepository
+type TypedRepository[T any] interface {
+	GetModelId() mdl.ModelId
+
+	BatchDeleteItems(ctx context.Context, items []T) (*OperationResult, error)
+	BatchGetItems(ctx context.Context, qb BatchGetItemsBuilder) ([]T, *OperationResult, error)
+	BatchPutItems(ctx context.Context, items []T) (*OperationResult, error)
+	DeleteItem(ctx context.Context, db DeleteItemBuilder, item *T) (*DeleteItemResult, error)
+	GetItem(ctx context.Context, qb GetItemBuilder, item *T) (*GetItemResult, error)
+	PutItem(ctx context.Context, qb PutItemBuilder, item *T) (*PutItemResult, error)
+	Query(ctx context.Context, qb QueryBuilder) ([]T, *QueryResult, error)
+	QueryPages(ctx context.Context, qb QueryBuilder, callback TypedResultCallback[T]) (*QueryResult, error)
+	Scan(ctx context.Context, sb ScanBuilder) ([]T, *ScanResult, error)
+	ScanPages(ctx context.Context, sb ScanBuilder, callback TypedResultCallback[T]) (*ScanResult, error)
+	UpdateItem(ctx context.Context, ub UpdateItemBuilder, item *T) (*UpdateItemResult, error)
+
+	BatchGetItemsBuilder() BatchGetItemsBuilder
+	DeleteItemBuilder() DeleteItemBuilder
+	GetItemBuilder() GetItemBuilder
+	QueryBuilder() QueryBuilder
+	PutItemBuilder() PutItemBuilder
+	ScanBuilder() ScanBuilder
+	UpdateItemBuilder() UpdateItemBuilder
+}
+
+type typedRepository[T any] struct {
+	repo Repository
+}
+
+// NewTypedRepository creates a Repository for the model type T. If no main model is configured in the settings, T is used.
+func NewTypedRepository[T any](ctx context.Context, config cfg.Config, logger log.Logger, settings *Settings, optFns ...gosoDynamodb.ClientOption) (TypedRepository[T], error) {
+	if settings.Main.Model == nil {
+		var model T
+		settings.Main.Model = model
+	}
+
+	repo, err := NewRepository(ctx, config, logger, settings, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTypedRepositoryWithInterfaces[T](repo), nil
+}
+
+func NewTypedRepositoryWithInterfaces[T any](repo Repository) TypedRepository[T] {
+	return &typedRepository[T]{
+		repo: repo,
+	}
+}
+
+func (r *typedRepository[T]) GetModelId() mdl.ModelId {
+	return r.repo.GetModelId()
+}
+
+func (r *typedRepository[T]) BatchDeleteItems(ctx context.Context, items []T) (*OperationResult, error) {
+	return r.repo.BatchDeleteItems(ctx, items)
+}
+
+func (r *typedRepository[T]) BatchGetItems(ctx context.Context, qb BatchGetItemsBuilder) ([]T, *OperationResult, error) {
+	items := make([]T, 0)
+	result, err := r.repo.BatchGetItems(ctx, qb, &items)
+
+	return items, result, err
+}
+
+func (r *typedRepository[T]) BatchPutItems(ctx context.Context, items []T) (*OperationResult, error) {
+	return r.repo.BatchPutItems(ctx, items)
+}
+
+func (r *typedRepository[T]) DeleteItem(ctx context.Context, db DeleteItemBuilder, item *T) (*DeleteItemResult, error) {
+	return r.repo.DeleteItem(ctx, db, item)
+}
+
+func (r *typedRepository[T]) GetItem(ctx context.Context, qb GetItemBuilder, item *T) (*GetItemResult, error) {
+	return r.repo.GetItem(ctx, qb, item)
+}
+
+func (r *typedRepository[T]) PutItem(ctx context.Context, qb PutItemBuilder, item *T) (*PutItemResult, error) {
+	return r.repo.PutItem(ctx, qb, item)
+}
+
+func (r *typedRepository[T]) Query(ctx context.Context, qb QueryBuilder) ([]T, *QueryResult, error) {
+	items := make([]T, 0)
+	result, err := r.repo.Query(ctx, qb, &items)
+
+	return items, result, err
+}
+
+func (r *typedRepository[T]) QueryPages(ctx context.Context, qb QueryBuilder, callback TypedResultCallback[T]) (*QueryResult, error) {
+	return r.repo.Query(ctx, qb, typedResultCallback(callback))
+}
+
+func (r *typedRepository[T]) Scan(ctx context.Context, sb ScanBuilder) ([]T, *ScanResult, error) {
+	items := make([]T, 0)
+	result, err := r.repo.Scan(ctx, sb, &items)
+
+	return items, result, err
+}
+
+func (r *typedRepository[T]) ScanPages(ctx context.Context, sb ScanBuilder, callback TypedResultCallback[T]) (*ScanResult, error) {
+	return r.repo.Scan(ctx, sb, typedResultCallback(callback))
+}
+
+func (r *typedRepository[T]) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item *T) (*UpdateItemResult, error) {
+	return r.repo.UpdateItem(ctx, ub, item)
+}
+
+func (r *typedRepository[T]) BatchGetItemsBuilder() BatchGetItemsBuilder {
+	return r.repo.BatchGetItemsBuilder()
+}
+
+func (r *typedRepository[T]) DeleteItemBuilder() DeleteItemBuilder {
+	return r.repo.DeleteItemBuilder()
+}
+
+func (r *typedRepository[T]) GetItemBuilder() GetItemBuilder {
+	return r.repo.GetItemBuilder()
+}
+
+func (r *typedRepository[T]) QueryBuilder() QueryBuilder {
+	return r.repo.QueryBuilder()
+}
+
+func (r *typedRepository[T]) PutItemBuilder() PutItemBuilder {
+	return r.repo.PutItemBuilder()
+}
+
+func (r *typedRepository[T]) ScanBuilder() ScanBuilder {
+	return r.repo.ScanBuilder()
+}
+
+func (r *typedRepository[T]) UpdateItemBuilder() UpdateItemBuilder {
+	return r.repo.UpdateItemBuilder()
+}
+
+// typedResultCallback adapts a TypedResultCallback to the ResultCallback used by the untyped repository.
+// The callback has to be a plain func type for the repository to recognize it.
+func typedResultCallback[T any](callback TypedResultCallback[T]) func(ctx context.Context, items interface{}, progress Progress) (bool, error) {
+	return func(ctx context.Context, items interface{}, progress Progress) (bool, error) {
+		typed, ok := items.([]T)
+
+		if !ok {
+			var model T
+			return false, fmt.Errorf("expected items of type %T but got %T, projections into other types are not supported", model, items)
+		}
+
+		return callback(ctx, typed, progress)
+	}
+}
@@ -0,0 +1,108 @@
+package ddb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamodbMocks "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodb/mocks"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTypedRepository(t *testing.T) (*dynamodbMocks.Client, ddb.TypedRepository[model]) {
+	client := new(dynamodbMocks.Client)
+
+	repo, err := ddb.NewWithInterfaces(logMocks.NewLoggerMockedAll(), tracing.NewNoopTracer(), client, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "myModel",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+	})
+	assert.NoError(t, err)
+
+	return client, ddb.NewTypedRepositoryWithInterfaces[model](repo)
+}
+
+func typedQueryInput(startKey map[string]types.AttributeValue) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		ExpressionAttributeNames: map[string]string{
+			"#0": "id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "1"},
+		},
+		KeyConditionExpression: aws.String("#0 = :0"),
+		TableName:              aws.String("applike-test-gosoline-ddb-myModel"),
+		ExclusiveStartKey:      startKey,
+	}
+}
+
+func typedQueryItem(rev string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":  &types.AttributeValueMemberN{Value: "1"},
+		"rev": &types.AttributeValueMemberS{Value: rev},
+		"foo": &types.AttributeValueMemberS{Value: "bar"},
+	}
+}
+
+func TestTypedRepository_Query(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getTypedRepository(t)
+
+	client.On("Query", ctx, typedQueryInput(nil)).Return(&dynamodb.QueryOutput{
+		Count: 2,
+		Items: []map[string]types.AttributeValue{typedQueryItem("0"), typedQueryItem("1")},
+	}, nil).Once()
+
+	items, result, err := repo.Query(ctx, repo.QueryBuilder().WithHash(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), result.ItemCount)
+	assert.Equal(t, []model{
+		{Id: 1, Rev: "0", Foo: "bar"},
+		{Id: 1, Rev: "1", Foo: "bar"},
+	}, items)
+	client.AssertExpectations(t)
+}
+
+func TestTypedRepository_QueryPages(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getTypedRepository(t)
+
+	client.On("Query", ctx, typedQueryInput(nil)).Return(&dynamodb.QueryOutput{
+		Count:            1,
+		Items:            []map[string]types.AttributeValue{typedQueryItem("0")},
+		LastEvaluatedKey: typedQueryItem("0"),
+	}, nil).Once()
+
+	client.On("Query", ctx, typedQueryInput(typedQueryItem("0"))).Return(&dynamodb.QueryOutput{
+		Count: 1,
+		Items: []map[string]types.AttributeValue{typedQueryItem("1")},
+	}, nil).Once()
+
+	pages := make([][]model, 0)
+	_, err := repo.QueryPages(ctx, repo.QueryBuilder().WithHash(1), func(ctx context.Context, items []model, progress ddb.Progress) (bool, error) {
+		pages = append(pages, items)
+
+		return true, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]model{
+		{{Id: 1, Rev: "0", Foo: "bar"}},
+		{{Id: 1, Rev: "1", Foo: "bar"}},
+	}, pages)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,218 @@
+package ddb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamodbMocks "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodb/mocks"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+const versionedTableName = "applike-test-gosoline-ddb-versionedModel"
+
+type versionedModel struct {
+	Id      int    `json:"id" ddb:"key=hash"`
+	Foo     string `json:"foo"`
+	Version int    `json:"version" ddb:"version=enabled"`
+}
+
+type settingsVersionedModel struct {
+	Id  int    `json:"id" ddb:"key=hash"`
+	Foo string `json:"foo"`
+	Rev *int64 `json:"rev"`
+}
+
+func getVersionedRepository(t *testing.T, model interface{}, versionAttribute string) (*dynamodbMocks.Client, ddb.Repository) {
+	client := new(dynamodbMocks.Client)
+
+	repo, err := ddb.NewWithInterfaces(logMocks.NewLoggerMockedAll(), tracing.NewNoopTracer(), client, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "versionedModel",
+		},
+		Main: ddb.MainSettings{
+			Model:            model,
+			VersionAttribute: versionAttribute,
+		},
+	})
+	assert.NoError(t, err)
+
+	return client, repo
+}
+
+func TestRepository_PutItemVersioned(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getVersionedRepository(t, versionedModel{}, "")
+
+	client.On("PutItem", ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(versionedTableName),
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "2"},
+		},
+		Item: map[string]types.AttributeValue{
+			"id":      &types.AttributeValueMemberN{Value: "1"},
+			"foo":     &types.AttributeValueMemberS{Value: "bar"},
+			"version": &types.AttributeValueMemberN{Value: "3"},
+		},
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	item := &versionedModel{
+		Id:      1,
+		Foo:     "bar",
+		Version: 2,
+	}
+
+	res, err := repo.PutItem(ctx, nil, item)
+
+	assert.NoError(t, err)
+	assert.False(t, res.ConditionalCheckFailed)
+	assert.Equal(t, 3, item.Version)
+	client.AssertExpectations(t)
+}
+
+func TestRepository_PutItemVersionedNew(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getVersionedRepository(t, versionedModel{}, "")
+
+	client.On("PutItem", ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(versionedTableName),
+		ConditionExpression: aws.String("attribute_not_exists (#0)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+		},
+		Item: map[string]types.AttributeValue{
+			"id":      &types.AttributeValueMemberN{Value: "1"},
+			"foo":     &types.AttributeValueMemberS{Value: "bar"},
+			"version": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	item := &versionedModel{
+		Id:  1,
+		Foo: "bar",
+	}
+
+	_, err := repo.PutItem(ctx, nil, item)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item.Version)
+	client.AssertExpectations(t)
+}
+
+func TestRepository_PutItemVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getVersionedRepository(t, versionedModel{}, "")
+
+	client.On("PutItem", ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(versionedTableName),
+		ConditionExpression: aws.String("(#0 = :0) AND (#1 = :1)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+			"#1": "foo",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "2"},
+			":1": &types.AttributeValueMemberS{Value: "baz"},
+		},
+		Item: map[string]types.AttributeValue{
+			"id":      &types.AttributeValueMemberN{Value: "1"},
+			"foo":     &types.AttributeValueMemberS{Value: "bar"},
+			"version": &types.AttributeValueMemberN{Value: "3"},
+		},
+	}).Return(nil, &types.ConditionalCheckFailedException{}).Twice()
+
+	getItem := client.On("GetItem", ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(versionedTableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#version"),
+		ExpressionAttributeNames: map[string]string{"#version": "version"},
+	})
+
+	item := &versionedModel{
+		Id:      1,
+		Foo:     "bar",
+		Version: 2,
+	}
+
+	// somebody else updated the item in the meantime
+	getItem.Return(&dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"version": &types.AttributeValueMemberN{Value: "3"},
+		},
+	}, nil).Once()
+
+	qb := repo.PutItemBuilder().WithCondition(ddb.Eq("foo", "baz"))
+	_, err := repo.PutItem(ctx, qb, item)
+
+	assert.ErrorIs(t, err, ddb.ErrVersionConflict)
+	assert.Equal(t, 2, item.Version)
+
+	// the version matches, so only the custom condition failed
+	getItem.Return(&dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"version": &types.AttributeValueMemberN{Value: "2"},
+		},
+	}, nil).Once()
+
+	qb = repo.PutItemBuilder().WithCondition(ddb.Eq("foo", "baz"))
+	res, err := repo.PutItem(ctx, qb, item)
+
+	assert.NoError(t, err)
+	assert.True(t, res.ConditionalCheckFailed)
+	assert.Equal(t, 2, item.Version)
+	client.AssertExpectations(t)
+}
+
+func TestRepository_UpdateItemVersioned(t *testing.T) {
+	ctx := context.Background()
+	client, repo := getVersionedRepository(t, settingsVersionedModel{}, "rev")
+
+	client.On("UpdateItem", ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(versionedTableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "rev",
+			"#1": "foo",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "5"},
+			":1": &types.AttributeValueMemberS{Value: "bar"},
+			":2": &types.AttributeValueMemberN{Value: "0"},
+			":3": &types.AttributeValueMemberN{Value: "1"},
+		},
+		UpdateExpression: aws.String("SET #1 = :1, #0 = if_not_exists(#0, :2) + :3\n"),
+	}).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	item := &settingsVersionedModel{
+		Id:  1,
+		Rev: mdl.Int64(5),
+	}
+
+	ub := repo.UpdateItemBuilder().Set("foo", "bar")
+	res, err := repo.UpdateItem(ctx, ub, item)
+
+	assert.NoError(t, err)
+	assert.False(t, res.ConditionalCheckFailed)
+	assert.Equal(t, mdl.Int64(6), item.Rev)
+	client.AssertExpectations(t)
+}
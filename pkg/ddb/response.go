@@ -99,6 +99,8 @@ type ScanResult struct {
 	ItemCount        int32
 	ScannedCount     int32
 	ConsumedCapacity *ConsumedCapacity
+	// LastEvaluatedKey is the key to continue the scan at, it is nil after the whole table has been scanned
+	LastEvaluatedKey map[string]types.AttributeValue
 }
 
 func (s ScanResult) GetRequestCount() int32 {
@@ -85,19 +85,29 @@ func (s *Service) CreateTable(ctx context.Context, settings *Settings) (*Metadat
 		return metadata, nil
 	}
 
+	if err = s.createTable(ctx, settings, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func (s *Service) createTable(ctx context.Context, settings *Settings, metadata *Metadata) error {
+	tableName := metadata.TableName
+
 	mainKeySchema, err := s.getKeySchema(metadata.Main)
 	if err != nil {
-		return metadata, fmt.Errorf("can not create main key schema for table %s: %w", tableName, err)
+		return fmt.Errorf("can not create main key schema for table %s: %w", tableName, err)
 	}
 
 	localIndices, err := s.getLocalSecondaryIndices(metadata)
 	if err != nil {
-		return metadata, fmt.Errorf("can not create definitions for local secondary indices on table %s: %w", tableName, err)
+		return fmt.Errorf("can not create definitions for local secondary indices on table %s: %w", tableName, err)
 	}
 
-	globalIndices, err := s.getGlobalSecondaryIndices(metadata)
+	globalIndices, err := s.getGlobalSecondaryIndices(settings, metadata)
 	if err != nil {
-		return metadata, fmt.Errorf("can not create definitions for global secondary indices on table %s: %w", tableName, err)
+		return fmt.Errorf("can not create definitions for global secondary indices on table %s: %w", tableName, err)
 	}
 
 	attributeDefinitions := s.getAttributeDefinitions(metadata)
@@ -118,34 +128,33 @@ func (s *Service) CreateTable(ctx context.Context, settings *Settings) (*Metadat
 		LocalSecondaryIndexes:  localIndices,
 		GlobalSecondaryIndexes: globalIndices,
 		StreamSpecification:    streamSpecification,
-		ProvisionedThroughput: &types.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(metadata.Main.ReadCapacityUnits),
-			WriteCapacityUnits: aws.Int64(metadata.Main.WriteCapacityUnits),
-		},
+		ProvisionedThroughput:  s.getProvisionedThroughput(settings, metadata.Main.metadataCapacity),
+	}
+
+	if settings.Main.BillingMode != "" {
+		input.BillingMode = settings.Main.BillingMode
 	}
 
 	_, err = s.client.CreateTable(ctx, input)
 
 	var errResourceInUseException *types.ResourceInUseException
 	if errors.As(err, &errResourceInUseException) {
-		return metadata, nil
+		return nil
 	}
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = s.waitForTableGettingAvailable(ctx, tableName)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	s.logger.Info("created ddb table %s", tableName)
 
-	err = s.updateTtlSpecification(ctx, metadata)
-
-	return metadata, err
+	return s.updateTtlSpecification(ctx, metadata)
 }
 
 func (s *Service) updateTtlSpecification(ctx context.Context, metadata *Metadata) error {
@@ -278,7 +287,7 @@ func (s *Service) getLocalSecondaryIndices(meta *Metadata) ([]types.LocalSeconda
 	return indices, nil
 }
 
-func (s *Service) getGlobalSecondaryIndices(meta *Metadata) ([]types.GlobalSecondaryIndex, error) {
+func (s *Service) getGlobalSecondaryIndices(settings *Settings, meta *Metadata) ([]types.GlobalSecondaryIndex, error) {
 	if len(meta.Global) == 0 {
 		return nil, nil
 	}
@@ -305,19 +314,27 @@ func (s *Service) getGlobalSecondaryIndices(meta *Metadata) ([]types.GlobalSecon
 		}
 
 		indices = append(indices, types.GlobalSecondaryIndex{
-			IndexName:  aws.String(name),
-			KeySchema:  keySchema,
-			Projection: projection,
-			ProvisionedThroughput: &types.ProvisionedThroughput{
-				ReadCapacityUnits:  aws.Int64(data.ReadCapacityUnits),
-				WriteCapacityUnits: aws.Int64(data.WriteCapacityUnits),
-			},
+			IndexName:             aws.String(name),
+			KeySchema:             keySchema,
+			Projection:            projection,
+			ProvisionedThroughput: s.getProvisionedThroughput(settings, data.metadataCapacity),
 		})
 	}
 
 	return indices, nil
 }
 
+func (s *Service) getProvisionedThroughput(settings *Settings, capacity metadataCapacity) *types.ProvisionedThroughput {
+	if getBillingMode(settings) == types.BillingModePayPerRequest {
+		return nil
+	}
+
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(capacity.ReadCapacityUnits),
+		WriteCapacityUnits: aws.Int64(capacity.WriteCapacityUnits),
+	}
+}
+
 func (s *Service) projectedFields(main FieldAware, second FieldAware) (*types.Projection, error) {
 	mainFields := main.GetFields()
 	secondFields := second.GetFields()
@@ -437,3 +454,11 @@ func (s *Service) checkExists(ctx context.Context, name string) (bool, error) {
 
 	return active, nil
 }
+
+func getBillingMode(settings *Settings) types.BillingMode {
+	if settings.Main.BillingMode == "" {
+		return types.BillingModeProvisioned
+	}
+
+	return settings.Main.BillingMode
+}
@@ -21,8 +21,12 @@ type Settings struct {
 type MainSettings struct {
 	Model              interface{}
 	StreamView         types.StreamViewType
+	BillingMode        types.BillingMode
 	ReadCapacityUnits  int64
 	WriteCapacityUnits int64
+	// VersionAttribute enables optimistic locking on the given numeric attribute.
+	// Alternatively, the attribute can be marked with a ddb:"version=enabled" tag.
+	VersionAttribute string
 }
 
 type LocalSettings struct {
@@ -0,0 +1,57 @@
+package ddb
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+)
+
+type settingsRegistryAppCtxKey int
+
+// SettingsRegistry keeps the settings of all repositories created in the application, e.g. to reconcile their tables.
+type SettingsRegistry struct {
+	lck      sync.Mutex
+	settings map[string]*Settings
+}
+
+func ProvideSettingsRegistry(ctx context.Context) (*SettingsRegistry, error) {
+	registry, err := appctx.Provide(ctx, settingsRegistryAppCtxKey(0), func() (interface{}, error) {
+		return &SettingsRegistry{
+			settings: make(map[string]*Settings),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.(*SettingsRegistry), nil
+}
+
+func (r *SettingsRegistry) Add(settings *Settings) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.settings[TableName(settings)] = settings
+}
+
+// All returns the registered settings ordered by their table name.
+func (r *SettingsRegistry) All() []*Settings {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	names := make([]string, 0, len(r.settings))
+	for name := range r.settings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	all := make([]*Settings, len(names))
+	for i, name := range names {
+		all[i] = r.settings[name]
+	}
+
+	return all
+}
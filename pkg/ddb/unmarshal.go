@@ -6,6 +6,8 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamTypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
 )
 
 type Unmarshaller struct {
@@ -101,3 +103,69 @@ func UnmarshalList(l []types.AttributeValue, out interface{}) error {
 func UnmarshalMap(m map[string]types.AttributeValue, out interface{}) error {
 	return NewDecoder().Decode(&types.AttributeValueMemberM{Value: m}, out)
 }
+
+// StreamImageToMap converts an image of a dynamodb stream record into a map ready to be encoded as json. Numbers
+// are kept in their original representation to not lose any precision.
+func StreamImageToMap(image map[string]streamTypes.AttributeValue) (map[string]interface{}, error) {
+	item, err := attributevalue.FromDynamoDBStreamsMap(image)
+	if err != nil {
+		return nil, fmt.Errorf("can not convert stream image: %w", err)
+	}
+
+	value, err := attributeValueToInterface(&types.AttributeValueMemberM{Value: item})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(map[string]interface{}), nil
+}
+
+func attributeValueToInterface(av types.AttributeValue) (interface{}, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberB:
+		return v.Value, nil
+	case *types.AttributeValueMemberBOOL:
+		return v.Value, nil
+	case *types.AttributeValueMemberBS:
+		return v.Value, nil
+	case *types.AttributeValueMemberN:
+		return json.RawMessage(v.Value), nil
+	case *types.AttributeValueMemberNS:
+		numbers := make([]json.RawMessage, len(v.Value))
+		for i, n := range v.Value {
+			numbers[i] = json.RawMessage(n)
+		}
+
+		return numbers, nil
+	case *types.AttributeValueMemberNULL:
+		return nil, nil
+	case *types.AttributeValueMemberS:
+		return v.Value, nil
+	case *types.AttributeValueMemberSS:
+		return v.Value, nil
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, len(v.Value))
+
+		for i, element := range v.Value {
+			var err error
+			if list[i], err = attributeValueToInterface(element); err != nil {
+				return nil, err
+			}
+		}
+
+		return list, nil
+	case *types.AttributeValueMemberM:
+		m := make(map[string]interface{}, len(v.Value))
+
+		for key, element := range v.Value {
+			var err error
+			if m[key], err = attributeValueToInterface(element); err != nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported attribute value of type %T", av)
+	}
+}
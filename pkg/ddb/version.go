@@ -0,0 +1,128 @@
+package ddb
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type metadataVersion struct {
+	Enabled   bool
+	Field     string
+	FieldName string
+}
+
+// condition requires the stored version to match the version the item was read with.
+// A version of 0 is treated as an item which doesn't exist yet.
+func (v metadataVersion) condition(version int64) expression.ConditionBuilder {
+	if version == 0 {
+		return expression.AttributeNotExists(expression.Name(v.Field))
+	}
+
+	return expression.Name(v.Field).Equal(expression.Value(version))
+}
+
+func (v metadataVersion) withCondition(version int64, cond *expression.ConditionBuilder) *expression.ConditionBuilder {
+	versionCond := v.condition(version)
+
+	if cond != nil {
+		versionCond = versionCond.And(*cond)
+	}
+
+	return &versionCond
+}
+
+func (v metadataVersion) attributeValue(version int64) types.AttributeValue {
+	return &types.AttributeValueMemberN{
+		Value: strconv.FormatInt(version, 10),
+	}
+}
+
+func (v metadataVersion) read(item interface{}) (int64, error) {
+	field, err := v.field(item)
+	if err != nil {
+		return 0, err
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return 0, nil
+		}
+
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return field.Int(), nil
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("the version field %s has to be an integer but is %s", v.FieldName, field.Type())
+}
+
+// write stores the version in the item. Items which are not passed by pointer are left untouched.
+func (v metadataVersion) write(item interface{}, version int64) error {
+	if !isPointer(item) {
+		return nil
+	}
+
+	field, err := v.field(item)
+	if err != nil {
+		return err
+	}
+
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		field.Set(value)
+		field = value.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		field.SetInt(version)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(version))
+	default:
+		return fmt.Errorf("the version field %s has to be an integer but is %s", v.FieldName, field.Type())
+	}
+
+	return nil
+}
+
+func (v metadataVersion) field(item interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(item)
+
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("can not read the version of %T as it is not a struct", item)
+	}
+
+	field := value.FieldByName(v.FieldName)
+
+	if !field.IsValid() {
+		return reflect.Value{}, fmt.Errorf("the item %T has no version field %s", item, v.FieldName)
+	}
+
+	return field, nil
+}
+
+func isVersionType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
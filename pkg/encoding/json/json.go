@@ -25,3 +25,5 @@ type Marshaler interface {
 type Unmarshaler interface {
 	json.Unmarshaler
 }
+
+type RawMessage = json.RawMessage
@@ -0,0 +1,121 @@
+package es
+
+import "encoding/json"
+
+// An Aggregation summarizes the documents matched by a search, see SearchResult.Aggregation for reading the results.
+type Aggregation interface {
+	Source() map[string]interface{}
+}
+
+type metricAggregation struct {
+	kind  string
+	field string
+}
+
+func (a metricAggregation) Source() map[string]interface{} {
+	return map[string]interface{}{a.kind: map[string]interface{}{"field": a.field}}
+}
+
+func AvgAggregation(field string) Aggregation {
+	return metricAggregation{kind: "avg", field: field}
+}
+
+func SumAggregation(field string) Aggregation {
+	return metricAggregation{kind: "sum", field: field}
+}
+
+func MinAggregation(field string) Aggregation {
+	return metricAggregation{kind: "min", field: field}
+}
+
+func MaxAggregation(field string) Aggregation {
+	return metricAggregation{kind: "max", field: field}
+}
+
+func CardinalityAggregation(field string) Aggregation {
+	return metricAggregation{kind: "cardinality", field: field}
+}
+
+// A BucketAggregation groups the documents into buckets which can be summarized further by sub aggregations.
+type BucketAggregation struct {
+	kind            string
+	params          map[string]interface{}
+	subAggregations map[string]Aggregation
+}
+
+func TermsAggregation(field string, size int) *BucketAggregation {
+	return &BucketAggregation{
+		kind:   "terms",
+		params: map[string]interface{}{"field": field, "size": size},
+	}
+}
+
+func DateHistogramAggregation(field string, calendarInterval string) *BucketAggregation {
+	return &BucketAggregation{
+		kind:   "date_histogram",
+		params: map[string]interface{}{"field": field, "calendar_interval": calendarInterval},
+	}
+}
+
+func (a *BucketAggregation) SubAggregation(name string, aggregation Aggregation) *BucketAggregation {
+	if a.subAggregations == nil {
+		a.subAggregations = make(map[string]Aggregation)
+	}
+
+	a.subAggregations[name] = aggregation
+
+	return a
+}
+
+func (a *BucketAggregation) Source() map[string]interface{} {
+	source := map[string]interface{}{a.kind: a.params}
+
+	if len(a.subAggregations) > 0 {
+		source["aggs"] = aggregationSources(a.subAggregations)
+	}
+
+	return source
+}
+
+func aggregationSources(aggregations map[string]Aggregation) map[string]interface{} {
+	sources := make(map[string]interface{}, len(aggregations))
+
+	for name, aggregation := range aggregations {
+		sources[name] = aggregation.Source()
+	}
+
+	return sources
+}
+
+// MetricAggregationResult is the result of an avg, sum, min, max or cardinality aggregation. The value is nil if no
+// document contained the field.
+type MetricAggregationResult struct {
+	Value *float64 `json:"value"`
+}
+
+type BucketAggregationResult struct {
+	Buckets []Bucket `json:"buckets"`
+}
+
+// A Bucket contains the results of the sub aggregations by their name besides the key and the document count.
+type Bucket struct {
+	Key             interface{} `json:"key"`
+	KeyAsString     string      `json:"key_as_string"`
+	DocCount        int         `json:"doc_count"`
+	subAggregations map[string]json.RawMessage
+}
+
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	type bucket Bucket
+
+	if err := json.Unmarshal(data, (*bucket)(b)); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, &b.subAggregations)
+}
+
+// Aggregation decodes the result of the sub aggregation with the given name into result.
+func (b Bucket) Aggregation(name string, result interface{}) error {
+	return decodeAggregation(b.subAggregations, name, result)
+}
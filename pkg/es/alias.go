@@ -0,0 +1,164 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+// AliasName returns the name of the alias of a model. Readers should use the alias, so the index behind it can be
+// replaced without downtime.
+func AliasName(modelId mdl.ModelId) string {
+	return strings.ToLower(fmt.Sprintf("%v-%v-%v-%v-%v", modelId.Project, modelId.Environment, modelId.Family, modelId.Application, modelId.Name))
+}
+
+// IndexName returns the name of the index of a model in a version.
+func IndexName(modelId mdl.ModelId, version int) string {
+	return fmt.Sprintf("%s-v%d", AliasName(modelId), version)
+}
+
+// CreateIndex creates the index with the given mappings if it doesn't exist yet. Without mappings, the fields are
+// mapped dynamically or by the templates of the client.
+func CreateIndex(ctx context.Context, client *ClientV7, index string, mappings map[string]interface{}) error {
+	res, err := client.Indices.Exists([]string{index}, client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("can not check if index %s exists: %w", index, err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	options := []func(*esapi.IndicesCreateRequest){
+		client.Indices.Create.WithContext(ctx),
+	}
+
+	if mappings != nil {
+		body, err := encodeBody(map[string]interface{}{"mappings": mappings})
+		if err != nil {
+			return err
+		}
+
+		options = append(options, client.Indices.Create.WithBody(body))
+	}
+
+	res, err = client.Indices.Create(index, options...)
+	if err != nil {
+		return fmt.Errorf("can not create index %s: %w", index, err)
+	}
+
+	defer res.Body.Close()
+
+	// the index might have been created concurrently
+	if res.IsError() && !strings.Contains(res.String(), "resource_already_exists_exception") {
+		return fmt.Errorf("can not create index %s: got error from ES: %s", index, res.String())
+	}
+
+	return nil
+}
+
+// CreateAlias points the alias to the index if the alias doesn't exist yet. An existing alias is left untouched, use
+// SwitchAlias to point it to another index.
+func CreateAlias(ctx context.Context, client *ClientV7, alias string, index string) error {
+	indices, err := getAliasIndices(ctx, client, alias)
+	if err != nil {
+		return err
+	}
+
+	if len(indices) > 0 {
+		return nil
+	}
+
+	return updateAliases(ctx, client, alias, index, []map[string]interface{}{
+		{"add": map[string]string{"index": index, "alias": alias}},
+	})
+}
+
+// SwitchAlias points the alias to the index and removes it from all other indices in a single atomic request. The
+// index is created if it doesn't exist yet.
+func SwitchAlias(ctx context.Context, client *ClientV7, alias string, index string) error {
+	if err := CreateIndex(ctx, client, index, nil); err != nil {
+		return err
+	}
+
+	indices, err := getAliasIndices(ctx, client, alias)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]map[string]interface{}, 0, len(indices)+1)
+
+	for _, current := range indices {
+		if current == index {
+			continue
+		}
+
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]string{"index": current, "alias": alias},
+		})
+	}
+
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]string{"index": index, "alias": alias},
+	})
+
+	return updateAliases(ctx, client, alias, index, actions)
+}
+
+func updateAliases(ctx context.Context, client *ClientV7, alias string, index string, actions []map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("can not encode alias actions: %w", err)
+	}
+
+	res, err := client.Indices.UpdateAliases(bytes.NewReader(body), client.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("can not point alias %s to index %s: %w", alias, index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("can not point alias %s to index %s: got error from ES: %s", alias, index, res.String())
+	}
+
+	return nil
+}
+
+func getAliasIndices(ctx context.Context, client *ClientV7, alias string) ([]string, error) {
+	res, err := client.Indices.GetAlias(client.Indices.GetAlias.WithName(alias), client.Indices.GetAlias.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("can not get alias %s: %w", alias, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("can not get alias %s: got error from ES: %s", alias, res.String())
+	}
+
+	response := make(map[string]interface{})
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("can not decode alias %s: %w", alias, err)
+	}
+
+	indices := make([]string, 0, len(response))
+
+	for index := range response {
+		indices = append(indices, index)
+	}
+
+	return indices, nil
+}
@@ -0,0 +1,48 @@
+package es_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAlias(t *testing.T) {
+	var updated string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/_alias/items":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"alias [items] missing","status":404}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/_aliases":
+			data, _ := ioutil.ReadAll(r.Body)
+			updated = string(data)
+
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		default:
+			assert.Failf(t, "unexpected request", "%s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	err := es.CreateAlias(context.Background(), client, "items", "items-v2")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"actions":[{"add":{"alias":"items","index":"items-v2"}}]}`, updated)
+}
+
+func TestCreateAlias_Exists(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/_alias/items":
+			_, _ = w.Write([]byte(`{"items-v1":{"aliases":{"items":{}}}}`))
+		default:
+			assert.Failf(t, "unexpected request", "%s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	err := es.CreateAlias(context.Background(), client, "items", "items-v2")
+	assert.NoError(t, err, "an existing alias should be left untouched")
+}
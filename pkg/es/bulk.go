@@ -0,0 +1,150 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const (
+	BulkActionIndex  = "index"
+	BulkActionDelete = "delete"
+)
+
+// A BulkItem is a single operation of a bulk request. If the item has a version, the external versioning of
+// elasticsearch is used: a document is only indexed if its stored version is lower than the version of the item. A
+// delete is also executed if the versions are equal, as a deleted model usually keeps the version of its last update.
+type BulkItem struct {
+	Action  string
+	Index   string
+	Id      string
+	Version *int64
+	Body    interface{}
+}
+
+type BulkItemResult struct {
+	Status int
+	// Conflict is set if the item was rejected as the stored document has the same or a newer version
+	Conflict bool
+	Error    error
+}
+
+type bulkMeta struct {
+	Index       string `json:"_index"`
+	Id          string `json:"_id"`
+	Version     *int64 `json:"version,omitempty"`
+	VersionType string `json:"version_type,omitempty"`
+}
+
+type bulkResponse struct {
+	Errors bool                                `json:"errors"`
+	Items  []map[string]bulkResponseItemResult `json:"items"`
+}
+
+type bulkResponseItemResult struct {
+	Status int `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// BulkError reports the items of a bulk request which failed.
+type BulkError struct {
+	Total    int
+	Failures []BulkFailure
+}
+
+type BulkFailure struct {
+	Id     string
+	Status int
+	Err    error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d documents failed, first error: %s", len(e.Failures), e.Total, e.Failures[0].Err)
+}
+
+// Bulk executes the items with a single request. The error is only set if the request as a whole failed, the
+// results contain the outcome of every item in the order of the items.
+func Bulk(ctx context.Context, client *ClientV7, items []BulkItem, o ...func(*esapi.BulkRequest)) ([]BulkItemResult, error) {
+	body, err := encodeBulkItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	o = append(o, client.Bulk.WithContext(ctx))
+
+	res, err := client.Bulk(bytes.NewReader(body), o...)
+	if err != nil {
+		return nil, fmt.Errorf("can not execute bulk request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("can not execute bulk request: got error from ES: %s", res.String())
+	}
+
+	response := &bulkResponse{}
+	if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+		return nil, fmt.Errorf("can not decode bulk response: %w", err)
+	}
+
+	if len(response.Items) != len(items) {
+		return nil, fmt.Errorf("the bulk response contains %d items, but %d items were requested", len(response.Items), len(items))
+	}
+
+	results := make([]BulkItemResult, len(items))
+
+	for i, item := range items {
+		result := response.Items[i][item.Action]
+		results[i].Status = result.Status
+		results[i].Conflict = result.Status == http.StatusConflict
+
+		if result.Error != nil {
+			results[i].Error = fmt.Errorf("can not %s document %s in index %s: %s: %s", item.Action, item.Id, item.Index, result.Error.Type, result.Error.Reason)
+		}
+	}
+
+	return results, nil
+}
+
+func encodeBulkItems(items []BulkItem) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+
+	for _, item := range items {
+		meta := bulkMeta{
+			Index:   item.Index,
+			Id:      item.Id,
+			Version: item.Version,
+		}
+
+		if item.Version != nil {
+			meta.VersionType = "external"
+		}
+
+		if item.Version != nil && item.Action == BulkActionDelete {
+			meta.VersionType = "external_gte"
+		}
+
+		if err := encoder.Encode(map[string]bulkMeta{item.Action: meta}); err != nil {
+			return nil, fmt.Errorf("can not encode bulk action of document %s: %w", item.Id, err)
+		}
+
+		if item.Action == BulkActionDelete {
+			continue
+		}
+
+		if err := encoder.Encode(item.Body); err != nil {
+			return nil, fmt.Errorf("can not encode document %s: %w", item.Id, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+package es_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *es.ClientV7 {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{server.URL},
+	})
+	assert.NoError(t, err)
+
+	return &es.ClientV7{Client: *client}
+}
+
+func TestBulk(t *testing.T) {
+	var body string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+
+		assert.Equal(t, "/_bulk", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"errors":true,"items":[
+			{"index":{"_id":"1","status":201}},
+			{"index":{"_id":"2","status":409,"error":{"type":"version_conflict_engine_exception","reason":"version conflict"}}},
+			{"delete":{"_id":"3","status":400,"error":{"type":"illegal_argument_exception","reason":"boom"}}}
+		]}`))
+	})
+
+	results, err := es.Bulk(context.Background(), client, []es.BulkItem{
+		{Action: es.BulkActionIndex, Index: "items-v0", Id: "1", Body: map[string]int{"id": 1}},
+		{Action: es.BulkActionIndex, Index: "items-v0", Id: "2", Version: mdl.Int64(5), Body: map[string]int{"id": 2}},
+		{Action: es.BulkActionDelete, Index: "items-v0", Id: "3", Version: mdl.Int64(7)},
+	})
+	assert.NoError(t, err)
+
+	expectedBody := `{"index":{"_index":"items-v0","_id":"1"}}
+{"id":1}
+{"index":{"_index":"items-v0","_id":"2","version":5,"version_type":"external"}}
+{"id":2}
+{"delete":{"_index":"items-v0","_id":"3","version":7,"version_type":"external_gte"}}
+`
+	assert.Equal(t, expectedBody, body)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, es.BulkItemResult{Status: 201}, results[0])
+	assert.True(t, results[1].Conflict)
+	assert.Error(t, results[1].Error)
+	assert.False(t, results[2].Conflict)
+	assert.EqualError(t, results[2].Error, "can not delete document 3 in index items-v0: illegal_argument_exception: boom")
+}
+
+func TestBulk_RequestFails(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := es.Bulk(context.Background(), client, []es.BulkItem{
+		{Action: es.BulkActionDelete, Index: "items-v0", Id: "1"},
+	})
+	assert.Error(t, err)
+}
+
+func TestIndexName(t *testing.T) {
+	modelId := mdl.ModelId{
+		Project:     "justtrack",
+		Environment: "test",
+		Family:      "gosoline",
+		Application: "Subscriber",
+		Name:        "items",
+	}
+
+	assert.Equal(t, "justtrack-test-gosoline-subscriber-items", es.AliasName(modelId))
+	assert.Equal(t, "justtrack-test-gosoline-subscriber-items-v2", es.IndexName(modelId, 2))
+}
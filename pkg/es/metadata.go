@@ -0,0 +1,280 @@
+package es
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	tagKey  = "key"
+	tagType = "type"
+
+	FieldTypeBinary  = "binary"
+	FieldTypeBoolean = "boolean"
+	FieldTypeDate    = "date"
+	FieldTypeDouble  = "double"
+	FieldTypeKeyword = "keyword"
+	FieldTypeLong    = "long"
+	FieldTypeObject  = "object"
+	FieldTypeText    = "text"
+)
+
+// Metadata describes the index of a model. The fields are derived from the json names of the struct fields of the
+// model, nested structs are separated by dots. The type of a field can be overwritten with an es:"type=text" tag and
+// the id of the documents is read from the field tagged with es:"key=id" or the top level field named id.
+type Metadata struct {
+	Index   string
+	IdField string
+	Fields  map[string]Field
+
+	idIndex    []int
+	idTagged   bool
+	properties map[string]interface{}
+}
+
+type Field struct {
+	Name string
+	Type string
+}
+
+func (m Metadata) HasField(name string) bool {
+	_, ok := m.Fields[name]
+
+	return ok
+}
+
+// Mappings returns the body of the mappings of the index.
+func (m Metadata) Mappings() map[string]interface{} {
+	return map[string]interface{}{
+		"properties": m.properties,
+	}
+}
+
+// GetId returns the value of the id field of the item as the id of its document.
+func (m Metadata) GetId(item interface{}) (string, error) {
+	value := reflect.ValueOf(item)
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", fmt.Errorf("can not read the id of a nil item")
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return "", fmt.Errorf("can not read the id of %T as it is not a struct", item)
+	}
+
+	id, err := value.FieldByIndexErr(m.idIndex)
+	if err != nil {
+		return "", fmt.Errorf("can not read the id field %s of %T: %w", m.IdField, item, err)
+	}
+
+	for id.Kind() == reflect.Ptr {
+		if id.IsNil() {
+			return "", fmt.Errorf("the id field %s of %T is nil", m.IdField, item)
+		}
+
+		id = id.Elem()
+	}
+
+	return fmt.Sprint(id.Interface()), nil
+}
+
+func ReadMetadata(index string, model interface{}) (Metadata, error) {
+	t := findBaseType(model)
+
+	if t.Kind() != reflect.Struct {
+		return Metadata{}, fmt.Errorf("can't read metadata from model as it is not a struct but instead is %T", model)
+	}
+
+	metadata := Metadata{
+		Index:  index,
+		Fields: make(map[string]Field),
+	}
+
+	var err error
+	if metadata.properties, err = readProperties(t, "", nil, &metadata); err != nil {
+		return Metadata{}, err
+	}
+
+	if metadata.IdField == "" {
+		return Metadata{}, fmt.Errorf("no id field defined on %T, tag a field with es:\"key=id\"", model)
+	}
+
+	return metadata, nil
+}
+
+func readProperties(t reflect.Type, prefix string, index []int, metadata *Metadata) (map[string]interface{}, error) {
+	properties := make(map[string]interface{})
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, ok := getFieldName(field)
+		if !ok {
+			continue
+		}
+
+		tags, err := getFieldTags(field)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldType := derefType(field.Type)
+
+		// embedded structs without a json name are flattened into the parent, like encoding/json does
+		if field.Anonymous && name == field.Name && fieldType.Kind() == reflect.Struct {
+			embedded, err := readProperties(fieldType, prefix, fieldIndex, metadata)
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range embedded {
+				properties[k] = v
+			}
+
+			continue
+		}
+
+		path := prefix + name
+		property, err := readProperty(fieldType, tags, path, fieldIndex, metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		properties[name] = property
+		metadata.Fields[path] = Field{
+			Name: path,
+			Type: property["type"].(string),
+		}
+
+		switch {
+		case tags[tagKey] == "id" && metadata.idTagged:
+			return nil, fmt.Errorf("multiple id fields defined: %s and %s", metadata.IdField, path)
+		case tags[tagKey] == "id":
+			metadata.IdField = path
+			metadata.idIndex = fieldIndex
+			metadata.idTagged = true
+		case strings.EqualFold(path, "id") && !metadata.idTagged:
+			metadata.IdField = path
+			metadata.idIndex = fieldIndex
+		}
+	}
+
+	return properties, nil
+}
+
+func readProperty(t reflect.Type, tags map[string]string, path string, index []int, metadata *Metadata) (map[string]interface{}, error) {
+	if fieldType, ok := tags[tagType]; ok {
+		return map[string]interface{}{"type": fieldType}, nil
+	}
+
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = derefType(t.Elem())
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": FieldTypeDate}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": FieldTypeKeyword}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": FieldTypeBoolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": FieldTypeLong}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": FieldTypeDouble}, nil
+	case reflect.Slice:
+		return map[string]interface{}{"type": FieldTypeBinary}, nil
+	case reflect.Struct:
+		properties, err := readProperties(t, path+".", index, metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"type": FieldTypeObject, "properties": properties}, nil
+	default:
+		return map[string]interface{}{"type": FieldTypeObject}, nil
+	}
+}
+
+func getFieldName(field reflect.StructField) (string, bool) {
+	jsonTag, ok := field.Tag.Lookup("json")
+
+	if !ok {
+		return field.Name, true
+	}
+
+	jsonTag = strings.TrimSpace(jsonTag)
+
+	if jsonTag == "-" {
+		return "", false
+	}
+
+	name := strings.SplitN(jsonTag, ",", 2)[0]
+
+	if len(name) == 0 {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func getFieldTags(field reflect.StructField) (map[string]string, error) {
+	tags := make(map[string]string)
+	tag, ok := field.Tag.Lookup("es")
+
+	if !ok {
+		return tags, nil
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.Split(strings.TrimSpace(part), "=")
+
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("the parts of an es tag should have the format x=y on field %s", field.Name)
+		}
+
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		tags[key] = strings.ToLower(strings.TrimSpace(kv[1]))
+	}
+
+	return tags, nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func findBaseType(value interface{}) reflect.Type {
+	t := reflect.TypeOf(value)
+
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func getTypeName(value interface{}) string {
+	t := findBaseType(value)
+	name := t.Name()
+
+	return strings.ToLower(string(name[0])) + name[1:]
+}
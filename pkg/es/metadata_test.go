@@ -0,0 +1,79 @@
+package es_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+)
+
+type metadataAddress struct {
+	City string `json:"city"`
+}
+
+type metadataItem struct {
+	db_repo.Model
+	Name        string            `json:"name"`
+	Description *string           `json:"description" es:"type=text"`
+	Amount      float64           `json:"amount"`
+	Tags        []string          `json:"tags"`
+	Seen        time.Time         `json:"seen"`
+	Address     metadataAddress   `json:"address"`
+	Labels      map[string]string `json:"labels"`
+	Ignored     string            `json:"-"`
+}
+
+type metadataTaggedItem struct {
+	Id  string `json:"id"`
+	Key string `json:"key" es:"key=id"`
+}
+
+func TestReadMetadata(t *testing.T) {
+	metadata, err := es.ReadMetadata("items", &metadataItem{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "items", metadata.Index)
+	assert.Equal(t, "Id", metadata.IdField)
+	assert.Equal(t, map[string]es.Field{
+		"Id":           {Name: "Id", Type: es.FieldTypeLong},
+		"UpdatedAt":    {Name: "UpdatedAt", Type: es.FieldTypeDate},
+		"CreatedAt":    {Name: "CreatedAt", Type: es.FieldTypeDate},
+		"name":         {Name: "name", Type: es.FieldTypeKeyword},
+		"description":  {Name: "description", Type: es.FieldTypeText},
+		"amount":       {Name: "amount", Type: es.FieldTypeDouble},
+		"tags":         {Name: "tags", Type: es.FieldTypeKeyword},
+		"seen":         {Name: "seen", Type: es.FieldTypeDate},
+		"address":      {Name: "address", Type: es.FieldTypeObject},
+		"address.city": {Name: "address.city", Type: es.FieldTypeKeyword},
+		"labels":       {Name: "labels", Type: es.FieldTypeObject},
+	}, metadata.Fields)
+
+	assert.Equal(t, map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+		"city": map[string]interface{}{"type": "keyword"},
+	}}, metadata.Mappings()["properties"].(map[string]interface{})["address"])
+
+	id, err := metadata.GetId(&metadataItem{Model: db_repo.Model{Id: mdl.Uint(3)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", id)
+
+	_, err = metadata.GetId(&metadataItem{})
+	assert.EqualError(t, err, "the id field Id of *es_test.metadataItem is nil")
+}
+
+func TestReadMetadata_TaggedId(t *testing.T) {
+	metadata, err := es.ReadMetadata("items", metadataTaggedItem{})
+	assert.NoError(t, err)
+	assert.Equal(t, "key", metadata.IdField)
+
+	id, err := metadata.GetId(metadataTaggedItem{Id: "a", Key: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", id)
+}
+
+func TestReadMetadata_NoId(t *testing.T) {
+	_, err := es.ReadMetadata("items", &metadataAddress{})
+	assert.EqualError(t, err, `no id field defined on *es_test.metadataAddress, tag a field with es:"key=id"`)
+}
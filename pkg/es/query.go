@@ -0,0 +1,159 @@
+package es
+
+// A Query is a part of the query DSL of elasticsearch.
+type Query interface {
+	Source() map[string]interface{}
+}
+
+type rawQuery map[string]interface{}
+
+func (q rawQuery) Source() map[string]interface{} {
+	return q
+}
+
+func MatchAll() Query {
+	return rawQuery{"match_all": map[string]interface{}{}}
+}
+
+func Term(field string, value interface{}) Query {
+	return rawQuery{"term": map[string]interface{}{field: value}}
+}
+
+func Terms(field string, values ...interface{}) Query {
+	return rawQuery{"terms": map[string]interface{}{field: values}}
+}
+
+func Exists(field string) Query {
+	return rawQuery{"exists": map[string]interface{}{"field": field}}
+}
+
+func Wildcard(field string, pattern string) Query {
+	return rawQuery{"wildcard": map[string]interface{}{field: pattern}}
+}
+
+// Match is a full-text query on an analyzed field.
+func Match(field string, text string) Query {
+	return rawQuery{"match": map[string]interface{}{field: text}}
+}
+
+// MatchPhrase is a full-text query on an analyzed field matching the terms of the text in the same order.
+func MatchPhrase(field string, text string) Query {
+	return rawQuery{"match_phrase": map[string]interface{}{field: text}}
+}
+
+// MultiMatch is a full-text query on multiple analyzed fields.
+func MultiMatch(text string, fields ...string) Query {
+	return rawQuery{"multi_match": map[string]interface{}{"query": text, "fields": fields}}
+}
+
+type RangeQuery struct {
+	field  string
+	bounds map[string]interface{}
+}
+
+func Range(field string) *RangeQuery {
+	return &RangeQuery{
+		field:  field,
+		bounds: make(map[string]interface{}),
+	}
+}
+
+func (q *RangeQuery) Gt(value interface{}) *RangeQuery {
+	q.bounds["gt"] = value
+
+	return q
+}
+
+func (q *RangeQuery) Gte(value interface{}) *RangeQuery {
+	q.bounds["gte"] = value
+
+	return q
+}
+
+func (q *RangeQuery) Lt(value interface{}) *RangeQuery {
+	q.bounds["lt"] = value
+
+	return q
+}
+
+func (q *RangeQuery) Lte(value interface{}) *RangeQuery {
+	q.bounds["lte"] = value
+
+	return q
+}
+
+func (q *RangeQuery) Source() map[string]interface{} {
+	return map[string]interface{}{"range": map[string]interface{}{q.field: q.bounds}}
+}
+
+type BoolQuery struct {
+	must               []Query
+	filter             []Query
+	should             []Query
+	mustNot            []Query
+	minimumShouldMatch *int
+}
+
+func Bool() *BoolQuery {
+	return &BoolQuery{}
+}
+
+func (q *BoolQuery) Must(queries ...Query) *BoolQuery {
+	q.must = append(q.must, queries...)
+
+	return q
+}
+
+// Filter adds queries which have to match, but don't contribute to the score.
+func (q *BoolQuery) Filter(queries ...Query) *BoolQuery {
+	q.filter = append(q.filter, queries...)
+
+	return q
+}
+
+func (q *BoolQuery) Should(queries ...Query) *BoolQuery {
+	q.should = append(q.should, queries...)
+
+	return q
+}
+
+func (q *BoolQuery) MustNot(queries ...Query) *BoolQuery {
+	q.mustNot = append(q.mustNot, queries...)
+
+	return q
+}
+
+func (q *BoolQuery) MinimumShouldMatch(count int) *BoolQuery {
+	q.minimumShouldMatch = &count
+
+	return q
+}
+
+func (q *BoolQuery) Source() map[string]interface{} {
+	clauses := make(map[string]interface{})
+
+	addQueries(clauses, "must", q.must)
+	addQueries(clauses, "filter", q.filter)
+	addQueries(clauses, "should", q.should)
+	addQueries(clauses, "must_not", q.mustNot)
+
+	if q.minimumShouldMatch != nil {
+		clauses["minimum_should_match"] = *q.minimumShouldMatch
+	}
+
+	return map[string]interface{}{"bool": clauses}
+}
+
+func addQueries(clauses map[string]interface{}, occur string, queries []Query) {
+	if len(queries) == 0 {
+		return
+	}
+
+	sources := make([]map[string]interface{}, len(queries))
+
+	for i, query := range queries {
+		sources[i] = query.Source()
+	}
+
+	clauses[occur] = sources
+}
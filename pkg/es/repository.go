@@ -0,0 +1,343 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/dx"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+type Settings struct {
+	ModelId    mdl.ModelId
+	ClientName string
+	Model      interface{}
+	// Version of the index, see IndexName
+	Version int
+	// UseAlias reads and writes the documents through the alias of the model instead of the index of the version,
+	// e.g. if the alias is switched by the es output of a mdlsub subscriber
+	UseAlias bool
+	// Refresh is passed to every write request, use "wait_for" to make the changes visible to searches before returning
+	Refresh string
+}
+
+//go:generate mockery --name Repository
+type Repository interface {
+	GetModelId() mdl.ModelId
+	GetMetadata() Metadata
+
+	BulkIndex(ctx context.Context, items interface{}) error
+	Count(ctx context.Context, sb *SearchBuilder) (int, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string, result interface{}) (bool, error)
+	Index(ctx context.Context, item interface{}) error
+	Scroll(ctx context.Context, sb *SearchBuilder, keepAlive time.Duration) Scroller
+	Search(ctx context.Context, sb *SearchBuilder, result interface{}) (*SearchResult, error)
+}
+
+type repository struct {
+	logger   log.Logger
+	client   *ClientV7
+	metadata Metadata
+	settings *Settings
+}
+
+func NewRepository(ctx context.Context, config cfg.Config, logger log.Logger, settings *Settings) (Repository, error) {
+	if settings.ModelId.Name == "" {
+		settings.ModelId.Name = getTypeName(settings.Model)
+	}
+
+	if settings.ClientName == "" {
+		settings.ClientName = "default"
+	}
+
+	settings.ModelId.PadFromConfig(config)
+
+	client, err := ProvideClient(config, logger, settings.ClientName)
+	if err != nil {
+		return nil, fmt.Errorf("can not create es client %s: %w", settings.ClientName, err)
+	}
+
+	repo, err := NewRepositoryWithInterfaces(logger, client, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// the index behind an alias is managed by whoever switches the alias
+	if settings.UseAlias || !dx.ShouldAutoCreate(config) {
+		return repo, nil
+	}
+
+	metadata := repo.GetMetadata()
+
+	if err = CreateIndex(ctx, client, metadata.Index, metadata.Mappings()); err != nil {
+		return nil, fmt.Errorf("can not create index for model %s: %w", settings.ModelId.String(), err)
+	}
+
+	return repo, nil
+}
+
+func NewRepositoryWithInterfaces(logger log.Logger, client *ClientV7, settings *Settings) (Repository, error) {
+	index := IndexName(settings.ModelId, settings.Version)
+
+	if settings.UseAlias {
+		index = AliasName(settings.ModelId)
+	}
+
+	metadata, err := ReadMetadata(index, settings.Model)
+	if err != nil {
+		return nil, fmt.Errorf("can not read metadata of model %s: %w", settings.ModelId.String(), err)
+	}
+
+	return &repository{
+		logger:   logger,
+		client:   client,
+		metadata: metadata,
+		settings: settings,
+	}, nil
+}
+
+func (r *repository) GetModelId() mdl.ModelId {
+	return r.settings.ModelId
+}
+
+func (r *repository) GetMetadata() Metadata {
+	return r.metadata
+}
+
+// BulkIndex indexes the items of a slice with a single request. If some of the items fail, a *BulkError is returned.
+func (r *repository) BulkIndex(ctx context.Context, items interface{}) error {
+	slice := reflect.ValueOf(items)
+
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("the items to index have to be a slice, but are %T", items)
+	}
+
+	if slice.Len() == 0 {
+		return nil
+	}
+
+	bulkItems := make([]BulkItem, slice.Len())
+
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i).Interface()
+
+		id, err := r.metadata.GetId(item)
+		if err != nil {
+			return err
+		}
+
+		bulkItems[i] = BulkItem{
+			Action: BulkActionIndex,
+			Index:  r.metadata.Index,
+			Id:     id,
+			Body:   item,
+		}
+	}
+
+	var options []func(*esapi.BulkRequest)
+	if r.settings.Refresh != "" {
+		options = append(options, r.client.Bulk.WithRefresh(r.settings.Refresh))
+	}
+
+	results, err := Bulk(ctx, r.client, bulkItems, options...)
+	if err != nil {
+		return fmt.Errorf("can not index %d documents into %s: %w", len(bulkItems), r.metadata.Index, err)
+	}
+
+	bulkErr := &BulkError{
+		Total: len(bulkItems),
+	}
+
+	for i, result := range results {
+		if result.Error == nil {
+			continue
+		}
+
+		bulkErr.Failures = append(bulkErr.Failures, BulkFailure{
+			Id:     bulkItems[i].Id,
+			Status: result.Status,
+			Err:    result.Error,
+		})
+	}
+
+	if len(bulkErr.Failures) > 0 {
+		return bulkErr
+	}
+
+	return nil
+}
+
+func (r *repository) Count(ctx context.Context, sb *SearchBuilder) (int, error) {
+	body, err := encodeBody(sb.buildCount())
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := r.client.Count(
+		r.client.Count.WithContext(ctx),
+		r.client.Count.WithIndex(r.metadata.Index),
+		r.client.Count.WithBody(body),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("can not count documents in %s: %w", r.metadata.Index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return 0, fmt.Errorf("can not count documents in %s: got error from ES: %s", r.metadata.Index, res.String())
+	}
+
+	response := &struct {
+		Count int `json:"count"`
+	}{}
+
+	if err = decodeResponse(res, response); err != nil {
+		return 0, fmt.Errorf("can not decode count response: %w", err)
+	}
+
+	return response.Count, nil
+}
+
+// Delete removes the document with the id. It is no error if the document doesn't exist.
+func (r *repository) Delete(ctx context.Context, id string) error {
+	options := []func(*esapi.DeleteRequest){
+		r.client.Delete.WithContext(ctx),
+	}
+
+	if r.settings.Refresh != "" {
+		options = append(options, r.client.Delete.WithRefresh(r.settings.Refresh))
+	}
+
+	res, err := r.client.Delete(r.metadata.Index, id, options...)
+	if err != nil {
+		return fmt.Errorf("can not delete document %s from %s: %w", id, r.metadata.Index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("can not delete document %s from %s: got error from ES: %s", id, r.metadata.Index, res.String())
+	}
+
+	return nil
+}
+
+// Get decodes the document with the id into result and returns false if the document doesn't exist.
+func (r *repository) Get(ctx context.Context, id string, result interface{}) (bool, error) {
+	res, err := r.client.Get(r.metadata.Index, id, r.client.Get.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("can not get document %s from %s: %w", id, r.metadata.Index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.IsError() {
+		return false, fmt.Errorf("can not get document %s from %s: got error from ES: %s", id, r.metadata.Index, res.String())
+	}
+
+	document := &struct {
+		Source interface{} `json:"_source"`
+	}{
+		Source: result,
+	}
+
+	if err = decodeResponse(res, document); err != nil {
+		return false, fmt.Errorf("can not decode document %s: %w", id, err)
+	}
+
+	return true, nil
+}
+
+func (r *repository) Index(ctx context.Context, item interface{}) error {
+	id, err := r.metadata.GetId(item)
+	if err != nil {
+		return err
+	}
+
+	body, err := encodeBody(item)
+	if err != nil {
+		return err
+	}
+
+	options := []func(*esapi.IndexRequest){
+		r.client.Index.WithContext(ctx),
+		r.client.Index.WithDocumentID(id),
+	}
+
+	if r.settings.Refresh != "" {
+		options = append(options, r.client.Index.WithRefresh(r.settings.Refresh))
+	}
+
+	res, err := r.client.Index(r.metadata.Index, body, options...)
+	if err != nil {
+		return fmt.Errorf("can not index document %s into %s: %w", id, r.metadata.Index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("can not index document %s into %s: got error from ES: %s", id, r.metadata.Index, res.String())
+	}
+
+	return nil
+}
+
+func (r *repository) Scroll(ctx context.Context, sb *SearchBuilder, keepAlive time.Duration) Scroller {
+	return &scroller{
+		client:    r.client,
+		index:     r.metadata.Index,
+		sb:        sb,
+		keepAlive: keepAlive,
+	}
+}
+
+// Search decodes the matching documents into result, which has to be a pointer to a slice of the model.
+func (r *repository) Search(ctx context.Context, sb *SearchBuilder, result interface{}) (*SearchResult, error) {
+	body, err := encodeBody(sb.Build())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(r.metadata.Index),
+		r.client.Search.WithBody(body),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can not search documents in %s: %w", r.metadata.Index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("can not search documents in %s: got error from ES: %s", r.metadata.Index, res.String())
+	}
+
+	response, err := decodeSearchResponse(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = response.decodeHits(result); err != nil {
+		return nil, err
+	}
+
+	return response.result(), nil
+}
+
+func decodeResponse(res *esapi.Response, result interface{}) error {
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,191 @@
+package es_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/es"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+)
+
+type repositoryItem struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+var repositoryModelId = mdl.ModelId{
+	Project:     "justtrack",
+	Environment: "test",
+	Family:      "gosoline",
+	Application: "search",
+	Name:        "item",
+}
+
+func newTestRepository(t *testing.T, handler http.HandlerFunc) es.Repository {
+	client := newTestClient(t, handler)
+
+	repo, err := es.NewRepositoryWithInterfaces(logMocks.NewLoggerMockedAll(), client, &es.Settings{
+		ModelId: repositoryModelId,
+		Model:   &repositoryItem{},
+		Version: 1,
+	})
+	assert.NoError(t, err)
+
+	return repo
+}
+
+func TestRepository_Search(t *testing.T) {
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/justtrack-test-gosoline-search-item-v1/_search", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{
+			"hits": {"total": {"value": 3}, "hits": [
+				{"_source": {"id": "a", "status": "active"}, "sort": [1622505600000, "a"]},
+				{"_source": {"id": "b", "status": "active"}, "sort": [9007199254740993, "b"]}
+			]},
+			"aggregations": {
+				"perStatus": {"buckets": [{"key": "active", "doc_count": 3, "count": {"value": 2}}]}
+			}
+		}`))
+	})
+
+	sb := es.NewSearchBuilder().
+		Query(es.Term("status", "active")).
+		OrderBy("createdAt", "desc").
+		OrderBy("id", "asc").
+		Size(2).
+		Aggregation("perStatus", es.TermsAggregation("status", 10).SubAggregation("count", es.CardinalityAggregation("id")))
+
+	items := make([]repositoryItem, 0)
+	result, err := repo.Search(context.Background(), sb, &items)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []repositoryItem{{Id: "a", Status: "active"}, {Id: "b", Status: "active"}}, items)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, []interface{}{json.Number("9007199254740993"), "b"}, result.SearchAfter, "large sort values have to be kept exactly")
+
+	perStatus := es.BucketAggregationResult{}
+	assert.NoError(t, result.Aggregation("perStatus", &perStatus))
+	assert.Len(t, perStatus.Buckets, 1)
+	assert.Equal(t, "active", perStatus.Buckets[0].Key)
+	assert.Equal(t, 3, perStatus.Buckets[0].DocCount)
+
+	count := es.MetricAggregationResult{}
+	assert.NoError(t, perStatus.Buckets[0].Aggregation("count", &count))
+	assert.Equal(t, mdl.Float64(2), count.Value)
+
+	assert.EqualError(t, result.Aggregation("missing", &count), "there is no result for aggregation missing")
+}
+
+func TestRepository_BulkIndex(t *testing.T) {
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"errors":true,"items":[
+			{"index":{"_id":"a","status":201}},
+			{"index":{"_id":"b","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}
+		]}`))
+	})
+
+	err := repo.BulkIndex(context.Background(), []repositoryItem{{Id: "a"}, {Id: "b"}})
+	assert.EqualError(t, err, "1 of 2 documents failed, first error: can not index document b in index justtrack-test-gosoline-search-item-v1: mapper_parsing_exception: failed to parse")
+
+	bulkErr, ok := err.(*es.BulkError)
+	assert.True(t, ok)
+	assert.Equal(t, "b", bulkErr.Failures[0].Id)
+	assert.Equal(t, http.StatusBadRequest, bulkErr.Failures[0].Status)
+}
+
+func TestRepository_Get(t *testing.T) {
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/justtrack-test-gosoline-search-item-v1/_doc/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"found":false}`))
+
+			return
+		}
+
+		assert.Equal(t, "/justtrack-test-gosoline-search-item-v1/_doc/a", r.URL.Path)
+		_, _ = w.Write([]byte(`{"found":true,"_source":{"id":"a","status":"active"}}`))
+	})
+
+	item := &repositoryItem{}
+	found, err := repo.Get(context.Background(), "a", item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, &repositoryItem{Id: "a", Status: "active"}, item)
+
+	found, err = repo.Get(context.Background(), "missing", item)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRepository_Count(t *testing.T) {
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		assert.Equal(t, "/justtrack-test-gosoline-search-item-v1/_count", r.URL.Path)
+		assert.JSONEq(t, `{"query":{"term":{"status":"active"}}}`, string(body), "the count request only supports the query")
+
+		_, _ = w.Write([]byte(`{"count":42}`))
+	})
+
+	sb := es.NewSearchBuilder().Query(es.Term("status", "active")).Page(10, 5)
+
+	count, err := repo.Count(context.Background(), sb)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestRepository_Scroll(t *testing.T) {
+	var requests []string
+
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("scroll_id"))
+
+		switch len(requests) {
+		case 1:
+			assert.Equal(t, "60000ms", r.URL.Query().Get("scroll"))
+			_, _ = w.Write([]byte(`{"_scroll_id":"s1","hits":{"hits":[{"_source":{"id":"a"}},{"_source":{"id":"b"}}]}}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"_scroll_id":"s2","hits":{"hits":[{"_source":{"id":"c"}}]}}`))
+		case 3:
+			_, _ = w.Write([]byte(`{"_scroll_id":"s2","hits":{"hits":[]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"succeeded":true}`))
+		}
+	})
+
+	ctx := context.Background()
+	scroller := repo.Scroll(ctx, es.NewSearchBuilder().Size(2), time.Minute)
+
+	var all []repositoryItem
+
+	for {
+		items := make([]repositoryItem, 0)
+
+		ok, err := scroller.Next(ctx, &items)
+		assert.NoError(t, err)
+
+		if !ok {
+			break
+		}
+
+		all = append(all, items...)
+	}
+
+	assert.NoError(t, scroller.Close(ctx))
+	assert.Equal(t, []repositoryItem{{Id: "a"}, {Id: "b"}, {Id: "c"}}, all)
+	assert.Equal(t, []string{
+		"GET /justtrack-test-gosoline-search-item-v1/_search ",
+		"GET /_search/scroll s1",
+		"GET /_search/scroll s2",
+		"DELETE /_search/scroll/s2 ",
+	}, requests)
+}
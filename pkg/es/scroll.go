@@ -0,0 +1,119 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A Scroller reads all documents matching a search page by page. The size of the pages is the size of the search.
+type Scroller interface {
+	// Next decodes the next page into result, which has to be a pointer to a slice of the model. It returns false if
+	// there are no more documents.
+	Next(ctx context.Context, result interface{}) (bool, error)
+	// Close releases the search context of the scroll.
+	Close(ctx context.Context) error
+}
+
+type scroller struct {
+	client    *ClientV7
+	index     string
+	sb        *SearchBuilder
+	keepAlive time.Duration
+	scrollId  string
+}
+
+func (s *scroller) Next(ctx context.Context, result interface{}) (bool, error) {
+	var err error
+	var response *searchResponse
+
+	if s.scrollId == "" {
+		response, err = s.search(ctx)
+	} else {
+		response, err = s.scroll(ctx)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	s.scrollId = response.ScrollId
+
+	if len(response.Hits.Hits) == 0 {
+		return false, nil
+	}
+
+	if err = response.decodeHits(result); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *scroller) search(ctx context.Context) (*searchResponse, error) {
+	body, err := encodeBody(s.sb.Build())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.client.Search(
+		s.client.Search.WithContext(ctx),
+		s.client.Search.WithIndex(s.index),
+		s.client.Search.WithBody(body),
+		s.client.Search.WithScroll(s.keepAlive),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can not start scroll in %s: %w", s.index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("can not start scroll in %s: got error from ES: %s", s.index, res.String())
+	}
+
+	return decodeSearchResponse(res.Body)
+}
+
+func (s *scroller) scroll(ctx context.Context) (*searchResponse, error) {
+	res, err := s.client.Scroll(
+		s.client.Scroll.WithContext(ctx),
+		s.client.Scroll.WithScrollID(s.scrollId),
+		s.client.Scroll.WithScroll(s.keepAlive),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can not scroll in %s: %w", s.index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("can not scroll in %s: got error from ES: %s", s.index, res.String())
+	}
+
+	return decodeSearchResponse(res.Body)
+}
+
+func (s *scroller) Close(ctx context.Context) error {
+	if s.scrollId == "" {
+		return nil
+	}
+
+	res, err := s.client.ClearScroll(
+		s.client.ClearScroll.WithContext(ctx),
+		s.client.ClearScroll.WithScrollID(s.scrollId),
+	)
+	if err != nil {
+		return fmt.Errorf("can not clear scroll in %s: %w", s.index, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("can not clear scroll in %s: got error from ES: %s", s.index, res.String())
+	}
+
+	s.scrollId = ""
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package es
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type sortField struct {
+	field     string
+	direction string
+}
+
+// SearchBuilder builds the body of a search request. A page can either be selected by Page or, for deep pagination,
+// by sorting on a unique field and passing the SearchAfter values of the previous result.
+type SearchBuilder struct {
+	query        Query
+	sort         []sortField
+	from         *int
+	size         *int
+	searchAfter  []interface{}
+	aggregations map[string]Aggregation
+}
+
+func NewSearchBuilder() *SearchBuilder {
+	return &SearchBuilder{}
+}
+
+func (b *SearchBuilder) Query(query Query) *SearchBuilder {
+	b.query = query
+
+	return b
+}
+
+func (b *SearchBuilder) OrderBy(field string, direction string) *SearchBuilder {
+	b.sort = append(b.sort, sortField{
+		field:     field,
+		direction: strings.ToLower(direction),
+	})
+
+	return b
+}
+
+func (b *SearchBuilder) Page(offset int, size int) *SearchBuilder {
+	b.from = &offset
+	b.size = &size
+
+	return b
+}
+
+func (b *SearchBuilder) Size(size int) *SearchBuilder {
+	b.size = &size
+
+	return b
+}
+
+func (b *SearchBuilder) SearchAfter(values ...interface{}) *SearchBuilder {
+	b.searchAfter = values
+
+	return b
+}
+
+func (b *SearchBuilder) Aggregation(name string, aggregation Aggregation) *SearchBuilder {
+	if b.aggregations == nil {
+		b.aggregations = make(map[string]Aggregation)
+	}
+
+	b.aggregations[name] = aggregation
+
+	return b
+}
+
+func (b *SearchBuilder) Build() map[string]interface{} {
+	body := map[string]interface{}{
+		"track_total_hits": true,
+	}
+
+	if b.query != nil {
+		body["query"] = b.query.Source()
+	}
+
+	if len(b.sort) > 0 {
+		sort := make([]map[string]interface{}, len(b.sort))
+
+		for i, s := range b.sort {
+			sort[i] = map[string]interface{}{s.field: map[string]interface{}{"order": s.direction}}
+		}
+
+		body["sort"] = sort
+	}
+
+	if b.from != nil {
+		body["from"] = *b.from
+	}
+
+	if b.size != nil {
+		body["size"] = *b.size
+	}
+
+	if len(b.searchAfter) > 0 {
+		body["search_after"] = b.searchAfter
+	}
+
+	if len(b.aggregations) > 0 {
+		body["aggs"] = aggregationSources(b.aggregations)
+	}
+
+	return body
+}
+
+// buildCount returns the body of a count request, which only supports the query.
+func (b *SearchBuilder) buildCount() map[string]interface{} {
+	body := make(map[string]interface{})
+
+	if b.query != nil {
+		body["query"] = b.query.Source()
+	}
+
+	return body
+}
+
+type SearchResult struct {
+	Total int
+	// SearchAfter contains the sort values of the last document to request the next page with
+	SearchAfter  []interface{}
+	aggregations map[string]json.RawMessage
+}
+
+// Aggregation decodes the result of the aggregation with the given name into result, e.g. a MetricAggregationResult
+// or a BucketAggregationResult.
+func (r *SearchResult) Aggregation(name string, result interface{}) error {
+	return decodeAggregation(r.aggregations, name, result)
+}
+
+func decodeAggregation(aggregations map[string]json.RawMessage, name string, result interface{}) error {
+	raw, ok := aggregations[name]
+	if !ok {
+		return fmt.Errorf("there is no result for aggregation %s", name)
+	}
+
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("can not decode result of aggregation %s: %w", name, err)
+	}
+
+	return nil
+}
+
+type searchResponse struct {
+	ScrollId string `json:"_scroll_id"`
+	Hits     struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source json.RawMessage `json:"_source"`
+			Sort   []interface{}   `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]json.RawMessage `json:"aggregations"`
+}
+
+func decodeSearchResponse(body io.Reader) (*searchResponse, error) {
+	response := &searchResponse{}
+
+	// numbers are kept as they are, otherwise large sort values couldn't be passed back with search_after
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(response); err != nil {
+		return nil, fmt.Errorf("can not decode search response: %w", err)
+	}
+
+	return response, nil
+}
+
+// decodeHits decodes the sources of the hits into result, which has to be a pointer to a slice.
+func (r *searchResponse) decodeHits(result interface{}) error {
+	sources := make([]json.RawMessage, len(r.Hits.Hits))
+
+	for i, hit := range r.Hits.Hits {
+		sources[i] = hit.Source
+	}
+
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("can not encode the documents of the search response: %w", err)
+	}
+
+	if err = json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("can not decode the documents of the search response into %T: %w", result, err)
+	}
+
+	return nil
+}
+
+func (r *searchResponse) result() *SearchResult {
+	result := &SearchResult{
+		Total:        r.Hits.Total.Value,
+		aggregations: r.Aggregations,
+	}
+
+	if len(r.Hits.Hits) > 0 {
+		result.SearchAfter = r.Hits.Hits[len(r.Hits.Hits)-1].Sort
+	}
+
+	return result
+}
+
+func encodeBody(body interface{}) (io.Reader, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("can not encode request body: %w", err)
+	}
+
+	return bytes.NewReader(data), nil
+}
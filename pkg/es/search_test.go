@@ -0,0 +1,40 @@
+package es_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchBuilder_Build(t *testing.T) {
+	sb := es.NewSearchBuilder().
+		Query(es.Bool().
+			Filter(es.Term("status", "active"), es.Range("amount").Gte(1).Lt(10)).
+			Must(es.MultiMatch("foo", "name", "description")).
+			MustNot(es.Exists("deletedAt"))).
+		OrderBy("createdAt", "DESC").
+		OrderBy("id", "ASC").
+		Size(20).
+		SearchAfter(1622505600000, "a").
+		Aggregation("perStatus", es.TermsAggregation("status", 5).SubAggregation("total", es.SumAggregation("amount")))
+
+	body, err := json.Marshal(sb.Build())
+	assert.NoError(t, err)
+
+	expected := `{
+		"track_total_hits": true,
+		"query": {"bool": {
+			"filter": [{"term": {"status": "active"}}, {"range": {"amount": {"gte": 1, "lt": 10}}}],
+			"must": [{"multi_match": {"query": "foo", "fields": ["name", "description"]}}],
+			"must_not": [{"exists": {"field": "deletedAt"}}]
+		}},
+		"sort": [{"createdAt": {"order": "desc"}}, {"id": {"order": "asc"}}],
+		"size": 20,
+		"search_after": [1622505600000, "a"],
+		"aggs": {"perStatus": {"terms": {"field": "status", "size": 5}, "aggs": {"total": {"sum": {"field": "amount"}}}}}
+	}`
+
+	assert.JSONEq(t, expected, string(body))
+}
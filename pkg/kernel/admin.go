@@ -0,0 +1,214 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+var (
+	ErrModuleNotFound       = fmt.Errorf("module not found")
+	ErrModuleNotRestartable = fmt.Errorf("module is not restartable")
+	ErrModuleNotRunning     = fmt.Errorf("module is not running")
+)
+
+// A RestartableModule can be restarted while the kernel is running if IsRestartable returns true. A restart cancels
+// the context of the module and calls Run again as soon as the previous call returned.
+//go:generate mockery --name RestartableModule
+type RestartableModule interface {
+	IsRestartable() bool
+}
+
+func isModuleRestartable(m Module) bool {
+	if rm, ok := m.(RestartableModule); ok {
+		return rm.IsRestartable()
+	}
+
+	return false
+}
+
+// A ModuleAdmin provides the state of all modules of the kernel and restarts modules at runtime.
+//go:generate mockery --name ModuleAdmin
+type ModuleAdmin interface {
+	GetModules() []ModuleStatus
+	RestartModule(name string) error
+}
+
+type ModuleStatus struct {
+	Name        string     `json:"name"`
+	Stage       int        `json:"stage"`
+	Type        string     `json:"type"`
+	IsRunning   bool       `json:"is_running"`
+	Restartable bool       `json:"restartable"`
+	Restarts    int        `json:"restarts"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	Uptime      string     `json:"uptime,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type moduleAdminAppctxKey int
+
+// ProvideModuleAdmin returns the module admin of the kernel running with the given context.
+func ProvideModuleAdmin(ctx context.Context) (ModuleAdmin, error) {
+	return provideModuleAdmin(ctx)
+}
+
+func provideModuleAdmin(ctx context.Context) (*moduleAdmin, error) {
+	admin, err := appctx.Provide(ctx, moduleAdminAppctxKey(0), func() (interface{}, error) {
+		return newModuleAdmin(clock.Provider), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not provide module admin: %w", err)
+	}
+
+	return admin.(*moduleAdmin), nil
+}
+
+type moduleAdmin struct {
+	clock   clock.Clock
+	lck     sync.RWMutex
+	modules map[string]*ModuleState
+}
+
+func newModuleAdmin(clock clock.Clock) *moduleAdmin {
+	return &moduleAdmin{
+		clock:   clock,
+		modules: make(map[string]*ModuleState),
+	}
+}
+
+func (a *moduleAdmin) addModule(name string, ms *ModuleState) {
+	a.lck.Lock()
+	defer a.lck.Unlock()
+
+	a.modules[name] = ms
+}
+
+func (a *moduleAdmin) GetModules() []ModuleStatus {
+	a.lck.RLock()
+	defer a.lck.RUnlock()
+
+	now := a.clock.Now()
+	modules := make([]ModuleStatus, 0, len(a.modules))
+
+	for name, ms := range a.modules {
+		ms.lck.Lock()
+
+		status := ModuleStatus{
+			Name:        name,
+			Stage:       ms.Config.Stage,
+			Type:        ms.Config.GetType(),
+			IsRunning:   ms.IsRunning,
+			Restartable: isModuleRestartable(ms.Module),
+			Restarts:    ms.restarts,
+		}
+
+		if !ms.startedAt.IsZero() {
+			startedAt := ms.startedAt
+			status.StartedAt = &startedAt
+		}
+
+		if ms.IsRunning {
+			status.Uptime = now.Sub(ms.startedAt).String()
+		}
+
+		if ms.Err != nil {
+			status.Error = ms.Err.Error()
+		}
+
+		ms.lck.Unlock()
+
+		modules = append(modules, status)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Stage != modules[j].Stage {
+			return modules[i].Stage < modules[j].Stage
+		}
+
+		return modules[i].Name < modules[j].Name
+	})
+
+	return modules
+}
+
+func (a *moduleAdmin) RestartModule(name string) error {
+	a.lck.RLock()
+	ms, ok := a.modules[name]
+	a.lck.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("can not restart module %s: %w", name, ErrModuleNotFound)
+	}
+
+	if !isModuleRestartable(ms.Module) {
+		return fmt.Errorf("can not restart module %s: %w", name, ErrModuleNotRestartable)
+	}
+
+	ms.lck.Lock()
+	defer ms.lck.Unlock()
+
+	if !ms.IsRunning || ms.cancelRun == nil {
+		return fmt.Errorf("can not restart module %s: %w", name, ErrModuleNotRunning)
+	}
+
+	ms.restartRequested = true
+	ms.cancelRun()
+
+	return nil
+}
+
+// runRestartable runs the module until it returns without a restart being requested in the meantime.
+func (a *moduleAdmin) runRestartable(ctx context.Context, logger log.Logger, name string, ms *ModuleState) error {
+	a.setStarted(ms)
+
+	if !isModuleRestartable(ms.Module) {
+		return ms.Module.Run(ctx)
+	}
+
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+
+		ms.lck.Lock()
+		ms.cancelRun = cancel
+		ms.lck.Unlock()
+
+		err := ms.Module.Run(runCtx)
+		cancel()
+
+		ms.lck.Lock()
+		restart := ms.restartRequested && ctx.Err() == nil
+		ms.restartRequested = false
+		ms.cancelRun = nil
+		ms.lck.Unlock()
+
+		if !restart {
+			return err
+		}
+
+		if err != nil {
+			logger.Warn("module %s returned an error during restart: %s", name, err.Error())
+		}
+
+		logger.Info("restarting module %s", name)
+
+		ms.lck.Lock()
+		ms.restarts++
+		ms.lck.Unlock()
+
+		a.setStarted(ms)
+	}
+}
+
+func (a *moduleAdmin) setStarted(ms *ModuleState) {
+	ms.lck.Lock()
+	defer ms.lck.Unlock()
+
+	ms.startedAt = a.clock.Now()
+}
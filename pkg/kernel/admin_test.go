@@ -0,0 +1,97 @@
+package kernel_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type restartableModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+
+	restartable bool
+	runs        int32
+}
+
+func (m *restartableModule) IsRestartable() bool {
+	return m.restartable
+}
+
+func (m *restartableModule) Run(ctx context.Context) error {
+	atomic.AddInt32(&m.runs, 1)
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestKernel_ModuleAdmin(t *testing.T) {
+	ctx := appctx.WithContainer(context.Background())
+	config, _, _ := createMocks()
+	logger := logMocks.NewLoggerMockedAll()
+
+	restartable := &restartableModule{restartable: true}
+	fixed := &restartableModule{}
+
+	k, err := kernel.New(ctx, config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("restartable", moduleFactory(restartable))
+	k.Add("fixed", moduleFactory(fixed))
+
+	admin, err := kernel.ProvideModuleAdmin(ctx)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		k.Run()
+		close(done)
+	}()
+
+	<-k.Running()
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&restartable.runs) == 1 && atomic.LoadInt32(&fixed.runs) == 1
+	}, time.Second, time.Millisecond)
+
+	err = admin.RestartModule("restartable")
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&restartable.runs) == 2
+	}, time.Second, time.Millisecond)
+
+	err = admin.RestartModule("fixed")
+	assert.ErrorIs(t, err, kernel.ErrModuleNotRestartable)
+
+	err = admin.RestartModule("unknown")
+	assert.ErrorIs(t, err, kernel.ErrModuleNotFound)
+
+	modules := admin.GetModules()
+	assert.Len(t, modules, 2)
+
+	assert.Equal(t, "fixed", modules[0].Name)
+	assert.True(t, modules[0].IsRunning)
+	assert.False(t, modules[0].Restartable)
+	assert.Equal(t, 0, modules[0].Restarts)
+
+	assert.Equal(t, "restartable", modules[1].Name)
+	assert.True(t, modules[1].IsRunning)
+	assert.True(t, modules[1].Restartable)
+	assert.Equal(t, 1, modules[1].Restarts)
+	assert.NotNil(t, modules[1].StartedAt)
+
+	k.Stop("test done")
+	<-done
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fixed.runs))
+
+	err = admin.RestartModule("restartable")
+	assert.ErrorIs(t, err, kernel.ErrModuleNotRunning)
+}
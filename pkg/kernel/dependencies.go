@@ -0,0 +1,153 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// resolveDependencies links every module with its dependencies and dependents. It fails if a module depends on
+// an unknown module, on a module in a later stage or if the dependencies contain a cycle.
+func (k *kernel) resolveDependencies() error {
+	all := make(map[string]*ModuleState)
+
+	for _, stage := range k.stages {
+		for name, ms := range stage.modules.modules {
+			all[name] = ms
+		}
+	}
+
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+
+	// sort the names to always report the same error for the same set of modules
+	sort.Strings(names)
+
+	for _, name := range names {
+		ms := all[name]
+
+		for _, depName := range ms.Config.Dependencies {
+			dep, ok := all[depName]
+			if !ok {
+				return fmt.Errorf("module %s depends on the unknown module %s", name, depName)
+			}
+
+			if dep.Config.Stage > ms.Config.Stage {
+				return fmt.Errorf("module %s in stage %d depends on the module %s in the later stage %d", name, ms.Config.Stage, depName, dep.Config.Stage)
+			}
+
+			ms.dependencies[depName] = dep
+			dep.dependents[name] = ms
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	marks := make(map[string]int, len(all))
+	path := make([]string, 0)
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+
+					return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+				}
+			}
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+
+		for _, depName := range all[name].Config.Dependencies {
+			if err := visit(depName); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		marks[name] = visited
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForDependencies blocks until all dependencies of the module are ready. It returns false if the module should
+// not be started anymore, either because the kernel is stopping or because one of the dependencies failed.
+func (k *kernel) waitForDependencies(ctx context.Context, name string, ms *ModuleState) bool {
+	for depName, dep := range ms.dependencies {
+		select {
+		case <-ctx.Done():
+			k.logger.Info("not starting module %s: kernel stopped while waiting for dependency %s", name, depName)
+			return false
+		case <-dep.ready.Channel():
+		}
+
+		if dep.done.Signaled() && dep.Err != nil {
+			k.logger.Warn("not starting module %s: dependency %s failed", name, depName)
+			return false
+		}
+	}
+
+	return true
+}
+
+// signalReady marks the module as ready as soon as it reports readiness or immediately if it does not implement ReadinessModule.
+func (k *kernel) signalReady(ms *ModuleState) {
+	rm, ok := ms.Module.(ReadinessModule)
+	if !ok {
+		ms.ready.Signal()
+		return
+	}
+
+	go func() {
+		select {
+		case <-rm.Ready():
+			ms.ready.Signal()
+		case <-ms.done.Channel():
+		}
+	}()
+}
+
+// stopModules cancels the modules of the stage in reverse dependency order, i.e. a module is only stopped after all
+// modules depending on it have exited.
+func (s *stage) stopModules() {
+	wg := sync.WaitGroup{}
+
+	for _, ms := range s.modules.modules {
+		wg.Add(1)
+
+		go func(ms *ModuleState) {
+			defer wg.Done()
+
+			for _, dependent := range ms.dependents {
+				<-dependent.done.Channel()
+			}
+
+			ms.cancel()
+		}(ms)
+	}
+
+	wg.Wait()
+}
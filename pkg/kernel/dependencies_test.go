@@ -0,0 +1,233 @@
+package kernel_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type eventRecorder struct {
+	lck    sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) record(event string) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+type dependencyModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+
+	name         string
+	dependencies []string
+	ready        chan struct{}
+	recorder     *eventRecorder
+}
+
+func (m *dependencyModule) GetDependencies() []string {
+	return m.dependencies
+}
+
+func (m *dependencyModule) Run(ctx context.Context) error {
+	m.recorder.record("start " + m.name)
+
+	if m.ready != nil {
+		time.Sleep(10 * time.Millisecond)
+		m.recorder.record("ready " + m.name)
+		close(m.ready)
+	}
+
+	<-ctx.Done()
+
+	// give dependencies the chance to stop too early
+	time.Sleep(10 * time.Millisecond)
+	m.recorder.record("stop " + m.name)
+
+	return nil
+}
+
+type readyDependencyModule struct {
+	*dependencyModule
+}
+
+func (m readyDependencyModule) Ready() <-chan struct{} {
+	return m.ready
+}
+
+type failingDependencyModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+}
+
+func (m failingDependencyModule) Run(_ context.Context) error {
+	return fmt.Errorf("dependency failed")
+}
+
+func moduleFactory(module kernel.Module) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return module, nil
+	}
+}
+
+func TestKernel_ModuleDependencies(t *testing.T) {
+	config, logger, _ := createMocks()
+	recorder := &eventRecorder{}
+
+	k, err := kernel.New(context.Background(), config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("worker", moduleFactory(&dependencyModule{
+		name:         "worker",
+		dependencies: []string{"api"},
+		recorder:     recorder,
+	}))
+	k.Add("api", moduleFactory(&dependencyModule{
+		name:     "api",
+		recorder: recorder,
+	}), kernel.ModuleDependsOn("db"))
+	k.Add("db", moduleFactory(readyDependencyModule{
+		dependencyModule: &dependencyModule{
+			name:     "db",
+			ready:    make(chan struct{}),
+			recorder: recorder,
+		},
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		k.Run()
+		close(done)
+	}()
+
+	<-k.Running()
+
+	assert.Eventually(t, func() bool {
+		recorder.lck.Lock()
+		defer recorder.lck.Unlock()
+
+		return len(recorder.events) == 4
+	}, time.Second, time.Millisecond)
+
+	k.Stop("test done")
+	<-done
+
+	assert.Equal(t, []string{
+		"start db",
+		"ready db",
+		"start api",
+		"start worker",
+		"stop worker",
+		"stop api",
+		"stop db",
+	}, recorder.events)
+}
+
+func TestKernel_ModuleDependencyFailed(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Error", "error running %s module %s: %w", "foreground", "db", fmt.Errorf("dependency failed")).Once()
+	logger.On("Error", "error during the execution of stage %d: %w", kernel.StageService, mock.Anything).Once()
+	logger.On("Warn", "not starting module %s: dependency %s failed", "api", "db").Once()
+
+	recorder := &eventRecorder{}
+
+	k, err := kernel.New(context.Background(), config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("worker", moduleFactory(&dependencyModule{
+		name:     "worker",
+		recorder: recorder,
+	}))
+	k.Add("api", moduleFactory(&dependencyModule{
+		name:         "api",
+		dependencies: []string{"db"},
+		recorder:     recorder,
+	}), kernel.ModuleType(kernel.TypeEssential))
+	k.Add("db", moduleFactory(failingDependencyModule{}), kernel.ModuleStage(kernel.StageService))
+
+	done := make(chan struct{})
+	go func() {
+		k.Run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the kernel should stop if an essential module is skipped because of a failed dependency")
+	}
+
+	assert.NotContains(t, recorder.events, "start api")
+	logger.AssertCalled(t, "Warn", "not starting module %s: dependency %s failed", "api", "db")
+}
+
+func TestKernel_ModuleDependenciesInvalid(t *testing.T) {
+	tests := map[string]struct {
+		modules  map[string][]string
+		stages   map[string]int
+		expected string
+	}{
+		"unknown": {
+			modules: map[string][]string{
+				"a": {"b"},
+			},
+			expected: "module a depends on the unknown module b",
+		},
+		"later stage": {
+			modules: map[string][]string{
+				"a": {"b"},
+				"b": nil,
+			},
+			stages: map[string]int{
+				"b": kernel.StageApplication + 1,
+			},
+			expected: fmt.Sprintf("module a in stage %d depends on the module b in the later stage %d", kernel.StageApplication, kernel.StageApplication+1),
+		},
+		"cycle": {
+			modules: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"a"},
+			},
+			expected: "dependency cycle detected: a -> b -> c -> a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, logger, _ := createMocks()
+			logger.On("Error", "error resolving module dependencies: %w", mock.Anything).Once()
+
+			k, err := kernel.New(context.Background(), config, logger, kernel.KillTimeout(time.Second))
+			assert.NoError(t, err)
+
+			for module, dependencies := range test.modules {
+				stage := kernel.StageApplication
+				if s, ok := test.stages[module]; ok {
+					stage = s
+				}
+
+				k.Add(module, moduleFactory(&dependencyModule{
+					name:         module,
+					dependencies: dependencies,
+					recorder:     &eventRecorder{},
+				}), kernel.ModuleStage(stage))
+			}
+
+			k.Run()
+
+			logger.AssertCalled(t, "Error", "error resolving module dependencies: %w", fmt.Errorf(test.expected))
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+)
+
+const (
+	HealthStateBooting  = "booting"
+	HealthStatePending  = "pending"
+	HealthStateRunning  = "running"
+	HealthStateFinished = "finished"
+	HealthStateStopping = "stopping"
+	HealthStateStopped  = "stopped"
+	HealthStateFailed   = "failed"
+)
+
+// A HealthChecker reports whether it is currently able to do its work. Modules implementing HealthChecker are
+// asked by the readiness check of the kernel as long as they are running. A nil error means healthy.
+//go:generate mockery --name HealthChecker
+type HealthChecker interface {
+	IsHealthy(ctx context.Context) error
+}
+
+// HealthCheckFunc turns a function into a HealthChecker.
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) IsHealthy(ctx context.Context) error {
+	return f(ctx)
+}
+
+// A HealthReporter provides the liveness and readiness of the kernel including a breakdown per stage, module
+// and registered health check.
+//go:generate mockery --name HealthReporter
+type HealthReporter interface {
+	Liveness(ctx context.Context) *HealthReport
+	Readiness(ctx context.Context) *HealthReport
+}
+
+type HealthReport struct {
+	Healthy bool                         `json:"healthy"`
+	Kernel  string                       `json:"kernel"`
+	Stages  map[int]string               `json:"stages"`
+	Modules map[string]ModuleHealth      `json:"modules"`
+	Checks  map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+type ModuleHealth struct {
+	Stage   int    `json:"stage"`
+	Type    string `json:"type"`
+	State   string `json:"state"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthCheckResult struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+var healthChecks = struct {
+	lck    sync.Mutex
+	checks map[string]HealthChecker
+}{
+	checks: make(map[string]HealthChecker),
+}
+
+// RegisterHealthCheck adds a check of a dependency (like a database connection) to the readiness check of
+// the kernel. Registering a check with an existing name replaces the previous one.
+func RegisterHealthCheck(name string, check HealthChecker) {
+	healthChecks.lck.Lock()
+	defer healthChecks.lck.Unlock()
+
+	healthChecks.checks[name] = check
+}
+
+func getHealthChecks() map[string]HealthChecker {
+	healthChecks.lck.Lock()
+	defer healthChecks.lck.Unlock()
+
+	checks := make(map[string]HealthChecker, len(healthChecks.checks))
+	for name, check := range healthChecks.checks {
+		checks[name] = check
+	}
+
+	return checks
+}
+
+type healthTrackerAppctxKey int
+
+// ProvideHealthReporter returns the health reporter of the kernel running with the given context.
+func ProvideHealthReporter(ctx context.Context) (HealthReporter, error) {
+	return provideHealthTracker(ctx)
+}
+
+func provideHealthTracker(ctx context.Context) (*healthTracker, error) {
+	tracker, err := appctx.Provide(ctx, healthTrackerAppctxKey(0), func() (interface{}, error) {
+		return newHealthTracker(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not provide health tracker: %w", err)
+	}
+
+	return tracker.(*healthTracker), nil
+}
+
+type moduleHealthState struct {
+	module Module
+	config ModuleConfig
+	state  string
+	err    error
+}
+
+type healthTracker struct {
+	lck     sync.RWMutex
+	state   string
+	stages  map[int]string
+	modules map[string]*moduleHealthState
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		state:   HealthStateBooting,
+		stages:  make(map[int]string),
+		modules: make(map[string]*moduleHealthState),
+	}
+}
+
+func (t *healthTracker) setKernelState(state string) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	// a failed kernel stays failed, even if it is stopped afterwards
+	if t.state == HealthStateFailed {
+		return
+	}
+
+	t.state = state
+}
+
+func (t *healthTracker) setStageState(stage int, state string) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	t.stages[stage] = state
+}
+
+func (t *healthTracker) addModule(name string, module Module, config ModuleConfig) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	if _, ok := t.stages[config.Stage]; !ok {
+		t.stages[config.Stage] = HealthStatePending
+	}
+
+	t.modules[name] = &moduleHealthState{
+		module: module,
+		config: config,
+		state:  HealthStatePending,
+	}
+}
+
+func (t *healthTracker) setModuleState(name string, state string, err error) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	if ms, ok := t.modules[name]; ok {
+		ms.state = state
+		ms.err = err
+	}
+}
+
+// Liveness reports the kernel as healthy as long as neither the kernel nor one of its modules failed.
+// Health checks are not part of the liveness, an unavailable dependency should not cause a restart.
+func (t *healthTracker) Liveness(_ context.Context) *HealthReport {
+	report, _ := t.snapshot()
+	report.Healthy = report.Kernel != HealthStateFailed
+
+	for _, module := range report.Modules {
+		report.Healthy = report.Healthy && module.Healthy
+	}
+
+	return report
+}
+
+// Readiness reports the kernel as healthy if all stages are up and running, no module failed and all
+// health checks of running modules and registered dependencies succeed.
+func (t *healthTracker) Readiness(ctx context.Context) *HealthReport {
+	report, checkers := t.snapshot()
+	report.Healthy = report.Kernel == HealthStateRunning
+
+	for name, checker := range checkers {
+		if err := checker.IsHealthy(ctx); err != nil {
+			module := report.Modules[name]
+			module.Healthy = false
+			module.Error = err.Error()
+			report.Modules[name] = module
+		}
+	}
+
+	for _, module := range report.Modules {
+		report.Healthy = report.Healthy && module.Healthy
+	}
+
+	report.Checks = make(map[string]HealthCheckResult)
+
+	for name, check := range getHealthChecks() {
+		result := HealthCheckResult{
+			Healthy: true,
+		}
+
+		if err := check.IsHealthy(ctx); err != nil {
+			result.Healthy = false
+			result.Error = err.Error()
+		}
+
+		report.Checks[name] = result
+		report.Healthy = report.Healthy && result.Healthy
+	}
+
+	return report
+}
+
+// snapshot copies the current state into a report and returns the health checkers of all running modules.
+func (t *healthTracker) snapshot() (*HealthReport, map[string]HealthChecker) {
+	t.lck.RLock()
+	defer t.lck.RUnlock()
+
+	report := &HealthReport{
+		Kernel:  t.state,
+		Stages:  make(map[int]string, len(t.stages)),
+		Modules: make(map[string]ModuleHealth, len(t.modules)),
+	}
+	checkers := make(map[string]HealthChecker)
+
+	for stage, state := range t.stages {
+		report.Stages[stage] = state
+	}
+
+	for name, ms := range t.modules {
+		module := ModuleHealth{
+			Stage:   ms.config.Stage,
+			Type:    ms.config.GetType(),
+			State:   ms.state,
+			Healthy: ms.state != HealthStateFailed,
+		}
+
+		if ms.err != nil {
+			module.Error = ms.err.Error()
+		}
+
+		report.Modules[name] = module
+
+		if checker, ok := ms.module.(HealthChecker); ok && ms.state == HealthStateRunning {
+			checkers[name] = checker
+		}
+	}
+
+	return report, checkers
+}
@@ -0,0 +1,148 @@
+package kernel_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type healthCheckedModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+	healthErr error
+}
+
+func (m *healthCheckedModule) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	return nil
+}
+
+func (m *healthCheckedModule) IsHealthy(_ context.Context) error {
+	return m.healthErr
+}
+
+type failingModule struct {
+	kernel.BackgroundModule
+	kernel.ServiceStage
+}
+
+func (m failingModule) Run(_ context.Context) error {
+	return fmt.Errorf("input died")
+}
+
+func TestKernel_Health(t *testing.T) {
+	ctx := appctx.WithContainer(context.Background())
+	config, _, _ := createMocks()
+	logger := logMocks.NewLoggerMockedAll()
+
+	checked := &healthCheckedModule{
+		healthErr: fmt.Errorf("not connected"),
+	}
+
+	k, err := kernel.New(ctx, config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("checked", func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return checked, nil
+	})
+
+	reporter, err := kernel.ProvideHealthReporter(ctx)
+	assert.NoError(t, err)
+
+	report := reporter.Readiness(ctx)
+	assert.False(t, report.Healthy, "a booting kernel should not be ready")
+	assert.Equal(t, kernel.HealthStateBooting, report.Kernel)
+	assert.True(t, reporter.Liveness(ctx).Healthy, "a booting kernel should be alive")
+
+	done := make(chan struct{})
+	go func() {
+		k.Run()
+		close(done)
+	}()
+
+	<-k.Running()
+
+	assert.Eventually(t, func() bool {
+		return reporter.Liveness(ctx).Modules["checked"].State == kernel.HealthStateRunning
+	}, time.Second, time.Millisecond)
+
+	report = reporter.Readiness(ctx)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, kernel.HealthStateRunning, report.Kernel)
+	assert.Equal(t, map[int]string{kernel.StageApplication: kernel.HealthStateRunning}, report.Stages)
+	assert.Equal(t, kernel.ModuleHealth{
+		Stage:   kernel.StageApplication,
+		Type:    "foreground",
+		State:   kernel.HealthStateRunning,
+		Healthy: false,
+		Error:   "not connected",
+	}, report.Modules["checked"])
+
+	checked.healthErr = nil
+	assert.True(t, reporter.Readiness(ctx).Healthy)
+
+	kernel.RegisterHealthCheck("kernel_test", kernel.HealthCheckFunc(func(ctx context.Context) error {
+		return fmt.Errorf("connection refused")
+	}))
+	defer kernel.RegisterHealthCheck("kernel_test", kernel.HealthCheckFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	report = reporter.Readiness(ctx)
+	assert.False(t, report.Healthy, "failing dependencies should make the kernel unready")
+	assert.Equal(t, kernel.HealthCheckResult{Healthy: false, Error: "connection refused"}, report.Checks["kernel_test"])
+	assert.True(t, reporter.Liveness(ctx).Healthy, "failing dependencies should not affect the liveness")
+
+	k.Stop("test done")
+	<-done
+
+	assert.Equal(t, kernel.HealthStateStopped, reporter.Liveness(ctx).Kernel)
+	assert.Equal(t, kernel.HealthStateFinished, reporter.Liveness(ctx).Modules["checked"].State)
+}
+
+func TestKernel_HealthFailedModule(t *testing.T) {
+	ctx := appctx.WithContainer(context.Background())
+	config, _, _ := createMocks()
+	logger := logMocks.NewLoggerMockedAll()
+
+	k, err := kernel.New(ctx, config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("checked", func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return &healthCheckedModule{}, nil
+	})
+	k.Add("failing", func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		return failingModule{}, nil
+	})
+
+	reporter, err := kernel.ProvideHealthReporter(ctx)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		k.Run()
+		close(done)
+	}()
+
+	<-k.Running()
+
+	assert.Eventually(t, func() bool {
+		return reporter.Liveness(ctx).Modules["failing"].State == kernel.HealthStateFailed
+	}, time.Second, time.Millisecond)
+
+	report := reporter.Liveness(ctx)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, "input died", report.Modules["failing"].Error)
+
+	k.Stop("test done")
+	<-done
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
 	"github.com/justtrackio/gosoline/pkg/coffin"
 	"github.com/justtrackio/gosoline/pkg/conc"
 	"github.com/justtrackio/gosoline/pkg/log"
@@ -43,6 +44,8 @@ type kernel struct {
 	middlewares           []Middleware
 
 	ctx               context.Context
+	health            *healthTracker
+	admin             *moduleAdmin
 	stages            map[int]*stage
 	stagesLck         conc.PoisonedLock
 	started           conc.PoisonedLock
@@ -72,6 +75,18 @@ func New(ctx context.Context, config cfg.Config, logger log.Logger, options ...O
 		forceExit:   os.Exit,
 	}
 
+	var err error
+
+	// the tracker is shared via the application context, so the health check module is able to report the state of
+	// the kernel. Kernels without an application context still track their state, it is just not reported anywhere.
+	if k.health, err = provideHealthTracker(ctx); err != nil {
+		k.health = newHealthTracker()
+	}
+
+	if k.admin, err = provideModuleAdmin(ctx); err != nil {
+		k.admin = newModuleAdmin(clock.Provider)
+	}
+
 	if err := k.Option(options...); err != nil {
 		return nil, fmt.Errorf("failed to configure kernel: %w", err)
 	}
@@ -125,29 +140,40 @@ func (k *kernel) Run() {
 
 	if err := k.runMultiFactories(k.ctx); err != nil {
 		k.logger.Error("error building additional modules by multiFactories: %w", err)
+		k.health.setKernelState(HealthStateFailed)
 		close(k.running)
 		return
 	}
 
 	if len(k.moduleSetupContainers) == 0 {
 		k.logger.Warn("nothing to run")
+		k.health.setKernelState(HealthStateStopped)
 		close(k.running)
 		return
 	}
 
 	if err := k.runFactories(k.ctx); err != nil {
 		k.logger.Error("error building modules: %w", err)
+		k.health.setKernelState(HealthStateFailed)
 		close(k.running)
 		return
 	}
 
 	k.logger.Info("all modules created")
 
+	if err := k.resolveDependencies(); err != nil {
+		k.logger.Error("error resolving module dependencies: %w", err)
+		k.health.setKernelState(HealthStateFailed)
+		close(k.running)
+		return
+	}
+
 	// poison our stages so any other thread trying to add a new stage will
 	// panic instead of hanging
 	k.stagesLck.Poison()
 
 	if !k.hasModules() {
+		k.health.setKernelState(HealthStateStopped)
 		close(k.running)
 		k.logger.Info("nothing to run")
 		return
@@ -156,6 +182,7 @@ func (k *kernel) Run() {
 	k.foregroundModules = int32(k.countForegroundModules())
 	if k.foregroundModules == 0 {
 		k.logger.Info("no foreground modules")
+		k.health.setKernelState(HealthStateStopped)
 		return
 	}
 
@@ -167,9 +194,11 @@ func (k *kernel) Run() {
 
 		for _, stageIndex := range k.getStageIndices() {
 			k.stages[stageIndex].run(k)
+			k.health.setStageState(stageIndex, HealthStateRunning)
 			k.logger.Info("stage %d up and running", stageIndex)
 		}
 
+		k.health.setKernelState(HealthStateRunning)
 		k.logger.Info("kernel up and running")
 		close(k.running)
 
@@ -195,14 +224,19 @@ func (k *kernel) Stop(reason string) {
 	k.stopped.Do(func() {
 		go func() {
 			k.logger.Info("stopping kernel due to: %s", reason)
+			k.health.setKernelState(HealthStateStopping)
 			indices := k.getStageIndices()
 
 			for i := len(indices) - 1; i >= 0; i-- {
 				stageIndex := indices[i]
 				k.logger.Info("stopping stage %d", stageIndex)
+				k.health.setStageState(stageIndex, HealthStateStopping)
 				k.stages[stageIndex].stopWait(stageIndex, k.logger)
+				k.health.setStageState(stageIndex, HealthStateStopped)
 				k.logger.Info("stopped stage %d", stageIndex)
 			}
+
+			k.health.setKernelState(HealthStateStopped)
 		}()
 	})
 }
@@ -277,6 +311,11 @@ func (k *kernel) addModuleToStage(name string, module Module, opts []ModuleOptio
 		Config:    getModuleConfig(module),
 		IsRunning: false,
 		Err:       nil,
+
+		ready:        conc.NewSignalOnce(),
+		done:         conc.NewSignalOnce(),
+		dependencies: make(map[string]*ModuleState),
+		dependents:   make(map[string]*ModuleState),
 	}
 
 	MergeOptions(opts)(&ms.Config)
@@ -306,6 +345,8 @@ func (k *kernel) addModuleToStage(name string, module Module, opts []ModuleOptio
 	}
 
 	stage.modules.modules[name] = ms
+	k.health.addModule(name, module, ms.Config)
+	k.admin.addModule(name, ms)
 
 	return nil
 }
@@ -352,11 +393,28 @@ func (k *kernel) debugConfig() {
 }
 
 func (k *kernel) runModule(ctx context.Context, name string, ms *ModuleState) (moduleErr error) {
+	// signal ready on exit, too, so dependent modules waiting for us don't block forever
+	defer ms.done.Signal()
+	defer ms.ready.Signal()
+
+	if !k.waitForDependencies(ctx, name, ms) {
+		// a skipped module counts as exited, otherwise the kernel would wait for it forever
+		k.health.setModuleState(name, HealthStateFinished, nil)
+		k.moduleExited(name, ms)
+
+		return nil
+	}
+
 	defer k.logger.Info("stopped %s module %s", ms.Config.GetType(), name)
 
 	k.logger.Info("running %s module %s in stage %d", ms.Config.GetType(), name, ms.Config.Stage)
 
+	ms.lck.Lock()
 	ms.IsRunning = true
+	ms.lck.Unlock()
+
+	k.health.setModuleState(name, HealthStateRunning, nil)
+	k.signalReady(ms)
 
 	defer func(ms *ModuleState) {
 		// recover any crash from the module - if we let the coffin handle this,
@@ -364,28 +422,44 @@ func (k *kernel) runModule(ctx context.Context, name string, ms *ModuleState) (m
 		// swallowed the error
 		panicErr := coffin.ResolveRecovery(recover())
 
+		ms.lck.Lock()
 		if panicErr != nil {
 			ms.Err = panicErr
 		}
+		ms.IsRunning = false
+		ms.lck.Unlock()
 
 		if ms.Err != nil {
 			k.logger.Error("error running %s module %s: %w", ms.Config.GetType(), name, ms.Err)
 		}
 
-		ms.IsRunning = false
-		if ms.Config.Essential {
-			k.essentialModuleExited(name)
-		} else if !ms.Config.Background {
-			k.foregroundModuleExited()
+		if ms.Err != nil {
+			k.health.setModuleState(name, HealthStateFailed, ms.Err)
+		} else {
+			k.health.setModuleState(name, HealthStateFinished, nil)
 		}
 
+		k.moduleExited(name, ms)
+
 		// make sure we are returning the correct error to our caller
 		moduleErr = ms.Err
 	}(ms)
 
-	ms.Err = ms.Module.Run(ctx)
+	err := k.admin.runRestartable(ctx, k.logger, name, ms)
+
+	ms.lck.Lock()
+	ms.Err = err
+	ms.lck.Unlock()
 
-	return ms.Err
+	return err
+}
+
+func (k *kernel) moduleExited(name string, ms *ModuleState) {
+	if ms.Config.Essential {
+		k.essentialModuleExited(name)
+	} else if !ms.Config.Background {
+		k.foregroundModuleExited()
+	}
 }
 
 func (k *kernel) essentialModuleExited(name string) {
@@ -2,8 +2,11 @@ package kernel
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/conc"
 	"github.com/justtrackio/gosoline/pkg/kernel/common"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
@@ -57,11 +60,20 @@ func getModuleStage(m Module) int {
 	return StageApplication
 }
 
+func getModuleDependencies(m Module) []string {
+	if dm, ok := m.(DependentModule); ok {
+		return dm.GetDependencies()
+	}
+
+	return nil
+}
+
 func getModuleConfig(m Module) ModuleConfig {
 	return ModuleConfig{
-		Essential:  isModuleEssential(m),
-		Background: isModuleBackground(m),
-		Stage:      getModuleStage(m),
+		Essential:    isModuleEssential(m),
+		Background:   isModuleBackground(m),
+		Stage:        getModuleStage(m),
+		Dependencies: getModuleDependencies(m),
 	}
 }
 
@@ -71,12 +83,26 @@ type ModuleState struct {
 	Config    ModuleConfig
 	IsRunning bool
 	Err       error
+
+	ctx          context.Context
+	cancel       context.CancelFunc
+	ready        conc.SignalOnce
+	done         conc.SignalOnce
+	dependencies map[string]*ModuleState
+	dependents   map[string]*ModuleState
+
+	lck              sync.Mutex
+	startedAt        time.Time
+	restarts         int
+	restartRequested bool
+	cancelRun        context.CancelFunc
 }
 
 type ModuleConfig struct {
-	Essential  bool
-	Background bool
-	Stage      int
+	Essential    bool
+	Background   bool
+	Stage        int
+	Dependencies []string
 }
 
 func (mc ModuleConfig) GetType() string {
@@ -143,6 +169,21 @@ type StagedModule interface {
 	GetStage() int
 }
 
+// A DependentModule declares the names of the modules it depends on. The kernel starts a dependent module
+// only after all of its dependencies are ready and stops it before any of its dependencies. A module can
+// only depend on modules in the same or an earlier stage.
+//go:generate mockery --name DependentModule
+type DependentModule interface {
+	GetDependencies() []string
+}
+
+// A ReadinessModule signals the kernel when it is ready to be used by the modules depending on it by closing
+// the channel returned by Ready. Modules not implementing ReadinessModule are ready as soon as they are started.
+//go:generate mockery --name ReadinessModule
+type ReadinessModule interface {
+	Ready() <-chan struct{}
+}
+
 // A FullModule provides all the methods a module can have and thus never relies on defaults.
 //go:generate mockery --name FullModule
 type FullModule interface {
@@ -29,6 +29,16 @@ func ModuleStage(moduleStage int) ModuleOption {
 	}
 }
 
+// Declare additional modules the module depends on. The module is started after
+// all of its dependencies are ready and stopped before its dependencies, e.g.
+//
+// k.Add("your module", NewYourModule(), kernel.ModuleDependsOn("database-migrations"))
+func ModuleDependsOn(names ...string) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.Dependencies = append(ms.Dependencies, names...)
+	}
+}
+
 // Combine a list of options by applying them in order.
 func MergeOptions(options []ModuleOption) ModuleOption {
 	return func(ms *ModuleConfig) {
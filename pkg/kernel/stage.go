@@ -12,10 +12,12 @@ import (
 var ErrKernelStopping = fmt.Errorf("stopping kernel")
 
 type stage struct {
-	cfn coffin.Coffin
-	ctx context.Context
+	cfn    coffin.Coffin
+	ctx    context.Context
+	parent context.Context
 
 	running    conc.SignalOnce
+	stopping   conc.SignalOnce
 	terminated conc.SignalOnce
 
 	modules modules
@@ -27,13 +29,15 @@ type modules struct {
 }
 
 func newStage(ctx context.Context) *stage {
-	cfn, ctx := coffin.WithContext(ctx)
+	cfn, cfnCtx := coffin.WithContext(ctx)
 
 	return &stage{
-		cfn: cfn,
-		ctx: ctx,
+		cfn:    cfn,
+		ctx:    cfnCtx,
+		parent: ctx,
 
 		running:    conc.NewSignalOnce(),
+		stopping:   conc.NewSignalOnce(),
 		terminated: conc.NewSignalOnce(),
 
 		modules: modules{
@@ -47,6 +51,9 @@ func (s *stage) run(k *kernel) {
 	s.modules.lck.Poison()
 
 	for name, ms := range s.modules.modules {
+		// every module gets its own context, so we can stop the modules in reverse dependency order
+		ms.ctx, ms.cancel = context.WithCancel(s.parent)
+
 		s.cfn.Gof(func(name string, ms *ModuleState) func() error {
 			return func() error {
 				// wait until every routine of the stage was spawned
@@ -55,16 +62,44 @@ func (s *stage) run(k *kernel) {
 				// new routine may be added after the last one exited)
 				<-s.running.Channel()
 
-				return k.runModule(s.ctx, name, ms)
+				return k.runModule(ms.ctx, name, ms)
 			}
 		}(name, ms), "panic during running of module %s", name)
 	}
 
+	go s.cancelOnError()
+
 	s.running.Signal()
 }
 
+// cancelOnError stops all modules of the stage at once if the stage dies without being stopped by the kernel,
+// e.g. because one of its modules failed.
+func (s *stage) cancelOnError() {
+	<-s.ctx.Done()
+
+	if s.stopping.Signaled() {
+		return
+	}
+
+	for _, ms := range s.modules.modules {
+		ms.cancel()
+	}
+}
+
 func (s *stage) stopWait(stageIndex int, logger log.Logger) {
+	s.stopping.Signal()
 	s.cfn.Kill(ErrKernelStopping)
+
+	// the modules are only spawned if the stage was started. Otherwise, we mark them as done,
+	// so modules of earlier stages depending on them can be stopped
+	if s.running.Signaled() {
+		s.stopModules()
+	} else {
+		for _, ms := range s.modules.modules {
+			ms.done.Signal()
+		}
+	}
+
 	err := s.cfn.Wait()
 
 	if err != nil && err != ErrKernelStopping {
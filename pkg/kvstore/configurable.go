@@ -39,23 +39,25 @@ type InMemoryConfiguration struct {
 	GetsPerPromote int32  `cfg:"gets_per_promote" default:"3"`
 }
 
-func NewConfigurableKvStore(ctx context.Context, config cfg.Config, logger log.Logger, name string) (KvStore, error) {
+// NewConfigurableKvStore creates the store configured at kvstore.<name>. The additional defaults are applied to the
+// configuration of the store before the configured values, e.g. to provide a ttl matching the use of the store.
+func NewConfigurableKvStore(ctx context.Context, config cfg.Config, logger log.Logger, name string, additionalDefaults ...cfg.UnmarshalDefaults) (KvStore, error) {
 	key := fmt.Sprintf("kvstore.%s.type", name)
 	t := config.GetString(key)
 
 	switch t {
 	case TypeChain:
-		return newKvStoreChainFromConfig(ctx, config, logger, name)
+		return newKvStoreChainFromConfig(ctx, config, logger, name, additionalDefaults)
 	}
 
 	return nil, fmt.Errorf("invalid kvstore %s of type %s", name, t)
 }
 
-func newKvStoreChainFromConfig(ctx context.Context, config cfg.Config, logger log.Logger, name string) (KvStore, error) {
+func newKvStoreChainFromConfig(ctx context.Context, config cfg.Config, logger log.Logger, name string, additionalDefaults []cfg.UnmarshalDefaults) (KvStore, error) {
 	key := GetConfigurableKey(name)
 
 	configuration := ChainConfiguration{}
-	config.UnmarshalKey(key, &configuration)
+	config.UnmarshalKey(key, &configuration, additionalDefaults...)
 
 	store, err := NewChainKvStore(ctx, config, logger, configuration.MissingCacheEnabled, &Settings{
 		AppId: cfg.AppId{
@@ -118,7 +120,7 @@ func ResetConfigurableKvStores() {
 	configurableKvStores = map[string]KvStore{}
 }
 
-func ProvideConfigurableKvStore(ctx context.Context, config cfg.Config, logger log.Logger, name string) (KvStore, error) {
+func ProvideConfigurableKvStore(ctx context.Context, config cfg.Config, logger log.Logger, name string, additionalDefaults ...cfg.UnmarshalDefaults) (KvStore, error) {
 	configurableKvStoreLock.Lock()
 	defer configurableKvStoreLock.Unlock()
 
@@ -127,7 +129,7 @@ func ProvideConfigurableKvStore(ctx context.Context, config cfg.Config, logger l
 	}
 
 	var err error
-	configurableKvStores[name], err = NewConfigurableKvStore(ctx, config, logger, name)
+	configurableKvStores[name], err = NewConfigurableKvStore(ctx, config, logger, name, additionalDefaults...)
 
 	if err != nil {
 		return nil, err
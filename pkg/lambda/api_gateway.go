@@ -0,0 +1,118 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gin-gonic/gin"
+	"github.com/justtrackio/gosoline/pkg/apiserver"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// NewApiGatewayHandlerFactory creates a lambda handler for API Gateway proxy events which routes every request
+// through the same gin router an apiserver.ApiServer would build from the given definer.
+func NewApiGatewayHandlerFactory(definer apiserver.Definer) HandlerFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (interface{}, error) {
+		logger = logger.WithChannel("api")
+		gin.SetMode(gin.ReleaseMode)
+
+		definitions, err := definer(ctx, config, logger.WithChannel("handler"))
+		if err != nil {
+			return nil, fmt.Errorf("could not define routes: %w", err)
+		}
+
+		router := gin.New()
+		router.Use(apiserver.RecoveryWithSentry(logger))
+		router.Use(apiserver.LoggingMiddleware(logger))
+
+		apiserver.BuildRouter(definitions, router)
+
+		return NewApiGatewayHandlerWithInterfaces(router).Handle, nil
+	}
+}
+
+type ApiGatewayHandler struct {
+	handler http.Handler
+}
+
+func NewApiGatewayHandlerWithInterfaces(handler http.Handler) *ApiGatewayHandler {
+	return &ApiGatewayHandler{
+		handler: handler,
+	}
+}
+
+func (h *ApiGatewayHandler) Handle(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	req, err := h.buildRequest(ctx, event)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	recorder := httptest.NewRecorder()
+	h.handler.ServeHTTP(recorder, req)
+
+	response := events.APIGatewayProxyResponse{
+		StatusCode:        recorder.Code,
+		MultiValueHeaders: recorder.Header(),
+		Body:              recorder.Body.String(),
+	}
+
+	return response, nil
+}
+
+func (h *ApiGatewayHandler) buildRequest(ctx context.Context, event events.APIGatewayProxyRequest) (*http.Request, error) {
+	body := []byte(event.Body)
+
+	if event.IsBase64Encoded {
+		var err error
+
+		if body, err = base64.StdEncoding.DecodeString(event.Body); err != nil {
+			return nil, fmt.Errorf("can not decode base64 encoded request body: %w", err)
+		}
+	}
+
+	query := url.Values{}
+
+	for key, value := range event.QueryStringParameters {
+		query.Set(key, value)
+	}
+
+	for key, values := range event.MultiValueQueryStringParameters {
+		query[key] = values
+	}
+
+	target := &url.URL{
+		Path:     event.Path,
+		RawQuery: query.Encode(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, event.HTTPMethod, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("can not create http request from api gateway event: %w", err)
+	}
+
+	for key, value := range event.Headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, values := range event.MultiValueHeaders {
+		req.Header.Del(key)
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if event.RequestContext.Identity.SourceIP != "" {
+		req.RemoteAddr = net.JoinHostPort(event.RequestContext.Identity.SourceIP, "0")
+	}
+
+	return req, nil
+}
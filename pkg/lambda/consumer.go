@@ -0,0 +1,240 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/hashicorp/go-multierror"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/coffin"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+const (
+	AttributeSnsMessageId          = "snsMessageId"
+	AttributeKinesisPartitionKey   = stream.AttributeKinesisPartitionKey
+	AttributeKinesisSequenceNumber = "kinesisSequenceNumber"
+)
+
+type ConsumerSettings struct {
+	Encoding     stream.EncodingType `cfg:"encoding" default:"application/json"`
+	Unmarshaller string              `cfg:"unmarshaller" default:"msg"`
+}
+
+func readConsumerSettings(config cfg.Config, name string) *ConsumerSettings {
+	settings := &ConsumerSettings{}
+	config.UnmarshalKey(fmt.Sprintf("lambda.consumer.%s", name), settings)
+
+	return settings
+}
+
+// consumerHandler feeds stream messages extracted from lambda events into a stream.ConsumerCallback
+// the same way a stream.Consumer does inside of a long-running service.
+type consumerHandler struct {
+	logger       log.Logger
+	encoder      stream.MessageEncoder
+	unmarshaller stream.UnmarshallerFunc
+	callback     stream.ConsumerCallback
+}
+
+func newConsumerHandler(ctx context.Context, config cfg.Config, logger log.Logger, name string, callbackFactory stream.ConsumerCallbackFactory) (*consumerHandler, error) {
+	var err error
+	var callback stream.ConsumerCallback
+	var unmarshaller stream.UnmarshallerFunc
+
+	settings := readConsumerSettings(config, name)
+	logger = logger.WithChannel("consumer")
+
+	if callback, err = callbackFactory(ctx, config, logger.WithChannel("consumerCallback")); err != nil {
+		return nil, fmt.Errorf("can not initiate callback for consumer %s: %w", name, err)
+	}
+
+	if unmarshaller, err = stream.ProvideUnmarshaller(settings.Unmarshaller); err != nil {
+		return nil, fmt.Errorf("can not initiate consumer %s: %w", name, err)
+	}
+
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: settings.Encoding,
+	})
+
+	return NewConsumerHandlerWithInterfaces(logger, encoder, unmarshaller, callback), nil
+}
+
+func NewConsumerHandlerWithInterfaces(logger log.Logger, encoder stream.MessageEncoder, unmarshaller stream.UnmarshallerFunc, callback stream.ConsumerCallback) *consumerHandler {
+	return &consumerHandler{
+		logger:       logger,
+		encoder:      encoder,
+		unmarshaller: unmarshaller,
+		callback:     callback,
+	}
+}
+
+// NewSqsHandlerFactory creates a lambda handler for SQS events. The body of every record is unmarshalled
+// with the unmarshaller configured at lambda.consumer.<name>.unmarshaller (msg, raw or sns) and passed to the callback.
+func NewSqsHandlerFactory(name string, callbackFactory stream.ConsumerCallbackFactory) HandlerFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (interface{}, error) {
+		handler, err := newConsumerHandler(ctx, config, logger, name, callbackFactory)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler.HandleSqsEvent, nil
+	}
+}
+
+// NewSnsHandlerFactory creates a lambda handler for SNS events published by a stream.Output of type sns.
+func NewSnsHandlerFactory(name string, callbackFactory stream.ConsumerCallbackFactory) HandlerFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (interface{}, error) {
+		handler, err := newConsumerHandler(ctx, config, logger, name, callbackFactory)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler.HandleSnsEvent, nil
+	}
+}
+
+// NewKinesisHandlerFactory creates a lambda handler for Kinesis events published by a stream.Output of type kinesis.
+func NewKinesisHandlerFactory(name string, callbackFactory stream.ConsumerCallbackFactory) HandlerFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (interface{}, error) {
+		handler, err := newConsumerHandler(ctx, config, logger, name, callbackFactory)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler.HandleKinesisEvent, nil
+	}
+}
+
+func (h *consumerHandler) HandleSqsEvent(ctx context.Context, event events.SQSEvent) error {
+	result := &multierror.Error{}
+
+	for _, record := range event.Records {
+		body := record.Body
+		msg, err := h.unmarshaller(&body)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not unmarshal sqs message %s: %w", record.MessageId, err))
+			continue
+		}
+
+		msg.Attributes = ensureAttributes(msg.Attributes)
+		msg.Attributes[stream.AttributeSqsMessageId] = record.MessageId
+
+		if err = h.handle(ctx, msg); err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not handle sqs message %s: %w", record.MessageId, err))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (h *consumerHandler) HandleSnsEvent(ctx context.Context, event events.SNSEvent) error {
+	result := &multierror.Error{}
+
+	for _, record := range event.Records {
+		msg := &stream.Message{}
+
+		if err := msg.UnmarshalFromString(record.SNS.Message); err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not unmarshal sns message %s: %w", record.SNS.MessageID, err))
+			continue
+		}
+
+		msg.Attributes = ensureAttributes(msg.Attributes)
+		msg.Attributes[AttributeSnsMessageId] = record.SNS.MessageID
+
+		if err := h.handle(ctx, msg); err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not handle sns message %s: %w", record.SNS.MessageID, err))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (h *consumerHandler) HandleKinesisEvent(ctx context.Context, event events.KinesisEvent) error {
+	result := &multierror.Error{}
+
+	for _, record := range event.Records {
+		msg := &stream.Message{}
+
+		if err := json.Unmarshal(record.Kinesis.Data, msg); err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not unmarshal kinesis record %s: %w", record.EventID, err))
+			continue
+		}
+
+		msg.Attributes = ensureAttributes(msg.Attributes)
+		msg.Attributes[AttributeKinesisPartitionKey] = record.Kinesis.PartitionKey
+		msg.Attributes[AttributeKinesisSequenceNumber] = record.Kinesis.SequenceNumber
+
+		if err := h.handle(ctx, msg); err != nil {
+			result = multierror.Append(result, fmt.Errorf("can not handle kinesis record %s: %w", record.EventID, err))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (h *consumerHandler) handle(ctx context.Context, msg *stream.Message) error {
+	if _, ok := msg.Attributes[stream.AttributeAggregate]; !ok {
+		return h.process(ctx, msg)
+	}
+
+	var err error
+	batch := make([]*stream.Message, 0)
+
+	if ctx, _, err = h.encoder.Decode(ctx, msg, &batch); err != nil {
+		return fmt.Errorf("an error occurred during disaggregation of the message: %w", err)
+	}
+
+	result := &multierror.Error{}
+
+	for _, m := range batch {
+		if err = h.process(ctx, m); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (h *consumerHandler) process(ctx context.Context, msg *stream.Message) (err error) {
+	defer func() {
+		if recovered := coffin.ResolveRecovery(recover()); recovered != nil {
+			err = recovered
+		}
+	}()
+
+	var ack bool
+	var model interface{}
+	var attributes map[string]interface{}
+
+	if model = h.callback.GetModel(msg.Attributes); model == nil {
+		return fmt.Errorf("can not get model for message attributes %v", msg.Attributes)
+	}
+
+	if ctx, attributes, err = h.encoder.Decode(ctx, msg, model); err != nil {
+		return fmt.Errorf("an error occurred during the decode operation: %w", err)
+	}
+
+	if ack, err = h.callback.Consume(ctx, model, attributes); err != nil {
+		h.logger.WithContext(ctx).Error("an error occurred during the consume operation: %w", err)
+
+		return fmt.Errorf("an error occurred during the consume operation: %w", err)
+	}
+
+	if !ack {
+		return fmt.Errorf("the message was not acknowledged by the consumer callback")
+	}
+
+	return nil
+}
+
+func ensureAttributes(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return make(map[string]interface{})
+	}
+
+	return attributes
+}
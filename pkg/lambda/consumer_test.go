@@ -0,0 +1,142 @@
+package lambda_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gin-gonic/gin"
+	"github.com/justtrackio/gosoline/pkg/lambda"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type consumerModel struct {
+	Id int `json:"id"`
+}
+
+func newConsumerCallback(expectedIds ...int) *mocks.ConsumerCallback {
+	callback := new(mocks.ConsumerCallback)
+	callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return &consumerModel{}
+	})
+
+	for _, id := range expectedIds {
+		callback.On("Consume", mock.Anything, &consumerModel{Id: id}, mock.AnythingOfType("map[string]interface {}")).Return(true, nil).Once()
+	}
+
+	return callback
+}
+
+type consumerHandler interface {
+	HandleSqsEvent(ctx context.Context, event events.SQSEvent) error
+	HandleSnsEvent(ctx context.Context, event events.SNSEvent) error
+	HandleKinesisEvent(ctx context.Context, event events.KinesisEvent) error
+}
+
+func newConsumerHandler(callback stream.ConsumerCallback, unmarshaller stream.UnmarshallerFunc) consumerHandler {
+	logger := logMocks.NewLoggerMockedAll()
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+
+	return lambda.NewConsumerHandlerWithInterfaces(logger, encoder, unmarshaller, callback)
+}
+
+func TestConsumerHandler_HandleSqsEvent(t *testing.T) {
+	callback := newConsumerCallback(1, 2)
+	consumer := newConsumerHandler(callback, stream.MessageUnmarshaller)
+
+	err := consumer.HandleSqsEvent(context.Background(), events.SQSEvent{
+		Records: []events.SQSMessage{
+			{MessageId: "m1", Body: `{"attributes":{"encoding":"application/json"},"body":"{\"id\":1}"}`},
+			{MessageId: "m2", Body: `{"attributes":{"encoding":"application/json"},"body":"{\"id\":2}"}`},
+		},
+	})
+
+	assert.NoError(t, err)
+	callback.AssertExpectations(t)
+}
+
+func TestConsumerHandler_HandleSqsEvent_SnsEnvelope(t *testing.T) {
+	callback := newConsumerCallback(3)
+	consumer := newConsumerHandler(callback, stream.SnsUnmarshaller)
+
+	err := consumer.HandleSqsEvent(context.Background(), events.SQSEvent{
+		Records: []events.SQSMessage{
+			{MessageId: "m1", Body: `{"Type":"Notification","Message":"{\"attributes\":{\"encoding\":\"application/json\"},\"body\":\"{\\\"id\\\":3}\"}"}`},
+		},
+	})
+
+	assert.NoError(t, err)
+	callback.AssertExpectations(t)
+}
+
+func TestConsumerHandler_HandleSnsEvent_NotAcknowledged(t *testing.T) {
+	callback := new(mocks.ConsumerCallback)
+	callback.On("GetModel", mock.Anything).Return(&consumerModel{})
+	callback.On("Consume", mock.Anything, &consumerModel{Id: 4}, mock.Anything).Return(false, nil).Once()
+
+	consumer := newConsumerHandler(callback, stream.MessageUnmarshaller)
+
+	err := consumer.HandleSnsEvent(context.Background(), events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{MessageID: "m1", Message: `{"attributes":{"encoding":"application/json"},"body":"{\"id\":4}"}`}},
+		},
+	})
+
+	assert.EqualError(t, err, "1 error occurred:\n\t* can not handle sns message m1: the message was not acknowledged by the consumer callback\n\n")
+	callback.AssertExpectations(t)
+}
+
+func TestConsumerHandler_HandleKinesisEvent_Error(t *testing.T) {
+	callback := new(mocks.ConsumerCallback)
+	callback.On("GetModel", mock.Anything).Return(&consumerModel{})
+	callback.On("Consume", mock.Anything, &consumerModel{Id: 5}, mock.Anything).Return(false, fmt.Errorf("boom")).Once()
+	callback.On("Consume", mock.Anything, &consumerModel{Id: 6}, mock.Anything).Return(true, nil).Once()
+
+	consumer := newConsumerHandler(callback, stream.MessageUnmarshaller)
+
+	err := consumer.HandleKinesisEvent(context.Background(), events.KinesisEvent{
+		Records: []events.KinesisEventRecord{
+			{EventID: "e1", Kinesis: events.KinesisRecord{Data: []byte(`{"attributes":{"encoding":"application/json"},"body":"{\"id\":5}"}`)}},
+			{EventID: "e2", Kinesis: events.KinesisRecord{Data: []byte(`{"attributes":{"encoding":"application/json"},"body":"{\"id\":6}"}`)}},
+		},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can not handle kinesis record e1")
+	assert.NotContains(t, err.Error(), "e2")
+	callback.AssertExpectations(t)
+}
+
+func TestApiGatewayHandler_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/items/:id", func(ginCtx *gin.Context) {
+		body, _ := ginCtx.GetRawData()
+
+		ginCtx.Header("X-Item", ginCtx.Param("id"))
+		ginCtx.String(http.StatusCreated, "%s:%s:%s", ginCtx.Query("filter"), ginCtx.GetHeader("X-Test"), string(body))
+	})
+
+	handler := lambda.NewApiGatewayHandlerWithInterfaces(router)
+
+	response, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodPost,
+		Path:                  "/items/17",
+		QueryStringParameters: map[string]string{"filter": "all"},
+		Headers:               map[string]string{"X-Test": "header"},
+		Body:                  "Ym9keQ==",
+		IsBase64Encoded:       true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, []string{"17"}, response.MultiValueHeaders["X-Item"])
+	assert.Equal(t, "all:header:body", response.Body)
+}
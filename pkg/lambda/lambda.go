@@ -2,12 +2,16 @@ package lambda
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	awsLambda "github.com/aws/aws-lambda-go/lambda"
 	"github.com/justtrackio/gosoline/pkg/appctx"
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/coffin"
 	"github.com/justtrackio/gosoline/pkg/log"
 	"github.com/justtrackio/gosoline/pkg/stream"
 )
@@ -70,5 +74,35 @@ func Start(handlerFactory HandlerFactory, configOptions ...cfg.Option) {
 		os.Exit(1)
 	}
 
-	awsLambda.Start(lambdaHandler)
+	settings := readLocalSettings(config)
+
+	if !settings.Enabled {
+		awsLambda.Start(lambdaHandler)
+	}
+
+	if err = runLocal(ctx, logger, settings, lambdaHandler); err != nil {
+		logger.Error("failed to run local lambda runtime: %w", err)
+
+		os.Exit(1)
+	}
+}
+
+// runLocal serves the Lambda Runtime API on the configured port and invokes the handler for every
+// event posted to the invoke endpoint until the process receives SIGINT or SIGTERM.
+func runLocal(ctx context.Context, logger log.Logger, settings *LocalSettings, lambdaHandler interface{}) error {
+	runtime, err := NewLocalRuntime(logger, settings)
+	if err != nil {
+		return fmt.Errorf("can not create local lambda runtime: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client := NewRuntimeClient(logger, runtime.Address(), lambdaHandler)
+
+	cfn, ctx := coffin.WithContext(ctx)
+	cfn.GoWithContextf(ctx, runtime.Run, "panic during run of the local lambda runtime")
+	cfn.GoWithContextf(ctx, client.Run, "panic during run of the lambda runtime client")
+
+	return cfn.Wait()
 }
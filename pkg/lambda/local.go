@@ -0,0 +1,247 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+const (
+	runtimeApiVersion      = "2018-06-01"
+	runtimeApiPathPrefix   = "/" + runtimeApiVersion + "/runtime/invocation/"
+	invokeApiPathPrefix    = "/2015-03-31/functions/"
+	headerRequestId        = "Lambda-Runtime-Aws-Request-Id"
+	headerDeadlineMs       = "Lambda-Runtime-Deadline-Ms"
+	headerInvokedArn       = "Lambda-Runtime-Invoked-Function-Arn"
+	headerFunctionError    = "X-Amz-Function-Error"
+	functionErrorUnhandled = "Unhandled"
+)
+
+type LocalSettings struct {
+	Enabled     bool          `cfg:"enabled" default:"false"`
+	Port        int           `cfg:"port" default:"9001"`
+	FunctionArn string        `cfg:"function_arn" default:"arn:aws:lambda:eu-central-1:000000000000:function:local"`
+	Timeout     time.Duration `cfg:"timeout" default:"15m"`
+}
+
+func readLocalSettings(config cfg.Config) *LocalSettings {
+	settings := &LocalSettings{}
+	config.UnmarshalKey("lambda.local", settings)
+
+	return settings
+}
+
+type invocationError struct {
+	ErrorMessage string `json:"errorMessage"`
+	ErrorType    string `json:"errorType"`
+}
+
+type invocationResult struct {
+	payload []byte
+	failed  bool
+}
+
+type invocation struct {
+	requestId string
+	deadline  time.Time
+	payload   []byte
+	result    chan invocationResult
+}
+
+// LocalRuntime emulates the AWS Lambda Runtime API over HTTP. Events posted to the invoke endpoint
+// (POST /2015-03-31/functions/{name}/invocations) are handed out to the runtime client via
+// GET /2018-06-01/runtime/invocation/next and the response of the handler is returned to the caller.
+type LocalRuntime struct {
+	logger   log.Logger
+	settings *LocalSettings
+
+	listener net.Listener
+	server   *http.Server
+
+	lck     sync.Mutex
+	queue   chan *invocation
+	pending map[string]*invocation
+}
+
+func NewLocalRuntime(logger log.Logger, settings *LocalSettings) (*LocalRuntime, error) {
+	var err error
+	var listener net.Listener
+
+	if listener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", settings.Port)); err != nil {
+		return nil, fmt.Errorf("can not listen on port %d: %w", settings.Port, err)
+	}
+
+	runtime := &LocalRuntime{
+		logger:   logger.WithChannel("lambda-local"),
+		settings: settings,
+		listener: listener,
+		queue:    make(chan *invocation),
+		pending:  make(map[string]*invocation),
+	}
+
+	runtime.server = &http.Server{
+		Handler: runtime,
+	}
+
+	return runtime, nil
+}
+
+// Address returns the host and port the runtime api is served on.
+func (r *LocalRuntime) Address() string {
+	return r.listener.Addr().String()
+}
+
+func (r *LocalRuntime) Run(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+
+		if err := r.server.Close(); err != nil {
+			r.logger.Error("can not close local lambda runtime server: %w", err)
+		}
+	}()
+
+	r.logger.Info("serving local lambda runtime api on address %s", r.Address())
+
+	if err := r.server.Serve(r.listener); err != http.ErrServerClosed {
+		return fmt.Errorf("local lambda runtime server closed unexpected: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LocalRuntime) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+
+	switch {
+	case strings.HasPrefix(path, invokeApiPathPrefix) && strings.HasSuffix(path, "/invocations") && req.Method == http.MethodPost:
+		r.handleInvoke(w, req)
+	case path == runtimeApiPathPrefix+"next" && req.Method == http.MethodGet:
+		r.handleNext(w, req)
+	case strings.HasPrefix(path, runtimeApiPathPrefix) && strings.HasSuffix(path, "/response") && req.Method == http.MethodPost:
+		r.handleResult(w, req, strings.TrimSuffix(strings.TrimPrefix(path, runtimeApiPathPrefix), "/response"), false)
+	case strings.HasPrefix(path, runtimeApiPathPrefix) && strings.HasSuffix(path, "/error") && req.Method == http.MethodPost:
+		r.handleResult(w, req, strings.TrimSuffix(strings.TrimPrefix(path, runtimeApiPathPrefix), "/error"), true)
+	case path == "/"+runtimeApiVersion+"/runtime/init/error" && req.Method == http.MethodPost:
+		r.handleInitError(w, req)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (r *LocalRuntime) handleInvoke(w http.ResponseWriter, req *http.Request) {
+	payload, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Errorf("can not read invocation payload: %w", err))
+		return
+	}
+
+	inv := &invocation{
+		requestId: uuid.New().String(),
+		deadline:  time.Now().Add(r.settings.Timeout),
+		payload:   payload,
+		result:    make(chan invocationResult, 1),
+	}
+
+	r.lck.Lock()
+	r.pending[inv.requestId] = inv
+	r.lck.Unlock()
+
+	defer func() {
+		r.lck.Lock()
+		delete(r.pending, inv.requestId)
+		r.lck.Unlock()
+	}()
+
+	select {
+	case r.queue <- inv:
+	case <-req.Context().Done():
+		return
+	}
+
+	select {
+	case result := <-inv.result:
+		if result.failed {
+			w.Header().Set(headerFunctionError, functionErrorUnhandled)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(headerRequestId, inv.requestId)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(result.payload)
+	case <-req.Context().Done():
+	}
+}
+
+func (r *LocalRuntime) handleNext(w http.ResponseWriter, req *http.Request) {
+	var inv *invocation
+
+	select {
+	case inv = <-r.queue:
+	case <-req.Context().Done():
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(headerRequestId, inv.requestId)
+	w.Header().Set(headerDeadlineMs, strconv.FormatInt(inv.deadline.UnixNano()/int64(time.Millisecond), 10))
+	w.Header().Set(headerInvokedArn, r.settings.FunctionArn)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(inv.payload)
+}
+
+func (r *LocalRuntime) handleResult(w http.ResponseWriter, req *http.Request, requestId string, failed bool) {
+	payload, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Errorf("can not read invocation result: %w", err))
+		return
+	}
+
+	r.lck.Lock()
+	inv, ok := r.pending[requestId]
+	r.lck.Unlock()
+
+	if !ok {
+		r.writeError(w, http.StatusBadRequest, fmt.Errorf("there is no pending invocation with request id %s", requestId))
+		return
+	}
+
+	if failed {
+		r.logger.Warn("invocation %s failed: %s", requestId, string(payload))
+	}
+
+	inv.result <- invocationResult{
+		payload: payload,
+		failed:  failed,
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *LocalRuntime) handleInitError(w http.ResponseWriter, req *http.Request) {
+	payload, _ := ioutil.ReadAll(req.Body)
+	r.logger.Error("lambda runtime failed to initialize: %s", string(payload))
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *LocalRuntime) writeError(w http.ResponseWriter, statusCode int, err error) {
+	body, _ := json.Marshal(invocationError{
+		ErrorMessage: err.Error(),
+		ErrorType:    "InvalidRequest",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
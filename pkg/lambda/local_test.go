@@ -0,0 +1,135 @@
+package lambda_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/lambda"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type testEvent struct {
+	Name string `json:"name"`
+}
+
+type LocalRuntimeTestSuite struct {
+	suite.Suite
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	url    string
+}
+
+func (s *LocalRuntimeTestSuite) SetupTest() {
+	logger := logMocks.NewLoggerMockedAll()
+	settings := &lambda.LocalSettings{
+		Port:        0,
+		FunctionArn: "arn:aws:lambda:eu-central-1:000000000000:function:test",
+		Timeout:     time.Second,
+	}
+
+	runtime, err := lambda.NewLocalRuntime(logger, settings)
+	s.NoError(err)
+
+	handler := func(ctx context.Context, event testEvent) (string, error) {
+		if event.Name == "" {
+			return "", fmt.Errorf("name is missing")
+		}
+
+		if event.Name == "slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		return fmt.Sprintf("hello %s", event.Name), nil
+	}
+
+	client := lambda.NewRuntimeClient(logger, runtime.Address(), handler)
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+	s.url = fmt.Sprintf("http://%s/2015-03-31/functions/function/invocations", runtime.Address())
+
+	go func() {
+		defer close(s.done)
+
+		go func() {
+			_ = client.Run(s.ctx)
+		}()
+
+		_ = runtime.Run(s.ctx)
+	}()
+}
+
+func (s *LocalRuntimeTestSuite) TearDownTest() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *LocalRuntimeTestSuite) invoke(payload string) (*http.Response, string) {
+	res, err := http.Post(s.url, "application/json", bytes.NewBufferString(payload))
+	s.NoError(err)
+
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	s.NoError(err)
+
+	return res, string(body)
+}
+
+func (s *LocalRuntimeTestSuite) TestInvoke() {
+	res, body := s.invoke(`{"name":"gosoline"}`)
+
+	s.Equal(http.StatusOK, res.StatusCode)
+	s.Empty(res.Header.Get("X-Amz-Function-Error"))
+	s.JSONEq(`"hello gosoline"`, body)
+}
+
+func (s *LocalRuntimeTestSuite) TestInvokeError() {
+	res, body := s.invoke(`{}`)
+
+	s.Equal(http.StatusOK, res.StatusCode)
+	s.Equal("Unhandled", res.Header.Get("X-Amz-Function-Error"))
+	s.JSONEq(`{"errorMessage":"name is missing","errorType":"*errors.errorString"}`, body)
+}
+
+func (s *LocalRuntimeTestSuite) TestInvokeSequential() {
+	for _, name := range []string{"a", "b", "c"} {
+		_, body := s.invoke(fmt.Sprintf(`{"name":"%s"}`, name))
+		assert.JSONEq(s.T(), fmt.Sprintf(`"hello %s"`, name), body)
+	}
+}
+
+func (s *LocalRuntimeTestSuite) TestInvokeCallerDisconnected() {
+	client := &http.Client{
+		Timeout: 50 * time.Millisecond,
+	}
+
+	_, err := client.Post(s.url, "application/json", bytes.NewBufferString(`{"name":"slow"}`))
+	s.Error(err)
+
+	// wait for the handler to finish the invocation of the disconnected caller
+	time.Sleep(250 * time.Millisecond)
+
+	client.Timeout = time.Second
+	res, err := client.Post(s.url, "application/json", bytes.NewBufferString(`{"name":"gosoline"}`))
+	s.NoError(err)
+
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	s.NoError(err)
+	s.JSONEq(`"hello gosoline"`, string(body))
+}
+
+func TestLocalRuntimeTestSuite(t *testing.T) {
+	suite.Run(t, new(LocalRuntimeTestSuite))
+}
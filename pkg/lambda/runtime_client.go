@@ -0,0 +1,147 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	awsLambda "github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/justtrackio/gosoline/pkg/coffin"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// A RuntimeClient polls the Lambda Runtime API for new invocations and hands them to the handler.
+// It is used to drive handlers against a LocalRuntime without deploying them.
+type RuntimeClient interface {
+	Run(ctx context.Context) error
+}
+
+type runtimeClient struct {
+	logger  log.Logger
+	client  *http.Client
+	baseUrl string
+	handler awsLambda.Handler
+}
+
+func NewRuntimeClient(logger log.Logger, address string, handler interface{}) RuntimeClient {
+	return &runtimeClient{
+		logger:  logger.WithChannel("lambda-runtime-client"),
+		client:  &http.Client{},
+		baseUrl: fmt.Sprintf("http://%s/%s/runtime/invocation/", address, runtimeApiVersion),
+		handler: awsLambda.NewHandler(handler),
+	}
+}
+
+func (c *runtimeClient) Run(ctx context.Context) error {
+	for {
+		if err := c.next(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+func (c *runtimeClient) next(ctx context.Context) error {
+	var err error
+	var req *http.Request
+	var res *http.Response
+	var payload []byte
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.baseUrl+"next", nil); err != nil {
+		return fmt.Errorf("can not create request for next invocation: %w", err)
+	}
+
+	if res, err = c.client.Do(req); err != nil {
+		return fmt.Errorf("can not fetch next invocation: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if payload, err = ioutil.ReadAll(res.Body); err != nil {
+		return fmt.Errorf("can not read payload of next invocation: %w", err)
+	}
+
+	requestId := res.Header.Get(headerRequestId)
+	deadlineMs, err := strconv.ParseInt(res.Header.Get(headerDeadlineMs), 10, 64)
+	if err != nil {
+		return fmt.Errorf("can not parse deadline of invocation %s: %w", requestId, err)
+	}
+
+	invokeCtx, cancel := context.WithDeadline(ctx, time.Unix(0, deadlineMs*int64(time.Millisecond)))
+	defer cancel()
+
+	invokeCtx = lambdacontext.NewContext(invokeCtx, &lambdacontext.LambdaContext{
+		AwsRequestID:       requestId,
+		InvokedFunctionArn: res.Header.Get(headerInvokedArn),
+	})
+
+	response, err := c.invoke(invokeCtx, payload)
+	if err != nil {
+		return c.post(ctx, requestId, "error", c.encodeError(err))
+	}
+
+	return c.post(ctx, requestId, "response", response)
+}
+
+func (c *runtimeClient) invoke(ctx context.Context, payload []byte) (response []byte, err error) {
+	defer func() {
+		if recovered := coffin.ResolveRecovery(recover()); recovered != nil {
+			err = recovered
+		}
+	}()
+
+	return c.handler.Invoke(ctx, payload)
+}
+
+func (c *runtimeClient) post(ctx context.Context, requestId string, kind string, body []byte) error {
+	var err error
+	var req *http.Request
+	var res *http.Response
+
+	url := fmt.Sprintf("%s%s/%s", c.baseUrl, requestId, kind)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body)); err != nil {
+		return fmt.Errorf("can not create %s request for invocation %s: %w", kind, requestId, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if res, err = c.client.Do(req); err != nil {
+		return fmt.Errorf("can not send %s for invocation %s: %w", kind, requestId, err)
+	}
+
+	defer res.Body.Close()
+
+	// the invocation is gone if its caller disconnected before the result was ready, which must not stop the client
+	if res.StatusCode == http.StatusBadRequest {
+		message, _ := ioutil.ReadAll(res.Body)
+		c.logger.WithContext(ctx).Warn("runtime api rejected %s for invocation %s: %s", kind, requestId, string(message))
+
+		return nil
+	}
+
+	if res.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("runtime api rejected %s for invocation %s with status code %d", kind, requestId, res.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *runtimeClient) encodeError(err error) []byte {
+	body, _ := json.Marshal(invocationError{
+		ErrorMessage: err.Error(),
+		ErrorType:    reflect.TypeOf(err).String(),
+	})
+
+	return body
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+)
+
+// channelLevels holds the log levels overwritten at runtime per channel. A level set for a channel
+// replaces the levels of all handlers for log messages of that channel.
+var channelLevels = struct {
+	lck    sync.RWMutex
+	levels map[string]int
+}{
+	levels: make(map[string]int),
+}
+
+// SetChannelLevel overwrites the log level of all handlers for the given channel until it is reset.
+func SetChannelLevel(channel string, level string) error {
+	priority, ok := levelPriorities[level]
+	if !ok {
+		return fmt.Errorf("unknown log level %s", level)
+	}
+
+	channelLevels.lck.Lock()
+	defer channelLevels.lck.Unlock()
+
+	channelLevels.levels[channel] = priority
+
+	return nil
+}
+
+// ResetChannelLevel removes the log level overwrite of the given channel, so the levels of the handlers apply again.
+func ResetChannelLevel(channel string) {
+	channelLevels.lck.Lock()
+	defer channelLevels.lck.Unlock()
+
+	delete(channelLevels.levels, channel)
+}
+
+// GetChannelLevels returns all channels with an overwritten log level.
+func GetChannelLevels() map[string]string {
+	channelLevels.lck.RLock()
+	defer channelLevels.lck.RUnlock()
+
+	levels := make(map[string]string, len(channelLevels.levels))
+	for channel, priority := range channelLevels.levels {
+		levels[channel] = levelNames[priority]
+	}
+
+	return levels
+}
+
+func getChannelLevel(channel string) (int, bool) {
+	channelLevels.lck.RLock()
+	defer channelLevels.lck.RUnlock()
+
+	priority, ok := channelLevels.levels[channel]
+
+	return priority, ok
+}
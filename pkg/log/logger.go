@@ -176,9 +176,16 @@ func (l *gosoLogger) copy() *gosoLogger {
 
 func (l *gosoLogger) log(level int, msg string, args []interface{}, loggedErr error) {
 	timestamp := l.clock.Now()
+	channelLevel, hasChannelLevel := getChannelLevel(l.data.Channel)
 
 	for _, handler := range l.handlers {
-		if handler.Level() > level {
+		handlerLevel := handler.Level()
+
+		if hasChannelLevel {
+			handlerLevel = channelLevel
+		}
+
+		if handlerLevel > level {
 			continue
 		}
 
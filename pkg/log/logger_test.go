@@ -55,3 +55,29 @@ func getLogLines(buf *bytes.Buffer) []string {
 
 	return lines
 }
+
+func TestLoggerChannelLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := log.NewHandlerIoWriter(log.LevelInfo, []string{}, log.FormatterJson, time.RFC3339, buf)
+	cl := clock.NewFakeClock()
+
+	logger := log.NewLoggerWithInterfaces(cl, []log.Handler{handler})
+	other := logger.WithChannel("other")
+
+	err := log.SetChannelLevel("other", log.LevelDebug)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"other": log.LevelDebug}, log.GetChannelLevels())
+
+	logger.Debug("main debug")
+	other.Debug("other debug")
+
+	log.ResetChannelLevel("other")
+	other.Debug("other debug after reset")
+
+	err = log.SetChannelLevel("other", "verbose")
+	assert.EqualError(t, err, "unknown log level verbose")
+
+	lines := getLogLines(buf)
+	assert.Len(t, lines, 1)
+	assert.JSONEq(t, `{"channel":"other","context":{},"fields":{},"level":1,"level_name":"debug","message":"other debug","timestamp":"1984-04-04T00:00:00Z"}`, lines[0])
+}
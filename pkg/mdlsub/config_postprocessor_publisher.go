@@ -59,6 +59,10 @@ func PublisherConfigPostProcessor(config cfg.GosoConf) (bool, error) {
 			cfg.WithConfigSetting(publisherKey, publisherSettings),
 		}
 
+		if publisherSettings.Resync.Enabled {
+			configOptions = append(configOptions, resyncConsumerConfigOptions(config, publisherSettings)...)
+		}
+
 		if err := config.Option(configOptions...); err != nil {
 			return false, fmt.Errorf("can not apply config settings for publisher %s: %w", publisherSettings.Name, err)
 		}
@@ -67,6 +71,34 @@ func PublisherConfigPostProcessor(config cfg.GosoConf) (bool, error) {
 	return true, nil
 }
 
+// resyncConsumerConfigOptions configures the consumer of the resync requests of a publisher, see NewResyncFactory.
+func resyncConsumerConfigOptions(config cfg.GosoConf, publisherSettings *PublisherSettings) []cfg.Option {
+	consumerName := getResyncConsumerName(publisherSettings.Name)
+
+	inputSettings := &stream.SnsInputConfiguration{}
+	config.UnmarshalDefaults(inputSettings)
+
+	inputSettings.ConsumerId = getResyncTopicId(publisherSettings.Name)
+	inputSettings.VisibilityTimeout = publisherSettings.Resync.VisibilityTimeout
+	inputSettings.Targets = []stream.SnsInputTargetConfiguration{
+		{
+			Family:      publisherSettings.Family,
+			Application: publisherSettings.Application,
+			TopicId:     getResyncTopicId(publisherSettings.Name),
+		},
+	}
+
+	consumerSettings := &stream.ConsumerSettings{}
+	config.UnmarshalDefaults(consumerSettings)
+
+	consumerSettings.Input = consumerName
+
+	return []cfg.Option{
+		cfg.WithConfigSetting(stream.ConfigurableConsumerKey(consumerName), consumerSettings, cfg.SkipExisting),
+		cfg.WithConfigSetting(stream.ConfigurableInputKey(consumerName), inputSettings, cfg.SkipExisting),
+	}
+}
+
 func getPublisherConfigKey(name string) string {
 	return fmt.Sprintf("%s.%s", ConfigKeyMdlSubPublishers, name)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/ddb"
 	"github.com/justtrackio/gosoline/pkg/kvstore"
 	"github.com/justtrackio/gosoline/pkg/stream"
 )
@@ -18,7 +19,8 @@ type (
 )
 
 var subscriberInputConfigPostProcessors = map[string]SubscriberInputConfigPostProcessor{
-	"sns": snsSubscriberInputConfigPostProcessor,
+	"dynamodb_stream": dynamoDbStreamSubscriberInputConfigPostProcessor,
+	"sns":             snsSubscriberInputConfigPostProcessor,
 }
 
 var subscriberOutputConfigPostProcessors = map[string]SubscriberOutputConfigPostProcessor{
@@ -71,6 +73,10 @@ func SubscriberConfigPostProcessor(config cfg.GosoConf) (bool, error) {
 			configOptions = append(configOptions, outputOption)
 		}
 
+		if subscriberSettings.Resync {
+			configOptions = append(configOptions, resyncProducerConfigOptions(config, name, subscriberSettings)...)
+		}
+
 		if err := config.Option(configOptions...); err != nil {
 			return false, fmt.Errorf("can not apply config settings for subscriber %s: %w", name, err)
 		}
@@ -97,6 +103,54 @@ func snsSubscriberInputConfigPostProcessor(config cfg.GosoConf, name string, sub
 	return cfg.WithConfigSetting(inputKey, inputSettings, cfg.SkipExisting)
 }
 
+// dynamoDbStreamSubscriberInputConfigPostProcessor reads the changes of the table of the source model. The records
+// of a table don't carry a version, so version 0 of the transformers is used unless the attributes of the input are
+// configured differently.
+func dynamoDbStreamSubscriberInputConfigPostProcessor(config cfg.GosoConf, name string, subscriberSettings *SubscriberSettings) cfg.Option {
+	inputKey := getInputConfigKey(name)
+
+	inputSettings := &stream.DynamoDbStreamInputConfiguration{}
+	config.UnmarshalDefaults(inputSettings)
+
+	modelId := subscriberSettings.SourceModel
+	modelId.PadFromConfig(config)
+
+	inputSettings.TableName = ddb.TableName(&ddb.Settings{
+		ModelId: modelId,
+	})
+	inputSettings.Attributes = map[string]string{
+		AttributeModelId: modelId.String(),
+		AttributeVersion: "0",
+	}
+
+	return cfg.WithConfigSetting(inputKey, inputSettings, cfg.SkipExisting)
+}
+
+// resyncProducerConfigOptions configures the producer of the resync requests of a subscriber. The requests are
+// published to the resync topic of the publisher of the source model.
+func resyncProducerConfigOptions(config cfg.GosoConf, name string, subscriberSettings *SubscriberSettings) []cfg.Option {
+	producerName := getResyncProducerName(name)
+
+	outputSettings := &stream.SnsOutputConfiguration{}
+	config.UnmarshalDefaults(outputSettings)
+
+	outputSettings.Type = stream.OutputTypeSns
+	outputSettings.Project = subscriberSettings.SourceModel.Project
+	outputSettings.Family = subscriberSettings.SourceModel.Family
+	outputSettings.Application = subscriberSettings.SourceModel.Application
+	outputSettings.TopicId = getResyncTopicId(subscriberSettings.SourceModel.Name)
+
+	producerSettings := &stream.ProducerSettings{}
+	config.UnmarshalDefaults(producerSettings)
+
+	producerSettings.Output = producerName
+
+	return []cfg.Option{
+		cfg.WithConfigSetting(stream.ConfigurableProducerKey(producerName), producerSettings, cfg.SkipExisting),
+		cfg.WithConfigSetting(stream.ConfigurableOutputKey(producerName), outputSettings, cfg.SkipExisting),
+	}
+}
+
 func kvstoreSubscriberOutputConfigPostProcessor(config cfg.GosoConf, name string, subscriberSettings *SubscriberSettings) cfg.Option {
 	kvstoreKey := kvstore.GetConfigurableKey(name)
 
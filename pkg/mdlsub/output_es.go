@@ -0,0 +1,275 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+const (
+	OutputTypeEs = "es"
+)
+
+func init() {
+	outputFactories[OutputTypeEs] = outputEsFactory
+}
+
+type OutputEsSettings struct {
+	Client string `cfg:"client" default:"default"`
+	// Maximum number of operations sent with a single bulk request
+	BatchSize int `cfg:"batch_size" default:"100" validate:"min=1"`
+	// Maximum time an operation waits for further operations before the bulk request is sent
+	BatchTimeout time.Duration `cfg:"batch_timeout" default:"100ms"`
+}
+
+// A VersionedModel provides the version of its document for the optimistic concurrency control of elasticsearch.
+// Models without a version use the time of their last update if they implement db_repo.TimestampAware.
+type VersionedModel interface {
+	GetVersion() int64
+}
+
+// An EsModelTransformer provides the model it transforms into, so the es output can create its indices with the
+// mappings of the model. The transformers of subscribers with an es output have to implement it.
+type EsModelTransformer interface {
+	GetOutput() Model
+}
+
+// EsBulkWriter collects the items written concurrently into bulk requests. Write returns after the bulk request
+// containing the item has been executed.
+//go:generate mockery --name EsBulkWriter
+type EsBulkWriter interface {
+	Write(ctx context.Context, item es.BulkItem) error
+}
+
+func outputEsFactory(ctx context.Context, config cfg.Config, logger log.Logger, settings *SubscriberSettings, transformers VersionedModelTransformers) (map[int]Output, error) {
+	client, err := es.ProvideClient(config, logger, settings.Es.Client)
+	if err != nil {
+		return nil, fmt.Errorf("can not create es client %s: %w", settings.Es.Client, err)
+	}
+
+	modelId := settings.TargetModel
+	modelId.PadFromConfig(config)
+
+	writer := NewEsBulkWriter(ctx, client, clock.NewRealClock(), &settings.Es)
+	outputs := make(map[int]Output)
+	latest := -1
+
+	for version, transformer := range transformers {
+		index := es.IndexName(modelId, version)
+
+		modelTransformer, ok := transformer.(EsModelTransformer)
+		if !ok {
+			return nil, fmt.Errorf("the transformer for version %d of subscription %s has to implement EsModelTransformer", version, settings.TargetModel.Name)
+		}
+
+		metadata, err := es.ReadMetadata(index, modelTransformer.GetOutput())
+		if err != nil {
+			return nil, fmt.Errorf("can not read metadata for version %d of subscription %s: %w", version, settings.TargetModel.Name, err)
+		}
+
+		if err = es.CreateIndex(ctx, client, index, metadata.Mappings()); err != nil {
+			return nil, fmt.Errorf("can not create index for version %d of subscription %s: %w", version, settings.TargetModel.Name, err)
+		}
+
+		if version > latest {
+			latest = version
+		}
+
+		outputs[version] = NewOutputEsWithInterfaces(writer, index)
+	}
+
+	// an existing alias is left untouched as the index of a new version might still be filled by a resync, see SwitchEsAlias
+	if latest >= 0 {
+		if err = es.CreateAlias(ctx, client, es.AliasName(modelId), es.IndexName(modelId, latest)); err != nil {
+			return nil, fmt.Errorf("can not create alias of subscription %s: %w", settings.TargetModel.Name, err)
+		}
+	}
+
+	return outputs, nil
+}
+
+// SwitchEsAlias points the alias of the target model of an es subscriber to the index of a version. To reindex
+// without downtime, subscribe to the new version, fill its index by a resync and switch the alias once the resync
+// has completed.
+func SwitchEsAlias(ctx context.Context, config cfg.Config, logger log.Logger, name string, version int) error {
+	settings := &SubscriberSettings{}
+	config.UnmarshalKey(fmt.Sprintf("%s.subscribers.%s", ConfigKeyMdlSub, name), settings)
+
+	if settings.Output != OutputTypeEs {
+		return fmt.Errorf("subscriber %s has output %s instead of %s", name, settings.Output, OutputTypeEs)
+	}
+
+	client, err := es.ProvideClient(config, logger, settings.Es.Client)
+	if err != nil {
+		return fmt.Errorf("can not create es client %s: %w", settings.Es.Client, err)
+	}
+
+	modelId := settings.TargetModel
+	modelId.PadFromConfig(config)
+
+	if err = es.SwitchAlias(ctx, client, es.AliasName(modelId), es.IndexName(modelId, version)); err != nil {
+		return fmt.Errorf("can not point alias of subscription %s to version %d: %w", name, version, err)
+	}
+
+	return nil
+}
+
+type OutputEs struct {
+	writer EsBulkWriter
+	index  string
+}
+
+func NewOutputEsWithInterfaces(writer EsBulkWriter, index string) *OutputEs {
+	return &OutputEs{
+		writer: writer,
+		index:  index,
+	}
+}
+
+func (p *OutputEs) Persist(ctx context.Context, model Model, op string) error {
+	item := es.BulkItem{
+		Index:   p.index,
+		Id:      fmt.Sprint(model.GetId()),
+		Version: getEsVersion(model),
+	}
+
+	switch op {
+	case TypeCreate, TypeUpdate:
+		item.Action = es.BulkActionIndex
+		item.Body = model
+	case TypeDelete:
+		item.Action = es.BulkActionDelete
+	default:
+		return fmt.Errorf("unknown operation %s in OutputEs", op)
+	}
+
+	return p.writer.Write(ctx, item)
+}
+
+func getEsVersion(model Model) *int64 {
+	var version int64
+
+	switch m := model.(type) {
+	case VersionedModel:
+		version = m.GetVersion()
+	case db_repo.TimestampAware:
+		if m.GetUpdatedAt() == nil {
+			return nil
+		}
+
+		version = m.GetUpdatedAt().UnixNano()
+	default:
+		return nil
+	}
+
+	return &version
+}
+
+type esBulkOperation struct {
+	item es.BulkItem
+	done chan error
+}
+
+type esBulkWriter struct {
+	ctx      context.Context
+	client   *es.ClientV7
+	clock    clock.Clock
+	settings *OutputEsSettings
+
+	lck        sync.Mutex
+	pending    []*esBulkOperation
+	generation int
+}
+
+// NewEsBulkWriter creates a writer flushing the batches completed by the timeout with the given context, as the
+// writes waiting for them might have been canceled already.
+func NewEsBulkWriter(ctx context.Context, client *es.ClientV7, clock clock.Clock, settings *OutputEsSettings) EsBulkWriter {
+	return &esBulkWriter{
+		ctx:      ctx,
+		client:   client,
+		clock:    clock,
+		settings: settings,
+	}
+}
+
+func (w *esBulkWriter) Write(ctx context.Context, item es.BulkItem) error {
+	op := &esBulkOperation{
+		item: item,
+		done: make(chan error, 1),
+	}
+
+	w.lck.Lock()
+	w.pending = append(w.pending, op)
+
+	switch {
+	case len(w.pending) >= w.settings.BatchSize:
+		ops := w.take()
+		w.lck.Unlock()
+
+		w.flush(ctx, ops)
+	case len(w.pending) == 1:
+		generation := w.generation
+		w.lck.Unlock()
+
+		go w.flushAfterTimeout(generation)
+	default:
+		w.lck.Unlock()
+	}
+
+	return <-op.done
+}
+
+// take returns the pending operations and starts a new batch. The caller has to hold the lock.
+func (w *esBulkWriter) take() []*esBulkOperation {
+	ops := w.pending
+	w.pending = nil
+	w.generation++
+
+	return ops
+}
+
+func (w *esBulkWriter) flushAfterTimeout(generation int) {
+	<-w.clock.After(w.settings.BatchTimeout)
+
+	w.lck.Lock()
+
+	// the batch has been flushed as it was full already
+	if w.generation != generation {
+		w.lck.Unlock()
+		return
+	}
+
+	ops := w.take()
+	w.lck.Unlock()
+
+	w.flush(w.ctx, ops)
+}
+
+func (w *esBulkWriter) flush(ctx context.Context, ops []*esBulkOperation) {
+	items := make([]es.BulkItem, len(ops))
+
+	for i, op := range ops {
+		items[i] = op.item
+	}
+
+	results, err := es.Bulk(ctx, w.client, items)
+
+	for i, op := range ops {
+		switch {
+		case err != nil:
+			op.done <- err
+		case results[i].Conflict:
+			// the stored document is up to date already
+			op.done <- nil
+		default:
+			op.done <- results[i].Error
+		}
+	}
+}
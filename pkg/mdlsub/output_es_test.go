@@ -0,0 +1,150 @@
+package mdlsub_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/mdlsub"
+	"github.com/justtrackio/gosoline/pkg/mdlsub/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type esItem struct {
+	Id string `json:"id"`
+}
+
+func (i *esItem) GetId() interface{} {
+	return i.Id
+}
+
+type esVersionedItem struct {
+	esItem
+	Version int64 `json:"version"`
+}
+
+func (i *esVersionedItem) GetVersion() int64 {
+	return i.Version
+}
+
+type esDbItem struct {
+	db_repo.Model
+}
+
+func (i *esDbItem) GetId() interface{} {
+	return *i.Id
+}
+
+func TestOutputEs_Persist(t *testing.T) {
+	updatedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	item := &esItem{Id: "a"}
+	versioned := &esVersionedItem{esItem: esItem{Id: "b"}, Version: 3}
+	dbItem := &esDbItem{Model: db_repo.Model{Id: mdl.Uint(4), Timestamps: db_repo.Timestamps{UpdatedAt: &updatedAt}}}
+
+	writer := new(mocks.EsBulkWriter)
+	writer.On("Write", mock.Anything, es.BulkItem{Action: es.BulkActionIndex, Index: "items-v1", Id: "a", Body: item}).Return(nil).Once()
+	writer.On("Write", mock.Anything, es.BulkItem{Action: es.BulkActionIndex, Index: "items-v1", Id: "b", Version: mdl.Int64(3), Body: versioned}).Return(nil).Once()
+	writer.On("Write", mock.Anything, es.BulkItem{Action: es.BulkActionDelete, Index: "items-v1", Id: "4", Version: mdl.Int64(updatedAt.UnixNano())}).Return(nil).Once()
+
+	output := mdlsub.NewOutputEsWithInterfaces(writer, "items-v1")
+
+	assert.NoError(t, output.Persist(context.Background(), item, mdlsub.TypeCreate))
+	assert.NoError(t, output.Persist(context.Background(), versioned, mdlsub.TypeUpdate))
+	assert.NoError(t, output.Persist(context.Background(), dbItem, mdlsub.TypeDelete))
+	assert.EqualError(t, output.Persist(context.Background(), item, "unknown"), "unknown operation unknown in OutputEs")
+
+	writer.AssertExpectations(t)
+}
+
+type esBulkServer struct {
+	lck      sync.Mutex
+	requests []string
+}
+
+func (s *esBulkServer) client(t *testing.T) *es.ClientV7 {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+		s.lck.Lock()
+		s.requests = append(s.requests, string(data))
+		s.lck.Unlock()
+
+		// every item is an index action with a body, the second one is rejected as stale
+		items := make([]string, 0, len(lines)/2)
+		for i := 0; i < len(lines)/2; i++ {
+			status := "201"
+			if i == 1 {
+				status = `409,"error":{"type":"version_conflict_engine_exception","reason":"stale"}`
+			}
+
+			items = append(items, `{"index":{"status":`+status+`}}`)
+		}
+
+		_, _ = w.Write([]byte(`{"errors":false,"items":[` + strings.Join(items, ",") + `]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{server.URL},
+	})
+	assert.NoError(t, err)
+
+	return &es.ClientV7{Client: *client}
+}
+
+func TestEsBulkWriter_BatchSize(t *testing.T) {
+	server := &esBulkServer{}
+	writer := mdlsub.NewEsBulkWriter(context.Background(), server.client(t), clock.NewFakeClock(), &mdlsub.OutputEsSettings{
+		BatchSize:    2,
+		BatchTimeout: time.Hour,
+	})
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	for _, id := range []string{"a", "b"} {
+		go func(id string) {
+			defer wg.Done()
+
+			err := writer.Write(context.Background(), es.BulkItem{Action: es.BulkActionIndex, Index: "items-v0", Id: id, Body: &esItem{Id: id}})
+			assert.NoError(t, err, "a stale document should not be an error")
+		}(id)
+	}
+
+	wg.Wait()
+
+	assert.Len(t, server.requests, 1, "both items should be sent with one bulk request")
+}
+
+func TestEsBulkWriter_BatchTimeout(t *testing.T) {
+	server := &esBulkServer{}
+	fakeClock := clock.NewFakeClock()
+	writer := mdlsub.NewEsBulkWriter(context.Background(), server.client(t), fakeClock, &mdlsub.OutputEsSettings{
+		BatchSize:    10,
+		BatchTimeout: time.Second,
+	})
+
+	done := make(chan error)
+
+	go func() {
+		done <- writer.Write(context.Background(), es.BulkItem{Action: es.BulkActionIndex, Index: "items-v0", Id: "a", Body: &esItem{Id: "a"}})
+	}()
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Second)
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{"{\"index\":{\"_index\":\"items-v0\",\"_id\":\"a\"}}\n{\"id\":\"a\"}\n"}, server.requests)
+}
@@ -25,6 +25,8 @@ type PublisherSettings struct {
 	Producer   string `cfg:"producer" validate:"required_without=OutputType"`
 	OutputType string `cfg:"output_type" validate:"required_without=Producer"`
 	Shared     bool   `cfg:"shared"`
+	// Resync settings are used if the publisher answers resync requests, see NewResyncFactory
+	Resync ResyncSettings `cfg:"resync"`
 }
 
 //go:generate mockery --name Publisher
@@ -0,0 +1,261 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/log/status"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+const (
+	// AttributeResync marks the messages published by a resync. They are published with type update, so the outputs
+	// of the subscribers apply them idempotently.
+	AttributeResync = "resync"
+)
+
+// ResyncRequest is sent by a subscriber to the resync topic of a publisher to receive all records of the model.
+type ResyncRequest struct {
+	ModelId    string `json:"modelId"`
+	Subscriber string `json:"subscriber"`
+}
+
+type ResyncSettings struct {
+	// Configures the consumer of the resync requests of a publisher
+	Enabled bool `cfg:"enabled"`
+	// Number of records published per batch
+	BatchSize int `cfg:"batch_size" default:"100" validate:"min=1"`
+	// Maximum number of records published per second, 0 disables the limit
+	RateLimit int `cfg:"rate_limit" default:"0" validate:"min=0"`
+	// The version the records are published in
+	Version int `cfg:"version" default:"0"`
+	// Visibility timeout of the resync requests in seconds. A request not answered within it is delivered again and
+	// continues the resync at its last checkpoint.
+	VisibilityTimeout int `cfg:"visibility_timeout" default:"900" validate:"min=1"`
+}
+
+// ResyncPosition is the position of a page of a ResyncSource. A resync is split into steps, e.g. the segments of a
+// scan, which are used to report its progress. The cursor is the position within a step, its meaning is up to the
+// source.
+type ResyncPosition struct {
+	Step   int    `json:"step"`
+	Cursor string `json:"cursor"`
+}
+
+// A ResyncSource reads all records of a model page by page. The position of the first page is the zero position.
+//go:generate mockery --name ResyncSource
+type ResyncSource interface {
+	GetSteps() int
+	Read(ctx context.Context, position ResyncPosition) (values []interface{}, next ResyncPosition, done bool, err error)
+}
+
+type (
+	ResyncSourceFactory func(ctx context.Context, config cfg.Config, logger log.Logger) (ResyncSource, error)
+	// ResyncSourceFactories maps the name of a publisher to the source of its records
+	ResyncSourceFactories map[string]ResyncSourceFactory
+)
+
+// NewResyncFactory creates a consumer for the resync requests of every publisher with a source, which requires the
+// resync of the publisher to be enabled. A request is answered by publishing all records of the source with the
+// publisher. As this can take a while, the position of the resync is checkpointed after every page, and a request
+// delivered again continues at it.
+func NewResyncFactory(sourceFactories ResyncSourceFactories) kernel.MultiModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (map[string]kernel.ModuleFactory, error) {
+		modules := make(map[string]kernel.ModuleFactory)
+
+		for name, sourceFactory := range sourceFactories {
+			if settings := readPublisherSetting(config, name); !settings.Resync.Enabled {
+				return nil, fmt.Errorf("resync is not enabled for publisher %s", name)
+			}
+
+			consumerName := getResyncConsumerName(name)
+			modules[consumerName] = stream.NewConsumer(consumerName, NewResyncCallbackFactory(name, sourceFactory))
+		}
+
+		return modules, nil
+	}
+}
+
+func NewResyncCallbackFactory(name string, sourceFactory ResyncSourceFactory) stream.ConsumerCallbackFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (stream.ConsumerCallback, error) {
+		settings := readPublisherSetting(config, name)
+
+		publisher, err := NewPublisherWithSettings(ctx, config, logger, settings)
+		if err != nil {
+			return nil, fmt.Errorf("can not create publisher %s: %w", name, err)
+		}
+
+		source, err := sourceFactory(ctx, config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create resync source of publisher %s: %w", name, err)
+		}
+
+		checkpointer, err := NewResyncCheckpointer(ctx, config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create resync checkpointer of publisher %s: %w", name, err)
+		}
+
+		return NewResyncCallbackWithInterfaces(logger, clock.NewRealClock(), publisher, source, checkpointer, status.ProvideManager(), settings.ModelId.String(), &settings.Resync), nil
+	}
+}
+
+type ResyncCallback struct {
+	logger        log.Logger
+	clock         clock.Clock
+	publisher     Publisher
+	source        ResyncSource
+	checkpointer  ResyncCheckpointer
+	statusManager status.Manager
+	modelId       string
+	settings      *ResyncSettings
+}
+
+func NewResyncCallbackWithInterfaces(
+	logger log.Logger,
+	clock clock.Clock,
+	publisher Publisher,
+	source ResyncSource,
+	checkpointer ResyncCheckpointer,
+	statusManager status.Manager,
+	modelId string,
+	settings *ResyncSettings,
+) *ResyncCallback {
+	return &ResyncCallback{
+		logger:        logger,
+		clock:         clock,
+		publisher:     publisher,
+		source:        source,
+		checkpointer:  checkpointer,
+		statusManager: statusManager,
+		modelId:       modelId,
+		settings:      settings,
+	}
+}
+
+func (c *ResyncCallback) GetModel(_ map[string]interface{}) interface{} {
+	return &ResyncRequest{}
+}
+
+func (c *ResyncCallback) Consume(ctx context.Context, model interface{}, _ map[string]interface{}) (bool, error) {
+	request, ok := model.(*ResyncRequest)
+	if !ok {
+		return false, fmt.Errorf("expected a model of type %T but got %T", request, model)
+	}
+
+	logger := c.logger.WithContext(ctx).WithFields(log.Fields{
+		"modelId":    request.ModelId,
+		"subscriber": request.Subscriber,
+	})
+
+	if request.ModelId != c.modelId {
+		logger.Warn("ignoring resync request of subscriber %s for modelId %s, as the modelId of the publisher is %s", request.Subscriber, request.ModelId, c.modelId)
+
+		return true, nil
+	}
+
+	key := fmt.Sprintf("resync-%s-%s", c.modelId, request.Subscriber)
+	work := c.statusManager.StartWork(key, c.source.GetSteps())
+
+	published, err := c.resync(ctx, logger, key, work)
+	if err != nil {
+		work.ReportError(err)
+
+		return false, fmt.Errorf("can not resync modelId %s for subscriber %s after publishing %d records: %w", c.modelId, request.Subscriber, published, err)
+	}
+
+	work.ReportDone()
+	logger.Info("finished resync of modelId %s for subscriber %s with %d records", c.modelId, request.Subscriber, published)
+
+	return true, nil
+}
+
+func (c *ResyncCallback) resync(ctx context.Context, logger log.Logger, key string, work status.WorkItem) (int, error) {
+	published := 0
+	started := c.clock.Now()
+	attributes := map[string]interface{}{
+		AttributeResync: true,
+	}
+
+	position, err := c.checkpointer.Get(ctx, key)
+	if err != nil {
+		return published, err
+	}
+
+	if position == (ResyncPosition{}) {
+		logger.Info("starting resync of modelId %s", c.modelId)
+	} else {
+		logger.Info("continuing resync of modelId %s at step %d", c.modelId, position.Step)
+	}
+
+	for {
+		values, next, done, err := c.source.Read(ctx, position)
+		if err != nil {
+			return published, fmt.Errorf("can not read records at step %d: %w", position.Step, err)
+		}
+
+		for start := 0; start < len(values); start += c.settings.BatchSize {
+			end := start + c.settings.BatchSize
+
+			if end > len(values) {
+				end = len(values)
+			}
+
+			if err = c.publisher.PublishBatch(ctx, TypeUpdate, c.settings.Version, values[start:end], attributes); err != nil {
+				return published, err
+			}
+
+			published += end - start
+
+			if err = c.throttle(ctx, started, published); err != nil {
+				return published, err
+			}
+		}
+
+		logger.Info("published %d records of modelId %s", published, c.modelId)
+
+		if done {
+			return published, c.checkpointer.Delete(ctx, key)
+		}
+
+		if err = c.checkpointer.Checkpoint(ctx, key, next); err != nil {
+			return published, err
+		}
+
+		work.ReportProgress(next.Step, 0)
+		position = next
+	}
+}
+
+// throttle waits until the number of published records is within the rate limit again.
+func (c *ResyncCallback) throttle(ctx context.Context, started time.Time, published int) error {
+	if c.settings.RateLimit <= 0 {
+		return nil
+	}
+
+	due := started.Add(time.Duration(published) * time.Second / time.Duration(c.settings.RateLimit))
+	wait := due.Sub(c.clock.Now())
+
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(wait):
+		return nil
+	}
+}
+
+func getResyncConsumerName(name string) string {
+	return fmt.Sprintf("publisher-%s-resync", name)
+}
+
+func getResyncTopicId(name string) string {
+	return fmt.Sprintf("%s-resync", name)
+}
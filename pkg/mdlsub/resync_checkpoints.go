@@ -0,0 +1,94 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+// ResyncCheckpoint stores the position of a running resync, so a request delivered again after a restart continues
+// the resync instead of publishing all records again.
+type ResyncCheckpoint struct {
+	Key    string `json:"key" ddb:"key=hash"`
+	Step   int    `json:"step"`
+	Cursor string `json:"cursor"`
+}
+
+//go:generate mockery --name ResyncCheckpointer
+type ResyncCheckpointer interface {
+	// Get returns the position of the resync, which is the zero position for a resync not started yet.
+	Get(ctx context.Context, key string) (ResyncPosition, error)
+	Checkpoint(ctx context.Context, key string, position ResyncPosition) error
+	Delete(ctx context.Context, key string) error
+}
+
+type resyncCheckpointer struct {
+	repository ddb.Repository
+}
+
+func NewResyncCheckpointer(ctx context.Context, config cfg.Config, logger log.Logger) (ResyncCheckpointer, error) {
+	repository, err := ddb.NewRepository(ctx, config, logger, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "mdlsub-resync-checkpoints",
+		},
+		DisableTracing: true,
+		Main: ddb.MainSettings{
+			Model:              ResyncCheckpoint{},
+			ReadCapacityUnits:  5,
+			WriteCapacityUnits: 5,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository: %w", err)
+	}
+
+	return NewResyncCheckpointerWithInterfaces(repository), nil
+}
+
+func NewResyncCheckpointerWithInterfaces(repository ddb.Repository) ResyncCheckpointer {
+	return &resyncCheckpointer{
+		repository: repository,
+	}
+}
+
+func (c *resyncCheckpointer) Get(ctx context.Context, key string) (ResyncPosition, error) {
+	checkpoint := &ResyncCheckpoint{}
+	qb := c.repository.GetItemBuilder().WithHash(key).WithConsistentRead(true)
+
+	if _, err := c.repository.GetItem(ctx, qb, checkpoint); err != nil {
+		return ResyncPosition{}, fmt.Errorf("can not read checkpoint of resync %s: %w", key, err)
+	}
+
+	return ResyncPosition{
+		Step:   checkpoint.Step,
+		Cursor: checkpoint.Cursor,
+	}, nil
+}
+
+func (c *resyncCheckpointer) Checkpoint(ctx context.Context, key string, position ResyncPosition) error {
+	checkpoint := &ResyncCheckpoint{
+		Key:    key,
+		Step:   position.Step,
+		Cursor: position.Cursor,
+	}
+
+	if _, err := c.repository.PutItem(ctx, nil, checkpoint); err != nil {
+		return fmt.Errorf("can not write checkpoint of resync %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (c *resyncCheckpointer) Delete(ctx context.Context, key string) error {
+	qb := c.repository.DeleteItemBuilder().WithHash(key)
+
+	if _, err := c.repository.DeleteItem(ctx, qb, &ResyncCheckpoint{}); err != nil {
+		return fmt.Errorf("can not delete checkpoint of resync %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+// A ResyncRequester asks the publisher of the source model of a subscriber to publish all of its records again.
+//go:generate mockery --name ResyncRequester
+type ResyncRequester interface {
+	Request(ctx context.Context) error
+}
+
+type resyncRequester struct {
+	producer stream.Producer
+	name     string
+	modelId  string
+}
+
+func NewResyncRequester(ctx context.Context, config cfg.Config, logger log.Logger, name string) (ResyncRequester, error) {
+	settings := &SubscriberSettings{}
+	config.UnmarshalKey(GetSubscriberConfigKey(name), settings)
+
+	if !settings.Resync {
+		return nil, fmt.Errorf("resync is not enabled for subscriber %s", name)
+	}
+
+	producerName := getResyncProducerName(name)
+
+	producer, err := stream.NewProducer(ctx, config, logger, producerName)
+	if err != nil {
+		return nil, fmt.Errorf("can not create producer %s: %w", producerName, err)
+	}
+
+	return NewResyncRequesterWithInterfaces(producer, name, settings.SourceModel.String()), nil
+}
+
+func NewResyncRequesterWithInterfaces(producer stream.Producer, name string, modelId string) ResyncRequester {
+	return &resyncRequester{
+		producer: producer,
+		name:     name,
+		modelId:  modelId,
+	}
+}
+
+func (r *resyncRequester) Request(ctx context.Context) error {
+	request := &ResyncRequest{
+		ModelId:    r.modelId,
+		Subscriber: r.name,
+	}
+
+	if err := r.producer.WriteOne(ctx, request); err != nil {
+		return fmt.Errorf("can not request resync of modelId %s for subscriber %s: %w", r.modelId, r.name, err)
+	}
+
+	return nil
+}
+
+// ResyncRequestModule requests a resync for every of its subscribers and terminates afterwards. Run it once to
+// bootstrap a new subscriber with the existing records of its source model.
+type ResyncRequestModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+
+	logger     log.Logger
+	requesters map[string]ResyncRequester
+}
+
+func NewResyncRequestModule(names ...string) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		requesters := make(map[string]ResyncRequester, len(names))
+
+		for _, name := range names {
+			requester, err := NewResyncRequester(ctx, config, logger, name)
+			if err != nil {
+				return nil, fmt.Errorf("can not create resync requester for subscriber %s: %w", name, err)
+			}
+
+			requesters[name] = requester
+		}
+
+		return NewResyncRequestModuleWithInterfaces(logger, requesters), nil
+	}
+}
+
+func NewResyncRequestModuleWithInterfaces(logger log.Logger, requesters map[string]ResyncRequester) *ResyncRequestModule {
+	return &ResyncRequestModule{
+		logger:     logger,
+		requesters: requesters,
+	}
+}
+
+func (m *ResyncRequestModule) Run(ctx context.Context) error {
+	for name, requester := range m.requesters {
+		if err := requester.Request(ctx); err != nil {
+			return err
+		}
+
+		m.logger.Info("requested resync for subscriber %s", name)
+	}
+
+	return nil
+}
+
+func getResyncProducerName(name string) string {
+	return fmt.Sprintf("subscriber-%s-resync", name)
+}
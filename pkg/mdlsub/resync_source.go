@@ -0,0 +1,201 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+)
+
+type dbRepoResyncSource struct {
+	repo     db_repo.Repository
+	model    db_repo.ModelBased
+	pageSize int
+}
+
+// NewDbRepoResyncSource reads the records of the repository ordered by their primary key in a single step. The cursor
+// of a page is the id of the last record of the previous page, so records created during a resync are published as
+// well.
+func NewDbRepoResyncSource(repo db_repo.Repository, model db_repo.ModelBased, pageSize int) ResyncSource {
+	return &dbRepoResyncSource{
+		repo:     repo,
+		model:    model,
+		pageSize: pageSize,
+	}
+}
+
+func (s *dbRepoResyncSource) GetSteps() int {
+	return 1
+}
+
+func (s *dbRepoResyncSource) Read(ctx context.Context, position ResyncPosition) ([]interface{}, ResyncPosition, bool, error) {
+	var err error
+	var lastId uint64
+
+	if position.Cursor != "" {
+		if lastId, err = strconv.ParseUint(position.Cursor, 10, 64); err != nil {
+			return nil, position, false, fmt.Errorf("can not parse cursor %s: %w", position.Cursor, err)
+		}
+	}
+
+	primaryKey := s.repo.GetMetadata().PrimaryKey
+	result := reflect.New(reflect.SliceOf(reflect.TypeOf(s.model)))
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where(fmt.Sprintf("%s > ?", primaryKey), lastId)
+	qb.OrderBy(primaryKey, "ASC")
+	qb.Page(0, s.pageSize)
+
+	if err = s.repo.Query(ctx, qb, result.Interface()); err != nil {
+		return nil, position, false, fmt.Errorf("can not query %s: %w", s.repo.GetModelId(), err)
+	}
+
+	values := sliceToInterfaces(result.Elem())
+
+	if len(values) == 0 {
+		return values, position, true, nil
+	}
+
+	last := values[len(values)-1].(db_repo.ModelBased).GetId()
+	next := ResyncPosition{
+		Cursor: strconv.FormatUint(uint64(*last), 10),
+	}
+
+	return values, next, len(values) < s.pageSize, nil
+}
+
+type ddbResyncSource struct {
+	repo     ddb.Repository
+	model    interface{}
+	segments int
+	pageSize int
+}
+
+// NewDdbResyncSource scans the table of the repository. The segments of the scan are read one after another and are
+// the steps of the resync, every segment is read page by page. The cursor of a page is the last evaluated key of the
+// previous page.
+func NewDdbResyncSource(repo ddb.Repository, model interface{}, segments int, pageSize int) ResyncSource {
+	return &ddbResyncSource{
+		repo:     repo,
+		model:    model,
+		segments: segments,
+		pageSize: pageSize,
+	}
+}
+
+func (s *ddbResyncSource) GetSteps() int {
+	return s.segments
+}
+
+func (s *ddbResyncSource) Read(ctx context.Context, position ResyncPosition) ([]interface{}, ResyncPosition, bool, error) {
+	startKey, err := decodeDdbResyncCursor(position.Cursor)
+	if err != nil {
+		return nil, position, false, err
+	}
+
+	result := reflect.New(reflect.SliceOf(reflect.TypeOf(s.model)))
+	sb := s.repo.ScanBuilder().WithSegment(position.Step, s.segments).WithLimit(s.pageSize)
+
+	if startKey != nil {
+		sb = sb.WithExclusiveStartKey(startKey)
+	}
+
+	res, err := s.repo.Scan(ctx, sb, result.Interface())
+	if err != nil {
+		return nil, position, false, fmt.Errorf("can not scan segment %d of %d: %w", position.Step, s.segments, err)
+	}
+
+	values := sliceToInterfaces(result.Elem())
+
+	if res.LastEvaluatedKey == nil {
+		next := ResyncPosition{
+			Step: position.Step + 1,
+		}
+
+		return values, next, next.Step >= s.segments, nil
+	}
+
+	cursor, err := encodeDdbResyncCursor(res.LastEvaluatedKey)
+	if err != nil {
+		return nil, position, false, err
+	}
+
+	next := ResyncPosition{
+		Step:   position.Step,
+		Cursor: cursor,
+	}
+
+	return values, next, false, nil
+}
+
+// ddbResyncKeyValue is the json representation of a key attribute, which is a string, number or binary value.
+type ddbResyncKeyValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+func encodeDdbResyncCursor(key map[string]types.AttributeValue) (string, error) {
+	values := make(map[string]ddbResyncKeyValue, len(key))
+
+	for name, attribute := range key {
+		switch v := attribute.(type) {
+		case *types.AttributeValueMemberS:
+			values[name] = ddbResyncKeyValue{S: &v.Value}
+		case *types.AttributeValueMemberN:
+			values[name] = ddbResyncKeyValue{N: &v.Value}
+		case *types.AttributeValueMemberB:
+			values[name] = ddbResyncKeyValue{B: v.Value}
+		default:
+			return "", fmt.Errorf("key attribute %s has the unsupported type %T", name, attribute)
+		}
+	}
+
+	cursor, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("can not encode the last evaluated key: %w", err)
+	}
+
+	return string(cursor), nil
+}
+
+func decodeDdbResyncCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	values := make(map[string]ddbResyncKeyValue)
+	if err := json.Unmarshal([]byte(cursor), &values); err != nil {
+		return nil, fmt.Errorf("can not decode cursor %s: %w", cursor, err)
+	}
+
+	key := make(map[string]types.AttributeValue, len(values))
+
+	for name, value := range values {
+		switch {
+		case value.S != nil:
+			key[name] = &types.AttributeValueMemberS{Value: *value.S}
+		case value.N != nil:
+			key[name] = &types.AttributeValueMemberN{Value: *value.N}
+		default:
+			key[name] = &types.AttributeValueMemberB{Value: value.B}
+		}
+	}
+
+	return key, nil
+}
+
+func sliceToInterfaces(slice reflect.Value) []interface{} {
+	values := make([]interface{}, slice.Len())
+
+	for i := range values {
+		values[i] = slice.Index(i).Interface()
+	}
+
+	return values
+}
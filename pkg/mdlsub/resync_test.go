@@ -0,0 +1,246 @@
+package mdlsub_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/db-repo"
+	dbRepoMocks "github.com/justtrackio/gosoline/pkg/db-repo/mocks"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	ddbMocks "github.com/justtrackio/gosoline/pkg/ddb/mocks"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/log/status"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/mdlsub"
+	"github.com/justtrackio/gosoline/pkg/mdlsub/mocks"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	resyncModelId = "gosoline.test.resync.item"
+	resyncKey     = "resync-gosoline.test.resync.item-subscriber"
+)
+
+var resyncAttributes = map[string]interface{}{
+	mdlsub.AttributeResync: true,
+}
+
+type resyncItem struct {
+	db_repo.Model
+}
+
+type ResyncCallbackTestSuite struct {
+	suite.Suite
+
+	clock        clock.FakeClock
+	publisher    *mocks.Publisher
+	source       *mocks.ResyncSource
+	checkpointer *mocks.ResyncCheckpointer
+	settings     *mdlsub.ResyncSettings
+	callback     *mdlsub.ResyncCallback
+}
+
+func (s *ResyncCallbackTestSuite) SetupTest() {
+	s.clock = clock.NewFakeClockAt(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	s.publisher = new(mocks.Publisher)
+	s.source = new(mocks.ResyncSource)
+	s.source.On("GetSteps").Return(1).Maybe()
+	s.checkpointer = new(mocks.ResyncCheckpointer)
+	s.settings = &mdlsub.ResyncSettings{
+		BatchSize: 2,
+		Version:   1,
+	}
+
+	s.callback = mdlsub.NewResyncCallbackWithInterfaces(logMocks.NewLoggerMockedAll(), s.clock, s.publisher, s.source, s.checkpointer, status.NewManager(), resyncModelId, s.settings)
+}
+
+func (s *ResyncCallbackTestSuite) TearDownTest() {
+	s.publisher.AssertExpectations(s.T())
+	s.source.AssertExpectations(s.T())
+	s.checkpointer.AssertExpectations(s.T())
+}
+
+func cursor(cursor string) mdlsub.ResyncPosition {
+	return mdlsub.ResyncPosition{Cursor: cursor}
+}
+
+func (s *ResyncCallbackTestSuite) consume(modelId string) (bool, error) {
+	return s.callback.Consume(context.Background(), &mdlsub.ResyncRequest{
+		ModelId:    modelId,
+		Subscriber: "subscriber",
+	}, map[string]interface{}{})
+}
+
+func (s *ResyncCallbackTestSuite) TestResync() {
+	s.checkpointer.On("Get", mock.Anything, resyncKey).Return(mdlsub.ResyncPosition{}, nil).Once()
+	s.source.On("Read", mock.Anything, mdlsub.ResyncPosition{}).Return([]interface{}{"a", "b", "c"}, cursor("3"), false, nil).Once()
+	s.checkpointer.On("Checkpoint", mock.Anything, resyncKey, cursor("3")).Return(nil).Once()
+	s.source.On("Read", mock.Anything, cursor("3")).Return([]interface{}{"d"}, cursor("4"), true, nil).Once()
+	s.checkpointer.On("Delete", mock.Anything, resyncKey).Return(nil).Once()
+
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"a", "b"}, resyncAttributes).Return(nil).Once()
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"c"}, resyncAttributes).Return(nil).Once()
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"d"}, resyncAttributes).Return(nil).Once()
+
+	ack, err := s.consume(resyncModelId)
+	s.NoError(err)
+	s.True(ack)
+}
+
+func (s *ResyncCallbackTestSuite) TestResyncContinuesAtCheckpoint() {
+	s.checkpointer.On("Get", mock.Anything, resyncKey).Return(cursor("3"), nil).Once()
+	s.source.On("Read", mock.Anything, cursor("3")).Return([]interface{}{"d"}, cursor("4"), true, nil).Once()
+	s.checkpointer.On("Delete", mock.Anything, resyncKey).Return(nil).Once()
+
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"d"}, resyncAttributes).Return(nil).Once()
+
+	ack, err := s.consume(resyncModelId)
+	s.NoError(err)
+	s.True(ack)
+}
+
+func (s *ResyncCallbackTestSuite) TestOtherModelId() {
+	ack, err := s.consume("gosoline.test.resync.other")
+	s.NoError(err)
+	s.True(ack, "requests for other models should be acknowledged without a resync")
+}
+
+func (s *ResyncCallbackTestSuite) TestPublishFails() {
+	s.checkpointer.On("Get", mock.Anything, resyncKey).Return(mdlsub.ResyncPosition{}, nil).Once()
+	s.source.On("Read", mock.Anything, mdlsub.ResyncPosition{}).Return([]interface{}{"a", "b", "c"}, cursor("3"), true, nil).Once()
+
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"a", "b"}, resyncAttributes).Return(nil).Once()
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"c"}, resyncAttributes).Return(fmt.Errorf("boom")).Once()
+
+	ack, err := s.consume(resyncModelId)
+	s.EqualError(err, "can not resync modelId gosoline.test.resync.item for subscriber subscriber after publishing 2 records: boom")
+	s.False(ack)
+}
+
+func (s *ResyncCallbackTestSuite) TestRateLimit() {
+	s.settings.BatchSize = 1
+	s.settings.RateLimit = 1
+
+	s.checkpointer.On("Get", mock.Anything, resyncKey).Return(mdlsub.ResyncPosition{}, nil).Once()
+	s.source.On("Read", mock.Anything, mdlsub.ResyncPosition{}).Return([]interface{}{"a", "b"}, cursor("2"), true, nil).Once()
+	s.checkpointer.On("Delete", mock.Anything, resyncKey).Return(nil).Once()
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"a"}, resyncAttributes).Return(nil).Once()
+	s.publisher.On("PublishBatch", mock.Anything, mdlsub.TypeUpdate, 1, []interface{}{"b"}, resyncAttributes).Return(nil).Once()
+
+	done := make(chan error)
+
+	go func() {
+		_, err := s.consume(resyncModelId)
+		done <- err
+	}()
+
+	// every record has to wait a second before the next one can be published
+	for i := 0; i < 2; i++ {
+		s.clock.BlockUntil(1)
+		s.clock.Advance(time.Second)
+	}
+
+	s.NoError(<-done)
+}
+
+func TestResyncCallbackTestSuite(t *testing.T) {
+	suite.Run(t, new(ResyncCallbackTestSuite))
+}
+
+func TestDbRepoResyncSource(t *testing.T) {
+	repo := new(dbRepoMocks.Repository)
+	repo.On("GetMetadata").Return(db_repo.Metadata{PrimaryKey: "items.id"})
+
+	page := func(position uint64, ids ...uint) {
+		qb := db_repo.NewQueryBuilder()
+		qb.Where("items.id > ?", position)
+		qb.OrderBy("items.id", "ASC")
+		qb.Page(0, 2)
+
+		repo.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*mdlsub_test.resyncItem")).Run(func(args mock.Arguments) {
+			result := args.Get(2).(*[]*resyncItem)
+
+			for _, id := range ids {
+				*result = append(*result, &resyncItem{Model: db_repo.Model{Id: mdl.Uint(id)}})
+			}
+		}).Return(nil).Once()
+	}
+
+	page(0, 1, 2)
+	page(2, 5)
+
+	source := mdlsub.NewDbRepoResyncSource(repo, &resyncItem{}, 2)
+
+	values, next, done, err := source.Read(context.Background(), mdlsub.ResyncPosition{})
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, cursor("2"), next)
+	assert.False(t, done)
+
+	values, _, done, err = source.Read(context.Background(), next)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{&resyncItem{Model: db_repo.Model{Id: mdl.Uint(5)}}}, values)
+	assert.True(t, done)
+
+	repo.AssertExpectations(t)
+}
+
+func TestDdbResyncSource(t *testing.T) {
+	repo := new(ddbMocks.Repository)
+	sb := new(ddbMocks.ScanBuilder)
+
+	lastKey := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberN{Value: "3"},
+	}
+
+	repo.On("ScanBuilder").Return(sb)
+	sb.On("WithSegment", 1, 2).Return(sb).Twice()
+	sb.On("WithLimit", 10).Return(sb).Twice()
+	sb.On("WithExclusiveStartKey", lastKey).Return(sb).Once()
+	repo.On("Scan", mock.Anything, sb, mock.AnythingOfType("*[]mdlsub_test.resyncItem")).Run(func(args mock.Arguments) {
+		result := args.Get(2).(*[]resyncItem)
+		*result = append(*result, resyncItem{Model: db_repo.Model{Id: mdl.Uint(3)}})
+	}).Return(&ddb.ScanResult{LastEvaluatedKey: lastKey}, nil).Once()
+	repo.On("Scan", mock.Anything, sb, mock.AnythingOfType("*[]mdlsub_test.resyncItem")).Run(func(args mock.Arguments) {
+		result := args.Get(2).(*[]resyncItem)
+		*result = append(*result, resyncItem{Model: db_repo.Model{Id: mdl.Uint(4)}})
+	}).Return(&ddb.ScanResult{}, nil).Once()
+
+	source := mdlsub.NewDdbResyncSource(repo, resyncItem{}, 2, 10)
+	assert.Equal(t, 2, source.GetSteps())
+
+	values, next, done, err := source.Read(context.Background(), mdlsub.ResyncPosition{Step: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{resyncItem{Model: db_repo.Model{Id: mdl.Uint(3)}}}, values)
+	assert.Equal(t, mdlsub.ResyncPosition{Step: 1, Cursor: `{"id":{"N":"3"}}`}, next)
+	assert.False(t, done, "the segment should be continued after the last evaluated key")
+
+	values, next, done, err = source.Read(context.Background(), next)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{resyncItem{Model: db_repo.Model{Id: mdl.Uint(4)}}}, values)
+	assert.Equal(t, mdlsub.ResyncPosition{Step: 2}, next)
+	assert.True(t, done)
+
+	repo.AssertExpectations(t)
+	sb.AssertExpectations(t)
+}
+
+func TestResyncRequester(t *testing.T) {
+	producer := new(streamMocks.Producer)
+	producer.On("WriteOne", mock.Anything, &mdlsub.ResyncRequest{
+		ModelId:    resyncModelId,
+		Subscriber: "item",
+	}).Return(nil).Once()
+
+	requester := mdlsub.NewResyncRequesterWithInterfaces(producer, "item", resyncModelId)
+	assert.NoError(t, requester.Request(context.Background()))
+
+	producer.AssertExpectations(t)
+}
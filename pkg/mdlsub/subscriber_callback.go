@@ -24,6 +24,12 @@ type SubscriberSettings struct {
 	RunnerCount int         `cfg:"runner_count" default:"10" validate:"min=1"`
 	SourceModel mdl.ModelId `cfg:"source"`
 	TargetModel mdl.ModelId `cfg:"target"`
+	// The versions the source model is published in. The subscriber fails to start if it can't handle one of them.
+	PublishedVersions []int `cfg:"published_versions"`
+	// Configures the producer of the resync requests of the subscriber, see NewResyncRequester
+	Resync bool `cfg:"resync"`
+	// Settings of the es output
+	Es OutputEsSettings `cfg:"es"`
 }
 
 type SubscriberModel struct {
@@ -21,12 +21,20 @@ type Settings struct {
 }
 
 func NewSubscriberFactory(transformerFactoryMap TransformerMapTypeVersionFactories) kernel.MultiModuleFactory {
+	return NewSubscriberFactoryWithUpcasters(transformerFactoryMap, nil)
+}
+
+func NewSubscriberFactoryWithUpcasters(transformerFactoryMap TransformerMapTypeVersionFactories, upcasterFactoryMap UpcasterMapTypeVersionFactories) kernel.MultiModuleFactory {
 	return func(ctx context.Context, config cfg.Config, logger log.Logger) (map[string]kernel.ModuleFactory, error) {
-		return SubscriberFactory(ctx, config, logger, transformerFactoryMap)
+		return SubscriberFactoryWithUpcasters(ctx, config, logger, transformerFactoryMap, upcasterFactoryMap)
 	}
 }
 
 func SubscriberFactory(ctx context.Context, config cfg.Config, logger log.Logger, transformerFactories TransformerMapTypeVersionFactories) (map[string]kernel.ModuleFactory, error) {
+	return SubscriberFactoryWithUpcasters(ctx, config, logger, transformerFactories, nil)
+}
+
+func SubscriberFactoryWithUpcasters(ctx context.Context, config cfg.Config, logger log.Logger, transformerFactories TransformerMapTypeVersionFactories, upcasterFactories UpcasterMapTypeVersionFactories) (map[string]kernel.ModuleFactory, error) {
 	settings := Settings{
 		Subscribers: make(map[string]*SubscriberSettings),
 	}
@@ -35,12 +43,25 @@ func SubscriberFactory(ctx context.Context, config cfg.Config, logger log.Logger
 
 	var err error
 	var transformers ModelTransformers
+	var upcasters ModelUpcasters
 	var outputs Outputs
 
 	if transformers, err = initTransformers(ctx, config, logger, settings.Subscribers, transformerFactories); err != nil {
 		return nil, fmt.Errorf("can not create subscribers: %w", err)
 	}
 
+	if upcasters, err = initUpcasters(ctx, config, logger, upcasterFactories); err != nil {
+		return nil, fmt.Errorf("can not create subscribers: %w", err)
+	}
+
+	if transformers, err = UpcastTransformers(transformers, upcasters); err != nil {
+		return nil, fmt.Errorf("can not create subscribers: %w", err)
+	}
+
+	if err = CheckCompatibility(logger, settings.Subscribers, transformers); err != nil {
+		return nil, fmt.Errorf("can not create subscribers: %w", err)
+	}
+
 	if outputs, err = initOutputs(ctx, config, logger, settings.Subscribers, transformers); err != nil {
 		return nil, fmt.Errorf("can not create subscribers: %w", err)
 	}
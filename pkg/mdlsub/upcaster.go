@@ -0,0 +1,158 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// An Upcaster migrates the payload of a model from the version it is registered for to the next version. With an
+// upcaster for every old version of a model, a subscriber only has to implement the transformer of the latest version.
+//go:generate mockery --name Upcaster
+type Upcaster interface {
+	GetInput() interface{}
+	Upcast(ctx context.Context, inp interface{}) (out interface{}, err error)
+}
+
+type (
+	UpcasterFactory                 func(ctx context.Context, config cfg.Config, logger log.Logger) (Upcaster, error)
+	UpcasterMapTypeVersionFactories map[string]UpcasterMapVersionFactories
+	UpcasterMapVersionFactories     map[int]UpcasterFactory
+	ModelUpcasters                  map[string]VersionedModelUpcasters
+	VersionedModelUpcasters         map[int]Upcaster
+)
+
+func NewGenericUpcaster(upcaster Upcaster) UpcasterFactory {
+	return func(_ context.Context, _ cfg.Config, _ log.Logger) (Upcaster, error) {
+		return upcaster, nil
+	}
+}
+
+func initUpcasters(ctx context.Context, config cfg.Config, logger log.Logger, upcasterFactories UpcasterMapTypeVersionFactories) (ModelUpcasters, error) {
+	var err error
+	upcasters := make(ModelUpcasters)
+
+	for modelId, versionedFactories := range upcasterFactories {
+		upcasters[modelId] = make(VersionedModelUpcasters)
+
+		for version, factory := range versionedFactories {
+			if upcasters[modelId][version], err = factory(ctx, config, logger); err != nil {
+				return nil, fmt.Errorf("can not create upcaster for modelId %s in version %d: %w", modelId, version, err)
+			}
+		}
+	}
+
+	return upcasters, nil
+}
+
+// UpcastTransformers returns the transformers extended by a transformer for every version which can be upcasted to a
+// version with a transformer. The transformer of a version always takes precedence over its upcaster.
+func UpcastTransformers(transformers ModelTransformers, upcasters ModelUpcasters) (ModelTransformers, error) {
+	result := make(ModelTransformers)
+
+	for modelId, versionedTransformers := range transformers {
+		result[modelId] = make(VersionedModelTransformers)
+
+		for version, transformer := range versionedTransformers {
+			result[modelId][version] = transformer
+		}
+	}
+
+	for modelId, versionedUpcasters := range upcasters {
+		if _, ok := transformers[modelId]; !ok {
+			return nil, fmt.Errorf("there is no transformer for the upcasters of modelId %s", modelId)
+		}
+
+		for version := range versionedUpcasters {
+			if _, ok := transformers[modelId][version]; ok {
+				continue
+			}
+
+			transformer, err := newUpcastingTransformer(modelId, version, transformers[modelId], versionedUpcasters)
+			if err != nil {
+				return nil, err
+			}
+
+			result[modelId][version] = transformer
+		}
+	}
+
+	return result, nil
+}
+
+type upcastingTransformer struct {
+	version     int
+	upcasters   []Upcaster
+	transformer ModelTransformer
+}
+
+func newUpcastingTransformer(modelId string, version int, transformers VersionedModelTransformers, upcasters VersionedModelUpcasters) (*upcastingTransformer, error) {
+	chain := make([]Upcaster, 0)
+
+	for target := version; ; target++ {
+		if transformer, ok := transformers[target]; ok {
+			return &upcastingTransformer{
+				version:     version,
+				upcasters:   chain,
+				transformer: transformer,
+			}, nil
+		}
+
+		upcaster, ok := upcasters[target]
+		if !ok {
+			return nil, fmt.Errorf("can not upcast modelId %s from version %d: there is neither an upcaster nor a transformer for version %d", modelId, version, target)
+		}
+
+		chain = append(chain, upcaster)
+	}
+}
+
+func (t *upcastingTransformer) GetInput() interface{} {
+	return t.upcasters[0].GetInput()
+}
+
+func (t *upcastingTransformer) Transform(ctx context.Context, inp interface{}) (Model, error) {
+	var err error
+
+	for i, upcaster := range t.upcasters {
+		if inp, err = upcaster.Upcast(ctx, inp); err != nil {
+			return nil, fmt.Errorf("can not upcast from version %d to version %d: %w", t.version+i, t.version+i+1, err)
+		}
+	}
+
+	return t.transformer.Transform(ctx, inp)
+}
+
+// CheckCompatibility logs the versions every subscriber can handle. It fails if a subscriber can't handle one of the
+// versions configured as published versions of its source model.
+func CheckCompatibility(logger log.Logger, subscriberSettings map[string]*SubscriberSettings, transformers ModelTransformers) error {
+	for name, settings := range subscriberSettings {
+		modelId := settings.SourceModel.String()
+		versions := make([]int, 0, len(transformers[modelId]))
+		unsupported := make([]int, 0)
+
+		for version := range transformers[modelId] {
+			versions = append(versions, version)
+		}
+
+		for _, version := range settings.PublishedVersions {
+			if _, ok := transformers[modelId][version]; !ok {
+				unsupported = append(unsupported, version)
+			}
+		}
+
+		sort.Ints(versions)
+		sort.Ints(unsupported)
+
+		logger.Info("subscriber %s can handle the versions %v of modelId %s", name, versions, modelId)
+
+		if len(unsupported) > 0 {
+			return fmt.Errorf("subscriber %s can not handle the published versions %v of modelId %s", name, unsupported, modelId)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package mdlsub_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/mdlsub"
+	"github.com/justtrackio/gosoline/pkg/mdlsub/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const upcasterModelId = "gosoline.test.upcaster.number"
+
+type numberV0 struct {
+	Number string
+}
+
+type numberV1 struct {
+	Number int
+}
+
+type numberV2 struct {
+	Id     uint
+	Number int
+}
+
+func (n *numberV2) GetId() interface{} {
+	return n.Id
+}
+
+func TestUpcastTransformers(t *testing.T) {
+	upcaster0 := new(mocks.Upcaster)
+	upcaster0.On("GetInput").Return(&numberV0{})
+	upcaster0.On("Upcast", context.Background(), &numberV0{Number: "3"}).Return(&numberV1{Number: 3}, nil).Once()
+
+	upcaster1 := new(mocks.Upcaster)
+	upcaster1.On("Upcast", context.Background(), &numberV1{Number: 3}).Return(&numberV2{Id: 1, Number: 3}, nil).Once()
+
+	transformer := new(mocks.ModelTransformer)
+	transformer.On("Transform", context.Background(), &numberV2{Id: 1, Number: 3}).Return(&numberV2{Id: 1, Number: 3}, nil).Once()
+
+	transformers, err := mdlsub.UpcastTransformers(mdlsub.ModelTransformers{
+		upcasterModelId: {2: transformer},
+	}, mdlsub.ModelUpcasters{
+		upcasterModelId: {0: upcaster0, 1: upcaster1},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, transformers[upcasterModelId], 3)
+	assert.Equal(t, &numberV0{}, transformers[upcasterModelId][0].GetInput())
+
+	model, err := transformers[upcasterModelId][0].Transform(context.Background(), &numberV0{Number: "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, &numberV2{Id: 1, Number: 3}, model)
+
+	upcaster0.AssertExpectations(t)
+	upcaster1.AssertExpectations(t)
+	transformer.AssertExpectations(t)
+}
+
+func TestUpcastTransformers_TransformerTakesPrecedence(t *testing.T) {
+	transformer0 := new(mocks.ModelTransformer)
+	transformer1 := new(mocks.ModelTransformer)
+
+	transformers, err := mdlsub.UpcastTransformers(mdlsub.ModelTransformers{
+		upcasterModelId: {0: transformer0, 1: transformer1},
+	}, mdlsub.ModelUpcasters{
+		upcasterModelId: {0: new(mocks.Upcaster)},
+	})
+	assert.NoError(t, err)
+	assert.Same(t, transformer0, transformers[upcasterModelId][0])
+	assert.Same(t, transformer1, transformers[upcasterModelId][1])
+}
+
+func TestUpcastTransformers_BrokenChain(t *testing.T) {
+	_, err := mdlsub.UpcastTransformers(mdlsub.ModelTransformers{
+		upcasterModelId: {2: new(mocks.ModelTransformer)},
+	}, mdlsub.ModelUpcasters{
+		upcasterModelId: {0: new(mocks.Upcaster)},
+	})
+	assert.EqualError(t, err, "can not upcast modelId gosoline.test.upcaster.number from version 0: there is neither an upcaster nor a transformer for version 1")
+}
+
+func TestUpcastTransformers_UpcastFails(t *testing.T) {
+	upcaster := new(mocks.Upcaster)
+	upcaster.On("Upcast", context.Background(), &numberV1{Number: 3}).Return(nil, fmt.Errorf("boom")).Once()
+
+	transformers, err := mdlsub.UpcastTransformers(mdlsub.ModelTransformers{
+		upcasterModelId: {2: new(mocks.ModelTransformer)},
+	}, mdlsub.ModelUpcasters{
+		upcasterModelId: {1: upcaster},
+	})
+	assert.NoError(t, err)
+
+	_, err = transformers[upcasterModelId][1].Transform(context.Background(), &numberV1{Number: 3})
+	assert.EqualError(t, err, "can not upcast from version 1 to version 2: boom")
+
+	upcaster.AssertExpectations(t)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	logger := logMocks.NewLoggerMockedAll()
+	transformers := mdlsub.ModelTransformers{
+		upcasterModelId: {0: new(mocks.ModelTransformer), 1: new(mocks.ModelTransformer)},
+	}
+	settings := &mdlsub.SubscriberSettings{
+		SourceModel: mdl.ModelId{
+			Project:     "gosoline",
+			Family:      "test",
+			Application: "upcaster",
+			Name:        "number",
+		},
+	}
+	subscribers := map[string]*mdlsub.SubscriberSettings{
+		"number": settings,
+	}
+
+	settings.PublishedVersions = []int{0, 1}
+	assert.NoError(t, mdlsub.CheckCompatibility(logger, subscribers, transformers))
+
+	settings.PublishedVersions = []int{3, 0, 2}
+	err := mdlsub.CheckCompatibility(logger, subscribers, transformers)
+	assert.EqualError(t, err, "subscriber number can not handle the published versions [2 3] of modelId gosoline.test.upcaster.number")
+}
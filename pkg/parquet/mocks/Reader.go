@@ -16,6 +16,29 @@ type Reader struct {
 	mock.Mock
 }
 
+// ListFilesFromDate provides a mock function with given fields: ctx, datetime
+func (_m *Reader) ListFilesFromDate(ctx context.Context, datetime time.Time) ([]string, error) {
+	ret := _m.Called(ctx, datetime)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []string); ok {
+		r0 = rf(ctx, datetime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, datetime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadDate provides a mock function with given fields: ctx, datetime, target
 func (_m *Reader) ReadDate(ctx context.Context, datetime time.Time, target interface{}) error {
 	ret := _m.Called(ctx, datetime, target)
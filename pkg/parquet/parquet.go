@@ -15,19 +15,27 @@ type (
 )
 
 const (
-	NamingStrategyDtErrored   = "errors/yyyy/MM/dd"
-	NamingStrategyDtSeparated = "yyyy/MM/dd"
+	NamingStrategyDtErrored       = "errors/yyyy/MM/dd"
+	NamingStrategyDtSeparated     = "yyyy/MM/dd"
+	NamingStrategyDtHourSeparated = "yyyy/MM/dd/HH"
 )
 
 var s3PrefixNamingStrategies = map[string]S3PrefixNamingStrategy{
-	NamingStrategyDtErrored:   dtErrored,
-	NamingStrategyDtSeparated: dtSeparated,
+	NamingStrategyDtErrored:       dtErrored,
+	NamingStrategyDtSeparated:     dtSeparated,
+	NamingStrategyDtHourSeparated: dtHourSeparated,
 }
 
 func RegisterS3PrefixNamingStrategy(name string, strategy S3PrefixNamingStrategy) {
 	s3PrefixNamingStrategies[name] = strategy
 }
 
+func GetS3PrefixNamingStrategy(name string) (S3PrefixNamingStrategy, bool) {
+	strategy, ok := s3PrefixNamingStrategies[name]
+
+	return strategy, ok
+}
+
 type ReaderSettings struct {
 	ModelId        mdl.ModelId
 	NamingStrategy string
@@ -44,10 +52,24 @@ var s3BucketNamingStrategy = func(appId cfg.AppId) string {
 	return fmt.Sprintf("%s-%s-%s", appId.Project, appId.Environment, appId.Family)
 }
 
+// GetS3BucketName returns the bucket the files of the model are stored in.
+func GetS3BucketName(modelId mdl.ModelId) string {
+	return s3BucketNamingStrategy(cfg.AppId{
+		Project:     modelId.Project,
+		Environment: modelId.Environment,
+		Family:      modelId.Family,
+		Application: modelId.Application,
+	})
+}
+
 func dtSeparated(modelId mdl.ModelId, datetime time.Time) string {
 	return fmt.Sprintf("datalake/%s/year=%s/month=%s/day=%s", modelId.Name, datetime.Format("2006"), datetime.Format("01"), datetime.Format("02"))
 }
 
+func dtHourSeparated(modelId mdl.ModelId, datetime time.Time) string {
+	return fmt.Sprintf("%s/hour=%s", dtSeparated(modelId, datetime), datetime.Format("15"))
+}
+
 func dtErrored(modelId mdl.ModelId, datetime time.Time) string {
 	return fmt.Sprintf("datalake-errors/%s/result=format-conversion-failed/year=%s/month=%s/day=%s", modelId.Name, datetime.Format("2006"), datetime.Format("01"), datetime.Format("02"))
 }
@@ -66,6 +88,11 @@ func WithKeyNamingStrategy(strategy S3KeyNamingStrategy) {
 	s3KeyNamingStrategy = strategy
 }
 
+// GetS3Key returns the key of a new file of the model using the configured key naming strategy.
+func GetS3Key(modelId mdl.ModelId, datetime time.Time, prefixCallback S3PrefixNamingStrategy) string {
+	return s3KeyNamingStrategy(modelId, datetime, prefixCallback)
+}
+
 type Partitionable interface {
 	GetPartitionTimestamp() time.Time
 }
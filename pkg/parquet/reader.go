@@ -35,6 +35,7 @@ type (
 
 //go:generate mockery --name Reader
 type Reader interface {
+	ListFilesFromDate(ctx context.Context, datetime time.Time) ([]string, error)
 	ReadDate(ctx context.Context, datetime time.Time, target interface{}) error
 	ReadDateAsync(ctx context.Context, datetime time.Time, target interface{}, callback ResultCallback) error
 	ReadFileIntoTarget(ctx context.Context, file string, target interface{}, batchSize int, offset int64) error
@@ -121,7 +122,7 @@ func (r *s3Reader) ReadDateAsync(ctx context.Context, datetime time.Time, target
 		return fmt.Errorf("target needs to be a pointer to a slice, but is %T", target)
 	}
 
-	files, err := r.listFilesFromDate(ctx, datetime)
+	files, err := r.ListFilesFromDate(ctx, datetime)
 	if err != nil {
 		return err
 	}
@@ -302,7 +303,8 @@ func (r *s3Reader) ReadFileColumns(ctx context.Context, columnNames []string, fi
 	return results, nil
 }
 
-func (r *s3Reader) listFilesFromDate(ctx context.Context, datetime time.Time) ([]string, error) {
+// ListFilesFromDate returns the keys of all files of the model written on the day of datetime.
+func (r *s3Reader) ListFilesFromDate(ctx context.Context, datetime time.Time) ([]string, error) {
 	prefix := r.prefixNamingStrategy(r.modelId, datetime)
 
 	files, err := r.listFiles(ctx, prefix)
@@ -383,10 +385,5 @@ func (r *s3Reader) decodeTimeMillisHook() interface{} {
 }
 
 func (r *s3Reader) getBucketName() string {
-	return s3BucketNamingStrategy(cfg.AppId{
-		Project:     r.modelId.Project,
-		Environment: r.modelId.Environment,
-		Family:      r.modelId.Family,
-		Application: r.modelId.Application,
-	})
+	return GetS3BucketName(r.modelId)
 }
@@ -186,12 +186,7 @@ func (w *s3Writer) parseItems(items interface{}) (string, []string, error) {
 }
 
 func (w *s3Writer) getBucketName() string {
-	return s3BucketNamingStrategy(cfg.AppId{
-		Project:     w.modelId.Project,
-		Environment: w.modelId.Environment,
-		Family:      w.modelId.Family,
-		Application: w.modelId.Application,
-	})
+	return GetS3BucketName(w.modelId)
 }
 
 func makeTags(tags map[string]string) []types.Tag {
@@ -1,11 +1,13 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/exec"
+	"github.com/justtrackio/gosoline/pkg/kernel"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
@@ -33,12 +35,22 @@ func ProvideClient(config cfg.Config, logger log.Logger, name string) (Client, e
 		return client, nil
 	}
 
-	var err error
-	if clients[cachKey], err = NewClient(config, logger, name); err != nil {
+	client, err := NewClient(config, logger, name)
+	if err != nil {
 		return nil, err
 	}
 
-	return clients[cachKey], nil
+	clients[cachKey] = client
+
+	kernel.RegisterHealthCheck(fmt.Sprintf("redis.%s", name), kernel.HealthCheckFunc(func(ctx context.Context) error {
+		if !client.IsAlive(ctx) {
+			return fmt.Errorf("redis %s at %s is not alive", name, settings.Address)
+		}
+
+		return nil
+	}))
+
+	return client, nil
 }
 
 func ReadSettings(config cfg.Config, name string) *Settings {
@@ -0,0 +1,92 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/kvstore"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+type CheckpointSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// Name of the kvstore to persist the checkpoints in
+	Store string `cfg:"store" default:"replay_checkpoints"`
+}
+
+// A Checkpoint is the position of a replay. Offset is the number of records of File which have been handled
+// already, filtered records included. A finished replay is not started again until its checkpoint is deleted.
+type Checkpoint struct {
+	File     string `json:"file"`
+	Offset   int    `json:"offset"`
+	Finished bool   `json:"finished"`
+}
+
+//go:generate mockery --name Checkpoints
+type Checkpoints interface {
+	Get(ctx context.Context) (*Checkpoint, error)
+	Put(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+func NewCheckpoints(ctx context.Context, config cfg.Config, logger log.Logger, name string, settings *CheckpointSettings) (Checkpoints, error) {
+	if !settings.Enabled {
+		return NewNoopCheckpoints(), nil
+	}
+
+	store, err := kvstore.NewConfigurableKvStore(ctx, config, logger, settings.Store)
+	if err != nil {
+		return nil, fmt.Errorf("can not create kvstore %s: %w", settings.Store, err)
+	}
+
+	return NewCheckpointsWithInterfaces(store, name), nil
+}
+
+type kvStoreCheckpoints struct {
+	store kvstore.KvStore
+	name  string
+}
+
+func NewCheckpointsWithInterfaces(store kvstore.KvStore, name string) Checkpoints {
+	return &kvStoreCheckpoints{
+		store: store,
+		name:  name,
+	}
+}
+
+func (c *kvStoreCheckpoints) Get(ctx context.Context) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{}
+
+	found, err := c.store.Get(ctx, c.name, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("can not get checkpoint of replay %s: %w", c.name, err)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return checkpoint, nil
+}
+
+func (c *kvStoreCheckpoints) Put(ctx context.Context, checkpoint *Checkpoint) error {
+	if err := c.store.Put(ctx, c.name, checkpoint); err != nil {
+		return fmt.Errorf("can not put checkpoint of replay %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+type noopCheckpoints struct{}
+
+func NewNoopCheckpoints() Checkpoints {
+	return noopCheckpoints{}
+}
+
+func (noopCheckpoints) Get(_ context.Context) (*Checkpoint, error) {
+	return nil, nil
+}
+
+func (noopCheckpoints) Put(_ context.Context, _ *Checkpoint) error {
+	return nil
+}
@@ -0,0 +1,279 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/log/status"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/spf13/cast"
+)
+
+// Settings of a replay are read from replay.<name>. The messages are either published to a stream output or
+// injected into the in-memory input of a consumer.
+type Settings struct {
+	Source SourceSettings `cfg:"source"`
+	// Name of the stream output to publish the messages to
+	Output string `cfg:"output"`
+	// Name of the consumer to inject the messages into. Its input has to be of type inMemory.
+	Consumer string `cfg:"consumer"`
+	// Maximum time the consumer may take to acknowledge a batch of injected messages before the replay fails
+	AckTimeout time.Duration `cfg:"ack_timeout" default:"5m" validate:"min=1"`
+	// Only messages archived within this time range are replayed. A zero time means the range is open on that side.
+	From time.Time `cfg:"from"`
+	To   time.Time `cfg:"to"`
+	// Only messages having all of these attributes with the given values are replayed
+	Attributes map[string]string `cfg:"attributes"`
+	BatchSize  int               `cfg:"batch_size" default:"10" validate:"min=1"`
+	// Maximum number of messages replayed per second, 0 disables the limit
+	RateLimit   int                `cfg:"rate_limit" default:"0" validate:"min=0"`
+	Checkpoints CheckpointSettings `cfg:"checkpoints"`
+}
+
+func ConfigurableReplayKey(name string) string {
+	return fmt.Sprintf("replay.%s", name)
+}
+
+// Module replays archived messages from a source to a target and terminates afterwards. Progress is reported to the
+// status manager, so it can be printed by sending a SIGUSR1 if the status module is running.
+type Module struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+
+	logger        log.Logger
+	clock         clock.Clock
+	source        Source
+	target        Target
+	checkpoints   Checkpoints
+	statusManager status.Manager
+	name          string
+	settings      *Settings
+
+	work     status.WorkItem
+	replayed int
+	started  time.Time
+}
+
+func NewModule(name string) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		logger = logger.WithChannel(fmt.Sprintf("replay-%s", name))
+
+		settings := &Settings{}
+		config.UnmarshalKey(ConfigurableReplayKey(name), settings)
+
+		source, err := NewSource(ctx, config, logger, &settings.Source)
+		if err != nil {
+			return nil, fmt.Errorf("can not create source of replay %s: %w", name, err)
+		}
+
+		target, err := NewTarget(ctx, config, logger, settings)
+		if err != nil {
+			return nil, fmt.Errorf("can not create target of replay %s: %w", name, err)
+		}
+
+		checkpoints, err := NewCheckpoints(ctx, config, logger, name, &settings.Checkpoints)
+		if err != nil {
+			return nil, fmt.Errorf("can not create checkpoints of replay %s: %w", name, err)
+		}
+
+		return NewModuleWithInterfaces(logger, clock.NewRealClock(), source, target, checkpoints, status.ProvideManager(), name, settings), nil
+	}
+}
+
+func NewModuleWithInterfaces(
+	logger log.Logger,
+	clock clock.Clock,
+	source Source,
+	target Target,
+	checkpoints Checkpoints,
+	statusManager status.Manager,
+	name string,
+	settings *Settings,
+) *Module {
+	return &Module{
+		logger:        logger,
+		clock:         clock,
+		source:        source,
+		target:        target,
+		checkpoints:   checkpoints,
+		statusManager: statusManager,
+		name:          name,
+		settings:      settings,
+	}
+}
+
+func (m *Module) Run(ctx context.Context) error {
+	defer m.target.Done()
+
+	// the number of steps is known after listing the files, the work item is replaced then
+	m.work = m.statusManager.StartWork(m.workKey(), 1)
+
+	err := m.run(ctx)
+
+	if err != nil {
+		m.work.ReportError(err)
+
+		return err
+	}
+
+	m.work.ReportDone()
+
+	return nil
+}
+
+func (m *Module) run(ctx context.Context) error {
+	checkpoint, err := m.checkpoints.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint != nil && checkpoint.Finished {
+		m.logger.Info("replay %s has finished already, delete its checkpoint to run it again", m.name)
+
+		return nil
+	}
+
+	files, err := m.source.ListFiles(ctx, m.settings.From, m.settings.To)
+	if err != nil {
+		return fmt.Errorf("can not list the files of replay %s: %w", m.name, err)
+	}
+
+	start, offset := m.resumeAt(files, checkpoint)
+	m.work = m.statusManager.StartWork(m.workKey(), len(files))
+	m.started = m.clock.Now()
+
+	m.logger.Info("replaying %d files starting at file %d and offset %d", len(files), start, offset)
+
+	for i := start; i < len(files); i++ {
+		m.work.ReportProgress(i, 0)
+
+		if err = m.replayFile(ctx, i, files[i], offset); err != nil {
+			return fmt.Errorf("can not replay file %s: %w", files[i], err)
+		}
+
+		offset = 0
+	}
+
+	if err = m.checkpoints.Put(ctx, &Checkpoint{Finished: true}); err != nil {
+		return err
+	}
+
+	m.logger.Info("replayed %d messages of %d files", m.replayed, len(files))
+
+	return nil
+}
+
+func (m *Module) workKey() string {
+	return fmt.Sprintf("replay-%s", m.name)
+}
+
+// resumeAt returns the index of the file and the offset in it to continue a replay at.
+func (m *Module) resumeAt(files []string, checkpoint *Checkpoint) (int, int) {
+	if checkpoint == nil {
+		return 0, 0
+	}
+
+	for i, file := range files {
+		if file == checkpoint.File {
+			return i, checkpoint.Offset
+		}
+	}
+
+	m.logger.Warn("can not find the file %s of the checkpoint, starting from the beginning", checkpoint.File)
+
+	return 0, 0
+}
+
+func (m *Module) replayFile(ctx context.Context, index int, file string, offset int) error {
+	records, err := m.source.ReadFile(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	batch := make([]*stream.Message, 0, m.settings.BatchSize)
+
+	for i := offset; i < len(records); i++ {
+		if m.matches(records[i]) {
+			batch = append(batch, records[i].Message)
+		}
+
+		if len(batch) < m.settings.BatchSize && i < len(records)-1 {
+			continue
+		}
+
+		if err = m.write(ctx, batch); err != nil {
+			return err
+		}
+
+		if err = m.checkpoints.Put(ctx, &Checkpoint{File: file, Offset: i + 1}); err != nil {
+			return err
+		}
+
+		m.work.ReportProgress(index, float64(i+1)/float64(len(records))*100)
+		batch = make([]*stream.Message, 0, m.settings.BatchSize)
+	}
+
+	m.logger.Info("replayed file %s", file)
+
+	return nil
+}
+
+func (m *Module) matches(record Record) bool {
+	if !record.Timestamp.IsZero() && !m.settings.From.IsZero() && record.Timestamp.Before(m.settings.From) {
+		return false
+	}
+
+	if !record.Timestamp.IsZero() && !m.settings.To.IsZero() && record.Timestamp.After(m.settings.To) {
+		return false
+	}
+
+	for key, value := range m.settings.Attributes {
+		attribute, ok := record.Message.Attributes[key]
+
+		if !ok || cast.ToString(attribute) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Module) write(ctx context.Context, batch []*stream.Message) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := m.target.Write(ctx, batch); err != nil {
+		return fmt.Errorf("can not write %d messages: %w", len(batch), err)
+	}
+
+	m.replayed += len(batch)
+
+	return m.throttle(ctx)
+}
+
+// throttle waits until the number of replayed messages is within the rate limit again.
+func (m *Module) throttle(ctx context.Context) error {
+	if m.settings.RateLimit <= 0 {
+		return nil
+	}
+
+	due := m.started.Add(time.Duration(m.replayed) * time.Second / time.Duration(m.settings.RateLimit))
+	wait := due.Sub(m.clock.Now())
+
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.clock.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,156 @@
+package replay_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/log/status"
+	"github.com/justtrackio/gosoline/pkg/replay"
+	replayMocks "github.com/justtrackio/gosoline/pkg/replay/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ModuleTestSuite struct {
+	suite.Suite
+
+	clock       clock.FakeClock
+	source      *replayMocks.Source
+	target      *replayMocks.Target
+	checkpoints *replayMocks.Checkpoints
+	settings    *replay.Settings
+	module      *replay.Module
+}
+
+func (s *ModuleTestSuite) SetupTest() {
+	s.clock = clock.NewFakeClockAt(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	s.source = new(replayMocks.Source)
+	s.target = new(replayMocks.Target)
+	s.checkpoints = new(replayMocks.Checkpoints)
+	s.settings = &replay.Settings{
+		From:      time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2021, 5, 30, 23, 59, 59, 0, time.UTC),
+		BatchSize: 2,
+	}
+
+	s.module = replay.NewModuleWithInterfaces(logMocks.NewLoggerMockedAll(), s.clock, s.source, s.target, s.checkpoints, status.NewManager(), "test", s.settings)
+}
+
+func (s *ModuleTestSuite) TearDownTest() {
+	s.source.AssertExpectations(s.T())
+	s.target.AssertExpectations(s.T())
+	s.checkpoints.AssertExpectations(s.T())
+}
+
+func (s *ModuleTestSuite) record(day int, body string, attributes map[string]interface{}) replay.Record {
+	return replay.Record{
+		Timestamp: time.Date(2021, 5, day, 0, 0, 0, 0, time.UTC),
+		Message:   stream.NewMessage(body, attributes),
+	}
+}
+
+func (s *ModuleTestSuite) TestReplay() {
+	s.settings.Attributes = map[string]string{"type": "order"}
+
+	a1 := s.record(2, "a1", map[string]interface{}{"type": "order"})
+	a2 := s.record(2, "a2", map[string]interface{}{"type": "invoice"})
+	a3 := s.record(3, "a3", map[string]interface{}{"type": "order"})
+	a4 := s.record(4, "a4", map[string]interface{}{"type": "order"})
+	b1 := s.record(30, "b1", map[string]interface{}{"type": "order"})
+	b2 := s.record(31, "b2", map[string]interface{}{"type": "order"})
+
+	s.checkpoints.On("Get", mock.Anything).Return(nil, nil).Once()
+	s.source.On("ListFiles", mock.Anything, s.settings.From, s.settings.To).Return([]string{"a", "b"}, nil).Once()
+	s.source.On("ReadFile", mock.Anything, "a").Return([]replay.Record{a1, a2, a3, a4}, nil).Once()
+	s.source.On("ReadFile", mock.Anything, "b").Return([]replay.Record{b1, b2}, nil).Once()
+
+	s.target.On("Write", mock.Anything, []*stream.Message{a1.Message, a3.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "a", Offset: 3}).Return(nil).Once()
+	s.target.On("Write", mock.Anything, []*stream.Message{a4.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "a", Offset: 4}).Return(nil).Once()
+	s.target.On("Write", mock.Anything, []*stream.Message{b1.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "b", Offset: 2}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{Finished: true}).Return(nil).Once()
+	s.target.On("Done").Once()
+
+	err := s.module.Run(context.Background())
+	s.NoError(err)
+}
+
+func (s *ModuleTestSuite) TestResume() {
+	b1 := s.record(3, "b1", nil)
+	b2 := s.record(3, "b2", nil)
+
+	s.checkpoints.On("Get", mock.Anything).Return(&replay.Checkpoint{File: "b", Offset: 1}, nil).Once()
+	s.source.On("ListFiles", mock.Anything, s.settings.From, s.settings.To).Return([]string{"a", "b"}, nil).Once()
+	s.source.On("ReadFile", mock.Anything, "b").Return([]replay.Record{b1, b2}, nil).Once()
+
+	s.target.On("Write", mock.Anything, []*stream.Message{b2.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "b", Offset: 2}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{Finished: true}).Return(nil).Once()
+	s.target.On("Done").Once()
+
+	err := s.module.Run(context.Background())
+	s.NoError(err)
+}
+
+func (s *ModuleTestSuite) TestFinished() {
+	s.checkpoints.On("Get", mock.Anything).Return(&replay.Checkpoint{Finished: true}, nil).Once()
+	s.target.On("Done").Once()
+
+	err := s.module.Run(context.Background())
+	s.NoError(err)
+}
+
+func (s *ModuleTestSuite) TestWriteFails() {
+	a1 := s.record(2, "a1", nil)
+
+	s.checkpoints.On("Get", mock.Anything).Return(nil, nil).Once()
+	s.source.On("ListFiles", mock.Anything, s.settings.From, s.settings.To).Return([]string{"a"}, nil).Once()
+	s.source.On("ReadFile", mock.Anything, "a").Return([]replay.Record{a1}, nil).Once()
+	s.target.On("Write", mock.Anything, []*stream.Message{a1.Message}).Return(fmt.Errorf("boom")).Once()
+	s.target.On("Done").Once()
+
+	err := s.module.Run(context.Background())
+	s.EqualError(err, "can not replay file a: can not write 1 messages: boom")
+}
+
+func (s *ModuleTestSuite) TestRateLimit() {
+	s.settings.BatchSize = 1
+	s.settings.RateLimit = 1
+
+	a1 := s.record(2, "a1", nil)
+	a2 := s.record(2, "a2", nil)
+
+	s.checkpoints.On("Get", mock.Anything).Return(nil, nil).Once()
+	s.source.On("ListFiles", mock.Anything, s.settings.From, s.settings.To).Return([]string{"a"}, nil).Once()
+	s.source.On("ReadFile", mock.Anything, "a").Return([]replay.Record{a1, a2}, nil).Once()
+	s.target.On("Write", mock.Anything, []*stream.Message{a1.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "a", Offset: 1}).Return(nil).Once()
+	s.target.On("Write", mock.Anything, []*stream.Message{a2.Message}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{File: "a", Offset: 2}).Return(nil).Once()
+	s.checkpoints.On("Put", mock.Anything, &replay.Checkpoint{Finished: true}).Return(nil).Once()
+	s.target.On("Done").Once()
+
+	done := make(chan error)
+	go func() {
+		done <- s.module.Run(context.Background())
+	}()
+
+	// every message has to wait a second before the next one can be written
+	for i := 0; i < 2; i++ {
+		s.clock.BlockUntil(1)
+		s.clock.Advance(time.Second)
+	}
+
+	s.NoError(<-done)
+}
+
+func TestModuleTestSuite(t *testing.T) {
+	suite.Run(t, new(ModuleTestSuite))
+}
@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/spf13/cast"
+)
+
+const (
+	SourceTypeFile    = "file"
+	SourceTypeParquet = "parquet"
+	SourceTypeS3      = "s3"
+)
+
+// A Record is a message read from an archive. Timestamp is the time the message was archived and is zero if the
+// source doesn't know it.
+type Record struct {
+	Timestamp time.Time
+	Message   *stream.Message
+}
+
+// A Source provides archived messages split into files. Files are replayed one after another in the order they are
+// listed, which allows to resume a replay at the last replayed message of a file.
+//go:generate mockery --name Source
+type Source interface {
+	// ListFiles returns the files which may contain messages archived between from and to. A zero time means the
+	// range is open on that side.
+	ListFiles(ctx context.Context, from time.Time, to time.Time) ([]string, error)
+	// ReadFile returns all records of a file in the order they were archived.
+	ReadFile(ctx context.Context, file string) ([]Record, error)
+}
+
+type SourceSettings struct {
+	Type string `cfg:"type" default:"file" validate:"required"`
+	// Path of a file or a directory of files with one json encoded stream.Message per line (type file).
+	Path string `cfg:"path"`
+	// Bucket and prefix of objects with one json encoded stream.Message per line (type s3).
+	Bucket string `cfg:"bucket"`
+	Prefix string `cfg:"prefix"`
+	// Model and naming strategy of a parquet dataset of archive.ParquetMessage records (type parquet).
+	ModelId        mdl.ModelId `cfg:"model_id"`
+	NamingStrategy string      `cfg:"naming_strategy" default:"yyyy/MM/dd"`
+	// Message attribute holding the time a message was archived at. Only used for the file and s3 type, as
+	// archive.ParquetMessage records carry their timestamp.
+	TimestampAttribute string `cfg:"timestamp_attribute"`
+}
+
+type SourceFactory func(ctx context.Context, config cfg.Config, logger log.Logger, settings *SourceSettings) (Source, error)
+
+var sourceFactories = map[string]SourceFactory{
+	SourceTypeFile:    NewFileSource,
+	SourceTypeParquet: NewParquetSource,
+	SourceTypeS3:      NewS3Source,
+}
+
+func SetSourceFactory(typ string, factory SourceFactory) {
+	sourceFactories[typ] = factory
+}
+
+func NewSource(ctx context.Context, config cfg.Config, logger log.Logger, settings *SourceSettings) (Source, error) {
+	factory, ok := sourceFactories[settings.Type]
+
+	if !ok {
+		return nil, fmt.Errorf("invalid replay source of type %s", settings.Type)
+	}
+
+	source, err := factory(ctx, config, logger, settings)
+	if err != nil {
+		return nil, fmt.Errorf("can not create replay source of type %s: %w", settings.Type, err)
+	}
+
+	return source, nil
+}
+
+// readJsonLines reads messages in the format of stream.FileOutput. The timestamp of a record is taken from the given
+// attribute if it is set.
+func readJsonLines(reader io.Reader, timestampAttribute string) ([]Record, error) {
+	records := make([]Record, 0)
+	buffered := bufio.NewReader(reader)
+
+	for line := 1; ; line++ {
+		data, err := buffered.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			record, recordErr := readJsonLine(data, timestampAttribute)
+			if recordErr != nil {
+				return nil, fmt.Errorf("can not read message in line %d: %w", line, recordErr)
+			}
+
+			records = append(records, record)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can not read line %d: %w", line, err)
+		}
+	}
+}
+
+func readJsonLine(data []byte, timestampAttribute string) (Record, error) {
+	msg := &stream.Message{}
+
+	if err := msg.UnmarshalFromBytes(data); err != nil {
+		return Record{}, fmt.Errorf("can not unmarshal message: %w", err)
+	}
+
+	record := Record{
+		Message: msg,
+	}
+
+	value, ok := msg.Attributes[timestampAttribute]
+	if timestampAttribute == "" || !ok {
+		return record, nil
+	}
+
+	timestamp, err := cast.ToTimeE(value)
+	if err != nil {
+		return Record{}, fmt.Errorf("can not parse the timestamp attribute %s: %w", timestampAttribute, err)
+	}
+
+	record.Timestamp = timestamp
+
+	return record, nil
+}
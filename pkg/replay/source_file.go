@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+type fileSource struct {
+	path               string
+	timestampAttribute string
+}
+
+func NewFileSource(_ context.Context, _ cfg.Config, _ log.Logger, settings *SourceSettings) (Source, error) {
+	if settings.Path == "" {
+		return nil, fmt.Errorf("the path of a file source is required")
+	}
+
+	return NewFileSourceWithInterfaces(settings.Path, settings.TimestampAttribute), nil
+}
+
+func NewFileSourceWithInterfaces(path string, timestampAttribute string) Source {
+	return &fileSource{
+		path:               path,
+		timestampAttribute: timestampAttribute,
+	}
+}
+
+// ListFiles returns the path itself if it is a file or all files of the directory sorted by name. Files can't be
+// filtered by time, so all of them are returned.
+func (s *fileSource) ListFiles(_ context.Context, _ time.Time, _ time.Time) ([]string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("can not stat %s: %w", s.path, err)
+	}
+
+	if !info.IsDir() {
+		return []string{s.path}, nil
+	}
+
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read directory %s: %w", s.path, err)
+	}
+
+	files := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		files = append(files, filepath.Join(s.path, entry.Name()))
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (s *fileSource) ReadFile(_ context.Context, file string) ([]Record, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("can not open file %s: %w", file, err)
+	}
+
+	defer reader.Close()
+
+	records, err := readJsonLines(reader, s.timestampAttribute)
+	if err != nil {
+		return nil, fmt.Errorf("can not read file %s: %w", file, err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/archive"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/parquet"
+)
+
+type parquetSource struct {
+	reader   parquet.Reader
+	clock    clock.Clock
+	interval time.Duration
+}
+
+func NewParquetSource(ctx context.Context, config cfg.Config, logger log.Logger, settings *SourceSettings) (Source, error) {
+	settings.ModelId.PadFromConfig(config)
+
+	reader, err := parquet.NewReader(ctx, config, logger, &parquet.ReaderSettings{
+		ModelId:        settings.ModelId,
+		NamingStrategy: settings.NamingStrategy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create parquet reader: %w", err)
+	}
+
+	interval := 24 * time.Hour
+	if settings.NamingStrategy == parquet.NamingStrategyDtHourSeparated {
+		interval = time.Hour
+	}
+
+	return NewParquetSourceWithInterfaces(reader, clock.NewRealClock(), interval), nil
+}
+
+// NewParquetSourceWithInterfaces creates a source for a dataset partitioned by the interval, which has to be a day
+// or an hour.
+func NewParquetSourceWithInterfaces(reader parquet.Reader, clock clock.Clock, interval time.Duration) Source {
+	return &parquetSource{
+		reader:   reader,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// ListFiles returns the files of every partition between from and to. As the files are listed by partition, from
+// is required. If to is zero, the files up to now are returned.
+func (s *parquetSource) ListFiles(ctx context.Context, from time.Time, to time.Time) ([]string, error) {
+	if from.IsZero() {
+		return nil, fmt.Errorf("a parquet source needs the start of the time range")
+	}
+
+	if to.IsZero() {
+		to = s.clock.Now()
+	}
+
+	files := make([]string, 0)
+	from = time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), 0, 0, 0, from.Location())
+
+	if s.interval >= 24*time.Hour {
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	}
+
+	for partition := from; !partition.After(to); partition = partition.Add(s.interval) {
+		partitionFiles, err := s.reader.ListFilesFromDate(ctx, partition)
+		if err != nil {
+			return nil, fmt.Errorf("can not list files of %s: %w", partition.Format(time.RFC3339), err)
+		}
+
+		files = append(files, partitionFiles...)
+	}
+
+	return files, nil
+}
+
+func (s *parquetSource) ReadFile(ctx context.Context, file string) ([]Record, error) {
+	messages := make([]archive.ParquetMessage, 0)
+
+	if err := s.reader.ReadFileIntoTarget(ctx, file, &messages, -1, 0); err != nil {
+		return nil, fmt.Errorf("can not read parquet file %s: %w", file, err)
+	}
+
+	records := make([]Record, len(messages))
+
+	for i := range messages {
+		msg, err := messages[i].GetMessage()
+		if err != nil {
+			return nil, fmt.Errorf("can not decode message %d of parquet file %s: %w", i, file, err)
+		}
+
+		records[i] = Record{
+			Timestamp: messages[i].Timestamp,
+			Message:   msg,
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,93 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoS3 "github.com/justtrackio/gosoline/pkg/cloud/aws/s3"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+type s3Source struct {
+	client             gosoS3.Client
+	bucket             string
+	prefix             string
+	timestampAttribute string
+}
+
+func NewS3Source(ctx context.Context, config cfg.Config, logger log.Logger, settings *SourceSettings) (Source, error) {
+	if settings.Bucket == "" {
+		return nil, fmt.Errorf("the bucket of a s3 source is required")
+	}
+
+	client, err := gosoS3.ProvideClient(ctx, config, logger, "default")
+	if err != nil {
+		return nil, fmt.Errorf("can not create s3 client default: %w", err)
+	}
+
+	return NewS3SourceWithInterfaces(client, settings.Bucket, settings.Prefix, settings.TimestampAttribute), nil
+}
+
+func NewS3SourceWithInterfaces(client gosoS3.Client, bucket string, prefix string, timestampAttribute string) Source {
+	return &s3Source{
+		client:             client,
+		bucket:             bucket,
+		prefix:             prefix,
+		timestampAttribute: timestampAttribute,
+	}
+}
+
+// ListFiles returns the keys of all objects with the prefix sorted by key. Objects last modified before from can't
+// contain messages archived after it and are skipped.
+func (s *s3Source) ListFiles(ctx context.Context, from time.Time, _ time.Time) ([]string, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: mdl.String(s.bucket),
+		Prefix: mdl.String(s.prefix),
+	}
+
+	files := make([]string, 0)
+
+	for {
+		out, err := s.client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("can not list objects of s3://%s/%s: %w", s.bucket, s.prefix, err)
+		}
+
+		for _, obj := range out.Contents {
+			if !from.IsZero() && obj.LastModified != nil && obj.LastModified.Before(from) {
+				continue
+			}
+
+			files = append(files, *obj.Key)
+		}
+
+		if !out.IsTruncated {
+			return files, nil
+		}
+
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func (s *s3Source) ReadFile(ctx context.Context, file string) ([]Record, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: mdl.String(s.bucket),
+		Key:    mdl.String(file),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not get object s3://%s/%s: %w", s.bucket, file, err)
+	}
+
+	defer out.Body.Close()
+
+	records, err := readJsonLines(out.Body, s.timestampAttribute)
+	if err != nil {
+		return nil, fmt.Errorf("can not read object s3://%s/%s: %w", s.bucket, file, err)
+	}
+
+	return records, nil
+}
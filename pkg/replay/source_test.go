@@ -0,0 +1,103 @@
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	s3Mocks "github.com/justtrackio/gosoline/pkg/cloud/aws/s3/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/replay"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const jsonLines = `{"attributes":{"archivedAt":"2021-05-02T10:00:00Z","encoding":"application/json"},"body":"{\"id\":1}"}
+{"attributes":{"encoding":"application/json"},"body":"{\"id\":2}"}
+`
+
+func TestFileSource(t *testing.T) {
+	path := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "b.jsonl"), []byte(jsonLines), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "a.jsonl"), []byte(""), 0o644))
+	assert.NoError(t, os.Mkdir(filepath.Join(path, "nested"), 0o755))
+
+	source := replay.NewFileSourceWithInterfaces(path, "archivedAt")
+
+	files, err := source.ListFiles(context.Background(), time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(path, "a.jsonl"), filepath.Join(path, "b.jsonl")}, files)
+
+	records, err := source.ReadFile(context.Background(), files[0])
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	records, err = source.ReadFile(context.Background(), files[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []replay.Record{
+		{
+			Timestamp: time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC),
+			Message: stream.NewMessage(`{"id":1}`, map[string]interface{}{
+				"archivedAt": "2021-05-02T10:00:00Z",
+				"encoding":   "application/json",
+			}),
+		},
+		{
+			Message: stream.NewMessage(`{"id":2}`, map[string]interface{}{
+				"encoding": "application/json",
+			}),
+		},
+	}, records)
+}
+
+func TestS3Source(t *testing.T) {
+	from := time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC)
+	client := new(s3Mocks.Client)
+
+	client.On("ListObjectsV2", mock.Anything, &s3.ListObjectsV2Input{
+		Bucket: mdl.String("bucket"),
+		Prefix: mdl.String("archive/"),
+	}).Return(&s3.ListObjectsV2Output{
+		Contents: []types.Object{
+			{Key: mdl.String("archive/1"), LastModified: mdl.Time(from.Add(-time.Hour))},
+			{Key: mdl.String("archive/2"), LastModified: mdl.Time(from.Add(time.Hour))},
+		},
+		IsTruncated:           true,
+		NextContinuationToken: mdl.String("next"),
+	}, nil).Once()
+	client.On("ListObjectsV2", mock.Anything, &s3.ListObjectsV2Input{
+		Bucket:            mdl.String("bucket"),
+		Prefix:            mdl.String("archive/"),
+		ContinuationToken: mdl.String("next"),
+	}).Return(&s3.ListObjectsV2Output{
+		Contents: []types.Object{
+			{Key: mdl.String("archive/3"), LastModified: mdl.Time(from.Add(2 * time.Hour))},
+		},
+	}, nil).Once()
+	client.On("GetObject", mock.Anything, &s3.GetObjectInput{
+		Bucket: mdl.String("bucket"),
+		Key:    mdl.String("archive/2"),
+	}).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(jsonLines))),
+	}, nil).Once()
+
+	source := replay.NewS3SourceWithInterfaces(client, "bucket", "archive/", "")
+
+	files, err := source.ListFiles(context.Background(), from, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"archive/2", "archive/3"}, files)
+
+	records, err := source.ReadFile(context.Background(), "archive/2")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.True(t, records[0].Timestamp.IsZero(), "the timestamp attribute is not configured")
+
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,196 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+// A Target receives the replayed messages. Write returns once the target has acknowledged the messages, so the
+// replay can checkpoint them. Done is called after the replay has finished or failed.
+//go:generate mockery --name Target
+type Target interface {
+	Write(ctx context.Context, msgs []*stream.Message) error
+	Done()
+}
+
+func NewTarget(ctx context.Context, config cfg.Config, logger log.Logger, settings *Settings) (Target, error) {
+	switch {
+	case settings.Output != "" && settings.Consumer != "":
+		return nil, fmt.Errorf("either the output or the consumer of a replay can be set, not both")
+	case settings.Output != "":
+		return newOutputTarget(ctx, config, logger, settings.Output)
+	case settings.Consumer != "":
+		return newConsumerTarget(config, settings.Consumer, settings.AckTimeout)
+	default:
+		return nil, fmt.Errorf("either the output or the consumer of a replay is required")
+	}
+}
+
+type outputTarget struct {
+	output stream.Output
+}
+
+func newOutputTarget(ctx context.Context, config cfg.Config, logger log.Logger, name string) (Target, error) {
+	output, err := stream.NewConfigurableOutput(ctx, config, logger, name)
+	if err != nil {
+		return nil, fmt.Errorf("can not create output %s: %w", name, err)
+	}
+
+	return NewOutputTargetWithInterfaces(output), nil
+}
+
+// NewOutputTargetWithInterfaces publishes the replayed messages to the output.
+func NewOutputTargetWithInterfaces(output stream.Output) Target {
+	return &outputTarget{
+		output: output,
+	}
+}
+
+func (t *outputTarget) Write(ctx context.Context, msgs []*stream.Message) error {
+	batch := make([]stream.WritableMessage, len(msgs))
+
+	for i, msg := range msgs {
+		batch[i] = msg
+	}
+
+	return t.output.Write(ctx, batch)
+}
+
+func (t *outputTarget) Done() {}
+
+type consumerTarget struct {
+	clock      clock.Clock
+	input      *stream.InMemoryInput
+	ackTimeout time.Duration
+	lck        sync.Mutex
+	pending    map[*stream.Message]struct{}
+	acked      chan struct{}
+}
+
+// newConsumerTarget injects the messages into the input of the consumer, which has to be an in-memory input. This
+// allows to run a consumer on archived messages without touching its usual input.
+func newConsumerTarget(config cfg.Config, consumer string, ackTimeout time.Duration) (Target, error) {
+	inputName := config.GetString(fmt.Sprintf("%s.input", stream.ConfigurableConsumerKey(consumer)), "consumer")
+	inputKey := stream.ConfigurableInputKey(inputName)
+
+	if typ := config.GetString(fmt.Sprintf("%s.type", inputKey), ""); typ != stream.InputTypeInMemory {
+		return nil, fmt.Errorf("the input %s of consumer %s has to be of type %s to inject messages, but is %q", inputName, consumer, stream.InputTypeInMemory, typ)
+	}
+
+	settings := &stream.InMemorySettings{}
+	config.UnmarshalKey(inputKey, settings)
+
+	return NewConsumerTargetWithInterfaces(clock.NewRealClock(), stream.ProvideInMemoryInput(inputName, settings), ackTimeout), nil
+}
+
+// NewConsumerTargetWithInterfaces publishes the replayed messages to the input and stops it after the replay, so the
+// consumer finishes as soon as it has processed all of them. A write waits until the consumer has acknowledged all
+// of its messages and fails if it didn't do so within the ack timeout, as the input never delivers a message again.
+func NewConsumerTargetWithInterfaces(clock clock.Clock, input *stream.InMemoryInput, ackTimeout time.Duration) Target {
+	target := &consumerTarget{
+		clock:      clock,
+		input:      input,
+		ackTimeout: ackTimeout,
+		pending:    make(map[*stream.Message]struct{}),
+		acked:      make(chan struct{}, 1),
+	}
+
+	input.OnAck(target.ack)
+
+	return target
+}
+
+func (t *consumerTarget) Write(ctx context.Context, msgs []*stream.Message) error {
+	t.lck.Lock()
+	for _, msg := range msgs {
+		t.pending[msg] = struct{}{}
+	}
+	t.lck.Unlock()
+
+	timeout := t.clock.After(t.ackTimeout)
+
+	for _, msg := range msgs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return t.timeoutError(msgs)
+		case t.input.Data() <- msg:
+		}
+	}
+
+	for t.hasPending() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return t.timeoutError(msgs)
+		case <-t.acked:
+		}
+	}
+
+	return nil
+}
+
+// timeoutError names the messages of the batch which have not been acknowledged and stops waiting for them.
+func (t *consumerTarget) timeoutError(msgs []*stream.Message) error {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	pending := make([]string, 0, len(t.pending))
+
+	for i, msg := range msgs {
+		if _, ok := t.pending[msg]; !ok {
+			continue
+		}
+
+		delete(t.pending, msg)
+		pending = append(pending, fmt.Sprintf("#%d %s", i, describeMessage(msg)))
+	}
+
+	return fmt.Errorf("the consumer did not acknowledge %d of %d messages within %s: %s", len(pending), len(msgs), t.ackTimeout, strings.Join(pending, ", "))
+}
+
+func (t *consumerTarget) ack(msgs []*stream.Message) {
+	t.lck.Lock()
+	for _, msg := range msgs {
+		delete(t.pending, msg)
+	}
+	t.lck.Unlock()
+
+	select {
+	case t.acked <- struct{}{}:
+	default:
+	}
+}
+
+func (t *consumerTarget) hasPending() bool {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	return len(t.pending) > 0
+}
+
+// describeMessage returns the start of the body of the message to identify it in an error.
+func describeMessage(msg *stream.Message) string {
+	body := msg.Body
+
+	if len(body) > 64 {
+		body = body[:64] + "..."
+	}
+
+	return strconv.Quote(body)
+}
+
+func (t *consumerTarget) Done() {
+	t.input.Stop()
+}
@@ -0,0 +1,77 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/replay"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerTarget_WriteWaitsForAck(t *testing.T) {
+	input := stream.NewInMemoryInput(&stream.InMemorySettings{Size: 2})
+	target := replay.NewConsumerTargetWithInterfaces(clock.NewRealClock(), input, time.Minute)
+
+	msg1 := stream.NewMessage("1")
+	msg2 := stream.NewMessage("2")
+
+	done := make(chan error)
+	go func() {
+		done <- target.Write(context.Background(), []*stream.Message{msg1, msg2})
+	}()
+
+	assert.Same(t, msg1, <-input.Data())
+	assert.Same(t, msg2, <-input.Data())
+	assert.NoError(t, input.Ack(context.Background(), msg1))
+
+	select {
+	case <-done:
+		assert.Fail(t, "the write should wait until all messages are acknowledged")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.NoError(t, input.AckBatch(context.Background(), []*stream.Message{msg2}))
+	assert.NoError(t, <-done)
+}
+
+func TestConsumerTarget_WriteCanceled(t *testing.T) {
+	input := stream.NewInMemoryInput(&stream.InMemorySettings{Size: 1})
+	target := replay.NewConsumerTargetWithInterfaces(clock.NewRealClock(), input, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- target.Write(ctx, []*stream.Message{stream.NewMessage("1")})
+	}()
+
+	<-input.Data()
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestConsumerTarget_WriteAckTimeout(t *testing.T) {
+	clk := clock.NewFakeClock()
+	input := stream.NewInMemoryInput(&stream.InMemorySettings{Size: 2})
+	target := replay.NewConsumerTargetWithInterfaces(clk, input, time.Minute)
+
+	msg1 := stream.NewMessage("1")
+	msg2 := stream.NewMessage("2")
+
+	done := make(chan error)
+	go func() {
+		done <- target.Write(context.Background(), []*stream.Message{msg1, msg2})
+	}()
+
+	<-input.Data()
+	<-input.Data()
+	assert.NoError(t, input.Ack(context.Background(), msg1))
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+
+	assert.EqualError(t, <-done, `the consumer did not acknowledge 1 of 2 messages within 1m0s: #1 "2"`)
+}
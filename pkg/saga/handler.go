@@ -0,0 +1,55 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/justtrackio/gosoline/pkg/apiserver"
+)
+
+// AddInspectionHandlers adds the routes to inspect the instances of a saga to the api definitions:
+// GET basePath lists the active instances and GET basePath/:id returns a single instance.
+func AddInspectionHandlers(d *apiserver.Definitions, basePath string, orchestrator Orchestrator) {
+	d.GET(basePath, apiserver.CreateHandler(&listHandler{
+		orchestrator: orchestrator,
+	}))
+	d.GET(fmt.Sprintf("%s/:id", basePath), apiserver.CreateHandler(&readHandler{
+		orchestrator: orchestrator,
+	}))
+}
+
+type listHandler struct {
+	orchestrator Orchestrator
+}
+
+func (h *listHandler) Handle(ctx context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	instances, err := h.orchestrator.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can not list active saga instances: %w", err)
+	}
+
+	return apiserver.NewJsonResponse(instances), nil
+}
+
+type readHandler struct {
+	orchestrator Orchestrator
+}
+
+func (h *readHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetStringFromRequest(request, "id")
+	if !valid {
+		return apiserver.NewStatusResponse(http.StatusBadRequest), nil
+	}
+
+	instance, found, err := h.orchestrator.Get(ctx, *id)
+	if err != nil {
+		return nil, fmt.Errorf("can not get saga instance %s: %w", *id, err)
+	}
+
+	if !found {
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	return apiserver.NewJsonResponse(instance), nil
+}
@@ -0,0 +1,121 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+// NewModules creates the modules driving a saga: a consumer for the replies of the steps and a module checking
+// for timed out steps.
+func NewModules(definition *Definition) kernel.MultiModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (map[string]kernel.ModuleFactory, error) {
+		return map[string]kernel.ModuleFactory{
+			fmt.Sprintf("saga-%s-replies", definition.Name):  NewReplyConsumer(definition),
+			fmt.Sprintf("saga-%s-timeouts", definition.Name): NewTimeoutModule(definition),
+		}, nil
+	}
+}
+
+// NewReplyConsumer creates a consumer reading the replies of the steps of a saga from the consumer configured
+// at saga.<name>.consumer.
+func NewReplyConsumer(definition *Definition) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		settings := readSettings(config, definition.Name)
+
+		callbackFactory := func(ctx context.Context, config cfg.Config, logger log.Logger) (stream.ConsumerCallback, error) {
+			orchestrator, err := ProvideOrchestrator(ctx, config, logger, definition)
+			if err != nil {
+				return nil, fmt.Errorf("can not create orchestrator of saga %s: %w", definition.Name, err)
+			}
+
+			return NewReplyCallback(orchestrator), nil
+		}
+
+		return stream.NewConsumer(settings.Consumer, callbackFactory)(ctx, config, logger)
+	}
+}
+
+type replyCallback struct {
+	orchestrator Orchestrator
+}
+
+func NewReplyCallback(orchestrator Orchestrator) stream.ConsumerCallback {
+	return &replyCallback{
+		orchestrator: orchestrator,
+	}
+}
+
+func (c *replyCallback) GetModel(_ map[string]interface{}) interface{} {
+	return &Reply{}
+}
+
+func (c *replyCallback) Consume(ctx context.Context, model interface{}, _ map[string]interface{}) (bool, error) {
+	reply, ok := model.(*Reply)
+	if !ok {
+		return false, fmt.Errorf("expected a saga reply but got %T", model)
+	}
+
+	// a failed reply is not acknowledged, so it is consumed again after a concurrent update or a temporary failure
+	if err := c.orchestrator.HandleReply(ctx, reply); err != nil {
+		return false, fmt.Errorf("can not handle reply of step %s of saga instance %s: %w", reply.Step, reply.SagaId, err)
+	}
+
+	return true, nil
+}
+
+type TimeoutModule struct {
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+
+	logger       log.Logger
+	orchestrator Orchestrator
+	ticker       clock.Ticker
+}
+
+// NewTimeoutModule creates a module checking the instances of a saga for timed out steps in the configured interval.
+func NewTimeoutModule(definition *Definition) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		settings := readSettings(config, definition.Name)
+		logger = logger.WithChannel("saga").WithFields(log.Fields{
+			"saga": definition.Name,
+		})
+
+		orchestrator, err := ProvideOrchestrator(ctx, config, logger, definition)
+		if err != nil {
+			return nil, fmt.Errorf("can not create orchestrator of saga %s: %w", definition.Name, err)
+		}
+
+		ticker := clock.NewRealTicker(settings.TimeoutInterval)
+
+		return NewTimeoutModuleWithInterfaces(logger, orchestrator, ticker), nil
+	}
+}
+
+func NewTimeoutModuleWithInterfaces(logger log.Logger, orchestrator Orchestrator, ticker clock.Ticker) *TimeoutModule {
+	return &TimeoutModule{
+		logger:       logger,
+		orchestrator: orchestrator,
+		ticker:       ticker,
+	}
+}
+
+func (m *TimeoutModule) Run(ctx context.Context) error {
+	defer m.ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-m.ticker.Tick():
+			if err := m.orchestrator.CheckTimeouts(ctx); err != nil {
+				m.logger.Error("can not check for timed out steps: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/uuid"
+)
+
+// An Orchestrator drives the instances of a saga: it sends the commands of the steps, advances the instances on
+// replies and compensates completed steps if a step fails.
+//go:generate mockery --name Orchestrator
+type Orchestrator interface {
+	// Start creates a new instance of the saga and sends the command of the first step.
+	Start(ctx context.Context, input interface{}) (string, error)
+	Get(ctx context.Context, id string) (*Instance, bool, error)
+	ListActive(ctx context.Context) ([]*Instance, error)
+	HandleReply(ctx context.Context, reply *Reply) error
+	// CheckTimeouts sends the commands of all steps without a reply within their timeout again or fails the step
+	// if it ran out of attempts.
+	CheckTimeouts(ctx context.Context) error
+}
+
+type orchestrator struct {
+	logger     log.Logger
+	clock      clock.Clock
+	uuid       uuid.Uuid
+	producer   stream.Producer
+	store      Store
+	definition *Definition
+	settings   *Settings
+}
+
+type orchestratorAppctxKey string
+
+// ProvideOrchestrator returns the orchestrator of the saga with the settings configured at saga.<name>. All modules
+// and handlers of an application share the same orchestrator of a saga.
+func ProvideOrchestrator(ctx context.Context, config cfg.Config, logger log.Logger, definition *Definition) (Orchestrator, error) {
+	orchestrator, err := appctx.Provide(ctx, orchestratorAppctxKey(definition.Name), func() (interface{}, error) {
+		return NewOrchestrator(ctx, config, logger, definition)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orchestrator.(Orchestrator), nil
+}
+
+func NewOrchestrator(ctx context.Context, config cfg.Config, logger log.Logger, definition *Definition) (Orchestrator, error) {
+	var err error
+	var producer stream.Producer
+	var store Store
+
+	if err = definition.validate(); err != nil {
+		return nil, fmt.Errorf("invalid saga definition: %w", err)
+	}
+
+	settings := readSettings(config, definition.Name)
+	logger = logger.WithChannel("saga").WithFields(log.Fields{
+		"saga": definition.Name,
+	})
+
+	if producer, err = stream.NewProducer(ctx, config, logger, settings.Producer); err != nil {
+		return nil, fmt.Errorf("can not create producer %s: %w", settings.Producer, err)
+	}
+
+	if store, err = NewStore(ctx, config, logger, definition.Name, &settings.Store); err != nil {
+		return nil, fmt.Errorf("can not create store of saga %s: %w", definition.Name, err)
+	}
+
+	return NewOrchestratorWithInterfaces(logger, clock.Provider, uuid.New(), producer, store, definition, settings), nil
+}
+
+func NewOrchestratorWithInterfaces(
+	logger log.Logger,
+	clock clock.Clock,
+	uuid uuid.Uuid,
+	producer stream.Producer,
+	store Store,
+	definition *Definition,
+	settings *Settings,
+) Orchestrator {
+	return &orchestrator{
+		logger:     logger,
+		clock:      clock,
+		uuid:       uuid,
+		producer:   producer,
+		store:      store,
+		definition: definition,
+		settings:   settings,
+	}
+}
+
+func (o *orchestrator) Start(ctx context.Context, input interface{}) (string, error) {
+	encodedInput, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("can not marshal input of saga %s: %w", o.definition.Name, err)
+	}
+
+	now := o.clock.Now()
+	instance := &Instance{
+		Id:        o.uuid.NewV4(),
+		Name:      o.definition.Name,
+		State:     StateRunning,
+		Input:     encodedInput,
+		Attempt:   1,
+		Results:   make(map[string]json.RawMessage),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = o.proceed(ctx, instance); err != nil {
+		return "", fmt.Errorf("can not start saga %s: %w", o.definition.Name, err)
+	}
+
+	return instance.Id, nil
+}
+
+func (o *orchestrator) Get(ctx context.Context, id string) (*Instance, bool, error) {
+	return o.store.Get(ctx, id)
+}
+
+func (o *orchestrator) ListActive(ctx context.Context) ([]*Instance, error) {
+	return o.store.ListActive(ctx)
+}
+
+func (o *orchestrator) HandleReply(ctx context.Context, reply *Reply) error {
+	logger := o.logger.WithContext(ctx).WithFields(log.Fields{
+		"saga_id":   reply.SagaId,
+		"saga_step": reply.Step,
+	})
+
+	instance, found, err := o.store.Get(ctx, reply.SagaId)
+	if err != nil {
+		return fmt.Errorf("can not get instance for reply: %w", err)
+	}
+
+	if !found {
+		logger.Warn("received a reply for the unknown saga instance %s", reply.SagaId)
+		return nil
+	}
+
+	if instance.IsFinal() || o.definition.Steps[instance.Step].Name != reply.Step || instance.kind() != reply.Kind {
+		logger.Info("ignoring the stale %s reply of step %s", reply.Kind, reply.Step)
+		return nil
+	}
+
+	switch {
+	case reply.Kind == KindAction && reply.Success:
+		instance.Results[reply.Step] = reply.Data
+		o.moveTo(instance, StateRunning, instance.Step+1)
+
+	case reply.Kind == KindAction:
+		logger.Warn("step %s failed, compensating completed steps: %s", reply.Step, reply.Error)
+		o.compensate(instance, fmt.Sprintf("step %s failed: %s", reply.Step, reply.Error))
+
+	case reply.Success:
+		o.moveTo(instance, StateCompensating, instance.Step-1)
+
+	default:
+		logger.Warn("compensation of step %s failed: %s", reply.Step, reply.Error)
+		o.retry(instance, fmt.Sprintf("compensation of step %s failed: %s", reply.Step, reply.Error))
+	}
+
+	return o.proceed(ctx, instance)
+}
+
+func (o *orchestrator) CheckTimeouts(ctx context.Context) error {
+	instances, err := o.store.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("can not list active instances: %w", err)
+	}
+
+	now := o.clock.Now()
+
+	for _, instance := range instances {
+		if instance.Deadline.After(now) {
+			continue
+		}
+
+		step := o.definition.Steps[instance.Step]
+		o.logger.WithContext(ctx).Warn("the %s of step %s of saga instance %s timed out after attempt %d", instance.kind(), step.Name, instance.Id, instance.Attempt)
+		o.retry(instance, fmt.Sprintf("the %s of step %s timed out", instance.kind(), step.Name))
+
+		err = o.proceed(ctx, instance)
+
+		if errors.Is(err, ErrInstanceConflict) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("can not retry timed out step %s of saga instance %s: %w", step.Name, instance.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// retry sends the command of the current step again if there are attempts left. A step running out of attempts
+// is compensated, a compensation running out of attempts fails the instance.
+func (o *orchestrator) retry(instance *Instance, reason string) {
+	if instance.Attempt < o.getMaxAttempts(o.definition.Steps[instance.Step]) {
+		instance.Attempt++
+		return
+	}
+
+	if instance.State == StateRunning {
+		o.compensate(instance, reason)
+		return
+	}
+
+	instance.State = StateFailed
+	instance.Error = fmt.Sprintf("%s, %s", instance.Error, reason)
+}
+
+func (o *orchestrator) compensate(instance *Instance, reason string) {
+	instance.Error = reason
+	o.moveTo(instance, StateCompensating, instance.Step-1)
+}
+
+func (o *orchestrator) moveTo(instance *Instance, state string, step int) {
+	instance.State = state
+	instance.Step = step
+	instance.Attempt = 1
+}
+
+// proceed skips steps without compensation, resolves final states, persists the instance and sends the next command.
+func (o *orchestrator) proceed(ctx context.Context, instance *Instance) error {
+	var err error
+	var command interface{}
+	var step Step
+
+	for !instance.IsFinal() {
+		if instance.State == StateRunning && instance.Step >= len(o.definition.Steps) {
+			instance.State = StateCompleted
+			instance.Step = len(o.definition.Steps) - 1
+			break
+		}
+
+		if instance.State == StateCompensating && instance.Step < 0 {
+			instance.State = StateCompensated
+			instance.Step = 0
+			break
+		}
+
+		step = o.definition.Steps[instance.Step]
+		commandFunc := step.Action
+
+		if instance.State == StateCompensating {
+			commandFunc = step.Compensation
+		}
+
+		if commandFunc == nil {
+			instance.Step--
+			continue
+		}
+
+		if command, err = commandFunc(ctx, instance); err == nil {
+			break
+		}
+
+		o.logger.WithContext(ctx).Error("can not build the %s command of step %s of saga instance %s: %w", instance.kind(), step.Name, instance.Id, err)
+
+		if instance.State == StateRunning {
+			o.compensate(instance, fmt.Sprintf("can not build the command of step %s: %s", step.Name, err))
+			continue
+		}
+
+		instance.State = StateFailed
+		instance.Error = fmt.Sprintf("%s, can not build the compensation command of step %s: %s", instance.Error, step.Name, err)
+	}
+
+	now := o.clock.Now()
+	instance.UpdatedAt = now
+	instance.Deadline = time.Time{}
+
+	if !instance.IsFinal() {
+		instance.Deadline = now.Add(o.getTimeout(step))
+	}
+
+	if err = o.store.Save(ctx, instance); err != nil {
+		return fmt.Errorf("can not save saga instance %s: %w", instance.Id, err)
+	}
+
+	if instance.IsFinal() {
+		o.logger.WithContext(ctx).Info("saga instance %s finished in state %s", instance.Id, instance.State)
+		return nil
+	}
+
+	attributes := map[string]interface{}{
+		AttributeSagaId:   instance.Id,
+		AttributeSagaName: instance.Name,
+		AttributeSagaStep: step.Name,
+		AttributeSagaKind: instance.kind(),
+	}
+
+	// a failed write is handled like a missing reply and retried as soon as the deadline passed
+	if err = o.producer.WriteOne(ctx, command, attributes); err != nil {
+		return fmt.Errorf("can not write the %s command of step %s of saga instance %s: %w", instance.kind(), step.Name, instance.Id, err)
+	}
+
+	return nil
+}
+
+func (o *orchestrator) getTimeout(step Step) time.Duration {
+	if step.Timeout > 0 {
+		return step.Timeout
+	}
+
+	return o.settings.StepTimeout
+}
+
+func (o *orchestrator) getMaxAttempts(step Step) int {
+	if step.MaxAttempts > 0 {
+		return step.MaxAttempts
+	}
+
+	return o.settings.MaxAttempts
+}
@@ -0,0 +1,169 @@
+package saga_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/saga"
+	"github.com/justtrackio/gosoline/pkg/saga/mocks"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	uuidMocks "github.com/justtrackio/gosoline/pkg/uuid/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSagaId = "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1"
+
+func newTestOrchestrator(store saga.Store, producer *streamMocks.Producer, clock clock.Clock) saga.Orchestrator {
+	uuid := new(uuidMocks.Uuid)
+	uuid.On("NewV4").Return(testSagaId)
+
+	definition := &saga.Definition{
+		Name: "order",
+		Steps: []saga.Step{
+			{
+				Name:         "reserve",
+				Action:       newOrderCommand("reserve"),
+				Compensation: newOrderCommand("release"),
+			},
+			{
+				Name:        "charge",
+				Action:      newOrderCommand("charge"),
+				Timeout:     time.Minute,
+				MaxAttempts: 2,
+			},
+		},
+	}
+
+	return saga.NewOrchestratorWithInterfaces(logMocks.NewLoggerMockedAll(), clock, uuid, producer, store, definition, &saga.Settings{
+		StepTimeout: 10 * time.Second,
+		MaxAttempts: 3,
+	})
+}
+
+func expectCommand(producer *streamMocks.Producer, action string, step string, kind string) {
+	producer.On("WriteOne", mock.Anything, &orderCommand{Action: action, OrderId: 42}, map[string]interface{}{
+		saga.AttributeSagaId:   testSagaId,
+		saga.AttributeSagaName: "order",
+		saga.AttributeSagaStep: step,
+		saga.AttributeSagaKind: kind,
+	}).Return(nil).Once()
+}
+
+func TestOrchestrator_CheckTimeouts(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clock.NewFakeClockAt(time.Date(2021, time.March, 3, 10, 0, 0, 0, time.UTC))
+	producer := new(streamMocks.Producer)
+	orchestrator := newTestOrchestrator(saga.NewInMemoryStore(), producer, fakeClock)
+
+	expectCommand(producer, "reserve", "reserve", saga.KindAction)
+	_, err := orchestrator.Start(ctx, &orderInput{OrderId: 42})
+	assert.NoError(t, err)
+
+	expectCommand(producer, "charge", "charge", saga.KindAction)
+	err = orchestrator.HandleReply(ctx, &saga.Reply{SagaId: testSagaId, Step: "reserve", Kind: saga.KindAction, Success: true})
+	assert.NoError(t, err)
+
+	// the step timeout of charge overrides the timeout of the settings
+	fakeClock.Advance(30 * time.Second)
+	assert.NoError(t, orchestrator.CheckTimeouts(ctx))
+
+	expectCommand(producer, "charge", "charge", saga.KindAction)
+	fakeClock.Advance(30 * time.Second)
+	assert.NoError(t, orchestrator.CheckTimeouts(ctx))
+
+	instance, _, err := orchestrator.Get(ctx, testSagaId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, instance.Attempt)
+	assert.Equal(t, fakeClock.Now().Add(time.Minute), instance.Deadline)
+
+	// charge ran out of attempts, so reserve is compensated
+	expectCommand(producer, "release", "reserve", saga.KindCompensation)
+	fakeClock.Advance(time.Minute)
+	assert.NoError(t, orchestrator.CheckTimeouts(ctx))
+
+	instance, _, err = orchestrator.Get(ctx, testSagaId)
+	assert.NoError(t, err)
+	assert.Equal(t, saga.StateCompensating, instance.State)
+	assert.Equal(t, "the action of step charge timed out", instance.Error)
+
+	err = orchestrator.HandleReply(ctx, &saga.Reply{SagaId: testSagaId, Step: "reserve", Kind: saga.KindCompensation, Success: true})
+	assert.NoError(t, err)
+
+	instance, _, err = orchestrator.Get(ctx, testSagaId)
+	assert.NoError(t, err)
+	assert.Equal(t, saga.StateCompensated, instance.State)
+	assert.True(t, instance.Deadline.IsZero())
+
+	producer.AssertExpectations(t)
+}
+
+func TestOrchestrator_StaleReply(t *testing.T) {
+	ctx := context.Background()
+	producer := new(streamMocks.Producer)
+	orchestrator := newTestOrchestrator(saga.NewInMemoryStore(), producer, clock.NewFakeClock())
+
+	expectCommand(producer, "reserve", "reserve", saga.KindAction)
+	_, err := orchestrator.Start(ctx, &orderInput{OrderId: 42})
+	assert.NoError(t, err)
+
+	err = orchestrator.HandleReply(ctx, &saga.Reply{SagaId: testSagaId, Step: "charge", Kind: saga.KindAction, Success: true})
+	assert.NoError(t, err)
+
+	err = orchestrator.HandleReply(ctx, &saga.Reply{SagaId: "unknown", Step: "reserve", Kind: saga.KindAction, Success: true})
+	assert.NoError(t, err)
+
+	instance, _, err := orchestrator.Get(ctx, testSagaId)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, instance.Step)
+	assert.Equal(t, 1, instance.Version)
+
+	producer.AssertExpectations(t)
+}
+
+func TestOrchestrator_Conflict(t *testing.T) {
+	ctx := context.Background()
+	producer := new(streamMocks.Producer)
+
+	store := new(mocks.Store)
+	store.On("Get", mock.Anything, testSagaId).Return(&saga.Instance{
+		Id:      testSagaId,
+		Name:    "order",
+		State:   saga.StateRunning,
+		Input:   []byte(`{"orderId":42}`),
+		Attempt: 1,
+		Results: map[string]json.RawMessage{},
+		Version: 1,
+	}, true, nil)
+	store.On("Save", mock.Anything, mock.AnythingOfType("*saga.Instance")).Return(saga.ErrInstanceConflict)
+
+	orchestrator := newTestOrchestrator(store, producer, clock.NewFakeClock())
+
+	err := orchestrator.HandleReply(ctx, &saga.Reply{SagaId: testSagaId, Step: "reserve", Kind: saga.KindAction, Success: true})
+	assert.ErrorIs(t, err, saga.ErrInstanceConflict)
+
+	producer.AssertNotCalled(t, "WriteOne", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInMemoryStore_Save(t *testing.T) {
+	ctx := context.Background()
+	store := saga.NewInMemoryStore()
+
+	instance := &saga.Instance{Id: testSagaId}
+	assert.NoError(t, store.Save(ctx, instance))
+	assert.Equal(t, 1, instance.Version)
+
+	stale := &saga.Instance{Id: testSagaId}
+	assert.Equal(t, saga.ErrInstanceConflict, store.Save(ctx, stale), "an existing instance should not be created again")
+
+	assert.NoError(t, store.Save(ctx, instance))
+	assert.Equal(t, 2, instance.Version)
+
+	stale.Version = 1
+	assert.Equal(t, saga.ErrInstanceConflict, store.Save(ctx, stale), fmt.Sprintf("version %d should be outdated", stale.Version))
+}
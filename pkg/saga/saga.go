@@ -0,0 +1,172 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+)
+
+const (
+	StateRunning      = "running"
+	StateCompensating = "compensating"
+	StateCompleted    = "completed"
+	StateCompensated  = "compensated"
+	StateFailed       = "failed"
+
+	KindAction       = "action"
+	KindCompensation = "compensation"
+
+	AttributeSagaId   = "sagaId"
+	AttributeSagaName = "sagaName"
+	AttributeSagaStep = "sagaStep"
+	AttributeSagaKind = "sagaKind"
+)
+
+// A CommandFunc builds the command which is sent to the service executing a step. The command is written
+// with the attributes of the saga, the service has to send a Reply built by NewReply once it is done.
+type CommandFunc func(ctx context.Context, instance *Instance) (interface{}, error)
+
+// A Step is executed by sending the command of its action. If a step fails, the compensations of all previously
+// completed steps are executed in reverse order. Steps without compensation are skipped while compensating.
+type Step struct {
+	Name         string
+	Action       CommandFunc
+	Compensation CommandFunc
+	// Timeout overrides the step timeout of the saga settings if set.
+	Timeout time.Duration
+	// MaxAttempts overrides the max attempts of the saga settings if set.
+	MaxAttempts int
+}
+
+type Definition struct {
+	Name  string
+	Steps []Step
+}
+
+func (d *Definition) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("the saga has no name")
+	}
+
+	if len(d.Steps) == 0 {
+		return fmt.Errorf("the saga %s has no steps", d.Name)
+	}
+
+	names := make(map[string]bool, len(d.Steps))
+
+	for _, step := range d.Steps {
+		if step.Action == nil {
+			return fmt.Errorf("the step %s of saga %s has no action", step.Name, d.Name)
+		}
+
+		if names[step.Name] {
+			return fmt.Errorf("the step %s of saga %s is defined more than once", step.Name, d.Name)
+		}
+
+		names[step.Name] = true
+	}
+
+	return nil
+}
+
+// An Instance is a single execution of a saga and is persisted after every transition.
+type Instance struct {
+	Id       string                     `json:"id" ddb:"key=hash"`
+	Name     string                     `json:"name"`
+	State    string                     `json:"state"`
+	Input    json.RawMessage            `json:"input"`
+	Step     int                        `json:"step"`
+	Attempt  int                        `json:"attempt"`
+	Deadline time.Time                  `json:"deadline"`
+	Results  map[string]json.RawMessage `json:"results"`
+	Error    string                     `json:"error,omitempty"`
+	// ActiveState is the state of a running or compensating instance and empty for final ones. It is the hash key of
+	// a sparse index, so the active instances can be looked up without reading the final ones.
+	ActiveState string `json:"activeState,omitempty" ddb:"global=hash"`
+	// Version is incremented with every update of the instance to detect concurrent modifications.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// IsFinal returns true if the instance will not make any further progress.
+func (i *Instance) IsFinal() bool {
+	return i.State == StateCompleted || i.State == StateCompensated || i.State == StateFailed
+}
+
+// UnmarshalInput decodes the input the saga was started with into the given value.
+func (i *Instance) UnmarshalInput(v interface{}) error {
+	if err := json.Unmarshal(i.Input, v); err != nil {
+		return fmt.Errorf("can not unmarshal input of saga %s: %w", i.Id, err)
+	}
+
+	return nil
+}
+
+// UnmarshalResult decodes the data of the reply of a completed step into the given value.
+func (i *Instance) UnmarshalResult(step string, v interface{}) (bool, error) {
+	result, ok := i.Results[step]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(result, v); err != nil {
+		return false, fmt.Errorf("can not unmarshal result of step %s of saga %s: %w", step, i.Id, err)
+	}
+
+	return true, nil
+}
+
+func (i *Instance) kind() string {
+	if i.State == StateCompensating {
+		return KindCompensation
+	}
+
+	return KindAction
+}
+
+// A Reply reports the outcome of a command to the saga.
+type Reply struct {
+	SagaId   string          `json:"sagaId"`
+	SagaName string          `json:"sagaName"`
+	Step     string          `json:"step"`
+	Kind     string          `json:"kind"`
+	Success  bool            `json:"success"`
+	Error    string          `json:"error,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// NewReply builds the reply to a command from the attributes the command was received with. A non nil error
+// marks the command as failed.
+func NewReply(attributes map[string]interface{}, data interface{}, err error) (*Reply, error) {
+	var ok bool
+	var encodeErr error
+	reply := &Reply{
+		Success: err == nil,
+	}
+
+	for key, value := range map[string]*string{
+		AttributeSagaId:   &reply.SagaId,
+		AttributeSagaName: &reply.SagaName,
+		AttributeSagaStep: &reply.Step,
+		AttributeSagaKind: &reply.Kind,
+	} {
+		if *value, ok = attributes[key].(string); !ok {
+			return nil, fmt.Errorf("the attribute %s is missing or not a string", key)
+		}
+	}
+
+	if err != nil {
+		reply.Error = err.Error()
+	}
+
+	if data != nil {
+		if reply.Data, encodeErr = json.Marshal(data); encodeErr != nil {
+			return nil, fmt.Errorf("can not marshal data of the reply: %w", encodeErr)
+		}
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,281 @@
+package saga_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/appctx"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/saga"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type orderInput struct {
+	OrderId int `json:"orderId"`
+}
+
+type orderCommand struct {
+	Action  string `json:"action"`
+	OrderId int    `json:"orderId"`
+}
+
+func newOrderCommand(action string) saga.CommandFunc {
+	return func(ctx context.Context, instance *saga.Instance) (interface{}, error) {
+		input := &orderInput{}
+		if err := instance.UnmarshalInput(input); err != nil {
+			return nil, err
+		}
+
+		return &orderCommand{
+			Action:  action,
+			OrderId: input.OrderId,
+		}, nil
+	}
+}
+
+// participant plays the services executing the steps: it reads the commands from the in memory output of the
+// saga and publishes the replies to the in memory input consumed by the saga.
+type participant struct {
+	output  *stream.InMemoryOutput
+	input   *stream.InMemoryInput
+	results map[string]error
+
+	lck      sync.Mutex
+	received []string
+}
+
+func (p *participant) run(ctx context.Context) {
+	for processed := 0; ; {
+		msg, ok := p.output.Get(processed)
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+				continue
+			}
+		}
+
+		processed++
+
+		command := &orderCommand{}
+		if err := json.Unmarshal([]byte(msg.Body), command); err != nil {
+			panic(err)
+		}
+
+		p.lck.Lock()
+		p.received = append(p.received, command.Action)
+		p.lck.Unlock()
+
+		reply, err := saga.NewReply(msg.Attributes, map[string]string{"ref": fmt.Sprintf("%s-%d", command.Action, command.OrderId)}, p.results[command.Action])
+		if err != nil {
+			panic(err)
+		}
+
+		body, err := json.Marshal(reply)
+		if err != nil {
+			panic(err)
+		}
+
+		p.input.Publish(stream.NewJsonMessage(string(body)))
+	}
+}
+
+func (p *participant) getReceived() []string {
+	p.lck.Lock()
+	defer p.lck.Unlock()
+
+	return append([]string{}, p.received...)
+}
+
+type SagaTestSuite struct {
+	suite.Suite
+
+	ctx          context.Context
+	config       cfg.GosoConf
+	logger       log.Logger
+	definition   *saga.Definition
+	orchestrator saga.Orchestrator
+}
+
+func TestSagaTestSuite(t *testing.T) {
+	suite.Run(t, new(SagaTestSuite))
+}
+
+func (s *SagaTestSuite) SetupTest() {
+	stream.ResetInMemoryInputs()
+	stream.ResetInMemoryOutputs()
+
+	s.ctx = appctx.WithContainer(context.Background())
+	s.logger = log.NewCliLogger()
+	s.config = cfg.New()
+
+	err := s.config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"env":         "test",
+		"app_project": "justtrack",
+		"app_family":  "gosoline",
+		"app_name":    "saga",
+		"stream": map[string]interface{}{
+			"output": map[string]interface{}{
+				"order-commands": map[string]interface{}{"type": "inMemory"},
+			},
+			"producer": map[string]interface{}{
+				"order": map[string]interface{}{"output": "order-commands"},
+			},
+			"input": map[string]interface{}{
+				"order-replies": map[string]interface{}{"type": "inMemory"},
+			},
+			"consumer": map[string]interface{}{
+				"order": map[string]interface{}{"input": "order-replies"},
+			},
+		},
+		"saga": map[string]interface{}{
+			"order": map[string]interface{}{
+				"store": map[string]interface{}{"type": "inMemory"},
+			},
+		},
+	}))
+	s.NoError(err)
+
+	s.definition = &saga.Definition{
+		Name: "order",
+		Steps: []saga.Step{
+			{
+				Name:         "reserve",
+				Action:       newOrderCommand("reserve"),
+				Compensation: newOrderCommand("release"),
+			},
+			{
+				Name:   "notify",
+				Action: newOrderCommand("notify"),
+			},
+			{
+				Name:         "charge",
+				Action:       newOrderCommand("charge"),
+				Compensation: newOrderCommand("refund"),
+			},
+			{
+				Name:   "ship",
+				Action: newOrderCommand("ship"),
+			},
+		},
+	}
+
+	s.orchestrator, err = saga.ProvideOrchestrator(s.ctx, s.config, s.logger, s.definition)
+	s.NoError(err)
+}
+
+func (s *SagaTestSuite) run(results map[string]error) (*participant, func()) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	wg := &sync.WaitGroup{}
+
+	p := &participant{
+		output:  stream.ProvideInMemoryOutput("order-commands"),
+		input:   stream.ProvideInMemoryInput("order-replies", &stream.InMemorySettings{Size: 1}),
+		results: results,
+	}
+
+	consumer, err := saga.NewReplyConsumer(s.definition)(s.ctx, s.config, s.logger)
+	s.NoError(err)
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		p.run(ctx)
+	}()
+
+	go func() {
+		defer wg.Done()
+		s.NoError(consumer.Run(ctx))
+	}()
+
+	return p, func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func (s *SagaTestSuite) waitForState(id string, state string) *saga.Instance {
+	var instance *saga.Instance
+
+	s.Eventually(func() bool {
+		var err error
+		instance, _, err = s.orchestrator.Get(s.ctx, id)
+		s.NoError(err)
+
+		return instance.State == state
+	}, 5*time.Second, time.Millisecond)
+
+	return instance
+}
+
+func (s *SagaTestSuite) TestCompleted() {
+	p, stop := s.run(map[string]error{})
+	defer stop()
+
+	id, err := s.orchestrator.Start(s.ctx, &orderInput{OrderId: 42})
+	s.NoError(err)
+
+	instance := s.waitForState(id, saga.StateCompleted)
+
+	s.Equal([]string{"reserve", "notify", "charge", "ship"}, p.getReceived())
+	s.Empty(instance.Error)
+
+	result := map[string]string{}
+	found, err := instance.UnmarshalResult("charge", &result)
+	s.NoError(err)
+	s.True(found)
+	s.Equal(map[string]string{"ref": "charge-42"}, result)
+
+	active, err := s.orchestrator.ListActive(s.ctx)
+	s.NoError(err)
+	s.Empty(active)
+}
+
+func (s *SagaTestSuite) TestCompensated() {
+	p, stop := s.run(map[string]error{
+		"ship": fmt.Errorf("out of stock"),
+	})
+	defer stop()
+
+	id, err := s.orchestrator.Start(s.ctx, &orderInput{OrderId: 42})
+	s.NoError(err)
+
+	instance := s.waitForState(id, saga.StateCompensated)
+
+	// notify has no compensation and is skipped
+	s.Equal([]string{"reserve", "notify", "charge", "ship", "refund", "release"}, p.getReceived())
+	s.Equal("step ship failed: out of stock", instance.Error)
+}
+
+func (s *SagaTestSuite) TestFailed() {
+	p, stop := s.run(map[string]error{
+		"charge":  fmt.Errorf("card declined"),
+		"release": fmt.Errorf("inventory unavailable"),
+	})
+	defer stop()
+
+	id, err := s.orchestrator.Start(s.ctx, &orderInput{OrderId: 42})
+	s.NoError(err)
+
+	instance := s.waitForState(id, saga.StateFailed)
+
+	s.Equal([]string{"reserve", "notify", "charge", "release", "release", "release"}, p.getReceived())
+	s.Equal("step charge failed: card declined, compensation of step reserve failed: inventory unavailable", instance.Error)
+}
+
+func TestNewReply_MissingAttributes(t *testing.T) {
+	_, err := saga.NewReply(map[string]interface{}{
+		saga.AttributeSagaId: "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1",
+	}, nil, nil)
+
+	assert.Error(t, err)
+}
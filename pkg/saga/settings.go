@@ -0,0 +1,41 @@
+package saga
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+)
+
+type Settings struct {
+	// Producer is the name of the stream producer the commands are written to, it defaults to the name of the saga.
+	Producer string `cfg:"producer"`
+	// Consumer is the name of the stream consumer the replies are read from, it defaults to the name of the saga.
+	Consumer string        `cfg:"consumer"`
+	Store    StoreSettings `cfg:"store"`
+	// StepTimeout is the duration to wait for a reply before a command is sent again.
+	StepTimeout time.Duration `cfg:"step_timeout" default:"1m"`
+	// MaxAttempts is the number of times a command is sent before the step is considered as failed.
+	MaxAttempts int `cfg:"max_attempts" default:"3"`
+	// TimeoutInterval is the interval in which the instances are checked for timed out steps.
+	TimeoutInterval time.Duration `cfg:"timeout_interval" default:"10s"`
+}
+
+type StoreSettings struct {
+	Type string `cfg:"type" default:"ddb" validate:"oneof=ddb inMemory"`
+}
+
+func readSettings(config cfg.Config, name string) *Settings {
+	settings := &Settings{}
+	config.UnmarshalKey(fmt.Sprintf("saga.%s", name), settings)
+
+	if settings.Producer == "" {
+		settings.Producer = name
+	}
+
+	if settings.Consumer == "" {
+		settings.Consumer = name
+	}
+
+	return settings
+}
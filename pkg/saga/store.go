@@ -0,0 +1,41 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+const (
+	StoreTypeDdb      = "ddb"
+	StoreTypeInMemory = "inMemory"
+)
+
+var ErrInstanceConflict = fmt.Errorf("the saga instance was modified concurrently")
+
+// A Store persists the instances of a saga. Save fails with ErrInstanceConflict if the version of the instance
+// doesn't match the stored version and increments the version on success.
+//go:generate mockery --name Store
+type Store interface {
+	Get(ctx context.Context, id string) (*Instance, bool, error)
+	Save(ctx context.Context, instance *Instance) error
+	// ListActive returns all instances which didn't reach a final state yet.
+	ListActive(ctx context.Context) ([]*Instance, error)
+}
+
+func NewStore(ctx context.Context, config cfg.Config, logger log.Logger, name string, settings *StoreSettings) (Store, error) {
+	switch settings.Type {
+	case StoreTypeDdb:
+		return NewDdbStore(ctx, config, logger, name)
+	case StoreTypeInMemory:
+		return NewInMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown store type %s for saga %s", settings.Type, name)
+	}
+}
+
+func isActive(state string) bool {
+	return state == StateRunning || state == StateCompensating
+}
@@ -0,0 +1,113 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+const ddbActiveIndex = "global-activeState"
+
+type ddbStore struct {
+	repository ddb.Repository
+}
+
+func NewDdbStore(ctx context.Context, config cfg.Config, logger log.Logger, name string) (Store, error) {
+	modelId := mdl.ModelId{
+		Name: fmt.Sprintf("saga-%s", name),
+	}
+	modelId.PadFromConfig(config)
+
+	repository, err := ddb.NewRepository(ctx, config, logger, &ddb.Settings{
+		ModelId: modelId,
+		Main: ddb.MainSettings{
+			Model:              Instance{},
+			ReadCapacityUnits:  5,
+			WriteCapacityUnits: 5,
+		},
+		Global: []ddb.GlobalSettings{
+			{
+				Name:               ddbActiveIndex,
+				Model:              Instance{},
+				ReadCapacityUnits:  5,
+				WriteCapacityUnits: 5,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository: %w", err)
+	}
+
+	return NewDdbStoreWithInterfaces(repository), nil
+}
+
+func NewDdbStoreWithInterfaces(repository ddb.Repository) Store {
+	return &ddbStore{
+		repository: repository,
+	}
+}
+
+func (s *ddbStore) Get(ctx context.Context, id string) (*Instance, bool, error) {
+	instance := &Instance{}
+	qb := s.repository.GetItemBuilder().WithHash(id).WithConsistentRead(true)
+
+	res, err := s.repository.GetItem(ctx, qb, instance)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not get saga instance %s: %w", id, err)
+	}
+
+	return instance, res.IsFound, nil
+}
+
+func (s *ddbStore) Save(ctx context.Context, instance *Instance) error {
+	condition := ddb.AttributeNotExists("id")
+	if instance.Version > 0 {
+		condition = ddb.Eq("version", instance.Version)
+	}
+
+	item := *instance
+	item.Version++
+	item.ActiveState = ""
+
+	if !item.IsFinal() {
+		item.ActiveState = item.State
+	}
+
+	qb := s.repository.PutItemBuilder().WithCondition(condition)
+
+	res, err := s.repository.PutItem(ctx, qb, &item)
+	if err != nil {
+		return fmt.Errorf("can not save saga instance %s: %w", instance.Id, err)
+	}
+
+	if res.ConditionalCheckFailed {
+		return ErrInstanceConflict
+	}
+
+	instance.Version = item.Version
+
+	return nil
+}
+
+// ListActive queries the sparse index of the active instances, which only contains running and compensating ones.
+// As the index is updated asynchronously, an instance might be returned shortly after it became final.
+func (s *ddbStore) ListActive(ctx context.Context) ([]*Instance, error) {
+	instances := make([]*Instance, 0)
+
+	for _, state := range []string{StateRunning, StateCompensating} {
+		active := make([]*Instance, 0)
+		qb := s.repository.QueryBuilder().WithIndex(ddbActiveIndex).WithHash(state)
+
+		if _, err := s.repository.Query(ctx, qb, &active); err != nil {
+			return nil, fmt.Errorf("can not query for %s saga instances: %w", state, err)
+		}
+
+		instances = append(instances, active...)
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,79 @@
+package saga
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+)
+
+type inMemoryStore struct {
+	lck       sync.Mutex
+	instances map[string]Instance
+}
+
+// NewInMemoryStore creates a store keeping the instances in memory only, which is useful for tests and local development.
+func NewInMemoryStore() Store {
+	return &inMemoryStore{
+		instances: make(map[string]Instance),
+	}
+}
+
+func (s *inMemoryStore) Get(_ context.Context, id string) (*Instance, bool, error) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	instance, ok := s.instances[id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return copyInstance(instance), true, nil
+}
+
+func (s *inMemoryStore) Save(_ context.Context, instance *Instance) error {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	stored, ok := s.instances[instance.Id]
+
+	if ok != (instance.Version > 0) || stored.Version != instance.Version {
+		return ErrInstanceConflict
+	}
+
+	instance.Version++
+	s.instances[instance.Id] = *copyInstance(*instance)
+
+	return nil
+}
+
+func (s *inMemoryStore) ListActive(_ context.Context) ([]*Instance, error) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	instances := make([]*Instance, 0)
+
+	for _, instance := range s.instances {
+		if isActive(instance.State) {
+			instances = append(instances, copyInstance(instance))
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
+	})
+
+	return instances, nil
+}
+
+func copyInstance(instance Instance) *Instance {
+	results := make(map[string]json.RawMessage, len(instance.Results))
+	for step, result := range instance.Results {
+		results[step] = result
+	}
+
+	instance.Results = results
+
+	return &instance
+}
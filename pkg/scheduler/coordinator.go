@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/conc"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/uuid"
+)
+
+const (
+	CoordinationNone           = "none"
+	CoordinationLeaderElection = "leader_election"
+	CoordinationLock           = "lock"
+)
+
+type JobCoordinationSettings struct {
+	// Type decides how a job is prevented from running on every replica: none, leader_election or lock.
+	Type string `cfg:"type" default:"none"`
+	// LeaderElection is the name of the leader election configured at conc.leader_election, defaults to the job name.
+	LeaderElection string `cfg:"leader_election"`
+	// LockTime is the minimum time the lock of a run is held, it should be longer than the clock skew between replicas.
+	LockTime time.Duration `cfg:"lock_time" default:"1m"`
+	// AcquireTimeout is the time to wait for the lock of a run before giving up.
+	AcquireTimeout time.Duration `cfg:"acquire_timeout" default:"5s"`
+}
+
+// A JobCoordinator decides which replica of an application runs a scheduled job.
+//go:generate mockery --name JobCoordinator
+type JobCoordinator interface {
+	// Acquire returns true if this replica should run the job scheduled at the given time.
+	Acquire(ctx context.Context, scheduledAt time.Time) (bool, error)
+	// Release has to be called after an acquired run finished.
+	Release(ctx context.Context, scheduledAt time.Time) error
+}
+
+func NewJobCoordinator(ctx context.Context, config cfg.Config, logger log.Logger, name string, settings *JobCoordinationSettings) (JobCoordinator, error) {
+	switch settings.Type {
+	case CoordinationNone, "":
+		return NewJobCoordinatorNoop(), nil
+
+	case CoordinationLeaderElection:
+		electionName := settings.LeaderElection
+		if electionName == "" {
+			electionName = name
+		}
+
+		leaderElection, err := conc.NewLeaderElection(ctx, config, logger, electionName)
+		if err != nil {
+			return nil, fmt.Errorf("can not create leader election %s: %w", electionName, err)
+		}
+
+		return NewLeaderElectionCoordinatorWithInterfaces(leaderElection, uuid.New().NewV4()), nil
+
+	case CoordinationLock:
+		lockProvider, err := conc.NewDdbLockProvider(ctx, config, logger, conc.DistributedLockSettings{
+			DefaultLockTime: settings.LockTime,
+			Domain:          "scheduler",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can not create lock provider: %w", err)
+		}
+
+		return NewLockCoordinatorWithInterfaces(clock.Provider, lockProvider, name, settings), nil
+
+	default:
+		return nil, fmt.Errorf("unknown coordination type %s of job %s", settings.Type, name)
+	}
+}
+
+type noopCoordinator struct{}
+
+// NewJobCoordinatorNoop returns a coordinator running the job on every replica.
+func NewJobCoordinatorNoop() JobCoordinator {
+	return noopCoordinator{}
+}
+
+func (c noopCoordinator) Acquire(_ context.Context, _ time.Time) (bool, error) {
+	return true, nil
+}
+
+func (c noopCoordinator) Release(_ context.Context, _ time.Time) error {
+	return nil
+}
+
+type leaderElectionCoordinator struct {
+	leaderElection conc.LeaderElection
+	memberId       string
+}
+
+// NewLeaderElectionCoordinatorWithInterfaces returns a coordinator running the job only on the elected leader.
+func NewLeaderElectionCoordinatorWithInterfaces(leaderElection conc.LeaderElection, memberId string) JobCoordinator {
+	return &leaderElectionCoordinator{
+		leaderElection: leaderElection,
+		memberId:       memberId,
+	}
+}
+
+func (c *leaderElectionCoordinator) Acquire(ctx context.Context, _ time.Time) (bool, error) {
+	isLeader, err := c.leaderElection.IsLeader(ctx, c.memberId)
+	if err != nil {
+		return false, fmt.Errorf("can not decide on leader: %w", err)
+	}
+
+	return isLeader, nil
+}
+
+func (c *leaderElectionCoordinator) Release(_ context.Context, _ time.Time) error {
+	return nil
+}
+
+type lockCoordinator struct {
+	clock    clock.Clock
+	provider conc.DistributedLockProvider
+	name     string
+	settings *JobCoordinationSettings
+
+	lck   sync.Mutex
+	locks map[time.Time]acquiredLock
+}
+
+type acquiredLock struct {
+	lock       conc.DistributedLock
+	acquiredAt time.Time
+}
+
+// NewLockCoordinatorWithInterfaces returns a coordinator running the job on the replica acquiring the lock of a run first.
+// Every scheduled run uses its own lock, which is held for at least the configured lock time, so a replica acquiring the
+// lock late is not able to run the job a second time.
+func NewLockCoordinatorWithInterfaces(clock clock.Clock, provider conc.DistributedLockProvider, name string, settings *JobCoordinationSettings) JobCoordinator {
+	return &lockCoordinator{
+		clock:    clock,
+		provider: provider,
+		name:     name,
+		settings: settings,
+		locks:    make(map[time.Time]acquiredLock),
+	}
+}
+
+func (c *lockCoordinator) Acquire(ctx context.Context, scheduledAt time.Time) (bool, error) {
+	acquireCtx, cancel := context.WithTimeout(ctx, c.settings.AcquireTimeout)
+	defer cancel()
+
+	resource := fmt.Sprintf("%s-%d", c.name, scheduledAt.Unix())
+
+	lock, err := c.provider.Acquire(acquireCtx, resource)
+	if errors.Is(err, conc.ErrOwnedLock) || (err != nil && acquireCtx.Err() != nil && ctx.Err() == nil) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("can not acquire lock %s: %w", resource, err)
+	}
+
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	c.locks[scheduledAt] = acquiredLock{
+		lock:       lock,
+		acquiredAt: c.clock.Now(),
+	}
+
+	return true, nil
+}
+
+func (c *lockCoordinator) Release(ctx context.Context, scheduledAt time.Time) error {
+	c.lck.Lock()
+	acquired, ok := c.locks[scheduledAt]
+	delete(c.locks, scheduledAt)
+	c.lck.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if remaining := acquired.acquiredAt.Add(c.settings.LockTime).Sub(c.clock.Now()); remaining > 0 {
+		select {
+		case <-ctx.Done():
+		case <-c.clock.After(remaining):
+		}
+	}
+
+	if err := acquired.lock.Release(); err != nil {
+		return fmt.Errorf("can not release lock of job %s: %w", c.name, err)
+	}
+
+	return nil
+}
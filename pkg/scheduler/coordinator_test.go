@@ -0,0 +1,55 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/conc"
+	concMocks "github.com/justtrackio/gosoline/pkg/conc/mocks"
+	"github.com/justtrackio/gosoline/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLockCoordinator(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clock.NewFakeClockAt(time.Date(2021, time.March, 3, 10, 0, 0, 0, time.UTC))
+	scheduledAt := fakeClock.Now()
+
+	lock := new(concMocks.DistributedLock)
+	lock.On("Release").Return(nil).Once()
+
+	provider := new(concMocks.DistributedLockProvider)
+	provider.On("Acquire", mock.Anything, "test-1614765600").Return(lock, nil).Once()
+	provider.On("Acquire", mock.Anything, "test-1614765600").Return(nil, conc.ErrOwnedLock).Once()
+
+	coordinator := scheduler.NewLockCoordinatorWithInterfaces(fakeClock, provider, "test", &scheduler.JobCoordinationSettings{
+		LockTime:       time.Minute,
+		AcquireTimeout: time.Second,
+	})
+
+	acquired, err := coordinator.Acquire(ctx, scheduledAt)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = coordinator.Acquire(ctx, scheduledAt)
+	assert.NoError(t, err)
+	assert.False(t, acquired, "a run should only be acquired once")
+
+	released := make(chan error)
+	go func() {
+		released <- coordinator.Release(ctx, scheduledAt)
+	}()
+
+	// the lock has to be held for the whole lock time, even if the job finished earlier
+	fakeClock.BlockUntil(1)
+	lock.AssertNotCalled(t, "Release")
+
+	fakeClock.Advance(time.Minute)
+	assert.NoError(t, <-released)
+
+	lock.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
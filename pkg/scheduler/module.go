@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/coffin"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/metric"
+)
+
+const (
+	metricNameJobSuccess  = "JobSuccess"
+	metricNameJobFailure  = "JobFailure"
+	metricNameJobSkipped  = "JobSkipped"
+	metricNameJobDuration = "JobDuration"
+)
+
+type JobSettings struct {
+	// Schedule is either a cron expression like "*/5 * * * *" or an interval like "@every 5m", see ParseSchedule.
+	Schedule string `cfg:"schedule"`
+	// Jitter delays every run by a random duration up to the given value to spread the load of many replicas.
+	Jitter time.Duration `cfg:"jitter" default:"0s"`
+	// Timeout cancels the context of a run after the given duration, 0 disables the timeout.
+	Timeout time.Duration `cfg:"timeout" default:"0s"`
+	// AllowOverlap starts a new run even if the previous run didn't finish yet.
+	AllowOverlap bool                    `cfg:"allow_overlap" default:"false"`
+	Coordination JobCoordinationSettings `cfg:"coordination"`
+}
+
+// A Job is a unit of work executed periodically by the scheduler.
+//go:generate mockery --name Job
+type Job interface {
+	Run(ctx context.Context) error
+}
+
+// JobFunc turns a function into a Job.
+type JobFunc func(ctx context.Context) error
+
+func (f JobFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+type JobFactory func(ctx context.Context, config cfg.Config, logger log.Logger) (Job, error)
+
+// JobModule runs a job according to its schedule. As a background module, it keeps running as long as the foreground
+// modules of the application do.
+type JobModule struct {
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+
+	logger       log.Logger
+	clock        clock.Clock
+	metricWriter metric.Writer
+	coordinator  JobCoordinator
+	job          Job
+	schedule     Schedule
+	name         string
+	settings     *JobSettings
+
+	running int32
+}
+
+// NewJobModule creates a kernel module running the job with the settings configured at scheduler.jobs.<name>.
+func NewJobModule(name string, factory JobFactory) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		settings := &JobSettings{}
+		config.UnmarshalKey(fmt.Sprintf("scheduler.jobs.%s", name), settings)
+
+		return NewJobModuleWithSettings(name, factory, settings)(ctx, config, logger)
+	}
+}
+
+func NewJobModuleWithSettings(name string, factory JobFactory, settings *JobSettings) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		var err error
+		var job Job
+		var schedule Schedule
+		var coordinator JobCoordinator
+
+		logger = logger.WithChannel("scheduler").WithFields(log.Fields{
+			"job": name,
+		})
+
+		if schedule, err = ParseSchedule(settings.Schedule); err != nil {
+			return nil, fmt.Errorf("can not parse schedule of job %s: %w", name, err)
+		}
+
+		if coordinator, err = NewJobCoordinator(ctx, config, logger, name, &settings.Coordination); err != nil {
+			return nil, fmt.Errorf("can not create coordinator of job %s: %w", name, err)
+		}
+
+		if job, err = factory(ctx, config, logger); err != nil {
+			return nil, fmt.Errorf("can not create job %s: %w", name, err)
+		}
+
+		defaultMetrics := getJobDefaultMetrics(name)
+		metricWriter := metric.NewDaemonWriter(defaultMetrics...)
+
+		return NewJobModuleWithInterfaces(logger, clock.Provider, metricWriter, coordinator, job, schedule, name, settings), nil
+	}
+}
+
+func NewJobModuleWithInterfaces(
+	logger log.Logger,
+	clock clock.Clock,
+	metricWriter metric.Writer,
+	coordinator JobCoordinator,
+	job Job,
+	schedule Schedule,
+	name string,
+	settings *JobSettings,
+) *JobModule {
+	return &JobModule{
+		logger:       logger,
+		clock:        clock,
+		metricWriter: metricWriter,
+		coordinator:  coordinator,
+		job:          job,
+		schedule:     schedule,
+		name:         name,
+		settings:     settings,
+	}
+}
+
+func (m *JobModule) Run(ctx context.Context) error {
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	next := m.schedule.Next(m.clock.Now())
+
+	for {
+		if next.IsZero() {
+			m.logger.Warn("job %s has no further runs scheduled", m.name)
+			return nil
+		}
+
+		wait := next.Sub(m.clock.Now()) + m.getJitter()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-m.clock.After(wait):
+		}
+
+		if !m.settings.AllowOverlap && !atomic.CompareAndSwapInt32(&m.running, 0, 1) {
+			m.logger.Warn("skipping run of job %s scheduled at %s: the previous run is still running", m.name, next.Format(time.RFC3339))
+			m.writeMetric(metricNameJobSkipped, 1, metric.UnitCount)
+		} else {
+			wg.Add(1)
+
+			go func(scheduledAt time.Time) {
+				defer wg.Done()
+				defer atomic.StoreInt32(&m.running, 0)
+
+				m.execute(ctx, scheduledAt)
+			}(next)
+		}
+
+		now := m.clock.Now()
+		if next.Before(now) {
+			next = now
+		}
+
+		next = m.schedule.Next(next)
+	}
+}
+
+func (m *JobModule) execute(ctx context.Context, scheduledAt time.Time) {
+	logger := m.logger.WithContext(ctx)
+
+	acquired, err := m.coordinator.Acquire(ctx, scheduledAt)
+	if err != nil {
+		logger.Error("can not coordinate run of job %s: %w", m.name, err)
+		m.writeMetric(metricNameJobFailure, 1, metric.UnitCount)
+
+		return
+	}
+
+	if !acquired {
+		logger.Info("skipping run of job %s scheduled at %s: running on another instance", m.name, scheduledAt.Format(time.RFC3339))
+		m.writeMetric(metricNameJobSkipped, 1, metric.UnitCount)
+
+		return
+	}
+
+	defer func() {
+		// the run is over: holding the lock out until its lock time elapsed must not prevent the next run
+		atomic.StoreInt32(&m.running, 0)
+
+		if err := m.coordinator.Release(ctx, scheduledAt); err != nil {
+			logger.Warn("can not release run of job %s: %s", m.name, err.Error())
+		}
+	}()
+
+	runCtx := ctx
+	if m.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, m.settings.Timeout)
+		defer cancel()
+	}
+
+	start := m.clock.Now()
+	err = m.runJob(runCtx)
+	duration := m.clock.Now().Sub(start)
+
+	m.writeMetric(metricNameJobDuration, float64(duration.Milliseconds()), metric.UnitMillisecondsAverage)
+
+	if err != nil {
+		logger.Error("run of job %s failed after %s: %w", m.name, duration, err)
+		m.writeMetric(metricNameJobFailure, 1, metric.UnitCount)
+
+		return
+	}
+
+	logger.Info("run of job %s finished after %s", m.name, duration)
+	m.writeMetric(metricNameJobSuccess, 1, metric.UnitCount)
+}
+
+func (m *JobModule) runJob(ctx context.Context) (err error) {
+	defer func() {
+		if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+
+	return m.job.Run(ctx)
+}
+
+func (m *JobModule) getJitter() time.Duration {
+	if m.settings.Jitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(m.settings.Jitter)))
+}
+
+func (m *JobModule) writeMetric(name string, value float64, unit metric.StandardUnit) {
+	m.metricWriter.WriteOne(&metric.Datum{
+		Priority:   metric.PriorityHigh,
+		MetricName: name,
+		Dimensions: map[string]string{
+			"Job": m.name,
+		},
+		Unit:  unit,
+		Value: value,
+	})
+}
+
+func getJobDefaultMetrics(name string) metric.Data {
+	data := metric.Data{}
+
+	for _, metricName := range []string{metricNameJobSuccess, metricNameJobFailure, metricNameJobSkipped} {
+		data = append(data, &metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricName,
+			Dimensions: map[string]string{
+				"Job": name,
+			},
+			Unit:  metric.UnitCount,
+			Value: 0.0,
+		})
+	}
+
+	return data
+}
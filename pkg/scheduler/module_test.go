@@ -0,0 +1,238 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/scheduler"
+	"github.com/justtrackio/gosoline/pkg/scheduler/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type jobModuleTestCase struct {
+	clock       clock.FakeClock
+	coordinator *mocks.JobCoordinator
+	job         *mocks.Job
+	module      *scheduler.JobModule
+
+	lck     sync.Mutex
+	metrics map[string]int
+}
+
+func newJobModuleTestCase(t *testing.T, settings *scheduler.JobSettings) *jobModuleTestCase {
+	tc := &jobModuleTestCase{
+		clock:       clock.NewFakeClockAt(time.Date(2021, time.March, 3, 10, 0, 0, 0, time.UTC)),
+		coordinator: new(mocks.JobCoordinator),
+		job:         new(mocks.Job),
+		metrics:     map[string]int{},
+	}
+
+	metricWriter := new(metricMocks.Writer)
+	metricWriter.On("WriteOne", mock.AnythingOfType("*metric.Datum")).Run(func(args mock.Arguments) {
+		datum := args.Get(0).(*metric.Datum)
+		assert.Equal(t, metric.Dimensions{"Job": "test"}, datum.Dimensions)
+
+		tc.lck.Lock()
+		defer tc.lck.Unlock()
+
+		tc.metrics[datum.MetricName]++
+	})
+
+	schedule, err := scheduler.ParseSchedule(settings.Schedule)
+	assert.NoError(t, err)
+
+	logger := logMocks.NewLoggerMockedAll()
+	tc.module = scheduler.NewJobModuleWithInterfaces(logger, tc.clock, metricWriter, tc.coordinator, tc.job, schedule, "test", settings)
+
+	return tc
+}
+
+func (tc *jobModuleTestCase) run() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- tc.module.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// tick waits for the scheduler to wait for the next run and moves the clock to it
+func (tc *jobModuleTestCase) tick(d time.Duration) {
+	tc.clock.BlockUntil(1)
+	tc.clock.Advance(d)
+}
+
+func (tc *jobModuleTestCase) getMetrics() map[string]int {
+	tc.lck.Lock()
+	defer tc.lck.Unlock()
+
+	metrics := make(map[string]int, len(tc.metrics))
+	for name, count := range tc.metrics {
+		metrics[name] = count
+	}
+
+	return metrics
+}
+
+func TestJobModule_Run(t *testing.T) {
+	tc := newJobModuleTestCase(t, &scheduler.JobSettings{
+		Schedule: "*/5 * * * *",
+	})
+
+	runs := make(chan time.Time)
+
+	tc.coordinator.On("Acquire", mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+	tc.coordinator.On("Release", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	tc.job.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		runs <- tc.clock.Now()
+	}).Return(nil).Once()
+	tc.job.On("Run", mock.Anything).Return(fmt.Errorf("job failed")).Once()
+
+	stop := tc.run()
+
+	tc.tick(5 * time.Minute)
+	assert.Equal(t, time.Date(2021, time.March, 3, 10, 5, 0, 0, time.UTC), <-runs)
+
+	tc.tick(5 * time.Minute)
+
+	assert.Eventually(t, func() bool {
+		return tc.getMetrics()["JobFailure"] == 1
+	}, time.Second, time.Millisecond)
+
+	stop()
+
+	assert.Equal(t, map[string]int{
+		"JobDuration": 2,
+		"JobSuccess":  1,
+		"JobFailure":  1,
+	}, tc.getMetrics())
+
+	tc.coordinator.AssertCalled(t, "Acquire", mock.Anything, time.Date(2021, time.March, 3, 10, 5, 0, 0, time.UTC))
+	tc.coordinator.AssertCalled(t, "Acquire", mock.Anything, time.Date(2021, time.March, 3, 10, 10, 0, 0, time.UTC))
+	tc.job.AssertExpectations(t)
+}
+
+func TestJobModule_PreventOverlap(t *testing.T) {
+	tc := newJobModuleTestCase(t, &scheduler.JobSettings{
+		Schedule: "@every 1m",
+	})
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+
+	tc.coordinator.On("Acquire", mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+	tc.coordinator.On("Release", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	tc.job.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		close(started)
+		<-finish
+	}).Return(nil).Once()
+
+	stop := tc.run()
+
+	tc.tick(time.Minute)
+	<-started
+
+	tc.tick(time.Minute)
+	tc.clock.BlockUntil(1)
+
+	assert.Equal(t, map[string]int{"JobSkipped": 1}, tc.getMetrics())
+
+	close(finish)
+	stop()
+
+	tc.job.AssertExpectations(t)
+}
+
+func TestJobModule_OverlapWithRelease(t *testing.T) {
+	tc := newJobModuleTestCase(t, &scheduler.JobSettings{
+		Schedule: "@every 1m",
+	})
+
+	runs := make(chan time.Time)
+	releasing := make(chan struct{})
+	release := make(chan struct{})
+
+	tc.coordinator.On("Acquire", mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+	tc.coordinator.On("Release", mock.Anything, time.Date(2021, time.March, 3, 10, 1, 0, 0, time.UTC)).Run(func(args mock.Arguments) {
+		close(releasing)
+		<-release
+	}).Return(nil).Once()
+	tc.coordinator.On("Release", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	tc.job.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		runs <- tc.clock.Now()
+	}).Return(nil).Twice()
+
+	stop := tc.run()
+
+	tc.tick(time.Minute)
+	assert.Equal(t, time.Date(2021, time.March, 3, 10, 1, 0, 0, time.UTC), <-runs)
+	<-releasing
+
+	tc.tick(time.Minute)
+	assert.Equal(t, time.Date(2021, time.March, 3, 10, 2, 0, 0, time.UTC), <-runs)
+
+	close(release)
+	stop()
+
+	assert.Equal(t, 0, tc.getMetrics()["JobSkipped"])
+	tc.job.AssertExpectations(t)
+}
+
+func TestJobModule_NotAcquired(t *testing.T) {
+	tc := newJobModuleTestCase(t, &scheduler.JobSettings{
+		Schedule: "@every 1m",
+	})
+
+	tc.coordinator.On("Acquire", mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	stop := tc.run()
+	tc.tick(time.Minute)
+
+	assert.Eventually(t, func() bool {
+		return tc.getMetrics()["JobSkipped"] == 1
+	}, time.Second, time.Millisecond)
+
+	stop()
+
+	tc.job.AssertNotCalled(t, "Run", mock.Anything)
+	tc.coordinator.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+}
+
+func TestJobModule_Timeout(t *testing.T) {
+	tc := newJobModuleTestCase(t, &scheduler.JobSettings{
+		Schedule: "@every 1m",
+		Timeout:  time.Millisecond,
+	})
+
+	tc.coordinator.On("Acquire", mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+	tc.coordinator.On("Release", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	tc.job.On("Run", mock.Anything).Return(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}).Once()
+
+	stop := tc.run()
+	tc.tick(time.Minute)
+
+	assert.Eventually(t, func() bool {
+		return tc.getMetrics()["JobFailure"] == 1
+	}, time.Second, time.Millisecond)
+
+	stop()
+
+	tc.job.AssertExpectations(t)
+}
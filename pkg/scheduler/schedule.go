@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule provides the times a job should run at.
+//go:generate mockery --name Schedule
+type Schedule interface {
+	// Next returns the first time after the given time the job should run at. A zero time means there is no next run.
+	Next(t time.Time) time.Time
+}
+
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses either a cron expression with the five fields minute, hour, day of month, month and day of
+// week, one of the descriptors @yearly, @monthly, @weekly, @daily or @hourly or an interval like "@every 5m".
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("can not parse interval of schedule %s: %w", expr, err)
+		}
+
+		return Every(interval)
+	}
+
+	if descriptor, ok := scheduleDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	return parseCron(expr)
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule running a job in a fixed interval.
+func Every(interval time.Duration) (Schedule, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("the interval of a schedule has to be positive, got %s", interval)
+	}
+
+	return &everySchedule{
+		interval: interval,
+	}, nil
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+type cronField struct {
+	name   string
+	min    int
+	max    int
+	values map[string]int
+}
+
+var (
+	cronFieldMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronFieldHour       = cronField{name: "hour", min: 0, max: 23}
+	cronFieldDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronFieldMonth      = cronField{name: "month", min: 1, max: 12, values: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronFieldDayOfWeek = cronField{name: "day of week", min: 0, max: 7, values: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// if day of month and day of week are both restricted, a day matching either of them is used
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+}
+
+func parseCron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("a cron expression needs 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	var err error
+	schedule := &cronSchedule{
+		dayOfMonthAny: fields[2] == "*" || fields[2] == "?",
+		dayOfWeekAny:  fields[4] == "*" || fields[4] == "?",
+	}
+
+	targets := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&schedule.minute, cronFieldMinute},
+		{&schedule.hour, cronFieldHour},
+		{&schedule.dayOfMonth, cronFieldDayOfMonth},
+		{&schedule.month, cronFieldMonth},
+		{&schedule.dayOfWeek, cronFieldDayOfWeek},
+	}
+
+	for i, target := range targets {
+		if *target.bits, err = target.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("can not parse cron expression %q: %w", expr, err)
+		}
+	}
+
+	// sunday can be written as 0 or 7
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", f.name, part)
+			}
+		}
+
+		from, to, err := f.parseRange(rangeExpr)
+		if err != nil {
+			return 0, err
+		}
+
+		// a single value with a step runs from the value until the end of the range, e.g. 5/15 for minutes
+		if step > 1 && !strings.Contains(rangeExpr, "-") && rangeExpr != "*" && rangeExpr != "?" {
+			to = f.max
+		}
+
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) parseRange(expr string) (int, int, error) {
+	if expr == "*" || expr == "?" {
+		return f.min, f.max, nil
+	}
+
+	bounds := strings.SplitN(expr, "-", 2)
+
+	from, err := f.parseValue(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(bounds) == 1 {
+		return from, from, nil
+	}
+
+	to, err := f.parseValue(bounds[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if from > to {
+		return 0, 0, fmt.Errorf("invalid range in %s field: %s", f.name, expr)
+	}
+
+	return from, to, nil
+}
+
+func (f cronField) parseValue(expr string) (int, error) {
+	if value, ok := f.values[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: %s", f.name, expr)
+	}
+
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("value %d of %s field is out of range [%d, %d]", value, f.name, f.min, f.max)
+	}
+
+	return value, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+
+	// there has to be a match within a few years, otherwise the expression describes an impossible date like 30th of february
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !hasBit(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !hasBit(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if !hasBit(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := hasBit(s.dayOfMonth, t.Day())
+	dayOfWeek := hasBit(s.dayOfWeek, int(t.Weekday()))
+
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+func hasBit(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
@@ -0,0 +1,127 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// a wednesday
+	now := time.Date(2021, time.March, 3, 10, 17, 42, 0, time.UTC)
+
+	tests := map[string]struct {
+		expr     string
+		expected []time.Time
+	}{
+		"every minute": {
+			expr: "* * * * *",
+			expected: []time.Time{
+				time.Date(2021, time.March, 3, 10, 18, 0, 0, time.UTC),
+				time.Date(2021, time.March, 3, 10, 19, 0, 0, time.UTC),
+			},
+		},
+		"steps": {
+			expr: "*/15 * * * *",
+			expected: []time.Time{
+				time.Date(2021, time.March, 3, 10, 30, 0, 0, time.UTC),
+				time.Date(2021, time.March, 3, 10, 45, 0, 0, time.UTC),
+				time.Date(2021, time.March, 3, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		"offset step": {
+			expr: "5/20 8 * * *",
+			expected: []time.Time{
+				time.Date(2021, time.March, 4, 8, 5, 0, 0, time.UTC),
+				time.Date(2021, time.March, 4, 8, 25, 0, 0, time.UTC),
+				time.Date(2021, time.March, 4, 8, 45, 0, 0, time.UTC),
+			},
+		},
+		"lists and ranges": {
+			expr: "0 9-10,16 * * mon-fri",
+			expected: []time.Time{
+				time.Date(2021, time.March, 3, 16, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 4, 9, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 4, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		"weekend": {
+			expr: "30 6 * * 6,7",
+			expected: []time.Time{
+				time.Date(2021, time.March, 6, 6, 30, 0, 0, time.UTC),
+				time.Date(2021, time.March, 7, 6, 30, 0, 0, time.UTC),
+				time.Date(2021, time.March, 13, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		"day of month or day of week": {
+			expr: "0 0 1 * sun",
+			expected: []time.Time{
+				time.Date(2021, time.March, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 21, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"leap day": {
+			expr: "0 12 29 feb *",
+			expected: []time.Time{
+				time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		"descriptor": {
+			expr: "@daily",
+			expected: []time.Time{
+				time.Date(2021, time.March, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"interval": {
+			expr: "@every 90s",
+			expected: []time.Time{
+				time.Date(2021, time.March, 3, 10, 19, 12, 0, time.UTC),
+				time.Date(2021, time.March, 3, 10, 20, 42, 0, time.UTC),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schedule, err := scheduler.ParseSchedule(test.expr)
+			assert.NoError(t, err)
+
+			next := now
+			for _, expected := range test.expected {
+				next = schedule.Next(next)
+				assert.Equal(t, expected, next)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Impossible(t *testing.T) {
+	schedule, err := scheduler.ParseSchedule("0 0 30 2 *")
+	assert.NoError(t, err)
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * foo *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@every",
+		"@every -5m",
+	} {
+		_, err := scheduler.ParseSchedule(expr)
+		assert.Error(t, err, "expression %q should be invalid", expr)
+	}
+}
@@ -0,0 +1,234 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	gosoS3 "github.com/justtrackio/gosoline/pkg/cloud/aws/s3"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/uuid"
+)
+
+const (
+	AttributeClaimCheck = "claimCheck"
+	claimCheckScheme    = "s3://"
+)
+
+// ClaimCheckSettings configure a producer to upload message bodies exceeding the threshold (in bytes) to S3.
+// The message is sent with a reference to the uploaded body instead.
+type ClaimCheckSettings struct {
+	Enabled   bool   `cfg:"enabled" default:"false"`
+	Threshold int    `cfg:"threshold" default:"204800"`
+	Bucket    string `cfg:"bucket"`
+	Prefix    string `cfg:"prefix" default:"claim-check"`
+}
+
+// ConsumerClaimCheckSettings configure a consumer to download the bodies of claim checked messages. If DeleteOnAck
+// is enabled, the uploaded body is deleted as soon as the message has been acknowledged. This only works if there is
+// exactly one consumer for the messages.
+type ConsumerClaimCheckSettings struct {
+	Enabled     bool `cfg:"enabled" default:"true"`
+	DeleteOnAck bool `cfg:"delete_on_ack" default:"false"`
+}
+
+type ClaimCheckHandler struct {
+	logger         log.Logger
+	lck            sync.Mutex
+	client         gosoS3.Client
+	clientProvider func() (gosoS3.Client, error)
+	uuid        uuid.Uuid
+	threshold   int
+	bucket      string
+	prefix      string
+	deleteOnAck bool
+}
+
+// NewClaimCheckHandler creates the handler for a producer. The bucket defaults to the one of the blob store.
+func NewClaimCheckHandler(ctx context.Context, config cfg.Config, logger log.Logger, settings *ClaimCheckSettings) (*ClaimCheckHandler, error) {
+	client, err := gosoS3.ProvideClient(ctx, config, logger, "default")
+	if err != nil {
+		return nil, fmt.Errorf("can not create s3 client default: %w", err)
+	}
+
+	if settings.Bucket == "" {
+		appId := cfg.GetAppIdFromConfig(config)
+		settings.Bucket = fmt.Sprintf("%s-%s-%s", appId.Project, appId.Environment, appId.Family)
+	}
+
+	return NewClaimCheckHandlerWithInterfaces(logger, client, uuid.New(), settings, false), nil
+}
+
+// NewConsumerClaimCheckHandler creates the handler for a consumer. It reads the bucket of a body from the reference
+// of the message, so it doesn't need to know the settings of the producer. The s3 client is only created with the
+// first claim checked message, so consumers which never receive one don't need access to s3.
+func NewConsumerClaimCheckHandler(ctx context.Context, config cfg.Config, logger log.Logger, settings *ConsumerClaimCheckSettings) *ClaimCheckHandler {
+	return NewConsumerClaimCheckHandlerWithInterfaces(logger, func() (gosoS3.Client, error) {
+		return gosoS3.ProvideClient(ctx, config, logger, "default")
+	}, settings)
+}
+
+func NewConsumerClaimCheckHandlerWithInterfaces(logger log.Logger, clientProvider func() (gosoS3.Client, error), settings *ConsumerClaimCheckSettings) *ClaimCheckHandler {
+	handler := NewClaimCheckHandlerWithInterfaces(logger, nil, uuid.New(), &ClaimCheckSettings{}, settings.DeleteOnAck)
+	handler.clientProvider = clientProvider
+
+	return handler
+}
+
+func NewClaimCheckHandlerWithInterfaces(logger log.Logger, client gosoS3.Client, uuid uuid.Uuid, settings *ClaimCheckSettings, deleteOnAck bool) *ClaimCheckHandler {
+	return &ClaimCheckHandler{
+		logger:      logger.WithChannel("claim-check"),
+		client:      client,
+		uuid:        uuid,
+		threshold:   settings.Threshold,
+		bucket:      settings.Bucket,
+		prefix:      settings.Prefix,
+		deleteOnAck: deleteOnAck,
+	}
+}
+
+func (h *ClaimCheckHandler) Encode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	return ctx, attributes, nil
+}
+
+func (h *ClaimCheckHandler) Decode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	return ctx, attributes, nil
+}
+
+// EncodeBody uploads bodies above the threshold and replaces them with the reference, which is also stored in the
+// attribute AttributeClaimCheck.
+func (h *ClaimCheckHandler) EncodeBody(ctx context.Context, body []byte, attributes map[string]interface{}) ([]byte, map[string]interface{}, error) {
+	if h.threshold <= 0 || len(body) <= h.threshold {
+		return body, attributes, nil
+	}
+
+	key := strings.TrimLeft(fmt.Sprintf("%s/%s", h.prefix, h.uuid.NewV4()), "/")
+
+	_, err := h.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: mdl.String(h.bucket),
+		Key:    mdl.String(key),
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		return nil, attributes, fmt.Errorf("can not upload the message body to s3://%s/%s: %w", h.bucket, key, err)
+	}
+
+	reference := fmt.Sprintf("%s%s/%s", claimCheckScheme, h.bucket, key)
+	attributes[AttributeClaimCheck] = reference
+
+	h.logger.WithContext(ctx).Debug("uploaded message body of %d bytes to %s", len(body), reference)
+
+	return []byte(reference), attributes, nil
+}
+
+// DecodeBody replaces the body of claim checked messages with the uploaded body.
+func (h *ClaimCheckHandler) DecodeBody(ctx context.Context, body []byte, attributes map[string]interface{}) ([]byte, map[string]interface{}, error) {
+	bucket, key, ok, err := getClaimCheckAttribute(attributes)
+	if err != nil || !ok {
+		return body, attributes, err
+	}
+
+	client, err := h.getClient()
+	if err != nil {
+		return nil, attributes, err
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: mdl.String(bucket),
+		Key:    mdl.String(key),
+	})
+	if err != nil {
+		return nil, attributes, fmt.Errorf("can not download the message body from s3://%s/%s: %w", bucket, key, err)
+	}
+
+	defer out.Body.Close()
+
+	if body, err = ioutil.ReadAll(out.Body); err != nil {
+		return nil, attributes, fmt.Errorf("can not read the message body from s3://%s/%s: %w", bucket, key, err)
+	}
+
+	delete(attributes, AttributeClaimCheck)
+
+	return body, attributes, nil
+}
+
+// Acknowledged deletes the uploaded bodies of the messages if DeleteOnAck is enabled. Errors are only logged, as
+// the messages are gone already.
+func (h *ClaimCheckHandler) Acknowledged(ctx context.Context, msgs []*Message) {
+	if !h.deleteOnAck {
+		return
+	}
+
+	logger := h.logger.WithContext(ctx)
+
+	for _, msg := range msgs {
+		bucket, key, ok, err := getClaimCheckAttribute(msg.Attributes)
+
+		if err != nil {
+			logger.Warn("can not delete the claim checked message body: %s", err)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		client, err := h.getClient()
+		if err != nil {
+			logger.Error("can not delete the message body s3://%s/%s: %w", bucket, key, err)
+			continue
+		}
+
+		_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: mdl.String(bucket),
+			Key:    mdl.String(key),
+		})
+
+		if err != nil {
+			logger.Error("can not delete the message body s3://%s/%s: %w", bucket, key, err)
+		}
+	}
+}
+
+func (h *ClaimCheckHandler) getClient() (gosoS3.Client, error) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	if h.client != nil {
+		return h.client, nil
+	}
+
+	client, err := h.clientProvider()
+	if err != nil {
+		return nil, fmt.Errorf("can not create s3 client default: %w", err)
+	}
+
+	h.client = client
+
+	return client, nil
+}
+
+func getClaimCheckAttribute(attributes map[string]interface{}) (bucket string, key string, ok bool, err error) {
+	value, ok := attributes[AttributeClaimCheck]
+	if !ok {
+		return "", "", false, nil
+	}
+
+	reference, ok := value.(string)
+	if !ok {
+		return "", "", false, fmt.Errorf("the %s attribute should be a string but is %T", AttributeClaimCheck, value)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(reference, claimCheckScheme), "/", 2)
+	if !strings.HasPrefix(reference, claimCheckScheme) || len(parts) != 2 {
+		return "", "", false, fmt.Errorf("the %s attribute %s is no valid s3 reference", AttributeClaimCheck, reference)
+	}
+
+	return parts[0], parts[1], true, nil
+}
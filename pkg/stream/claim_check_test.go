@@ -0,0 +1,200 @@
+package stream_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	gosoS3 "github.com/justtrackio/gosoline/pkg/cloud/aws/s3"
+	s3Mocks "github.com/justtrackio/gosoline/pkg/cloud/aws/s3/mocks"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	uuidMocks "github.com/justtrackio/gosoline/pkg/uuid/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type claimCheckAckInput struct {
+	stream.Input
+	*streamMocks.AcknowledgeableInput
+}
+
+type ClaimCheckTestSuite struct {
+	suite.Suite
+
+	client  *s3Mocks.Client
+	uuid    *uuidMocks.Uuid
+	handler *stream.ClaimCheckHandler
+	encoder stream.MessageEncoder
+}
+
+func (s *ClaimCheckTestSuite) SetupTest() {
+	s.client = new(s3Mocks.Client)
+	s.uuid = new(uuidMocks.Uuid)
+
+	s.handler = stream.NewClaimCheckHandlerWithInterfaces(logMocks.NewLoggerMockedAll(), s.client, s.uuid, &stream.ClaimCheckSettings{
+		Enabled:   true,
+		Threshold: 16,
+		Bucket:    "bucket",
+		Prefix:    "claim-check",
+	}, true)
+
+	s.encoder = stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding:       stream.EncodingJson,
+		EncodeHandlers: []stream.EncodeHandler{s.handler},
+	})
+}
+
+func (s *ClaimCheckTestSuite) TearDownTest() {
+	s.client.AssertExpectations(s.T())
+	s.uuid.AssertExpectations(s.T())
+}
+
+func (s *ClaimCheckTestSuite) TestBelowThreshold() {
+	msg, err := s.encoder.Encode(context.Background(), map[string]int{"a": 1})
+	s.NoError(err)
+	s.Equal(`{"a":1}`, msg.Body)
+	s.NotContains(msg.Attributes, stream.AttributeClaimCheck)
+
+	out := map[string]int{}
+	_, attributes, err := s.encoder.Decode(context.Background(), msg, &out)
+	s.NoError(err)
+	s.Equal(map[string]int{"a": 1}, out)
+	s.Empty(attributes)
+}
+
+func (s *ClaimCheckTestSuite) TestEncodeDecode() {
+	body := `{"text":"a body above the threshold"}`
+	reference := "s3://bucket/claim-check/8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a"
+
+	s.uuid.On("NewV4").Return("8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a").Once()
+	s.client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		uploaded, err := ioutil.ReadAll(input.Body)
+
+		return err == nil && *input.Bucket == "bucket" && *input.Key == "claim-check/8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a" && string(uploaded) == body
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	msg, err := s.encoder.Encode(context.Background(), map[string]string{"text": "a body above the threshold"})
+	s.NoError(err)
+	s.Equal(reference, msg.Body)
+	s.Equal(reference, msg.Attributes[stream.AttributeClaimCheck])
+
+	s.client.On("GetObject", mock.Anything, &s3.GetObjectInput{
+		Bucket: mdl.String("bucket"),
+		Key:    mdl.String("claim-check/8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a"),
+	}).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil).Once()
+
+	out := map[string]string{}
+	_, attributes, err := s.encoder.Decode(context.Background(), msg, &out)
+	s.NoError(err)
+	s.Equal(map[string]string{"text": "a body above the threshold"}, out)
+	s.NotContains(attributes, stream.AttributeClaimCheck)
+	s.Contains(msg.Attributes, stream.AttributeClaimCheck, "the message itself should stay untouched")
+}
+
+func (s *ClaimCheckTestSuite) TestUploadFails() {
+	s.uuid.On("NewV4").Return("8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a").Once()
+	s.client.On("PutObject", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom")).Once()
+
+	_, err := s.encoder.Encode(context.Background(), map[string]string{"text": "a body above the threshold"})
+	s.EqualError(err, "can not apply encoding handler on message body: can not upload the message body to s3://bucket/claim-check/8e3f0f1c-8c4f-4f6c-9d2b-6b1f3d1c7f0a: boom")
+}
+
+func (s *ClaimCheckTestSuite) TestDecodeInvalidReference() {
+	msg := stream.NewJsonMessage("s3://bucket", map[string]interface{}{
+		stream.AttributeClaimCheck: "s3://bucket",
+	})
+
+	out := map[string]string{}
+	_, _, err := s.encoder.Decode(context.Background(), msg, &out)
+	s.EqualError(err, "can not apply encoding handler on message body: the claimCheck attribute s3://bucket is no valid s3 reference")
+}
+
+func (s *ClaimCheckTestSuite) TestDeleteOnAck() {
+	input := new(streamMocks.AcknowledgeableInput)
+	ack := stream.NewConsumerAcknowledgeWithInterfaces(logMocks.NewLoggerMockedAll(), claimCheckAckInput{AcknowledgeableInput: input}, s.handler)
+
+	claimChecked := stream.NewJsonMessage("s3://bucket/claim-check/key", map[string]interface{}{
+		stream.AttributeClaimCheck: "s3://bucket/claim-check/key",
+	})
+	plain := stream.NewJsonMessage(`{}`)
+
+	input.On("AckBatch", mock.Anything, []*stream.Message{claimChecked, plain}).Return(nil).Once()
+	s.client.On("DeleteObject", mock.Anything, &s3.DeleteObjectInput{
+		Bucket: mdl.String("bucket"),
+		Key:    mdl.String("claim-check/key"),
+	}).Return(&s3.DeleteObjectOutput{}, nil).Once()
+
+	ack.AcknowledgeBatch(context.Background(), []*stream.Message{claimChecked, plain})
+
+	input.On("Ack", mock.Anything, claimChecked).Return(fmt.Errorf("ack failed")).Once()
+
+	ack.Acknowledge(context.Background(), claimChecked)
+
+	input.AssertExpectations(s.T())
+}
+
+func (s *ClaimCheckTestSuite) TestConsumerCreatesClientLazily() {
+	provided := 0
+	handler := stream.NewConsumerClaimCheckHandlerWithInterfaces(logMocks.NewLoggerMockedAll(), func() (gosoS3.Client, error) {
+		provided++
+
+		return s.client, nil
+	}, &stream.ConsumerClaimCheckSettings{
+		Enabled: true,
+	})
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding:       stream.EncodingJson,
+		EncodeHandlers: []stream.EncodeHandler{handler},
+	})
+
+	out := map[string]string{}
+	_, _, err := encoder.Decode(context.Background(), stream.NewJsonMessage(`{"text":"plain"}`), &out)
+	s.NoError(err)
+	s.Equal(0, provided, "the client should not be created for messages without claim check")
+
+	claimChecked := stream.NewJsonMessage("s3://bucket/claim-check/key", map[string]interface{}{
+		stream.AttributeClaimCheck: "s3://bucket/claim-check/key",
+	})
+
+	s.client.On("GetObject", mock.Anything, &s3.GetObjectInput{
+		Bucket: mdl.String("bucket"),
+		Key:    mdl.String("claim-check/key"),
+	}).Return(func(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{
+			Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"text":"claim checked"}`))),
+		}
+	}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, _, err = encoder.Decode(context.Background(), claimChecked, &out)
+		s.NoError(err)
+		s.Equal(map[string]string{"text": "claim checked"}, out)
+	}
+
+	s.Equal(1, provided, "the client should be created once")
+}
+
+func (s *ClaimCheckTestSuite) TestConsumerClientFails() {
+	handler := stream.NewConsumerClaimCheckHandlerWithInterfaces(logMocks.NewLoggerMockedAll(), func() (gosoS3.Client, error) {
+		return nil, fmt.Errorf("no credentials")
+	}, &stream.ConsumerClaimCheckSettings{
+		Enabled: true,
+	})
+
+	_, _, err := handler.DecodeBody(context.Background(), []byte("s3://bucket/claim-check/key"), map[string]interface{}{
+		stream.AttributeClaimCheck: "s3://bucket/claim-check/key",
+	})
+	s.EqualError(err, "can not create s3 client default: no credentials")
+}
+
+func TestClaimCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(ClaimCheckTestSuite))
+}
@@ -69,12 +69,14 @@ func (c *Consumer) run(ctx context.Context) error {
 	defer c.logger.Debug("runConsuming is ending")
 	defer c.wg.Done()
 
+	data := c.partitioner.Data()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return fmt.Errorf("return from consuming as the coffin is dying")
 
-		case msg, ok := <-c.input.Data():
+		case msg, ok := <-data:
 			if !ok {
 				return nil
 			}
@@ -84,6 +86,9 @@ func (c *Consumer) run(ctx context.Context) error {
 			} else {
 				c.processSingleMessage(ctx, msg)
 			}
+
+		case msg := <-c.retry.Data():
+			c.processSingleMessage(ctx, msg)
 		}
 	}
 }
@@ -132,17 +137,23 @@ func (c *Consumer) process(ctx context.Context, msg *Message) bool {
 	var model interface{}
 	var attributes map[string]interface{}
 
-	if model = c.callback.GetModel(msg.Attributes); model == nil {
-		err := fmt.Errorf("can not get model for message attributes %v", msg.Attributes)
+	if ctx, model, attributes, err = c.decodeMessage(ctx, msg); err != nil {
 		c.handleError(ctx, err, "an error occurred during the consume operation")
 		return false
 	}
 
-	if ctx, attributes, err = c.encoder.Decode(ctx, msg, model); err != nil {
-		c.handleError(ctx, err, "an error occurred during the consume operation")
+	var dedupKey string
+	var duplicate bool
+
+	if dedupKey, duplicate, err = c.isDuplicate(ctx, model, attributes); err != nil {
+		c.handleError(ctx, err, "an error occurred during the dedup check")
 		return false
 	}
 
+	if duplicate {
+		return true
+	}
+
 	ctx, span := c.tracer.StartSpanFromContext(ctx, c.id)
 	defer span.Finish()
 
@@ -150,5 +161,13 @@ func (c *Consumer) process(ctx context.Context, msg *Message) bool {
 		c.handleError(ctx, err, "an error occurred during the consume operation")
 	}
 
+	if !ack {
+		return c.retryMessage(ctx, msg)
+	}
+
+	if err == nil {
+		c.markProcessed(ctx, dedupKey)
+	}
+
 	return ack
 }
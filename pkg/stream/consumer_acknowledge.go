@@ -6,15 +6,22 @@ import (
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
+// An AcknowledgeHandler is notified about messages which have been acknowledged successfully.
+type AcknowledgeHandler interface {
+	Acknowledged(ctx context.Context, msgs []*Message)
+}
+
 type ConsumerAcknowledge struct {
-	logger log.Logger
-	input  Input
+	logger   log.Logger
+	input    Input
+	handlers []AcknowledgeHandler
 }
 
-func NewConsumerAcknowledgeWithInterfaces(logger log.Logger, input Input) ConsumerAcknowledge {
+func NewConsumerAcknowledgeWithInterfaces(logger log.Logger, input Input, handlers ...AcknowledgeHandler) ConsumerAcknowledge {
 	return ConsumerAcknowledge{
-		logger: logger,
-		input:  input,
+		logger:   logger,
+		input:    input,
+		handlers: handlers,
 	}
 }
 
@@ -28,7 +35,10 @@ func (c *ConsumerAcknowledge) Acknowledge(ctx context.Context, msg *Message) {
 
 	if err := ackInput.Ack(ctx, msg); err != nil {
 		c.logger.WithContext(ctx).Error("could not acknowledge the message: %w", err)
+		return
 	}
+
+	c.acknowledged(ctx, []*Message{msg})
 }
 
 func (c *ConsumerAcknowledge) AcknowledgeBatch(ctx context.Context, msg []*Message) {
@@ -41,5 +51,14 @@ func (c *ConsumerAcknowledge) AcknowledgeBatch(ctx context.Context, msg []*Messa
 
 	if err := ackInput.AckBatch(ctx, msg); err != nil {
 		c.logger.WithContext(ctx).Error("could not acknowledge the messages: %w", err)
+		return
+	}
+
+	c.acknowledged(ctx, msg)
+}
+
+func (c *ConsumerAcknowledge) acknowledged(ctx context.Context, msgs []*Message) {
+	for _, handler := range c.handlers {
+		handler.Acknowledged(ctx, msgs)
 	}
 }
@@ -2,6 +2,7 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -32,10 +33,14 @@ type BaseConsumerCallback interface {
 }
 
 type ConsumerSettings struct {
-	Input       string        `cfg:"input" default:"consumer" validate:"required"`
-	RunnerCount int           `cfg:"runner_count" default:"1" validate:"min=1"`
-	Encoding    EncodingType  `cfg:"encoding" default:"application/json"`
-	IdleTimeout time.Duration `cfg:"idle_timeout" default:"10s"`
+	Input        string                       `cfg:"input" default:"consumer" validate:"required"`
+	RunnerCount  int                          `cfg:"runner_count" default:"1" validate:"min=1"`
+	Encoding     EncodingType                 `cfg:"encoding" default:"application/json"`
+	IdleTimeout  time.Duration                `cfg:"idle_timeout" default:"10s"`
+	Dedup        ConsumerDedupSettings        `cfg:"dedup"`
+	Retry        ConsumerRetrySettings        `cfg:"retry"`
+	Partitioning ConsumerPartitioningSettings `cfg:"partitioning"`
+	ClaimCheck   ConsumerClaimCheckSettings   `cfg:"claim_check"`
 }
 
 type baseConsumer struct {
@@ -48,6 +53,9 @@ type baseConsumer struct {
 	metricWriter metric.Writer
 	tracer       tracing.Tracer
 	encoder      MessageEncoder
+	dedup        ConsumerDeduplicator
+	retry        ConsumerRetryHandler
+	partitioner  *consumerPartitioner
 
 	wg      sync.WaitGroup
 	stopped sync.Once
@@ -77,11 +85,32 @@ func NewBaseConsumer(ctx context.Context, config cfg.Config, logger log.Logger,
 		return nil, err
 	}
 
+	encodeHandlers := make([]EncodeHandler, 0, len(defaultEncodeHandlers)+1)
+	encodeHandlers = append(encodeHandlers, defaultEncodeHandlers...)
+	ackHandlers := make([]AcknowledgeHandler, 0)
+
+	if settings.ClaimCheck.Enabled {
+		claimCheck := NewConsumerClaimCheckHandler(ctx, config, logger, &settings.ClaimCheck)
+		encodeHandlers = append(encodeHandlers, claimCheck)
+		ackHandlers = append(ackHandlers, claimCheck)
+	}
+
 	encoder := NewMessageEncoder(&MessageEncoderSettings{
-		Encoding: settings.Encoding,
+		Encoding:       settings.Encoding,
+		EncodeHandlers: encodeHandlers,
 	})
 
-	return NewBaseConsumerWithInterfaces(logger, metricWriter, tracer, input, encoder, consumerCallback, settings, name, appId), nil
+	dedup, err := NewConsumerDeduplicator(ctx, config, logger, name, &settings.Dedup, consumerCallback)
+	if err != nil {
+		return nil, fmt.Errorf("can not create consumer deduplicator: %w", err)
+	}
+
+	retry, err := NewConsumerRetryHandler(ctx, config, logger, metricWriter, input, name, &settings.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("can not create consumer retry handler: %w", err)
+	}
+
+	return NewBaseConsumerWithInterfaces(logger, metricWriter, tracer, input, encoder, dedup, retry, consumerCallback, settings, name, appId, ackHandlers...), nil
 }
 
 func NewBaseConsumerWithInterfaces(
@@ -90,25 +119,34 @@ func NewBaseConsumerWithInterfaces(
 	tracer tracing.Tracer,
 	input Input,
 	encoder MessageEncoder,
+	dedup ConsumerDeduplicator,
+	retry ConsumerRetryHandler,
 	consumerCallback interface{},
 	settings *ConsumerSettings,
 	name string,
 	appId cfg.AppId,
+	ackHandlers ...AcknowledgeHandler,
 ) *baseConsumer {
 	logger = logger.WithChannel("consumer")
 
-	return &baseConsumer{
+	consumer := &baseConsumer{
 		name:                name,
 		id:                  fmt.Sprintf("consumer-%s-%s-%s", appId.Family, appId.Application, name),
 		logger:              logger,
 		metricWriter:        metricWriter,
 		tracer:              tracer,
-		ConsumerAcknowledge: NewConsumerAcknowledgeWithInterfaces(logger, input),
+		ConsumerAcknowledge: NewConsumerAcknowledgeWithInterfaces(logger, input, ackHandlers...),
 		encoder:             encoder,
+		dedup:               dedup,
+		retry:               retry,
 		settings:            settings,
 		consumerCallback:    consumerCallback,
 		clock:               clock.Provider,
 	}
+
+	consumer.partitioner = newConsumerPartitioner(logger, input, consumer.decodeMessage, consumerCallback, settings.RunnerCount, &settings.Partitioning)
+
+	return consumer
 }
 
 func (c *baseConsumer) run(kernelCtx context.Context, inputRunner func(ctx context.Context) error) error {
@@ -124,6 +162,8 @@ func (c *baseConsumer) run(kernelCtx context.Context, inputRunner func(ctx conte
 
 	cfn.GoWithContextf(manualCtx, c.logConsumeCounter, "panic during counter log")
 	cfn.GoWithContextf(manualCtx, c.runConsumerCallback, "panic during run of the consumerCallback")
+	cfn.GoWithContextf(manualCtx, c.partitioner.Run, "panic during run of the consumer partitioner")
+	cfn.GoWithContextf(manualCtx, c.runRetryHandler, "panic during run of the consumer retry handler")
 	// run the input after the counters are running to make sure our coffin does not immediately
 	// die just because Run() immediately returns
 	cfn.GoWithContextf(dyingCtx, c.input.Run, "panic during run of the consumer input")
@@ -155,6 +195,20 @@ func (c *baseConsumer) run(kernelCtx context.Context, inputRunner func(ctx conte
 	return nil
 }
 
+// IsHealthy reports the consumer as unhealthy if its input implements kernel.HealthChecker and is unhealthy.
+func (c *baseConsumer) IsHealthy(ctx context.Context) error {
+	checker, ok := c.input.(kernel.HealthChecker)
+	if !ok {
+		return nil
+	}
+
+	if err := checker.IsHealthy(ctx); err != nil {
+		return fmt.Errorf("input %s is unhealthy: %w", c.settings.Input, err)
+	}
+
+	return nil
+}
+
 func (c *baseConsumer) logConsumeCounter(ctx context.Context) error {
 	logger := c.logger.WithContext(ctx)
 	defer logger.Debug("logConsumeCounter is ending")
@@ -188,6 +242,10 @@ func (c *baseConsumer) runConsumerCallback(ctx context.Context) error {
 	return nil
 }
 
+func (c *baseConsumer) runRetryHandler(ctx context.Context) error {
+	return c.retry.Run(ctx, c.Acknowledge)
+}
+
 func (c *baseConsumer) stopConsuming() error {
 	defer c.logger.Debug("stopConsuming is ending")
 
@@ -221,6 +279,99 @@ func (c *baseConsumer) handleError(ctx context.Context, err error, msg string) {
 	})
 }
 
+// decodedMessage keeps the result of decoding a message, so the partitioner and the runner of a consumer don't have to
+// decode the same message twice.
+type decodedMessage struct {
+	ctx        context.Context
+	model      interface{}
+	attributes map[string]interface{}
+}
+
+// decodeMessage decodes the message into the model provided by the consumer callback. If the message was already
+// decoded by the partitioner to get the partition key from the model, the previous result is returned.
+func (c *baseConsumer) decodeMessage(ctx context.Context, msg *Message) (context.Context, interface{}, map[string]interface{}, error) {
+	if decoded := msg.decoded; decoded != nil {
+		msg.decoded = nil
+
+		return decoded.ctx, decoded.model, decoded.attributes, nil
+	}
+
+	c.retry.TrackAttempt(msg)
+
+	var model interface{}
+
+	if callback, ok := c.consumerCallback.(BaseConsumerCallback); ok {
+		model = callback.GetModel(msg.Attributes)
+	}
+
+	if model == nil {
+		return ctx, nil, nil, fmt.Errorf("can not get model for message attributes %v", msg.Attributes)
+	}
+
+	ctx, attributes, err := c.encoder.Decode(ctx, msg, model)
+
+	return ctx, model, attributes, err
+}
+
+// isDuplicate checks whether the message was already processed by this consumer. Duplicates are counted
+// and should be acknowledged without passing them to the callback again.
+func (c *baseConsumer) isDuplicate(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, bool, error) {
+	key, duplicate, err := c.dedup.IsDuplicate(ctx, model, attributes)
+
+	// retrying a message without a dedup key would not change anything, so it is processed without dedup
+	if errors.Is(err, ErrMissingDedupKey) {
+		c.logger.WithContext(ctx).Warn("processing message without dedup: %s", err.Error())
+
+		return "", false, nil
+	}
+
+	if err != nil || !duplicate {
+		return key, false, err
+	}
+
+	c.logger.WithContext(ctx).Info("skipping already processed message with dedup key %s", key)
+
+	c.metricWriter.Write(metric.Data{
+		&metric.Datum{
+			MetricName: metricNameConsumerDuplicateCount,
+			Dimensions: map[string]string{
+				"Consumer": c.name,
+			},
+			Value: 1.0,
+		},
+	})
+
+	return key, true, nil
+}
+
+func (c *baseConsumer) markProcessed(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+
+	if err := c.dedup.MarkProcessed(ctx, key); err != nil {
+		c.handleError(ctx, err, "can not mark the message as processed")
+	}
+}
+
+// retryMessage schedules a failed message for another attempt. It returns true if the message has to be
+// acknowledged, as a copy of it was written to the delay output.
+func (c *baseConsumer) retryMessage(ctx context.Context, msg *Message) bool {
+	ack, err := c.retry.Retry(ctx, msg)
+	if err != nil {
+		c.handleError(ctx, err, "can not schedule a retry of the message")
+
+		return false
+	}
+
+	if ack {
+		// the copy in the delay queue still references the claim checked body, so it must survive the acknowledgement
+		delete(msg.Attributes, AttributeClaimCheck)
+	}
+
+	return ack
+}
+
 func (c *baseConsumer) writeMetrics(duration time.Duration, processedCount int) {
 	c.metricWriter.Write(metric.Data{
 		&metric.Datum{
@@ -262,6 +413,24 @@ func getConsumerDefaultMetrics(name string, _ int) metric.Data {
 			Unit:  metric.UnitCount,
 			Value: 0.0,
 		},
+		{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameConsumerDuplicateCount,
+			Dimensions: map[string]string{
+				"Consumer": name,
+			},
+			Unit:  metric.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameConsumerRetryCount,
+			Dimensions: map[string]string{
+				"Consumer": name,
+			},
+			Unit:  metric.UnitCount,
+			Value: 0.0,
+		},
 	}
 }
 
@@ -89,6 +89,8 @@ func (c *BatchConsumer) run(ctx context.Context) error {
 	defer c.wg.Done()
 	defer c.processBatch(context.Background())
 
+	data := c.partitioner.Data()
+
 	for {
 		force := false
 
@@ -96,7 +98,7 @@ func (c *BatchConsumer) run(ctx context.Context) error {
 		case <-ctx.Done():
 			return fmt.Errorf("return from consuming as the coffin is dying")
 
-		case msg, ok := <-c.input.Data():
+		case msg, ok := <-data:
 			if !ok {
 				return nil
 			}
@@ -107,6 +109,9 @@ func (c *BatchConsumer) run(ctx context.Context) error {
 				c.processSingleMessage(ctx, msg)
 			}
 
+		case msg := <-c.retry.Data():
+			c.processSingleMessage(ctx, msg)
+
 		case <-c.ticker.C:
 			force = true
 		}
@@ -171,6 +176,12 @@ func (c *BatchConsumer) consumeBatch(kernelCtx context.Context, batch []*Message
 
 	logger := c.logger.WithContext(batchCtx)
 
+	messages, models, attributes, dedupKeys := c.skipDuplicates(batchCtx, messages, models, attributes)
+
+	if len(messages) == 0 {
+		return
+	}
+
 	acks, err := c.callback.Consume(batchCtx, models, attributes)
 	if err != nil {
 		logger.Error("an error occurred during the consume batch operation: %w", err)
@@ -181,9 +192,19 @@ func (c *BatchConsumer) consumeBatch(kernelCtx context.Context, batch []*Message
 	}
 
 	ackMessages := make([]*Message, 0, len(messages))
-	for i, ack := range acks {
-		if ack {
-			ackMessages = append(ackMessages, messages[i])
+	for i, msg := range messages {
+		if i >= len(acks) || !acks[i] {
+			if c.retryMessage(batchCtx, msg) {
+				ackMessages = append(ackMessages, msg)
+			}
+
+			continue
+		}
+
+		ackMessages = append(ackMessages, msg)
+
+		if err == nil {
+			c.markProcessed(batchCtx, dedupKeys[i])
 		}
 	}
 
@@ -202,9 +223,7 @@ func (c *BatchConsumer) decodeMessages(batchCtx context.Context, batch []*Messag
 	newBatch := make([]*Message, 0, len(batch))
 
 	for _, msg := range batch {
-		model := c.callback.GetModel(msg.Attributes)
-
-		msgCtx, attribute, err := c.encoder.Decode(batchCtx, msg, model)
+		msgCtx, model, attribute, err := c.decodeMessage(batchCtx, msg)
 		if err != nil {
 			c.logger.WithContext(msgCtx).Error("an error occurred during the batch decode message operation: %w", err)
 			continue
@@ -220,3 +239,38 @@ func (c *BatchConsumer) decodeMessages(batchCtx context.Context, batch []*Messag
 
 	return newBatch, models, attributes, spans
 }
+
+// skipDuplicates removes all messages from the batch which were already processed and acknowledges them.
+// Messages whose dedup state can't be determined are removed without an acknowledgement, so they are
+// delivered again later.
+func (c *BatchConsumer) skipDuplicates(batchCtx context.Context, batch []*Message, models []interface{}, attributes []map[string]interface{}) ([]*Message, []interface{}, []map[string]interface{}, []string) {
+	newBatch := make([]*Message, 0, len(batch))
+	newModels := make([]interface{}, 0, len(batch))
+	newAttributes := make([]map[string]interface{}, 0, len(batch))
+	dedupKeys := make([]string, 0, len(batch))
+	duplicates := make([]*Message, 0)
+
+	for i, msg := range batch {
+		key, duplicate, err := c.isDuplicate(batchCtx, models[i], attributes[i])
+		if err != nil {
+			c.handleError(batchCtx, err, "an error occurred during the dedup check")
+			continue
+		}
+
+		if duplicate {
+			duplicates = append(duplicates, msg)
+			continue
+		}
+
+		newBatch = append(newBatch, msg)
+		newModels = append(newModels, models[i])
+		newAttributes = append(newAttributes, attributes[i])
+		dedupKeys = append(dedupKeys, key)
+	}
+
+	if len(duplicates) > 0 {
+		c.AcknowledgeBatch(batchCtx, duplicates)
+	}
+
+	return newBatch, newModels, newAttributes, dedupKeys
+}
@@ -32,6 +32,8 @@ type BatchConsumerTestSuite struct {
 	stop func()
 
 	input *acknowledgeableInput
+	dedup stream.ConsumerDeduplicator
+	retry stream.ConsumerRetryHandler
 
 	callback      *mocks.RunnableBatchConsumerCallback
 	batchConsumer *stream.BatchConsumer
@@ -53,6 +55,9 @@ func (s *BatchConsumerTestSuite) SetupTest() {
 	s.input = new(acknowledgeableInput)
 	s.callback = new(mocks.RunnableBatchConsumerCallback)
 
+	s.dedup = stream.NewConsumerDeduplicatorNoop()
+	s.retry = stream.NewConsumerRetryHandlerNoop()
+
 	logger := logMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
 	mw := metricMocks.NewWriterMockedAll()
@@ -68,7 +73,7 @@ func (s *BatchConsumerTestSuite) SetupTest() {
 		BatchSize:   5,
 	}
 
-	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.callback, settings, "test", cfg.AppId{})
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.dedup, s.retry, s.callback, settings, "test", cfg.AppId{})
 	s.batchConsumer = stream.NewBatchConsumerWithInterfaces(baseConsumer, s.callback, ticker, batchSettings)
 }
 
@@ -0,0 +1,161 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kvstore"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/spf13/cast"
+)
+
+const metricNameConsumerDuplicateCount = "DuplicateCount"
+
+// ErrMissingDedupKey is returned by a ConsumerDeduplicator if a message carries no dedup key. Such messages are
+// processed without dedup.
+var ErrMissingDedupKey = errors.New("the message has no dedup key")
+
+type ConsumerDedupSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// Attribute is the name of the message attribute holding the dedup key. If empty, the consumer
+	// callback has to implement DedupKeyExtractor.
+	Attribute string `cfg:"attribute"`
+	// Store is the name of the configurable kvstore (kvstore.<name>) used to remember processed keys.
+	Store string `cfg:"store" default:"consumer_dedup"`
+	// Window defines how long a processed key is remembered. It is the default ttl of the entries of the store.
+	Window time.Duration `cfg:"window" default:"24h"`
+}
+
+// A DedupKeyExtractor provides the key used to detect duplicate deliveries of a message if the key can't
+// be taken from a message attribute. It is implemented by the consumer callback.
+//go:generate mockery --name DedupKeyExtractor
+type DedupKeyExtractor interface {
+	GetDedupKey(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, error)
+}
+
+// A ConsumerDeduplicator remembers the messages which were already processed successfully by a consumer.
+//go:generate mockery --name ConsumerDeduplicator
+type ConsumerDeduplicator interface {
+	// IsDuplicate returns the dedup key of the message and whether it was already processed.
+	IsDuplicate(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, bool, error)
+	// MarkProcessed records the key after the message was processed successfully.
+	MarkProcessed(ctx context.Context, key string) error
+}
+
+type dedupEntry struct {
+	ProcessedAt time.Time `json:"processedAt"`
+}
+
+type consumerDeduplicator struct {
+	clock     clock.Clock
+	store     kvstore.KvStore
+	extractor DedupKeyExtractor
+	name      string
+	settings  *ConsumerDedupSettings
+}
+
+func NewConsumerDeduplicator(ctx context.Context, config cfg.Config, logger log.Logger, name string, settings *ConsumerDedupSettings, consumerCallback interface{}) (ConsumerDeduplicator, error) {
+	if !settings.Enabled {
+		return NewConsumerDeduplicatorNoop(), nil
+	}
+
+	extractor, _ := consumerCallback.(DedupKeyExtractor)
+
+	if settings.Attribute == "" && extractor == nil {
+		return nil, fmt.Errorf("dedup of consumer %s requires either a key attribute or a consumer callback implementing DedupKeyExtractor", name)
+	}
+
+	store, err := kvstore.ProvideConfigurableKvStore(ctx, config, logger, settings.Store, cfg.UnmarshalWithDefaultForKey("ttl", settings.Window))
+	if err != nil {
+		return nil, fmt.Errorf("can not create dedup kvstore %s: %w", settings.Store, err)
+	}
+
+	return NewConsumerDeduplicatorWithInterfaces(clock.Provider, store, extractor, name, settings), nil
+}
+
+func NewConsumerDeduplicatorWithInterfaces(clock clock.Clock, store kvstore.KvStore, extractor DedupKeyExtractor, name string, settings *ConsumerDedupSettings) ConsumerDeduplicator {
+	return &consumerDeduplicator{
+		clock:     clock,
+		store:     store,
+		extractor: extractor,
+		name:      name,
+		settings:  settings,
+	}
+}
+
+func (d *consumerDeduplicator) IsDuplicate(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, bool, error) {
+	var err error
+	var key string
+	var found bool
+
+	if key, err = d.getKey(ctx, model, attributes); err != nil {
+		return "", false, err
+	}
+
+	entry := &dedupEntry{}
+
+	if found, err = d.store.Get(ctx, key, entry); err != nil {
+		return key, false, fmt.Errorf("can not read dedup key %s: %w", key, err)
+	}
+
+	if !found {
+		return key, false, nil
+	}
+
+	return key, d.clock.Now().Sub(entry.ProcessedAt) < d.settings.Window, nil
+}
+
+func (d *consumerDeduplicator) MarkProcessed(ctx context.Context, key string) error {
+	entry := &dedupEntry{
+		ProcessedAt: d.clock.Now(),
+	}
+
+	if err := d.store.Put(ctx, key, entry); err != nil {
+		return fmt.Errorf("can not write dedup key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (d *consumerDeduplicator) getKey(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, error) {
+	var err error
+	var key string
+
+	if d.settings.Attribute != "" {
+		value, ok := attributes[d.settings.Attribute]
+
+		if !ok {
+			return "", fmt.Errorf("%w: attribute %s is missing", ErrMissingDedupKey, d.settings.Attribute)
+		}
+
+		if key, err = cast.ToStringE(value); err != nil {
+			return "", fmt.Errorf("can not cast dedup attribute %s to string: %w", d.settings.Attribute, err)
+		}
+	} else if key, err = d.extractor.GetDedupKey(ctx, model, attributes); err != nil {
+		return "", fmt.Errorf("can not extract dedup key: %w", err)
+	}
+
+	if key == "" {
+		return "", fmt.Errorf("%w: the key is empty", ErrMissingDedupKey)
+	}
+
+	return fmt.Sprintf("%s:%s", d.name, key), nil
+}
+
+type consumerDeduplicatorNoop struct{}
+
+func NewConsumerDeduplicatorNoop() ConsumerDeduplicator {
+	return consumerDeduplicatorNoop{}
+}
+
+func (d consumerDeduplicatorNoop) IsDuplicate(_ context.Context, _ interface{}, _ map[string]interface{}) (string, bool, error) {
+	return "", false, nil
+}
+
+func (d consumerDeduplicatorNoop) MarkProcessed(_ context.Context, _ string) error {
+	return nil
+}
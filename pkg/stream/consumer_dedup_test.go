@@ -0,0 +1,247 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/kvstore"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestDeduplicator(clk clock.Clock, extractor stream.DedupKeyExtractor, attribute string) stream.ConsumerDeduplicator {
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Name: "dedup",
+		InMemorySettings: kvstore.InMemorySettings{
+			MaxSize: 100,
+		},
+	})
+
+	return stream.NewConsumerDeduplicatorWithInterfaces(clk, store, extractor, "test", &stream.ConsumerDedupSettings{
+		Enabled:   true,
+		Attribute: attribute,
+		Window:    time.Hour,
+	})
+}
+
+func TestConsumerDeduplicator_Attribute(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFakeClock()
+	dedup := newTestDeduplicator(clk, nil, "messageId")
+	attributes := map[string]interface{}{"messageId": "abc"}
+
+	key, duplicate, err := dedup.IsDuplicate(ctx, nil, attributes)
+	assert.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, "test:abc", key)
+
+	assert.NoError(t, dedup.MarkProcessed(ctx, key))
+
+	_, duplicate, err = dedup.IsDuplicate(ctx, nil, attributes)
+	assert.NoError(t, err)
+	assert.True(t, duplicate)
+
+	clk.Advance(time.Hour)
+
+	_, duplicate, err = dedup.IsDuplicate(ctx, nil, attributes)
+	assert.NoError(t, err)
+	assert.False(t, duplicate, "keys outside of the window should not be treated as duplicates")
+
+	_, _, err = dedup.IsDuplicate(ctx, nil, map[string]interface{}{})
+	assert.ErrorIs(t, err, stream.ErrMissingDedupKey)
+	assert.EqualError(t, err, "the message has no dedup key: attribute messageId is missing")
+}
+
+func TestConsumerDeduplicator_Extractor(t *testing.T) {
+	ctx := context.Background()
+	model := mdl.String("model")
+
+	extractor := new(mocks.DedupKeyExtractor)
+	extractor.On("GetDedupKey", ctx, model, map[string]interface{}{}).Return("model-1", nil)
+
+	dedup := newTestDeduplicator(clock.NewFakeClock(), extractor, "")
+
+	key, duplicate, err := dedup.IsDuplicate(ctx, model, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, "test:model-1", key)
+
+	extractor.AssertExpectations(t)
+}
+
+func TestConsumer_SkipDuplicates(t *testing.T) {
+	data := make(chan *stream.Message, 10)
+	input := new(mocks.Input)
+	callback := new(mocks.RunnableConsumerCallback)
+	dedup := newTestDeduplicator(clock.NewFakeClock(), nil, "id")
+
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 1,
+		IdleTimeout: time.Second,
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracing.NewNoopTracer(), input, me, dedup, stream.NewConsumerRetryHandlerNoop(), callback, settings, "test", cfg.AppId{})
+	consumer := stream.NewConsumerWithInterfaces(baseConsumer, callback)
+
+	input.On("Data").Return(data)
+	input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})
+		data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})
+		data <- stream.NewJsonMessage(`"bar"`, map[string]interface{}{"id": "2"})
+		data <- stream.NewJsonMessage(`"bar"`, map[string]interface{}{"id": "2"})
+		close(data)
+	}).Return(nil)
+	input.On("Stop").Once()
+
+	consumed := make([]string, 0)
+	callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return mdl.String("")
+	})
+	callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			consumed = append(consumed, *args[1].(*string))
+		}).
+		Return(func(_ context.Context, model interface{}, _ map[string]interface{}) bool {
+			// the first delivery of "bar" fails and has to be processed again
+			return !(*model.(*string) == "bar" && len(consumed) == 2)
+		}, nil)
+	callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := consumer.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "bar"}, consumed)
+
+	mw.AssertCalled(t, "Write", mock.MatchedBy(func(data metric.Data) bool {
+		return len(data) == 1 && data[0].MetricName == "DuplicateCount"
+	}))
+	input.AssertExpectations(t)
+	callback.AssertExpectations(t)
+}
+
+func TestConsumer_MissingDedupKey(t *testing.T) {
+	data := make(chan *stream.Message, 10)
+	input := new(mocks.Input)
+	callback := new(mocks.RunnableConsumerCallback)
+	dedup := newTestDeduplicator(clock.NewFakeClock(), nil, "id")
+
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 1,
+		IdleTimeout: time.Second,
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracing.NewNoopTracer(), input, me, dedup, stream.NewConsumerRetryHandlerNoop(), callback, settings, "test", cfg.AppId{})
+	consumer := stream.NewConsumerWithInterfaces(baseConsumer, callback)
+
+	input.On("Data").Return(data)
+	input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		data <- stream.NewJsonMessage(`"foo"`)
+		data <- stream.NewJsonMessage(`"foo"`)
+		close(data)
+	}).Return(nil)
+	input.On("Stop").Once()
+
+	consumed := make([]string, 0)
+	callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return mdl.String("")
+	})
+	callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			consumed = append(consumed, *args[1].(*string))
+		}).
+		Return(true, nil)
+	callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := consumer.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "foo"}, consumed, "messages without dedup key should be processed without dedup")
+
+	mw.AssertNotCalled(t, "Write", mock.MatchedBy(func(data metric.Data) bool {
+		return len(data) == 1 && data[0].MetricName == "Error"
+	}))
+	input.AssertExpectations(t)
+	callback.AssertExpectations(t)
+}
+
+func TestBatchConsumer_SkipDuplicates(t *testing.T) {
+	data := make(chan *stream.Message, 10)
+	input := new(acknowledgeableInput)
+	callback := new(mocks.RunnableBatchConsumerCallback)
+	dedup := newTestDeduplicator(clock.NewFakeClock(), nil, "id")
+
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 1,
+		IdleTimeout: time.Second,
+	}
+	batchSettings := &stream.BatchConsumerSettings{
+		IdleTimeout: time.Second,
+		BatchSize:   2,
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracing.NewNoopTracer(), input, me, dedup, stream.NewConsumerRetryHandlerNoop(), callback, settings, "test", cfg.AppId{})
+	consumer := stream.NewBatchConsumerWithInterfaces(baseConsumer, callback, time.NewTicker(time.Second), batchSettings)
+
+	input.Input.On("Data").Return(data)
+	input.Input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})
+		data <- stream.NewJsonMessage(`"bar"`, map[string]interface{}{"id": "2"})
+		data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})
+		data <- stream.NewJsonMessage(`"baz"`, map[string]interface{}{"id": "3"})
+		close(data)
+	}).Return(nil)
+	input.Input.On("Stop").Once()
+
+	acked := make([]int, 0)
+	input.AcknowledgeableInput.On("AckBatch", mock.Anything, mock.AnythingOfType("[]*stream.Message")).
+		Run(func(args mock.Arguments) {
+			acked = append(acked, len(args[1].([]*stream.Message)))
+		}).
+		Return(nil)
+
+	consumed := make([]int, 0)
+	callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return mdl.String("")
+	})
+	callback.On("Consume", mock.Anything, mock.AnythingOfType("[]interface {}"), mock.AnythingOfType("[]map[string]interface {}")).
+		Run(func(args mock.Arguments) {
+			consumed = append(consumed, len(args[1].([]interface{})))
+		}).
+		Return(func(_ context.Context, models []interface{}, _ []map[string]interface{}) []bool {
+			acks := make([]bool, len(models))
+			for i := range acks {
+				acks[i] = true
+			}
+
+			return acks
+		}, nil)
+	callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := consumer.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, consumed, "the duplicate of foo should not be passed to the callback")
+	assert.Equal(t, []int{2, 1, 1}, acked, "the duplicate should be acknowledged on its own")
+}
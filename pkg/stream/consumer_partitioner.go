@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/spf13/cast"
+)
+
+type ConsumerPartitioningSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// Attribute is the name of the message attribute holding the partition key. If empty or missing on a
+	// message, the sqs message group id or kinesis partition key is used. As a last resort, the key is
+	// requested from the consumer callback if it implements PartitionKeyExtractor.
+	Attribute string `cfg:"attribute"`
+}
+
+// A PartitionKeyExtractor provides the partition key of a message based on its model. Messages with the same
+// partition key are always processed by the same runner of a consumer in the order they were received.
+//go:generate mockery --name PartitionKeyExtractor
+type PartitionKeyExtractor interface {
+	GetPartitionKey(ctx context.Context, model interface{}, attributes map[string]interface{}) (string, error)
+}
+
+// consumerPartitioner distributes the messages of the input to the runners of a consumer. If partitioning is
+// enabled, every message is assigned to a fixed runner based on the hash of its partition key. Messages without
+// a partition key are distributed round-robin.
+type consumerPartitioner struct {
+	logger    log.Logger
+	input     Input
+	decode    messageDecoder
+	extractor PartitionKeyExtractor
+	settings  *ConsumerPartitioningSettings
+
+	channels []chan *Message
+	runner   int32
+	next     uint32
+}
+
+// messageDecoder decodes a message into the model of the consumer callback.
+type messageDecoder func(ctx context.Context, msg *Message) (context.Context, interface{}, map[string]interface{}, error)
+
+func newConsumerPartitioner(logger log.Logger, input Input, decode messageDecoder, consumerCallback interface{}, runnerCount int, settings *ConsumerPartitioningSettings) *consumerPartitioner {
+	partitioner := &consumerPartitioner{
+		logger:   logger,
+		input:    input,
+		decode:   decode,
+		settings: settings,
+	}
+
+	if !settings.Enabled {
+		return partitioner
+	}
+
+	partitioner.extractor, _ = consumerCallback.(PartitionKeyExtractor)
+	partitioner.channels = make([]chan *Message, runnerCount)
+
+	for i := range partitioner.channels {
+		partitioner.channels[i] = make(chan *Message)
+	}
+
+	return partitioner
+}
+
+// Data returns the channel a runner has to read its messages from. Every call returns the channel of the next runner.
+func (p *consumerPartitioner) Data() chan *Message {
+	if !p.settings.Enabled {
+		return p.input.Data()
+	}
+
+	runner := atomic.AddInt32(&p.runner, 1) - 1
+
+	return p.channels[int(runner)%len(p.channels)]
+}
+
+func (p *consumerPartitioner) Run(ctx context.Context) error {
+	if !p.settings.Enabled {
+		return nil
+	}
+
+	defer func() {
+		for _, channel := range p.channels {
+			close(channel)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case msg, ok := <-p.input.Data():
+			if !ok {
+				return nil
+			}
+
+			runner := p.getRunner(ctx, msg)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case p.channels[runner] <- msg:
+			}
+		}
+	}
+}
+
+func (p *consumerPartitioner) getRunner(ctx context.Context, msg *Message) int {
+	key, err := p.getKey(ctx, msg)
+	if err != nil {
+		p.logger.WithContext(ctx).Warn("can not get the partition key of the message, falling back to round-robin: %s", err.Error())
+	}
+
+	if key == "" {
+		return int(atomic.AddUint32(&p.next, 1) % uint32(len(p.channels)))
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+
+	return int(hash.Sum32() % uint32(len(p.channels)))
+}
+
+func (p *consumerPartitioner) getKey(ctx context.Context, msg *Message) (string, error) {
+	keys := []string{AttributeSqsMessageGroupId, AttributeKinesisPartitionKey}
+
+	if p.settings.Attribute != "" {
+		keys = append([]string{p.settings.Attribute}, keys...)
+	}
+
+	for _, key := range keys {
+		if value, ok := msg.Attributes[key]; ok {
+			return cast.ToStringE(value)
+		}
+	}
+
+	// the model of an aggregate is a list of messages, so it can't be passed to the extractor
+	if _, ok := msg.Attributes[AttributeAggregate]; ok || p.extractor == nil {
+		return "", nil
+	}
+
+	ctx, model, attributes, err := p.decode(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("can not decode message: %w", err)
+	}
+
+	// the runner uses the decoded message instead of decoding it a second time
+	msg.decoded = &decodedMessage{
+		ctx:        ctx,
+		model:      model,
+		attributes: attributes,
+	}
+
+	return p.extractor.GetPartitionKey(ctx, model, attributes)
+}
@@ -0,0 +1,141 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type partitionedCallback struct {
+	mocks.RunnableConsumerCallback
+	mocks.PartitionKeyExtractor
+}
+
+func runPartitionedConsumer(t *testing.T, attribute string, msgs []*stream.Message) map[string][]string {
+	data := make(chan *stream.Message, len(msgs))
+	input := new(mocks.Input)
+	callback := new(partitionedCallback)
+
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 4,
+		IdleTimeout: time.Second,
+		Partitioning: stream.ConsumerPartitioningSettings{
+			Enabled:   true,
+			Attribute: attribute,
+		},
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracing.NewNoopTracer(), input, me, stream.NewConsumerDeduplicatorNoop(), stream.NewConsumerRetryHandlerNoop(), callback, settings, "test", cfg.AppId{})
+	consumer := stream.NewConsumerWithInterfaces(baseConsumer, callback)
+
+	input.On("Data").Return(data)
+	input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		for _, msg := range msgs {
+			data <- msg
+		}
+
+		close(data)
+	}).Return(nil)
+	input.On("Stop").Once()
+
+	lck := sync.Mutex{}
+	consumed := make(map[string][]string)
+
+	callback.PartitionKeyExtractor.On("GetPartitionKey", mock.Anything, mock.AnythingOfType("*string"), mock.Anything).Return(func(_ context.Context, model interface{}, _ map[string]interface{}) string {
+		return strings.Split(*model.(*string), ":")[0]
+	}, nil)
+	callback.RunnableConsumerCallback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return mdl.String("")
+	})
+	callback.RunnableConsumerCallback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			parts := strings.Split(*args[1].(*string), ":")
+
+			// slow down the first messages of every key to provoke reordering without partitioning
+			if parts[1] == "0" {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			lck.Lock()
+			defer lck.Unlock()
+
+			consumed[parts[0]] = append(consumed[parts[0]], parts[1])
+		}).
+		Return(true, nil)
+	callback.RunnableConsumerCallback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := consumer.Run(context.Background())
+	assert.NoError(t, err)
+
+	callback.RunnableConsumerCallback.AssertNumberOfCalls(t, "GetModel", len(msgs))
+
+	return consumed
+}
+
+func newPartitionedMessages(keyAttribute string) []*stream.Message {
+	msgs := make([]*stream.Message, 0)
+
+	for i := 0; i < 5; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			attributes := map[string]interface{}{}
+
+			if keyAttribute != "" {
+				attributes[keyAttribute] = key
+			}
+
+			msgs = append(msgs, stream.NewJsonMessage(fmt.Sprintf(`"%s:%d"`, key, i), attributes))
+		}
+	}
+
+	return msgs
+}
+
+func TestConsumer_Partitioning(t *testing.T) {
+	expected := map[string][]string{
+		"a": {"0", "1", "2", "3", "4"},
+		"b": {"0", "1", "2", "3", "4"},
+		"c": {"0", "1", "2", "3", "4"},
+	}
+
+	tests := map[string]struct {
+		settingsAttribute string
+		messageAttribute  string
+	}{
+		"attribute": {
+			settingsAttribute: "entityId",
+			messageAttribute:  "entityId",
+		},
+		"sqsMessageGroupId": {
+			messageAttribute: stream.AttributeSqsMessageGroupId,
+		},
+		"kinesisPartitionKey": {
+			messageAttribute: stream.AttributeKinesisPartitionKey,
+		},
+		"extractor": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			consumed := runPartitionedConsumer(t, test.settingsAttribute, newPartitionedMessages(test.messageAttribute))
+
+			assert.Equal(t, expected, consumed)
+		})
+	}
+}
@@ -0,0 +1,427 @@
+package stream
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	"github.com/spf13/cast"
+)
+
+const (
+	AttributeRetryAttempt = "goso.retry.attempt"
+
+	metricNameConsumerRetryCount = "RetryCount"
+	maxNativeRetryDelay          = 12 * time.Hour
+)
+
+type ConsumerRetrySettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// InitialInterval is the delay before the second attempt of a message.
+	InitialInterval time.Duration `cfg:"initial_interval" default:"10s"`
+	// Multiplier is applied to the delay for every further attempt.
+	Multiplier float64 `cfg:"multiplier" default:"2"`
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration `cfg:"max_delay" default:"15m"`
+	// MaxAttempts is the number of attempts after which a message isn't rescheduled anymore. A message
+	// which ran out of attempts is treated like a failed message without retry policy. 0 means no limit.
+	MaxAttempts int `cfg:"max_attempts" default:"0"`
+	// Output is the name of the configurable output (stream.output.<name>) used as delay queue. It has to write
+	// back to the input of the consumer. The delay of a sqs output is passed as sqs delay seconds and capped at
+	// 15 minutes, the messages for any other output are held in memory until they are due. Without an output,
+	// inputs without native delay support hold the messages in memory and pass them to the consumer again.
+	Output string `cfg:"output"`
+}
+
+// A RetryableInput is able to delay the redelivery of a message natively.
+//go:generate mockery --name RetryableInput
+type RetryableInput interface {
+	Retry(ctx context.Context, msg *Message, delay time.Duration) error
+}
+
+// A ConsumerRetryHandler reschedules messages which could not be consumed using an exponential backoff.
+//go:generate mockery --name ConsumerRetryHandler
+type ConsumerRetryHandler interface {
+	// Run runs the in-memory delay queue of the handler. Due messages are written to the delay output and
+	// acknowledged afterwards or, without a delay output, passed to the consumer again by Data. Pending messages
+	// are written to the delay output as soon as the context is canceled.
+	Run(ctx context.Context, acknowledge func(ctx context.Context, msg *Message)) error
+	// Data returns the messages which are due for another attempt and have to be consumed again.
+	Data() <-chan *Message
+	// TrackAttempt exposes the current attempt of the message to the consumer callback by the attribute
+	// AttributeRetryAttempt.
+	TrackAttempt(msg *Message)
+	// Retry schedules the message for another attempt. It returns true if the message was written to the
+	// delay output and has to be acknowledged on the input. Messages held in the delay queue are acknowledged
+	// by Run after they have been written to the delay output.
+	Retry(ctx context.Context, msg *Message) (bool, error)
+}
+
+type consumerRetryHandler struct {
+	logger       log.Logger
+	metricWriter metric.Writer
+	clock        clock.Clock
+	input        Input
+	output       Output
+	outputType   string
+	queue        *delayQueue
+	data         chan *Message
+	name         string
+	settings     *ConsumerRetrySettings
+}
+
+func NewConsumerRetryHandler(ctx context.Context, config cfg.Config, logger log.Logger, metricWriter metric.Writer, input Input, name string, settings *ConsumerRetrySettings) (ConsumerRetryHandler, error) {
+	if !settings.Enabled {
+		return NewConsumerRetryHandlerNoop(), nil
+	}
+
+	var err error
+	var output Output
+	var outputType string
+
+	if settings.Output != "" {
+		if output, err = NewConfigurableOutput(ctx, config, logger, settings.Output); err != nil {
+			return nil, fmt.Errorf("can not create retry output %s: %w", settings.Output, err)
+		}
+
+		outputType = config.GetString(fmt.Sprintf("%s.type", ConfigurableOutputKey(settings.Output)))
+	}
+
+	return NewConsumerRetryHandlerWithInterfaces(logger, metricWriter, clock.Provider, input, output, outputType, name, settings), nil
+}
+
+func NewConsumerRetryHandlerWithInterfaces(
+	logger log.Logger,
+	metricWriter metric.Writer,
+	clock clock.Clock,
+	input Input,
+	output Output,
+	outputType string,
+	name string,
+	settings *ConsumerRetrySettings,
+) ConsumerRetryHandler {
+	return &consumerRetryHandler{
+		logger:       logger.WithChannel("consumer-retry"),
+		metricWriter: metricWriter,
+		clock:        clock,
+		input:        input,
+		output:       output,
+		outputType:   outputType,
+		queue:        newDelayQueue(),
+		data:         make(chan *Message),
+		name:         name,
+		settings:     settings,
+	}
+}
+
+func (h *consumerRetryHandler) Run(ctx context.Context, acknowledge func(ctx context.Context, msg *Message)) error {
+	for {
+		var timer <-chan time.Time
+
+		if due, ok := h.queue.nextDue(); ok {
+			timer = h.clock.After(due.Sub(h.clock.Now()))
+		}
+
+		select {
+		case <-ctx.Done():
+			h.flush(acknowledge)
+
+			return nil
+		case <-h.queue.wakeup:
+		case <-timer:
+			for _, msg := range h.queue.popDue(h.clock.Now()) {
+				h.deliver(ctx, msg, acknowledge)
+			}
+		}
+	}
+}
+
+func (h *consumerRetryHandler) Data() <-chan *Message {
+	return h.data
+}
+
+func (h *consumerRetryHandler) TrackAttempt(msg *Message) {
+	if msg.Attributes == nil {
+		msg.Attributes = make(map[string]interface{})
+	}
+
+	msg.Attributes[AttributeRetryAttempt] = getRetryAttempt(msg)
+}
+
+func (h *consumerRetryHandler) Retry(ctx context.Context, msg *Message) (bool, error) {
+	logger := h.logger.WithContext(ctx)
+	attempt := getRetryAttempt(msg)
+
+	if h.settings.MaxAttempts > 0 && attempt >= h.settings.MaxAttempts {
+		logger.Warn("message of consumer %s failed on its last attempt %d", h.name, attempt)
+
+		return false, nil
+	}
+
+	ack := false
+	delay := h.getDelay(attempt)
+	_, retryable := h.input.(RetryableInput)
+
+	switch {
+	case h.output == nil && retryable:
+		if err := h.retryNative(ctx, msg, delay); err != nil {
+			return false, err
+		}
+	case h.output != nil && h.outputType == OutputTypeSqs:
+		retryMsg := h.getRetryMessage(msg, attempt)
+
+		if seconds := int32(math.Min(delay.Seconds(), sqs.MaxDelaySeconds)); seconds > 0 {
+			retryMsg.Attributes[sqs.AttributeSqsDelaySeconds] = seconds
+		}
+
+		// the message is only acknowledged after it was written, otherwise it would be lost on a crash
+		if err := h.output.WriteOne(ctx, retryMsg); err != nil {
+			return false, fmt.Errorf("can not write the message to the retry output %s: %w", h.settings.Output, err)
+		}
+
+		ack = true
+	default:
+		// the message stays unacknowledged until it was written to the delay output or consumed again
+		h.queue.push(msg, h.clock.Now().Add(delay))
+	}
+
+	h.writeMetric()
+	logger.Info("retrying message of consumer %s in %s", h.name, delay)
+
+	return ack, nil
+}
+
+// getRetryMessage copies the message for the next attempt, so it can be written to the delay output.
+func (h *consumerRetryHandler) getRetryMessage(msg *Message, attempt int) *Message {
+	retryMsg := &Message{
+		Attributes: make(map[string]interface{}, len(msg.Attributes)),
+		Body:       msg.Body,
+	}
+
+	for key, value := range msg.Attributes {
+		retryMsg.Attributes[key] = value
+	}
+
+	// the input specific attributes are set again on the next delivery of the message
+	delete(retryMsg.Attributes, AttributeSqsMessageId)
+	delete(retryMsg.Attributes, AttributeSqsReceiptHandle)
+	delete(retryMsg.Attributes, AttributeSqsReceiveCount)
+
+	retryMsg.Attributes[AttributeRetryAttempt] = attempt + 1
+
+	return retryMsg
+}
+
+// deliver writes a due message to the delay output and acknowledges it or passes it to the consumer again.
+func (h *consumerRetryHandler) deliver(ctx context.Context, msg *Message, acknowledge func(ctx context.Context, msg *Message)) {
+	attempt := getRetryAttempt(msg)
+
+	if h.output == nil {
+		msg.Attributes[AttributeRetryAttempt] = attempt + 1
+
+		select {
+		case <-ctx.Done():
+			h.logger.Warn("dropping a pending retry of consumer %s as it is stopping", h.name)
+		case h.data <- msg:
+		}
+
+		return
+	}
+
+	if err := h.output.WriteOne(ctx, h.getRetryMessage(msg, attempt)); err != nil {
+		h.logger.WithContext(ctx).Error("can not write the message to the retry output %s: %w", h.settings.Output, err)
+
+		return
+	}
+
+	// the copy in the delay output still references the claim checked body, so it must survive the acknowledgement
+	delete(msg.Attributes, AttributeClaimCheck)
+	acknowledge(ctx, msg)
+}
+
+// flush writes all pending messages to the delay output, so they are not lost on shutdown.
+func (h *consumerRetryHandler) flush(acknowledge func(ctx context.Context, msg *Message)) {
+	msgs := h.queue.popAll()
+
+	if len(msgs) == 0 {
+		return
+	}
+
+	if h.output == nil {
+		h.logger.Warn("dropping %d pending retries of consumer %s as it is stopping", len(msgs), h.name)
+
+		return
+	}
+
+	for _, msg := range msgs {
+		h.deliver(context.Background(), msg, acknowledge)
+	}
+}
+
+func (h *consumerRetryHandler) retryNative(ctx context.Context, msg *Message, delay time.Duration) error {
+	retryableInput, ok := h.input.(RetryableInput)
+	if !ok {
+		return fmt.Errorf("the input of consumer %s does not support native retries", h.name)
+	}
+
+	if delay > maxNativeRetryDelay {
+		delay = maxNativeRetryDelay
+	}
+
+	if err := retryableInput.Retry(ctx, msg, delay); err != nil {
+		return fmt.Errorf("can not delay the message: %w", err)
+	}
+
+	return nil
+}
+
+// getDelay returns the backoff before the next attempt: InitialInterval * Multiplier^(attempt-1), capped at MaxDelay.
+func (h *consumerRetryHandler) getDelay(attempt int) time.Duration {
+	delay := float64(h.settings.InitialInterval) * math.Pow(h.settings.Multiplier, float64(attempt-1))
+
+	if delay > float64(h.settings.MaxDelay) {
+		return h.settings.MaxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+func (h *consumerRetryHandler) writeMetric() {
+	h.metricWriter.Write(metric.Data{
+		&metric.Datum{
+			MetricName: metricNameConsumerRetryCount,
+			Dimensions: map[string]string{
+				"Consumer": h.name,
+			},
+			Value: 1.0,
+		},
+	})
+}
+
+// getRetryAttempt returns the attempt of the message, starting with 1 for the first delivery. The attribute
+// written for the delay queue takes precedence, as sqs starts counting the deliveries again for every copy
+// written to the delay output. Sqs counts the deliveries of natively delayed messages itself.
+func getRetryAttempt(msg *Message) int {
+	for _, key := range []string{AttributeRetryAttempt, AttributeSqsReceiveCount} {
+		if value, ok := msg.Attributes[key]; ok {
+			if attempt, err := cast.ToIntE(value); err == nil && attempt > 0 {
+				return attempt
+			}
+		}
+	}
+
+	return 1
+}
+
+type delayedMessage struct {
+	msg *Message
+	due time.Time
+}
+
+type delayedMessages []delayedMessage
+
+func (d delayedMessages) Len() int            { return len(d) }
+func (d delayedMessages) Less(i, j int) bool  { return d[i].due.Before(d[j].due) }
+func (d delayedMessages) Swap(i, j int)       { d[i], d[j] = d[j], d[i] }
+func (d *delayedMessages) Push(x interface{}) { *d = append(*d, x.(delayedMessage)) }
+
+func (d *delayedMessages) Pop() interface{} {
+	old := *d
+	item := old[len(old)-1]
+	*d = old[:len(old)-1]
+
+	return item
+}
+
+// delayQueue keeps messages ordered by the time they are due again.
+type delayQueue struct {
+	lck      sync.Mutex
+	messages delayedMessages
+	wakeup   chan struct{}
+}
+
+func newDelayQueue() *delayQueue {
+	return &delayQueue{
+		messages: make(delayedMessages, 0),
+		wakeup:   make(chan struct{}, 1),
+	}
+}
+
+func (q *delayQueue) push(msg *Message, due time.Time) {
+	q.lck.Lock()
+	heap.Push(&q.messages, delayedMessage{
+		msg: msg,
+		due: due,
+	})
+	q.lck.Unlock()
+
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (q *delayQueue) nextDue() (time.Time, bool) {
+	q.lck.Lock()
+	defer q.lck.Unlock()
+
+	if len(q.messages) == 0 {
+		return time.Time{}, false
+	}
+
+	return q.messages[0].due, true
+}
+
+func (q *delayQueue) popDue(now time.Time) []*Message {
+	q.lck.Lock()
+	defer q.lck.Unlock()
+
+	msgs := make([]*Message, 0)
+
+	for len(q.messages) > 0 && !q.messages[0].due.After(now) {
+		msgs = append(msgs, heap.Pop(&q.messages).(delayedMessage).msg)
+	}
+
+	return msgs
+}
+
+func (q *delayQueue) popAll() []*Message {
+	q.lck.Lock()
+	defer q.lck.Unlock()
+
+	msgs := make([]*Message, 0, len(q.messages))
+
+	for len(q.messages) > 0 {
+		msgs = append(msgs, heap.Pop(&q.messages).(delayedMessage).msg)
+	}
+
+	return msgs
+}
+
+type consumerRetryHandlerNoop struct{}
+
+func NewConsumerRetryHandlerNoop() ConsumerRetryHandler {
+	return consumerRetryHandlerNoop{}
+}
+
+func (h consumerRetryHandlerNoop) Run(_ context.Context, _ func(ctx context.Context, msg *Message)) error {
+	return nil
+}
+
+func (h consumerRetryHandlerNoop) Data() <-chan *Message {
+	return nil
+}
+
+func (h consumerRetryHandlerNoop) TrackAttempt(_ *Message) {}
+
+func (h consumerRetryHandlerNoop) Retry(_ context.Context, _ *Message) (bool, error) {
+	return false, nil
+}
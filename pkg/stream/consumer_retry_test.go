@@ -0,0 +1,260 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type retryableInput struct {
+	mocks.RetryableInput
+	mocks.Input
+}
+
+func newRetrySettings() *stream.ConsumerRetrySettings {
+	return &stream.ConsumerRetrySettings{
+		Enabled:         true,
+		InitialInterval: 10 * time.Second,
+		Multiplier:      2,
+		MaxDelay:        time.Minute,
+		MaxAttempts:     5,
+	}
+}
+
+func TestConsumerRetryHandler_Native(t *testing.T) {
+	ctx := context.Background()
+	input := new(retryableInput)
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+
+	handler := stream.NewConsumerRetryHandlerWithInterfaces(logger, mw, clock.NewFakeClock(), input, nil, "", "test", newRetrySettings())
+
+	tests := map[string]struct {
+		receiveCount int
+		delay        time.Duration
+	}{
+		"first":  {receiveCount: 1, delay: 10 * time.Second},
+		"third":  {receiveCount: 3, delay: 40 * time.Second},
+		"capped": {receiveCount: 4, delay: time.Minute},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			msg := stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+				stream.AttributeSqsReceiveCount: test.receiveCount,
+			})
+
+			handler.TrackAttempt(msg)
+			assert.Equal(t, test.receiveCount, msg.Attributes[stream.AttributeRetryAttempt])
+
+			input.RetryableInput.On("Retry", ctx, msg, test.delay).Return(nil).Once()
+
+			ack, err := handler.Retry(ctx, msg)
+			assert.NoError(t, err)
+			assert.False(t, ack, "natively delayed messages must not be acknowledged")
+		})
+	}
+
+	msg := stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+		stream.AttributeSqsReceiveCount: 5,
+	})
+
+	ack, err := handler.Retry(ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, ack)
+
+	input.RetryableInput.AssertExpectations(t)
+}
+
+func TestConsumerRetryHandler_SqsOutput(t *testing.T) {
+	ctx := context.Background()
+	input := new(mocks.Input)
+	output := new(mocks.Output)
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+
+	settings := newRetrySettings()
+	settings.Output = "retry"
+	settings.MaxDelay = time.Hour
+	settings.MaxAttempts = 0
+
+	handler := stream.NewConsumerRetryHandlerWithInterfaces(logger, mw, clock.NewFakeClock(), input, output, stream.OutputTypeSqs, "test", settings)
+
+	first := stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+		stream.AttributeSqsReceiptHandle: "handle",
+		stream.AttributeSqsReceiveCount:  1,
+	})
+	output.On("WriteOne", mock.Anything, stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+		stream.AttributeRetryAttempt: 2,
+		sqs.AttributeSqsDelaySeconds: int32(10),
+	})).Return(nil).Once()
+
+	ack, err := handler.Retry(ctx, first)
+	assert.NoError(t, err)
+	assert.True(t, ack, "messages written to the delay output have to be acknowledged")
+
+	// sqs counts the deliveries of every copy written to the delay output from 1 again
+	second := stream.NewJsonMessage(`"bar"`, map[string]interface{}{
+		stream.AttributeRetryAttempt:    8.0,
+		stream.AttributeSqsReceiveCount: 1,
+	})
+	output.On("WriteOne", mock.Anything, stream.NewJsonMessage(`"bar"`, map[string]interface{}{
+		stream.AttributeRetryAttempt: 9,
+		sqs.AttributeSqsDelaySeconds: int32(sqs.MaxDelaySeconds),
+	})).Return(fmt.Errorf("boom")).Once()
+
+	ack, err = handler.Retry(ctx, second)
+	assert.EqualError(t, err, "can not write the message to the retry output retry: boom")
+	assert.False(t, ack, "messages which could not be written must not be acknowledged")
+
+	output.AssertExpectations(t)
+}
+
+func TestConsumerRetryHandler_Queue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := new(mocks.Input)
+	clk := clock.NewFakeClock()
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+
+	handler := stream.NewConsumerRetryHandlerWithInterfaces(logger, mw, clk, input, nil, "", "test", newRetrySettings())
+
+	go func() {
+		_ = handler.Run(ctx, func(_ context.Context, _ *stream.Message) {
+			assert.Fail(t, "messages passed to the consumer again must not be acknowledged")
+		})
+	}()
+
+	msg := stream.NewJsonMessage(`"foo"`, nil)
+	handler.TrackAttempt(msg)
+
+	ack, err := handler.Retry(ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, ack, "messages passed to the consumer again must not be acknowledged")
+
+	clk.BlockUntil(1)
+	clk.Advance(10 * time.Second)
+
+	retried := <-handler.Data()
+	assert.Same(t, msg, retried)
+	assert.Equal(t, 2, retried.Attributes[stream.AttributeRetryAttempt])
+}
+
+func TestConsumerRetryHandler_QueueOutput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	input := new(mocks.Input)
+	output := new(mocks.Output)
+	clk := clock.NewFakeClock()
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+
+	settings := newRetrySettings()
+	settings.Output = "retry"
+
+	handler := stream.NewConsumerRetryHandlerWithInterfaces(logger, mw, clk, input, output, stream.OutputTypeRedis, "test", settings)
+
+	acknowledged := make(chan *stream.Message, 2)
+	done := make(chan error)
+
+	go func() {
+		done <- handler.Run(ctx, func(_ context.Context, msg *stream.Message) {
+			acknowledged <- msg
+		})
+	}()
+
+	first := stream.NewJsonMessage(`"foo"`, nil)
+	second := stream.NewJsonMessage(`"bar"`, map[string]interface{}{
+		stream.AttributeRetryAttempt: 2,
+	})
+
+	output.On("WriteOne", mock.Anything, stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+		stream.AttributeRetryAttempt: 2,
+	})).Return(nil).Once()
+	output.On("WriteOne", mock.Anything, stream.NewJsonMessage(`"bar"`, map[string]interface{}{
+		stream.AttributeRetryAttempt: 3,
+	})).Return(nil).Once()
+
+	ack, err := handler.Retry(ctx, first)
+	assert.NoError(t, err)
+	assert.False(t, ack, "messages are only acknowledged after they have been written to the delay output")
+
+	ack, err = handler.Retry(ctx, second)
+	assert.NoError(t, err)
+	assert.False(t, ack)
+
+	clk.BlockUntil(1)
+	clk.Advance(10 * time.Second)
+	assert.Same(t, first, <-acknowledged)
+
+	// pending messages are written to the delay output on shutdown
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Same(t, second, <-acknowledged)
+
+	output.AssertExpectations(t)
+}
+
+func TestConsumer_Retry(t *testing.T) {
+	data := make(chan *stream.Message, 10)
+	input := new(acknowledgeableInput)
+	callback := new(mocks.RunnableConsumerCallback)
+	retry := new(mocks.ConsumerRetryHandler)
+
+	logger := logMocks.NewLoggerMockedAll()
+	mw := metricMocks.NewWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 1,
+		IdleTimeout: time.Second,
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracing.NewNoopTracer(), input, me, stream.NewConsumerDeduplicatorNoop(), retry, callback, settings, "test", cfg.AppId{})
+	consumer := stream.NewConsumerWithInterfaces(baseConsumer, callback)
+
+	input.Input.On("Data").Return(data)
+	input.Input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})
+		data <- stream.NewJsonMessage(`"bar"`, map[string]interface{}{"id": "2"})
+		close(data)
+	}).Return(nil)
+	input.Input.On("Stop").Once()
+	input.AcknowledgeableInput.On("Ack", mock.Anything, stream.NewJsonMessage(`"bar"`, map[string]interface{}{"id": "2"})).Return(nil).Once()
+
+	retry.On("Run", mock.AnythingOfType("*context.cancelCtx"), mock.Anything).Return(nil)
+	retry.On("Data").Return(nil)
+	retry.On("TrackAttempt", mock.AnythingOfType("*stream.Message"))
+	retry.On("Retry", mock.Anything, stream.NewJsonMessage(`"foo"`, map[string]interface{}{"id": "1"})).Return(false, nil).Once()
+
+	callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).Return(func(_ map[string]interface{}) interface{} {
+		return mdl.String("")
+	})
+	callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.Anything).
+		Return(func(_ context.Context, model interface{}, _ map[string]interface{}) bool {
+			return *model.(*string) == "bar"
+		}, nil)
+	callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := consumer.Run(context.Background())
+
+	assert.NoError(t, err)
+	input.Input.AssertExpectations(t)
+	input.AcknowledgeableInput.AssertExpectations(t)
+	retry.AssertExpectations(t)
+}
@@ -28,6 +28,8 @@ type ConsumerTestSuite struct {
 	stop func()
 
 	input *mocks.Input
+	dedup stream.ConsumerDeduplicator
+	retry stream.ConsumerRetryHandler
 
 	callback *mocks.RunnableConsumerCallback
 	consumer *stream.Consumer
@@ -45,6 +47,9 @@ func (s *ConsumerTestSuite) SetupTest() {
 	s.input = new(mocks.Input)
 	s.callback = new(mocks.RunnableConsumerCallback)
 
+	s.dedup = stream.NewConsumerDeduplicatorNoop()
+	s.retry = stream.NewConsumerRetryHandlerNoop()
+
 	logger := logMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
 	mw := metricMocks.NewWriterMockedAll()
@@ -55,7 +60,7 @@ func (s *ConsumerTestSuite) SetupTest() {
 		IdleTimeout: time.Second,
 	}
 
-	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.callback, settings, "test", cfg.AppId{})
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.dedup, s.retry, s.callback, settings, "test", cfg.AppId{})
 	s.consumer = stream.NewConsumerWithInterfaces(baseConsumer, s.callback)
 }
 
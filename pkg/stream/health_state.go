@@ -0,0 +1,24 @@
+package stream
+
+import "sync"
+
+// healthState remembers the result of the last execution of a recurring operation, like receiving messages
+// from a queue or writing them to an output.
+type healthState struct {
+	lck sync.RWMutex
+	err error
+}
+
+func (h *healthState) set(err error) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	h.err = err
+}
+
+func (h *healthState) get() error {
+	h.lck.RLock()
+	defer h.lck.RUnlock()
+
+	return h.err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/cloud/aws/kinesis"
 	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
@@ -12,23 +13,25 @@ import (
 )
 
 const (
-	InputTypeFile     = "file"
-	InputTypeInMemory = "inMemory"
-	InputTypeKinesis  = "kinesis"
-	InputTypeRedis    = "redis"
-	InputTypeSns      = "sns"
-	InputTypeSqs      = "sqs"
+	InputTypeDynamoDbStream = "dynamodb_stream"
+	InputTypeFile           = "file"
+	InputTypeInMemory       = "inMemory"
+	InputTypeKinesis        = "kinesis"
+	InputTypeRedis          = "redis"
+	InputTypeSns            = "sns"
+	InputTypeSqs            = "sqs"
 )
 
 type InputFactory func(ctx context.Context, config cfg.Config, logger log.Logger, name string) (Input, error)
 
 var inputFactories = map[string]InputFactory{
-	InputTypeFile:     newFileInputFromConfig,
-	InputTypeInMemory: newInMemoryInputFromConfig,
-	InputTypeKinesis:  newKinesisInputFromConfig,
-	InputTypeRedis:    newRedisInputFromConfig,
-	InputTypeSns:      newSnsInputFromConfig,
-	InputTypeSqs:      newSqsInputFromConfig,
+	InputTypeDynamoDbStream: newDynamoDbStreamInputFromConfig,
+	InputTypeFile:           newFileInputFromConfig,
+	InputTypeInMemory:       newInMemoryInputFromConfig,
+	InputTypeKinesis:        newKinesisInputFromConfig,
+	InputTypeRedis:          newRedisInputFromConfig,
+	InputTypeSns:            newSnsInputFromConfig,
+	InputTypeSqs:            newSqsInputFromConfig,
 }
 
 func SetInputFactory(typ string, factory InputFactory) {
@@ -70,6 +73,40 @@ func NewConfigurableInput(ctx context.Context, config cfg.Config, logger log.Log
 	return input, nil
 }
 
+type DynamoDbStreamInputConfiguration struct {
+	Type               string            `cfg:"type" default:"dynamodb_stream"`
+	TableName          string            `cfg:"table_name" validate:"required"`
+	ApplicationName    string            `cfg:"application_name" default:"{app_name}"`
+	ClientName         string            `cfg:"client_name" default:"default"`
+	StartingPosition   string            `cfg:"starting_position" default:"TRIM_HORIZON" validate:"oneof=TRIM_HORIZON LATEST"`
+	BatchSize          int32             `cfg:"batch_size" default:"100" validate:"min=1,max=1000"`
+	WaitTime           time.Duration     `cfg:"wait_time" default:"1s"`
+	ShardCheckInterval time.Duration     `cfg:"shard_check_interval" default:"1m"`
+	LeaseDuration      time.Duration     `cfg:"lease_duration" default:"1m"`
+	Attributes         map[string]string `cfg:"attributes"`
+}
+
+func newDynamoDbStreamInputFromConfig(ctx context.Context, config cfg.Config, logger log.Logger, name string) (Input, error) {
+	key := ConfigurableInputKey(name)
+
+	configuration := DynamoDbStreamInputConfiguration{}
+	config.UnmarshalKey(key, &configuration)
+
+	settings := &DynamoDbStreamInputSettings{
+		TableName:          configuration.TableName,
+		ApplicationName:    configuration.ApplicationName,
+		ClientName:         configuration.ClientName,
+		StartingPosition:   types.ShardIteratorType(configuration.StartingPosition),
+		BatchSize:          configuration.BatchSize,
+		WaitTime:           configuration.WaitTime,
+		ShardCheckInterval: configuration.ShardCheckInterval,
+		LeaseDuration:      configuration.LeaseDuration,
+		Attributes:         configuration.Attributes,
+	}
+
+	return NewDynamoDbStreamInput(ctx, config, logger, settings)
+}
+
 func newFileInputFromConfig(_ context.Context, config cfg.Config, logger log.Logger, name string) (Input, error) {
 	key := ConfigurableInputKey(name)
 	settings := FileSettings{}
@@ -0,0 +1,438 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	gosoDynamoDbStreams "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodbstreams"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/uuid"
+)
+
+const (
+	// AttributeDynamoDbOperation carries the crud type (create, update or delete) of the change event and uses the
+	// same name as the attribute written by the publishers of mdlsub.
+	AttributeDynamoDbOperation      = "type"
+	AttributeDynamoDbEventName      = "dynamoDbEventName"
+	AttributeDynamoDbSequenceNumber = "dynamoDbSequenceNumber"
+	AttributeDynamoDbOldImage       = "dynamoDbOldImage"
+)
+
+type DynamoDbStreamInputSettings struct {
+	TableName          string
+	ApplicationName    string
+	ClientName         string
+	StartingPosition   types.ShardIteratorType
+	BatchSize          int32
+	WaitTime           time.Duration
+	ShardCheckInterval time.Duration
+	LeaseDuration      time.Duration
+	Attributes         map[string]string
+}
+
+// dynamoDbStreamInput reads the change events of a dynamodb table. Every shard of the stream is consumed by a single
+// client only, the progress and the ownership of the shards are stored by the checkpointer. Child shards are read
+// after their parent is finished to keep the events of an item in order.
+type dynamoDbStreamInput struct {
+	logger       log.Logger
+	clock        clock.Clock
+	client       gosoDynamoDbStreams.Client
+	checkpointer DynamoDbStreamCheckpointer
+	settings     *DynamoDbStreamInputSettings
+
+	channel chan *Message
+	cancel  context.CancelFunc
+	lck     sync.Mutex
+	wg      sync.WaitGroup
+	running map[string]bool
+}
+
+func NewDynamoDbStreamInput(ctx context.Context, config cfg.Config, logger log.Logger, settings *DynamoDbStreamInputSettings) (Input, error) {
+	var err error
+	var client gosoDynamoDbStreams.Client
+	var checkpointer DynamoDbStreamCheckpointer
+
+	logger = logger.WithFields(log.Fields{
+		"dynamodb_table": settings.TableName,
+	})
+
+	if client, err = gosoDynamoDbStreams.ProvideClient(ctx, config, logger, settings.ClientName); err != nil {
+		return nil, fmt.Errorf("can not create dynamodb streams client: %w", err)
+	}
+
+	if checkpointer, err = NewDynamoDbStreamCheckpointer(ctx, config, logger, uuid.New().NewV4(), settings); err != nil {
+		return nil, fmt.Errorf("can not create checkpointer: %w", err)
+	}
+
+	return NewDynamoDbStreamInputWithInterfaces(logger, clock.Provider, client, checkpointer, settings), nil
+}
+
+func NewDynamoDbStreamInputWithInterfaces(logger log.Logger, clock clock.Clock, client gosoDynamoDbStreams.Client, checkpointer DynamoDbStreamCheckpointer, settings *DynamoDbStreamInputSettings) Input {
+	return &dynamoDbStreamInput{
+		logger:       logger,
+		clock:        clock,
+		client:       client,
+		checkpointer: checkpointer,
+		settings:     settings,
+		channel:      make(chan *Message),
+		cancel:       func() {},
+		running:      map[string]bool{},
+	}
+}
+
+func (i *dynamoDbStreamInput) Data() chan *Message {
+	return i.channel
+}
+
+func (i *dynamoDbStreamInput) Run(ctx context.Context) error {
+	defer close(i.channel)
+	defer i.wg.Wait()
+
+	i.lck.Lock()
+	ctx, i.cancel = context.WithCancel(ctx)
+	i.lck.Unlock()
+
+	streamArn, err := i.getStreamArn(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err = i.startShards(ctx, streamArn); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-i.clock.After(i.settings.ShardCheckInterval):
+		}
+	}
+}
+
+func (i *dynamoDbStreamInput) Stop() {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	i.cancel()
+}
+
+// getStreamArn returns the latest stream of the table. After a stream has been disabled and enabled again, the old
+// stream is still listed until it expires.
+func (i *dynamoDbStreamInput) getStreamArn(ctx context.Context) (string, error) {
+	var latest *types.Stream
+	var lastStreamArn *string
+
+	for {
+		out, err := i.client.ListStreams(ctx, &dynamodbstreams.ListStreamsInput{
+			TableName:               aws.String(i.settings.TableName),
+			ExclusiveStartStreamArn: lastStreamArn,
+		})
+		if err != nil {
+			return "", fmt.Errorf("can not list the streams of table %s: %w", i.settings.TableName, err)
+		}
+
+		// the labels are timestamps in ISO 8601 format
+		for j := range out.Streams {
+			if latest == nil || aws.ToString(out.Streams[j].StreamLabel) > aws.ToString(latest.StreamLabel) {
+				latest = &out.Streams[j]
+			}
+		}
+
+		if lastStreamArn = out.LastEvaluatedStreamArn; lastStreamArn == nil {
+			break
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("there is no stream enabled for table %s", i.settings.TableName)
+	}
+
+	return aws.ToString(latest.StreamArn), nil
+}
+
+func (i *dynamoDbStreamInput) startShards(ctx context.Context, streamArn string) error {
+	shards, err := i.describeShards(ctx, streamArn)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		known[aws.ToString(shard.ShardId)] = true
+	}
+
+	for _, shard := range shards {
+		shardId := aws.ToString(shard.ShardId)
+
+		if i.isRunning(shardId) {
+			continue
+		}
+
+		startingPosition := i.settings.StartingPosition
+
+		if parentId := aws.ToString(shard.ParentShardId); parentId != "" {
+			parent, found, err := i.checkpointer.Get(ctx, parentId)
+			if err != nil {
+				return fmt.Errorf("can not check the parent of shard %s: %w", shardId, err)
+			}
+
+			// a parent which is not part of the stream anymore has been trimmed and can't be read anyway
+			if known[parentId] && !parent.Finished {
+				continue
+			}
+
+			// the records written between the split of the parent and the claim of the child must not be skipped,
+			// so the configured position only applies to shards without a known lineage
+			if found {
+				startingPosition = types.ShardIteratorTypeTrimHorizon
+			}
+		}
+
+		checkpoint, claimed, err := i.checkpointer.Claim(ctx, shardId)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		i.setRunning(shardId, true)
+		i.wg.Add(1)
+
+		go func(checkpoint *DynamoDbStreamCheckpoint, startingPosition types.ShardIteratorType) {
+			defer i.wg.Done()
+			defer i.setRunning(checkpoint.ShardId, false)
+
+			i.consumeShard(ctx, streamArn, checkpoint, startingPosition)
+		}(checkpoint, startingPosition)
+	}
+
+	return nil
+}
+
+func (i *dynamoDbStreamInput) describeShards(ctx context.Context, streamArn string) ([]types.Shard, error) {
+	var shards []types.Shard
+	var lastShardId *string
+
+	for {
+		out, err := i.client.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(streamArn),
+			ExclusiveStartShardId: lastShardId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can not describe stream %s: %w", streamArn, err)
+		}
+
+		shards = append(shards, out.StreamDescription.Shards...)
+		lastShardId = out.StreamDescription.LastEvaluatedShardId
+
+		if lastShardId == nil {
+			return shards, nil
+		}
+	}
+}
+
+func (i *dynamoDbStreamInput) consumeShard(ctx context.Context, streamArn string, checkpoint *DynamoDbStreamCheckpoint, startingPosition types.ShardIteratorType) {
+	logger := i.logger.WithFields(log.Fields{
+		"shard_id": checkpoint.ShardId,
+	})
+
+	defer func() {
+		// the context might already be canceled at this point, but the lease should be released nevertheless
+		if err := i.checkpointer.Release(context.Background(), checkpoint.ShardId); err != nil && !errors.Is(err, ErrDynamoDbStreamLeaseLost) {
+			logger.Error("can not release shard: %w", err)
+		}
+	}()
+
+	iterator, err := i.getShardIterator(ctx, streamArn, checkpoint, startingPosition)
+	if err != nil {
+		logger.Error("can not get shard iterator: %w", err)
+		return
+	}
+
+	sequenceNumber := checkpoint.SequenceNumber
+	renewedAt := i.clock.Now()
+
+	for iterator != nil {
+		out, err := i.client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(i.settings.BatchSize),
+		})
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			logger.Error("can not get records: %w", err)
+			return
+		}
+
+		for _, record := range out.Records {
+			msg, err := i.buildMessage(record)
+			if err != nil {
+				// skipping the record would lose the change, so the shard stops at it and is claimed again later
+				logger.Error("can not build message from record %s, stopping to consume the shard: %w", aws.ToString(record.Dynamodb.SequenceNumber), err)
+				i.checkpointProgress(ctx, logger, checkpoint, sequenceNumber)
+
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case i.channel <- msg:
+			}
+
+			sequenceNumber = aws.ToString(record.Dynamodb.SequenceNumber)
+		}
+
+		iterator = out.NextShardIterator
+
+		// the lease is renewed with every checkpoint, so we also checkpoint without new records once half of it is up
+		if len(out.Records) > 0 || iterator == nil || i.clock.Now().Sub(renewedAt) > i.settings.LeaseDuration/2 {
+			if err = i.checkpointer.Checkpoint(ctx, checkpoint.ShardId, sequenceNumber, iterator == nil); err != nil {
+				logger.Error("can not checkpoint shard: %w", err)
+				return
+			}
+
+			renewedAt = i.clock.Now()
+		}
+
+		if iterator == nil {
+			logger.Info("shard is closed and has been consumed completely")
+			return
+		}
+
+		if len(out.Records) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.clock.After(i.settings.WaitTime):
+		}
+	}
+}
+
+// checkpointProgress stores the progress of a shard, which is stopped before being consumed completely.
+func (i *dynamoDbStreamInput) checkpointProgress(ctx context.Context, logger log.Logger, checkpoint *DynamoDbStreamCheckpoint, sequenceNumber string) {
+	if sequenceNumber == checkpoint.SequenceNumber {
+		return
+	}
+
+	if err := i.checkpointer.Checkpoint(ctx, checkpoint.ShardId, sequenceNumber, false); err != nil {
+		logger.Error("can not checkpoint shard: %w", err)
+	}
+}
+
+// getShardIterator continues after the checkpoint of the shard or starts at the given position if there is none.
+func (i *dynamoDbStreamInput) getShardIterator(ctx context.Context, streamArn string, checkpoint *DynamoDbStreamCheckpoint, startingPosition types.ShardIteratorType) (*string, error) {
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String(checkpoint.ShardId),
+		ShardIteratorType: startingPosition,
+	}
+
+	if checkpoint.SequenceNumber != "" {
+		input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		input.SequenceNumber = aws.String(checkpoint.SequenceNumber)
+	}
+
+	out, err := i.client.GetShardIterator(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.ShardIterator, nil
+}
+
+func (i *dynamoDbStreamInput) buildMessage(record types.Record) (*Message, error) {
+	var err error
+	var body []byte
+	var image map[string]interface{}
+
+	attributes := map[string]interface{}{
+		AttributeDynamoDbEventName:      string(record.EventName),
+		AttributeDynamoDbSequenceNumber: aws.ToString(record.Dynamodb.SequenceNumber),
+	}
+
+	for key, value := range i.settings.Attributes {
+		attributes[key] = value
+	}
+
+	switch record.EventName {
+	case types.OperationTypeInsert:
+		attributes[AttributeDynamoDbOperation] = ddb.Create
+		image, err = ddb.StreamImageToMap(record.Dynamodb.NewImage)
+	case types.OperationTypeModify:
+		attributes[AttributeDynamoDbOperation] = ddb.Update
+		image, err = ddb.StreamImageToMap(record.Dynamodb.NewImage)
+
+		if err == nil && len(record.Dynamodb.OldImage) > 0 {
+			err = i.addOldImage(attributes, record.Dynamodb.OldImage)
+		}
+	case types.OperationTypeRemove:
+		attributes[AttributeDynamoDbOperation] = ddb.Delete
+		image, err = ddb.StreamImageToMap(record.Dynamodb.OldImage)
+	default:
+		return nil, fmt.Errorf("unknown event name %s", record.EventName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body, err = json.Marshal(image); err != nil {
+		return nil, fmt.Errorf("can not marshal image: %w", err)
+	}
+
+	return NewJsonMessage(string(body), attributes), nil
+}
+
+func (i *dynamoDbStreamInput) addOldImage(attributes map[string]interface{}, oldImage map[string]types.AttributeValue) error {
+	image, err := ddb.StreamImageToMap(oldImage)
+	if err != nil {
+		return fmt.Errorf("can not convert old image: %w", err)
+	}
+
+	body, err := json.Marshal(image)
+	if err != nil {
+		return fmt.Errorf("can not marshal old image: %w", err)
+	}
+
+	attributes[AttributeDynamoDbOldImage] = string(body)
+
+	return nil
+}
+
+func (i *dynamoDbStreamInput) isRunning(shardId string) bool {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	return i.running[shardId]
+}
+
+func (i *dynamoDbStreamInput) setRunning(shardId string, running bool) {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	if running {
+		i.running[shardId] = true
+	} else {
+		delete(i.running, shardId)
+	}
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+var ErrDynamoDbStreamLeaseLost = fmt.Errorf("the lease of the shard is owned by another client")
+
+// DynamoDbStreamCheckpoint stores the progress of a client consuming a shard of a dynamodb stream. A shard is
+// consumed by the client owning the lease only, the lease is taken over by other clients after it expired.
+type DynamoDbStreamCheckpoint struct {
+	Namespace      string `json:"namespace" ddb:"key=hash"`
+	ShardId        string `json:"shardId" ddb:"key=range"`
+	OwnerId        string `json:"ownerId"`
+	LeasedUntil    int64  `json:"leasedUntil"`
+	SequenceNumber string `json:"sequenceNumber"`
+	Finished       bool   `json:"finished"`
+}
+
+//go:generate mockery --name DynamoDbStreamCheckpointer
+type DynamoDbStreamCheckpointer interface {
+	// Claim acquires the lease of the shard and returns the last checkpoint if the shard is not owned by another client.
+	Claim(ctx context.Context, shardId string) (*DynamoDbStreamCheckpoint, bool, error)
+	// Checkpoint stores the sequence number of the last record read from the shard and renews the lease.
+	Checkpoint(ctx context.Context, shardId string, sequenceNumber string, finished bool) error
+	Release(ctx context.Context, shardId string) error
+	// Get returns the checkpoint of the shard and whether the shard has been claimed before.
+	Get(ctx context.Context, shardId string) (*DynamoDbStreamCheckpoint, bool, error)
+}
+
+type dynamoDbStreamCheckpointer struct {
+	clock      clock.Clock
+	repository ddb.Repository
+	namespace  string
+	ownerId    string
+	lease      time.Duration
+}
+
+func NewDynamoDbStreamCheckpointer(ctx context.Context, config cfg.Config, logger log.Logger, ownerId string, settings *DynamoDbStreamInputSettings) (DynamoDbStreamCheckpointer, error) {
+	repository, err := ddb.NewRepository(ctx, config, logger, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "dynamodb-stream-checkpoints",
+		},
+		DisableTracing: true,
+		Main: ddb.MainSettings{
+			Model:              DynamoDbStreamCheckpoint{},
+			ReadCapacityUnits:  5,
+			WriteCapacityUnits: 5,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository: %w", err)
+	}
+
+	namespace := fmt.Sprintf("%s-%s", settings.ApplicationName, settings.TableName)
+
+	return NewDynamoDbStreamCheckpointerWithInterfaces(clock.Provider, repository, namespace, ownerId, settings.LeaseDuration), nil
+}
+
+func NewDynamoDbStreamCheckpointerWithInterfaces(clock clock.Clock, repository ddb.Repository, namespace string, ownerId string, lease time.Duration) DynamoDbStreamCheckpointer {
+	return &dynamoDbStreamCheckpointer{
+		clock:      clock,
+		repository: repository,
+		namespace:  namespace,
+		ownerId:    ownerId,
+		lease:      lease,
+	}
+}
+
+func (c *dynamoDbStreamCheckpointer) Claim(ctx context.Context, shardId string) (*DynamoDbStreamCheckpoint, bool, error) {
+	checkpoint, _, err := c.Get(ctx, shardId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if checkpoint.Finished {
+		return nil, false, nil
+	}
+
+	now := c.clock.Now()
+	condition := ddb.Or(
+		ddb.AttributeNotExists("namespace"),
+		ddb.Eq("ownerId", c.ownerId),
+		ddb.Lt("leasedUntil", now.Unix()),
+	)
+
+	checkpoint.OwnerId = c.ownerId
+	checkpoint.LeasedUntil = now.Add(c.lease).Unix()
+
+	qb := c.repository.PutItemBuilder().WithCondition(condition)
+	res, err := c.repository.PutItem(ctx, qb, checkpoint)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not claim shard %s: %w", shardId, err)
+	}
+
+	if res.ConditionalCheckFailed {
+		return nil, false, nil
+	}
+
+	return checkpoint, true, nil
+}
+
+func (c *dynamoDbStreamCheckpointer) Checkpoint(ctx context.Context, shardId string, sequenceNumber string, finished bool) error {
+	checkpoint := &DynamoDbStreamCheckpoint{
+		Namespace:      c.namespace,
+		ShardId:        shardId,
+		OwnerId:        c.ownerId,
+		LeasedUntil:    c.clock.Now().Add(c.lease).Unix(),
+		SequenceNumber: sequenceNumber,
+		Finished:       finished,
+	}
+
+	return c.putOwned(ctx, checkpoint)
+}
+
+func (c *dynamoDbStreamCheckpointer) Release(ctx context.Context, shardId string) error {
+	checkpoint, _, err := c.Get(ctx, shardId)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.OwnerId != c.ownerId {
+		return nil
+	}
+
+	checkpoint.LeasedUntil = 0
+
+	return c.putOwned(ctx, checkpoint)
+}
+
+func (c *dynamoDbStreamCheckpointer) Get(ctx context.Context, shardId string) (*DynamoDbStreamCheckpoint, bool, error) {
+	checkpoint := &DynamoDbStreamCheckpoint{}
+	qb := c.repository.GetItemBuilder().WithHash(c.namespace).WithRange(shardId).WithConsistentRead(true)
+
+	res, err := c.repository.GetItem(ctx, qb, checkpoint)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not read checkpoint of shard %s: %w", shardId, err)
+	}
+
+	if !res.IsFound {
+		checkpoint.Namespace = c.namespace
+		checkpoint.ShardId = shardId
+	}
+
+	return checkpoint, res.IsFound, nil
+}
+
+func (c *dynamoDbStreamCheckpointer) putOwned(ctx context.Context, checkpoint *DynamoDbStreamCheckpoint) error {
+	qb := c.repository.PutItemBuilder().WithCondition(ddb.Eq("ownerId", c.ownerId))
+
+	res, err := c.repository.PutItem(ctx, qb, checkpoint)
+	if err != nil {
+		return fmt.Errorf("can not write checkpoint of shard %s: %w", checkpoint.ShardId, err)
+	}
+
+	if res.ConditionalCheckFailed {
+		return ErrDynamoDbStreamLeaseLost
+	}
+
+	return nil
+}
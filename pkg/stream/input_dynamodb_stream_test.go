@@ -0,0 +1,305 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	dynamodbstreamsMocks "github.com/justtrackio/gosoline/pkg/cloud/aws/dynamodbstreams/mocks"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func buildDynamoDbStreamRecord(eventName types.OperationType, sequenceNumber string, newImage map[string]types.AttributeValue, oldImage map[string]types.AttributeValue) types.Record {
+	return types.Record{
+		EventName: eventName,
+		Dynamodb: &types.StreamRecord{
+			SequenceNumber: aws.String(sequenceNumber),
+			NewImage:       newImage,
+			OldImage:       oldImage,
+		},
+	}
+}
+
+func buildDynamoDbStreamImage(name string, count string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "a"},
+		"name":  &types.AttributeValueMemberS{Value: name},
+		"count": &types.AttributeValueMemberN{Value: count},
+	}
+}
+
+func TestDynamoDbStreamInput_Run(t *testing.T) {
+	ctx := context.Background()
+	streamArn := "arn:aws:dynamodb:eu-central-1:000000000000:table/items/stream/2021-01-01T00:00:00.000"
+
+	client := new(dynamodbstreamsMocks.Client)
+	client.On("ListStreams", mock.Anything, &dynamodbstreams.ListStreamsInput{
+		TableName: aws.String("items"),
+	}).Return(&dynamodbstreams.ListStreamsOutput{
+		Streams: []types.Stream{{StreamArn: aws.String(streamArn)}},
+	}, nil).Once()
+
+	client.On("DescribeStream", mock.Anything, &dynamodbstreams.DescribeStreamInput{
+		StreamArn: aws.String(streamArn),
+	}).Return(&dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &types.StreamDescription{
+			Shards: []types.Shard{
+				{ShardId: aws.String("shard-1")},
+				{ShardId: aws.String("shard-2"), ParentShardId: aws.String("shard-1")},
+			},
+		},
+	}, nil)
+
+	client.On("GetShardIterator", mock.Anything, &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String("shard-1"),
+		ShardIteratorType: types.ShardIteratorTypeAfterSequenceNumber,
+		SequenceNumber:    aws.String("0"),
+	}).Return(&dynamodbstreams.GetShardIteratorOutput{
+		ShardIterator: aws.String("iterator-1"),
+	}, nil).Once()
+
+	client.On("GetRecords", mock.Anything, &dynamodbstreams.GetRecordsInput{
+		ShardIterator: aws.String("iterator-1"),
+		Limit:         aws.Int32(10),
+	}).Return(&dynamodbstreams.GetRecordsOutput{
+		Records: []types.Record{
+			buildDynamoDbStreamRecord(types.OperationTypeInsert, "1", buildDynamoDbStreamImage("foo", "1"), nil),
+			buildDynamoDbStreamRecord(types.OperationTypeModify, "2", buildDynamoDbStreamImage("bar", "2"), buildDynamoDbStreamImage("foo", "1")),
+			buildDynamoDbStreamRecord(types.OperationTypeRemove, "3", nil, buildDynamoDbStreamImage("bar", "2")),
+		},
+	}, nil).Once()
+
+	checkpointer := new(mocks.DynamoDbStreamCheckpointer)
+	checkpointer.On("Claim", mock.Anything, "shard-1").Return(&stream.DynamoDbStreamCheckpoint{
+		ShardId:        "shard-1",
+		SequenceNumber: "0",
+	}, true, nil).Once()
+	checkpointer.On("Get", mock.Anything, "shard-1").Return(&stream.DynamoDbStreamCheckpoint{
+		ShardId: "shard-1",
+	}, true, nil).Once()
+	checkpointer.On("Checkpoint", mock.Anything, "shard-1", "3", true).Return(nil).Once()
+	checkpointer.On("Release", mock.Anything, "shard-1").Return(nil).Once()
+
+	input := stream.NewDynamoDbStreamInputWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewRealClock(), client, checkpointer, &stream.DynamoDbStreamInputSettings{
+		TableName:          "items",
+		StartingPosition:   types.ShardIteratorTypeTrimHorizon,
+		BatchSize:          10,
+		WaitTime:           time.Second,
+		ShardCheckInterval: time.Hour,
+		LeaseDuration:      time.Minute,
+		Attributes: map[string]string{
+			"modelId": "justtrack.gosoline.test.items",
+		},
+	})
+
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		assert.NoError(t, input.Run(ctx))
+	}()
+
+	var messages []*stream.Message
+	for i := 0; i < 3; i++ {
+		messages = append(messages, <-input.Data())
+	}
+
+	input.Stop()
+	<-runDone
+
+	expectedTypes := []string{"create", "update", "delete"}
+	expectedBodies := []string{
+		`{"count":1,"id":"a","name":"foo"}`,
+		`{"count":2,"id":"a","name":"bar"}`,
+		`{"count":2,"id":"a","name":"bar"}`,
+	}
+
+	for i, msg := range messages {
+		assert.Equal(t, expectedTypes[i], msg.Attributes[stream.AttributeDynamoDbOperation])
+		assert.Equal(t, "justtrack.gosoline.test.items", msg.Attributes["modelId"])
+		assert.Equal(t, stream.EncodingJson, msg.Attributes[stream.AttributeEncoding])
+		assert.JSONEq(t, expectedBodies[i], msg.Body)
+	}
+
+	assert.Equal(t, "MODIFY", messages[1].Attributes[stream.AttributeDynamoDbEventName])
+	assert.JSONEq(t, `{"count":1,"id":"a","name":"foo"}`, messages[1].Attributes[stream.AttributeDynamoDbOldImage].(string))
+
+	_, ok := <-input.Data()
+	assert.False(t, ok, "the channel should be closed after the input stopped")
+
+	client.AssertExpectations(t)
+	checkpointer.AssertExpectations(t)
+	checkpointer.AssertNotCalled(t, "Claim", mock.Anything, "shard-2")
+}
+
+func TestDynamoDbStreamInput_ChildShardStartsAtTrimHorizon(t *testing.T) {
+	ctx := context.Background()
+	streamArn := "arn:aws:dynamodb:eu-central-1:000000000000:table/items/stream/2021-01-01T00:00:00.000"
+
+	client := new(dynamodbstreamsMocks.Client)
+	client.On("ListStreams", mock.Anything, mock.Anything).Return(&dynamodbstreams.ListStreamsOutput{
+		Streams: []types.Stream{{StreamArn: aws.String(streamArn)}},
+	}, nil).Once()
+
+	// the parent of shard-2 has been trimmed already, shard-3 has no parent at all
+	client.On("DescribeStream", mock.Anything, mock.Anything).Return(&dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &types.StreamDescription{
+			Shards: []types.Shard{
+				{ShardId: aws.String("shard-2"), ParentShardId: aws.String("shard-1")},
+				{ShardId: aws.String("shard-3")},
+			},
+		},
+	}, nil).Once()
+
+	iterators := make(chan struct{}, 2)
+	for shardId, iteratorType := range map[string]types.ShardIteratorType{
+		"shard-2": types.ShardIteratorTypeTrimHorizon,
+		"shard-3": types.ShardIteratorTypeLatest,
+	} {
+		client.On("GetShardIterator", mock.Anything, &dynamodbstreams.GetShardIteratorInput{
+			StreamArn:         aws.String(streamArn),
+			ShardId:           aws.String(shardId),
+			ShardIteratorType: iteratorType,
+		}).Run(func(_ mock.Arguments) {
+			iterators <- struct{}{}
+		}).Return(nil, fmt.Errorf("stop here")).Once()
+	}
+
+	checkpointer := new(mocks.DynamoDbStreamCheckpointer)
+	checkpointer.On("Get", mock.Anything, "shard-1").Return(&stream.DynamoDbStreamCheckpoint{
+		ShardId:  "shard-1",
+		Finished: true,
+	}, true, nil).Once()
+
+	for _, shardId := range []string{"shard-2", "shard-3"} {
+		checkpointer.On("Claim", mock.Anything, shardId).Return(&stream.DynamoDbStreamCheckpoint{
+			ShardId: shardId,
+		}, true, nil).Once()
+		checkpointer.On("Release", mock.Anything, shardId).Return(nil).Once()
+	}
+
+	input := stream.NewDynamoDbStreamInputWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewRealClock(), client, checkpointer, &stream.DynamoDbStreamInputSettings{
+		TableName:          "items",
+		StartingPosition:   types.ShardIteratorTypeLatest,
+		BatchSize:          10,
+		WaitTime:           time.Second,
+		ShardCheckInterval: time.Hour,
+		LeaseDuration:      time.Minute,
+	})
+
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		assert.NoError(t, input.Run(ctx))
+	}()
+
+	<-iterators
+	<-iterators
+
+	input.Stop()
+	<-runDone
+
+	client.AssertExpectations(t)
+	checkpointer.AssertExpectations(t)
+}
+
+func TestDynamoDbStreamInput_InvalidRecord(t *testing.T) {
+	ctx := context.Background()
+	oldStreamArn := "arn:aws:dynamodb:eu-central-1:000000000000:table/items/stream/2021-01-01T00:00:00.000"
+	streamArn := "arn:aws:dynamodb:eu-central-1:000000000000:table/items/stream/2021-02-01T00:00:00.000"
+
+	client := new(dynamodbstreamsMocks.Client)
+	client.On("ListStreams", mock.Anything, &dynamodbstreams.ListStreamsInput{
+		TableName: aws.String("items"),
+	}).Return(&dynamodbstreams.ListStreamsOutput{
+		Streams:                []types.Stream{{StreamArn: aws.String(oldStreamArn), StreamLabel: aws.String("2021-01-01T00:00:00.000")}},
+		LastEvaluatedStreamArn: aws.String(oldStreamArn),
+	}, nil).Once()
+	client.On("ListStreams", mock.Anything, &dynamodbstreams.ListStreamsInput{
+		TableName:               aws.String("items"),
+		ExclusiveStartStreamArn: aws.String(oldStreamArn),
+	}).Return(&dynamodbstreams.ListStreamsOutput{
+		Streams: []types.Stream{{StreamArn: aws.String(streamArn), StreamLabel: aws.String("2021-02-01T00:00:00.000")}},
+	}, nil).Once()
+
+	client.On("DescribeStream", mock.Anything, &dynamodbstreams.DescribeStreamInput{
+		StreamArn: aws.String(streamArn),
+	}).Return(&dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &types.StreamDescription{
+			Shards: []types.Shard{{ShardId: aws.String("shard-1")}},
+		},
+	}, nil)
+
+	client.On("GetShardIterator", mock.Anything, mock.Anything).Return(&dynamodbstreams.GetShardIteratorOutput{
+		ShardIterator: aws.String("iterator-1"),
+	}, nil).Once()
+
+	client.On("GetRecords", mock.Anything, mock.Anything).Return(&dynamodbstreams.GetRecordsOutput{
+		Records: []types.Record{
+			buildDynamoDbStreamRecord(types.OperationTypeInsert, "1", buildDynamoDbStreamImage("foo", "1"), nil),
+			buildDynamoDbStreamRecord("UNKNOWN", "2", nil, nil),
+			buildDynamoDbStreamRecord(types.OperationTypeRemove, "3", nil, buildDynamoDbStreamImage("foo", "1")),
+		},
+		NextShardIterator: aws.String("iterator-2"),
+	}, nil).Once()
+
+	released := make(chan struct{})
+
+	checkpointer := new(mocks.DynamoDbStreamCheckpointer)
+	checkpointer.On("Claim", mock.Anything, "shard-1").Return(&stream.DynamoDbStreamCheckpoint{
+		ShardId: "shard-1",
+	}, true, nil).Once()
+	checkpointer.On("Checkpoint", mock.Anything, "shard-1", "1", false).Return(nil).Once()
+	checkpointer.On("Release", mock.Anything, "shard-1").Run(func(_ mock.Arguments) {
+		close(released)
+	}).Return(nil).Once()
+
+	input := stream.NewDynamoDbStreamInputWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewRealClock(), client, checkpointer, &stream.DynamoDbStreamInputSettings{
+		TableName:          "items",
+		StartingPosition:   types.ShardIteratorTypeTrimHorizon,
+		BatchSize:          10,
+		WaitTime:           time.Second,
+		ShardCheckInterval: time.Hour,
+		LeaseDuration:      time.Minute,
+	})
+
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		assert.NoError(t, input.Run(ctx))
+	}()
+
+	msg := <-input.Data()
+	assert.JSONEq(t, `{"count":1,"id":"a","name":"foo"}`, msg.Body)
+
+	<-released
+	input.Stop()
+	<-runDone
+
+	_, ok := <-input.Data()
+	assert.False(t, ok, "the records after the invalid one should not be consumed")
+
+	client.AssertExpectations(t)
+	checkpointer.AssertExpectations(t)
+}
+
+func TestDynamoDbStreamInput_NoStream(t *testing.T) {
+	client := new(dynamodbstreamsMocks.Client)
+	client.On("ListStreams", mock.Anything, mock.Anything).Return(&dynamodbstreams.ListStreamsOutput{}, nil)
+
+	input := stream.NewDynamoDbStreamInputWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewRealClock(), client, new(mocks.DynamoDbStreamCheckpointer), &stream.DynamoDbStreamInputSettings{
+		TableName: "items",
+	})
+
+	err := input.Run(context.Background())
+	assert.EqualError(t, err, "there is no stream enabled for table items")
+}
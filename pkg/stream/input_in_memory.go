@@ -5,6 +5,8 @@ import (
 	"sync"
 )
 
+var _ AcknowledgeableInput = &InMemoryInput{}
+
 var inMemoryInputsLock sync.Mutex
 var inMemoryInputs = make(map[string]*InMemoryInput)
 
@@ -26,6 +28,7 @@ type InMemoryInput struct {
 	channel       chan *Message
 	stopped       chan struct{}
 	closedStopped bool
+	ackHandlers   []func(msgs []*Message)
 	settings      *InMemorySettings
 }
 
@@ -58,6 +61,7 @@ func (i *InMemoryInput) Reset() {
 	i.channel = make(chan *Message, i.settings.Size)
 	i.stopped = make(chan struct{})
 	i.closedStopped = false
+	i.ackHandlers = nil
 }
 
 func (i *InMemoryInput) Publish(messages ...*Message) {
@@ -89,3 +93,27 @@ func (i *InMemoryInput) Stop() {
 func (i *InMemoryInput) Data() chan *Message {
 	return i.channel
 }
+
+func (i *InMemoryInput) Ack(ctx context.Context, msg *Message) error {
+	return i.AckBatch(ctx, []*Message{msg})
+}
+
+func (i *InMemoryInput) AckBatch(_ context.Context, msgs []*Message) error {
+	i.lck.Lock()
+	handlers := i.ackHandlers
+	i.lck.Unlock()
+
+	for _, handler := range handlers {
+		handler(msgs)
+	}
+
+	return nil
+}
+
+// OnAck registers a handler which is called with the messages acknowledged by the consumer of the input.
+func (i *InMemoryInput) OnAck(handler func(msgs []*Message)) {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	i.ackHandlers = append(i.ackHandlers, handler)
+}
@@ -42,6 +42,8 @@ func NewKinesisMessageHandler(channel chan *Message) kinesis.MessageHandler {
 	}
 }
 
+// Handle forwards a record to the consumer. Kinsumer only provides the data of the record, so the partition key is
+// known from the AttributeKinesisPartitionKey of the message, which the kinesis output uses as partition key, too.
 func (s kinesisMessageHandler) Handle(rawMessage []byte) error {
 	msg := Message{}
 	err := json.Unmarshal(rawMessage, &msg)
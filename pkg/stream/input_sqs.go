@@ -3,15 +3,24 @@ package stream
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
 	"github.com/justtrackio/gosoline/pkg/coffin"
+	"github.com/justtrackio/gosoline/pkg/kernel"
 	"github.com/justtrackio/gosoline/pkg/log"
 )
 
-var _ AcknowledgeableInput = &sqsInput{}
+var (
+	_ AcknowledgeableInput = &sqsInput{}
+	_ RetryableInput       = &sqsInput{}
+	_ kernel.HealthChecker = &sqsInput{}
+)
 
 type SqsInputSettings struct {
 	cfg.AppId
@@ -43,6 +52,7 @@ type sqsInput struct {
 	queue       sqs.Queue
 	settings    *SqsInputSettings
 	unmarshaler UnmarshallerFunc
+	health      healthState
 
 	cfn     coffin.Coffin
 	channel chan *Message
@@ -61,7 +71,6 @@ func NewSqsInput(ctx context.Context, config cfg.Config, logger log.Logger, sett
 		ClientName:        settings.ClientName,
 	}
 
-	var ok bool
 	var err error
 	var queue sqs.Queue
 	var unmarshaller UnmarshallerFunc
@@ -70,8 +79,8 @@ func NewSqsInput(ctx context.Context, config cfg.Config, logger log.Logger, sett
 		return nil, fmt.Errorf("can not create queue: %w", err)
 	}
 
-	if unmarshaller, ok = unmarshallers[settings.Unmarshaller]; !ok {
-		return nil, fmt.Errorf("unknown unmarshaller %s", settings.Unmarshaller)
+	if unmarshaller, err = ProvideUnmarshaller(settings.Unmarshaller); err != nil {
+		return nil, err
 	}
 
 	return NewSqsInputWithInterfaces(logger, queue, unmarshaller, settings), nil
@@ -123,6 +132,8 @@ func (i *sqsInput) runLoop(ctx context.Context) error {
 		}
 
 		sqsMessages, err := i.queue.Receive(ctx, i.settings.MaxNumberOfMessages, i.settings.WaitTime)
+		i.health.set(err)
+
 		if err != nil {
 			i.logger.Error("could not get messages from sqs: %w", err)
 			continue
@@ -142,6 +153,14 @@ func (i *sqsInput) runLoop(ctx context.Context) error {
 			msg.Attributes[AttributeSqsMessageId] = *sqsMessage.MessageId
 			msg.Attributes[AttributeSqsReceiptHandle] = *sqsMessage.ReceiptHandle
 
+			if receiveCount, err := strconv.Atoi(sqsMessage.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]); err == nil {
+				msg.Attributes[AttributeSqsReceiveCount] = receiveCount
+			}
+
+			if groupId, ok := sqsMessage.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]; ok {
+				msg.Attributes[AttributeSqsMessageGroupId] = groupId
+			}
+
 			i.channel <- msg
 		}
 	}
@@ -152,23 +171,12 @@ func (i *sqsInput) Stop() {
 }
 
 func (i *sqsInput) Ack(ctx context.Context, msg *Message) error {
-	var ok bool
-	var receiptHandleInterface interface{}
-	var receiptHandleString string
-
-	if receiptHandleInterface, ok = msg.Attributes[AttributeSqsReceiptHandle]; !ok {
-		return fmt.Errorf("the message has no attribute %s", AttributeSqsReceiptHandle)
-	}
-
-	if receiptHandleString, ok = receiptHandleInterface.(string); !ok {
-		return fmt.Errorf("the attribute %s of the message should be string but instead is %T", AttributeSqsReceiptHandle, receiptHandleInterface)
+	receiptHandle, err := getReceiptHandle(msg)
+	if err != nil {
+		return err
 	}
 
-	if receiptHandleString == "" {
-		return fmt.Errorf("the attribute %s of the message should not be empty", AttributeSqsReceiptHandle)
-	}
-
-	return i.queue.DeleteMessage(ctx, receiptHandleString)
+	return i.queue.DeleteMessage(ctx, receiptHandle)
 }
 
 func (i *sqsInput) AckBatch(ctx context.Context, msgs []*Message) error {
@@ -212,6 +220,25 @@ func (i *sqsInput) AckBatch(ctx context.Context, msgs []*Message) error {
 	return multiError.ErrorOrNil()
 }
 
+// Retry makes the message visible again after the given delay by changing its visibility timeout.
+func (i *sqsInput) Retry(ctx context.Context, msg *Message, delay time.Duration) error {
+	receiptHandle, err := getReceiptHandle(msg)
+	if err != nil {
+		return err
+	}
+
+	return i.queue.ChangeMessageVisibility(ctx, receiptHandle, int32(delay.Seconds()))
+}
+
+// IsHealthy reports the input as unhealthy as long as receiving messages from the queue fails.
+func (i *sqsInput) IsHealthy(_ context.Context) error {
+	if err := i.health.get(); err != nil {
+		return fmt.Errorf("can not receive messages from queue %s: %w", i.queue.GetName(), err)
+	}
+
+	return nil
+}
+
 func (i *sqsInput) SetUnmarshaler(unmarshaler UnmarshallerFunc) {
 	i.unmarshaler = unmarshaler
 }
@@ -223,3 +250,23 @@ func (i *sqsInput) GetQueueUrl() string {
 func (i *sqsInput) GetQueueArn() string {
 	return i.queue.GetArn()
 }
+
+func getReceiptHandle(msg *Message) (string, error) {
+	var ok bool
+	var receiptHandleInterface interface{}
+	var receiptHandleString string
+
+	if receiptHandleInterface, ok = msg.Attributes[AttributeSqsReceiptHandle]; !ok {
+		return "", fmt.Errorf("the message has no attribute %s", AttributeSqsReceiptHandle)
+	}
+
+	if receiptHandleString, ok = receiptHandleInterface.(string); !ok {
+		return "", fmt.Errorf("the attribute %s of the message should be string but instead is %T", AttributeSqsReceiptHandle, receiptHandleInterface)
+	}
+
+	if receiptHandleString == "" {
+		return "", fmt.Errorf("the attribute %s of the message should not be empty", AttributeSqsReceiptHandle)
+	}
+
+	return receiptHandleString, nil
+}
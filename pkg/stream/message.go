@@ -5,13 +5,19 @@ import (
 )
 
 const (
-	AttributeSqsMessageId     = "sqsMessageId"
-	AttributeSqsReceiptHandle = "sqsReceiptHandle"
+	AttributeSqsMessageId        = "sqsMessageId"
+	AttributeSqsMessageGroupId   = "sqsMessageGroupId"
+	AttributeSqsReceiptHandle    = "sqsReceiptHandle"
+	AttributeSqsReceiveCount     = "sqsReceiveCount"
+	AttributeKinesisPartitionKey = "kinesisPartitionKey"
 )
 
 type Message struct {
 	Attributes map[string]interface{} `json:"attributes"`
 	Body       string                 `json:"body"`
+
+	// decoded is set if the message was already decoded before it reached a runner of a consumer
+	decoded *decodedMessage
 }
 
 func (m *Message) GetAttributes() map[string]interface{} {
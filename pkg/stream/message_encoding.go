@@ -12,6 +12,14 @@ type EncodeHandler interface {
 	Decode(ctx context.Context, data interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error)
 }
 
+// A BodyEncodeHandler is an EncodeHandler which additionally gets access to the body of a message. EncodeBody is
+// called with the encoded and compressed body, DecodeBody before the body is decompressed and decoded.
+type BodyEncodeHandler interface {
+	EncodeHandler
+	EncodeBody(ctx context.Context, body []byte, attributes map[string]interface{}) ([]byte, map[string]interface{}, error)
+	DecodeBody(ctx context.Context, body []byte, attributes map[string]interface{}) ([]byte, map[string]interface{}, error)
+}
+
 var defaultEncodeHandlers = make([]EncodeHandler, 0)
 
 func AddDefaultEncodeHandler(handler EncodeHandler) {
@@ -79,6 +87,17 @@ func (e *messageEncoder) Encode(ctx context.Context, data interface{}, attribute
 		}
 	}
 
+	for _, handler := range e.encodeHandlers {
+		bodyHandler, ok := handler.(BodyEncodeHandler)
+		if !ok {
+			continue
+		}
+
+		if body, attributes, err = bodyHandler.EncodeBody(ctx, body, attributes); err != nil {
+			return nil, fmt.Errorf("can not apply encoding handler on message body: %w", err)
+		}
+	}
+
 	msg := &Message{
 		Attributes: attributes,
 		Body:       string(body),
@@ -132,9 +151,25 @@ func (e *messageEncoder) Decode(ctx context.Context, msg *Message, out interface
 	var err error
 	var body []byte
 
-	attributes := msg.Attributes
+	// copy the attributes so the message itself stays untouched and can be written again (e.g. for a retry)
+	attributes := make(map[string]interface{}, len(msg.Attributes))
+	for key, value := range msg.Attributes {
+		attributes[key] = value
+	}
+
 	body = []byte(msg.Body)
 
+	for i := len(e.encodeHandlers) - 1; i >= 0; i-- {
+		bodyHandler, ok := e.encodeHandlers[i].(BodyEncodeHandler)
+		if !ok {
+			continue
+		}
+
+		if body, attributes, err = bodyHandler.DecodeBody(ctx, body, attributes); err != nil {
+			return ctx, attributes, fmt.Errorf("can not apply encoding handler on message body: %w", err)
+		}
+	}
+
 	if body, err = e.decompressBody(attributes, body); err != nil {
 		return ctx, attributes, err
 	}
@@ -2,8 +2,11 @@ package stream
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -17,6 +20,7 @@ import (
 	"github.com/justtrackio/gosoline/pkg/exec"
 	"github.com/justtrackio/gosoline/pkg/log"
 	"github.com/justtrackio/gosoline/pkg/uuid"
+	"github.com/spf13/cast"
 )
 
 const kinesisBatchSizeMax = 500
@@ -87,15 +91,26 @@ func (o *kinesisOutput) Write(ctx context.Context, batch []WritableMessage) erro
 		"kinesis_write_request_id": o.uuidGen.NewV4(),
 	})
 
-	var err, errs error
-	var chunks Chunks
+	var errs error
+	records := make([]*kinesis.PutRecordsRequestEntry, 0, len(batch))
+
+	for _, msg := range batch {
+		data, err := msg.MarshalToBytes()
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("can not marshal message: %w", err))
+			continue
+		}
 
-	if chunks, err = BuildChunks(batch, kinesisBatchSizeMax); err != nil {
-		return fmt.Errorf("could not build batch for messages: %w", err)
+		records = append(records, &kinesis.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(getKinesisPartitionKey(msg, data)),
+		})
 	}
 
-	for _, chunk := range chunks {
-		if err = o.writeBatch(ctx, chunk); err != nil {
+	for i := 0; i < len(records); i += kinesisBatchSizeMax {
+		end := int(math.Min(float64(i+kinesisBatchSizeMax), float64(len(records))))
+
+		if err := o.writeBatch(ctx, records[i:end]); err != nil {
 			errs = multierror.Append(errs, err)
 		}
 	}
@@ -107,18 +122,8 @@ func (o *kinesisOutput) Write(ctx context.Context, batch []WritableMessage) erro
 	return nil
 }
 
-func (o *kinesisOutput) writeBatch(ctx context.Context, batch [][]byte) error {
+func (o *kinesisOutput) writeBatch(ctx context.Context, records []*kinesis.PutRecordsRequestEntry) error {
 	var err error
-	records := make([]*kinesis.PutRecordsRequestEntry, 0, len(batch))
-
-	for _, data := range batch {
-		req := &kinesis.PutRecordsRequestEntry{
-			Data:         data,
-			PartitionKey: aws.String(o.uuidGen.NewV4()),
-		}
-
-		records = append(records, req)
-	}
 
 	_, err = o.batchExec.Execute(ctx, func(ctx context.Context) (interface{}, error) {
 		records, err = o.putRecordsAndCollectFailed(ctx, records)
@@ -161,6 +166,20 @@ func (o *kinesisOutput) putRecordsAndCollectFailed(ctx context.Context, records
 	return nil, nil
 }
 
+// getKinesisPartitionKey returns the AttributeKinesisPartitionKey of the message. Without it, the key is derived from
+// the message itself, so the same message always ends up in the same shard, e.g. if it is written again.
+func getKinesisPartitionKey(msg WritableMessage, data []byte) string {
+	if key, ok := getAttributes(msg)[AttributeKinesisPartitionKey]; ok {
+		if keyString, err := cast.ToStringE(key); err == nil && keyString != "" {
+			return keyString
+		}
+	}
+
+	sum := md5.Sum(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
 type RecordsFailedError struct {
 	total  []*kinesis.PutRecordsRequestEntry
 	failed []*kinesis.PutRecordsRequestEntry
@@ -2,6 +2,8 @@ package stream_test
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -53,3 +55,35 @@ func TestWriter_WriteEvents(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestWriter_WritePartitionKeys(t *testing.T) {
+	kinesisClient := new(cloudMocks.KinesisAPI)
+	executor := gosoAws.NewTestableExecutor(&kinesisClient.Mock)
+
+	keyed := stream.NewJsonMessage(`"1"`, map[string]interface{}{
+		stream.AttributeKinesisPartitionKey: "entity",
+	})
+	unkeyed := stream.NewJsonMessage(`"2"`)
+
+	unkeyedData, err := unkeyed.MarshalToBytes()
+	assert.NoError(t, err)
+
+	var partitionKeys []string
+	executor.ExpectExecution("PutRecordsRequest", mock.MatchedBy(func(input *kinesis.PutRecordsInput) bool {
+		for _, record := range input.Records {
+			partitionKeys = append(partitionKeys, aws.StringValue(record.PartitionKey))
+		}
+
+		return true
+	}), &kinesis.PutRecordsOutput{Records: []*kinesis.PutRecordsResultEntry{}}, nil)
+
+	writer := stream.NewKinesisOutputWithInterfaces(logMocks.NewLoggerMockedAll(), kinesisClient, executor, &stream.KinesisOutputSettings{
+		StreamName: "streamName",
+	})
+
+	err = writer.Write(context.Background(), []stream.WritableMessage{keyed, unkeyed})
+	assert.NoError(t, err)
+
+	unkeyedSum := md5.Sum(unkeyedData)
+	assert.Equal(t, []string{"entity", hex.EncodeToString(unkeyedSum[:])}, partitionKeys, "the partition keys have to be stable")
+}
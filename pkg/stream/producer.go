@@ -14,6 +14,7 @@ type ProducerSettings struct {
 	Encoding    EncodingType           `cfg:"encoding"`
 	Compression CompressionType        `cfg:"compression" default:"none"`
 	Daemon      ProducerDaemonSettings `cfg:"daemon"`
+	ClaimCheck  ClaimCheckSettings     `cfg:"claim_check"`
 }
 
 type Producer interface {
@@ -48,6 +49,15 @@ func NewProducer(ctx context.Context, config cfg.Config, logger log.Logger, name
 	encodeHandlers = append(encodeHandlers, defaultEncodeHandlers...)
 	encodeHandlers = append(encodeHandlers, handlers...)
 
+	if settings.ClaimCheck.Enabled {
+		claimCheck, err := NewClaimCheckHandler(ctx, config, logger, &settings.ClaimCheck)
+		if err != nil {
+			return nil, fmt.Errorf("can not create claim check handler for producer %s: %w", name, err)
+		}
+
+		encodeHandlers = append(encodeHandlers, claimCheck)
+	}
+
 	encoder := NewMessageEncoder(&MessageEncoderSettings{
 		Encoding:       settings.Encoding,
 		Compression:    settings.Compression,
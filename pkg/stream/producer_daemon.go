@@ -2,6 +2,7 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -21,6 +22,9 @@ const (
 	metricNameBatchSize     = "BatchSize"
 	metricNameAggregateSize = "AggregateSize"
 	metricNameIdleDuration  = "IdleDuration"
+	metricNameSpilled       = "SpilledCount"
+	metricNameSpillDropped  = "SpillDroppedCount"
+	metricNameReplayed      = "ReplayedCount"
 )
 
 var (
@@ -58,6 +62,8 @@ type ProducerDaemonSettings struct {
 	AggregationMaxSize int `cfg:"aggregation_max_size" default:"65536" validate:"min=0"`
 	// Additional attributes we append to each message
 	MessageAttributes map[string]interface{} `cfg:"message_attributes"`
+	// Buffer batches on local disk if they can't be written to the output
+	Spill ProducerDaemonSpillSettings `cfg:"spill"`
 }
 
 type producerDaemon struct {
@@ -74,6 +80,8 @@ type producerDaemon struct {
 	tickerFactory clock.TickerFactory
 	ticker        clock.Ticker
 	settings      ProducerDaemonSettings
+	health        healthState
+	spill         *producerDaemonSpill
 }
 
 func ResetProducerDaemons() {
@@ -109,7 +117,7 @@ func NewProducerDaemon(ctx context.Context, config cfg.Config, logger log.Logger
 		return nil, fmt.Errorf("can not create output for producer daemon %s: %w", name, err)
 	}
 
-	defaultMetrics := getProducerDaemonDefaultMetrics(name)
+	defaultMetrics := getProducerDaemonDefaultMetrics(name, settings.Daemon)
 	metric := metric.NewDaemonWriter(defaultMetrics...)
 
 	aggregator, err := NewProducerDaemonAggregator(settings.Daemon, settings.Compression)
@@ -121,6 +129,12 @@ func NewProducerDaemon(ctx context.Context, config cfg.Config, logger log.Logger
 }
 
 func NewProducerDaemonWithInterfaces(logger log.Logger, metric metric.Writer, aggregator ProducerDaemonAggregator, output Output, tickerFactory clock.TickerFactory, name string, settings ProducerDaemonSettings) *producerDaemon {
+	var spill *producerDaemonSpill
+
+	if settings.Spill.Enabled {
+		spill = newProducerDaemonSpill(logger, name, settings.Spill)
+	}
+
 	return &producerDaemon{
 		name:          name,
 		logger:        logger,
@@ -131,6 +145,7 @@ func NewProducerDaemonWithInterfaces(logger log.Logger, metric metric.Writer, ag
 		output:        output,
 		tickerFactory: tickerFactory,
 		settings:      settings,
+		spill:         spill,
 	}
 }
 
@@ -144,6 +159,13 @@ func (d *producerDaemon) Run(kernelCtx context.Context) error {
 	d.ticker = d.tickerFactory(d.settings.Interval)
 	d.lck.Unlock()
 
+	// move the spilled messages of the last run aside before any batch can be spilled, so we only replay these
+	if d.spill != nil {
+		if err := d.spill.MoveAside(); err != nil {
+			d.logger.Error("can not prepare the replay of the spilled messages of producer %s: %w", d.name, err)
+		}
+	}
+
 	cfn := coffin.New()
 	// start the output loops before the ticker look - the output loop can't terminate until
 	// we call close, while the ticker can if the context is already canceled
@@ -153,6 +175,10 @@ func (d *producerDaemon) Run(kernelCtx context.Context) error {
 
 	cfn.GoWithContextf(kernelCtx, d.tickerLoop, "panic during running the ticker loop")
 
+	if d.spill != nil {
+		cfn.GoWithContextf(kernelCtx, d.replaySpill, "panic during replaying the spilled messages")
+	}
+
 	select {
 	case <-cfn.Dying():
 		if err := d.close(); err != nil {
@@ -167,6 +193,15 @@ func (d *producerDaemon) Run(kernelCtx context.Context) error {
 	return cfn.Wait()
 }
 
+// IsHealthy reports the producer daemon as unhealthy as long as writing to its output fails.
+func (d *producerDaemon) IsHealthy(_ context.Context) error {
+	if err := d.health.get(); err != nil {
+		return fmt.Errorf("can not write messages to the output of producer %s: %w", d.name, err)
+	}
+
+	return nil
+}
+
 func (d *producerDaemon) WriteOne(ctx context.Context, msg WritableMessage) error {
 	return d.Write(ctx, []WritableMessage{msg})
 }
@@ -335,7 +370,10 @@ func (d *producerDaemon) outputLoop(ctx context.Context) error {
 		}
 
 		// no need to have some delayed cancel context or so here - if you need this, your output should've already provided that
-		if err := d.output.Write(ctx, batch); err != nil {
+		err := d.output.Write(ctx, batch)
+		d.health.set(err)
+
+		if err != nil {
 			if exec.IsRequestCanceled(err) {
 				// we were not fast enough to write all messages and have just lost some messages.
 				// however, if this would be a problem, you shouldn't be using the producer daemon at all.
@@ -343,6 +381,8 @@ func (d *producerDaemon) outputLoop(ctx context.Context) error {
 			} else {
 				d.logger.Error("can not write messages to output in producer %s: %w", d.name, err)
 			}
+
+			d.spillBatch(batch)
 		}
 
 		d.writeMetricBatchSize(len(batch))
@@ -350,6 +390,62 @@ func (d *producerDaemon) outputLoop(ctx context.Context) error {
 	}
 }
 
+// spillBatch writes a batch which could not be written to the output to the spill file, if enabled.
+func (d *producerDaemon) spillBatch(batch []WritableMessage) {
+	if d.spill == nil {
+		return
+	}
+
+	err := d.spill.Write(batch)
+
+	if errors.Is(err, ErrSpillFull) {
+		d.logger.Error("dropping %d messages of producer %s: %w", len(batch), d.name, err)
+		d.writeMetricCount(metricNameSpillDropped, len(batch))
+
+		return
+	}
+
+	if err != nil {
+		d.logger.Error("can not spill %d messages of producer %s: %w", len(batch), d.name, err)
+		d.writeMetricCount(metricNameSpillDropped, len(batch))
+
+		return
+	}
+
+	d.logger.Warn("spilled %d messages of producer %s to disk", len(batch), d.name)
+	d.writeMetricCount(metricNameSpilled, len(batch))
+}
+
+// replaySpill writes the messages spilled during the last run to the output.
+func (d *producerDaemon) replaySpill(ctx context.Context) error {
+	replayed, err := d.spill.Replay(ctx, d.settings.BatchSize, d.output.Write)
+	d.writeMetricCount(metricNameReplayed, replayed)
+
+	if err != nil {
+		d.logger.Error("can not replay the spilled messages of producer %s: %w", d.name, err)
+
+		return nil
+	}
+
+	if replayed > 0 {
+		d.logger.Info("replayed %d spilled messages of producer %s", replayed, d.name)
+	}
+
+	return nil
+}
+
+func (d *producerDaemon) writeMetricCount(metricName string, count int) {
+	d.metric.WriteOne(&metric.Datum{
+		Priority:   metric.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: map[string]string{
+			"ProducerDaemon": d.name,
+		},
+		Unit:  metric.UnitCount,
+		Value: float64(count),
+	})
+}
+
 func (d *producerDaemon) writeMetricMessageCount(count int) {
 	d.metric.WriteOne(&metric.Datum{
 		MetricName: metricNameMessageCount,
@@ -396,8 +492,8 @@ func (d *producerDaemon) writeMetricIdleDuration(idleDuration time.Duration) {
 	})
 }
 
-func getProducerDaemonDefaultMetrics(name string) metric.Data {
-	return metric.Data{
+func getProducerDaemonDefaultMetrics(name string, settings ProducerDaemonSettings) metric.Data {
+	defaults := metric.Data{
 		{
 			Priority:   metric.PriorityHigh,
 			MetricName: metricNameMessageCount,
@@ -426,6 +522,24 @@ func getProducerDaemonDefaultMetrics(name string) metric.Data {
 			Value: 0.0,
 		},
 	}
+
+	if !settings.Spill.Enabled {
+		return defaults
+	}
+
+	for _, metricName := range []string{metricNameSpilled, metricNameSpillDropped, metricNameReplayed} {
+		defaults = append(defaults, &metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricName,
+			Dimensions: map[string]string{
+				"ProducerDaemon": name,
+			},
+			Unit:  metric.UnitCount,
+			Value: 0.0,
+		})
+	}
+
+	return defaults
 }
 
 func BuildAggregateMessage(aggregateBody string, attributes ...map[string]interface{}) *Message {
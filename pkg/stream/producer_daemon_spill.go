@@ -0,0 +1,228 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+var ErrSpillFull = errors.New("the spill file reached its maximum size")
+
+type ProducerDaemonSpillSettings struct {
+	// Write batches to a file on local disk if the output fails to accept them. Spilled messages are written to the
+	// output again on the next start of the daemon.
+	Enabled bool `cfg:"enabled" default:"false"`
+	// Directory of the spill files. Every daemon writes to a file named after the producer.
+	Path string `cfg:"path" default:"/tmp/gosoline/producer_daemon"`
+	// Maximum size of a spill file in bytes. Batches which don't fit anymore are dropped.
+	MaxSize int64 `cfg:"max_size" default:"104857600" validate:"min=1"`
+}
+
+// producerDaemonSpill stores messages in the same format as the file output, so a spill file can be inspected or
+// consumed with the file input.
+type producerDaemonSpill struct {
+	lck      sync.Mutex
+	logger   log.Logger
+	filename string
+	replay   string
+	size     int64
+	settings ProducerDaemonSpillSettings
+}
+
+func newProducerDaemonSpill(logger log.Logger, name string, settings ProducerDaemonSpillSettings) *producerDaemonSpill {
+	return &producerDaemonSpill{
+		logger:   logger,
+		filename: filepath.Join(settings.Path, fmt.Sprintf("%s.jsonl", name)),
+		replay:   filepath.Join(settings.Path, fmt.Sprintf("%s.jsonl.replay", name)),
+		size:     -1,
+		settings: settings,
+	}
+}
+
+// Write appends the batch to the spill file. It returns ErrSpillFull if the batch would exceed the maximum size.
+func (s *producerDaemonSpill) Write(batch []WritableMessage) error {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	buf := &bytes.Buffer{}
+
+	for _, msg := range batch {
+		data, err := msg.MarshalToBytes()
+		if err != nil {
+			return fmt.Errorf("can not marshal message: %w", err)
+		}
+
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := s.initSize(); err != nil {
+		return err
+	}
+
+	if s.size+int64(buf.Len()) > s.settings.MaxSize {
+		return ErrSpillFull
+	}
+
+	if err := os.MkdirAll(s.settings.Path, 0o755); err != nil {
+		return fmt.Errorf("can not create spill directory %s: %w", s.settings.Path, err)
+	}
+
+	file, err := os.OpenFile(s.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("can not open spill file %s: %w", s.filename, err)
+	}
+
+	written, err := file.Write(buf.Bytes())
+	s.size += int64(written)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not write to spill file %s: %w", s.filename, err)
+	}
+
+	return nil
+}
+
+// Replay writes all messages moved aside by MoveAside in batches of batchSize. It returns the number of replayed
+// messages.
+func (s *producerDaemonSpill) Replay(ctx context.Context, batchSize int, write func(ctx context.Context, batch []WritableMessage) error) (int, error) {
+	file, err := os.Open(s.replay)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("can not open spill file %s: %w", s.replay, err)
+	}
+
+	replayed, err := s.replayFile(ctx, file, batchSize, write)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return replayed, err
+	}
+
+	if err = os.Remove(s.replay); err != nil {
+		return replayed, fmt.Errorf("can not remove spill file %s: %w", s.replay, err)
+	}
+
+	return replayed, nil
+}
+
+func (s *producerDaemonSpill) replayFile(ctx context.Context, file io.Reader, batchSize int, write func(ctx context.Context, batch []WritableMessage) error) (int, error) {
+	replayed := 0
+	reader := bufio.NewReader(file)
+	batch := make([]WritableMessage, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := write(ctx, batch); err != nil {
+			s.logger.Warn("can not replay %d spilled messages, spilling them again: %s", len(batch), err)
+
+			if err = s.Write(batch); err != nil {
+				return fmt.Errorf("can not spill messages again: %w", err)
+			}
+		} else {
+			replayed += len(batch)
+		}
+
+		batch = make([]WritableMessage, 0, batchSize)
+
+		return nil
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			msg := &Message{}
+
+			if unmarshalErr := msg.UnmarshalFromBytes(line); unmarshalErr != nil {
+				s.logger.Error("can not unmarshal spilled message: %w", unmarshalErr)
+			} else {
+				batch = append(batch, msg)
+			}
+		}
+
+		if len(batch) >= batchSize {
+			if flushErr := flush(); flushErr != nil {
+				return replayed, flushErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return replayed, fmt.Errorf("can not read spill file: %w", err)
+		}
+	}
+
+	return replayed, flush()
+}
+
+// MoveAside renames the spill file to the replay file, so batches failing during the replay or afterwards are
+// spilled to a new file. An existing replay file is left over from an interrupted replay and is replayed first, the
+// spill file will be replayed on the next start then.
+func (s *producerDaemonSpill) MoveAside() error {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	if _, err := os.Stat(s.replay); err == nil {
+		return nil
+	}
+
+	err := os.Rename(s.filename, s.replay)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not move spill file %s aside: %w", s.filename, err)
+	}
+
+	s.size = 0
+
+	return nil
+}
+
+func (s *producerDaemonSpill) initSize() error {
+	if s.size >= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(s.filename)
+
+	if errors.Is(err, os.ErrNotExist) {
+		s.size = 0
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not stat spill file %s: %w", s.filename, err)
+	}
+
+	s.size = info.Size()
+
+	return nil
+}
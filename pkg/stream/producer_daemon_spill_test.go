@@ -0,0 +1,166 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSpillingProducerDaemon(t *testing.T, output stream.Output, path string, maxSize int64) (stream.Output, func(ctx context.Context) error) {
+	settings := stream.ProducerDaemonSettings{
+		Enabled:         true,
+		Interval:        time.Hour,
+		BufferSize:      1,
+		RunnerCount:     1,
+		BatchSize:       2,
+		AggregationSize: 1,
+		Spill: stream.ProducerDaemonSpillSettings{
+			Enabled: true,
+			Path:    path,
+			MaxSize: maxSize,
+		},
+	}
+
+	aggregator, err := stream.NewProducerDaemonAggregator(settings, stream.CompressionNone)
+	assert.NoError(t, err)
+
+	tickerFactory := func(_ time.Duration) clock.Ticker {
+		return clock.NewFakeTicker()
+	}
+
+	daemon := stream.NewProducerDaemonWithInterfaces(logMocks.NewLoggerMockedAll(), metricMocks.NewWriterMockedAll(), aggregator, output, tickerFactory, "spilling", settings)
+
+	return daemon, daemon.Run
+}
+
+func readSpilledMessages(t *testing.T, filename string) []*stream.Message {
+	input := stream.NewFileInputWithInterfaces(logMocks.NewLoggerMockedAll(), stream.FileSettings{
+		Filename: filename,
+	})
+
+	go func() {
+		assert.NoError(t, input.Run(context.Background()))
+	}()
+
+	messages := make([]*stream.Message, 0)
+	for msg := range input.Data() {
+		messages = append(messages, msg)
+	}
+
+	return messages
+}
+
+func TestProducerDaemon_Spill(t *testing.T) {
+	path := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	output := new(streamMocks.Output)
+	output.On("Write", mock.Anything, mock.Anything).Return(fmt.Errorf("output unavailable")).Twice()
+
+	daemon, run := newSpillingProducerDaemon(t, output, path, 1024)
+
+	done := make(chan error)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	err := daemon.Write(context.Background(), []stream.WritableMessage{
+		stream.NewMessage(`{"id":1}`),
+		stream.NewMessage(`{"id":2}`),
+		stream.NewMessage(`{"id":3}`),
+	})
+	assert.NoError(t, err)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	messages := readSpilledMessages(t, filepath.Join(path, "spilling.jsonl"))
+	assert.Equal(t, []*stream.Message{
+		stream.NewMessage(`{"id":1}`),
+		stream.NewMessage(`{"id":2}`),
+		stream.NewMessage(`{"id":3}`),
+	}, messages)
+
+	output.AssertExpectations(t)
+}
+
+func TestProducerDaemon_SpillFull(t *testing.T) {
+	path := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	output := new(streamMocks.Output)
+	output.On("Write", mock.Anything, mock.Anything).Return(fmt.Errorf("output unavailable")).Once()
+
+	daemon, run := newSpillingProducerDaemon(t, output, path, 16)
+
+	done := make(chan error)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	err := daemon.Write(context.Background(), []stream.WritableMessage{
+		stream.NewMessage(`{"id":1}`),
+	})
+	assert.NoError(t, err)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	_, err = os.Stat(filepath.Join(path, "spilling.jsonl"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "the batch should have been dropped")
+
+	output.AssertExpectations(t)
+}
+
+func TestProducerDaemon_Replay(t *testing.T) {
+	path := t.TempDir()
+	filename := filepath.Join(path, "spilling.jsonl")
+
+	spilled := []stream.WritableMessage{
+		stream.NewMessage(`{"id":1}`),
+		stream.NewMessage(`{"id":2}`),
+		stream.NewMessage(`{"id":3}`),
+	}
+
+	fileOutput := stream.NewFileOutput(nil, logMocks.NewLoggerMockedAll(), &stream.FileOutputSettings{
+		Filename: filename,
+	})
+	assert.NoError(t, fileOutput.Write(context.Background(), spilled))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	replayed := make(chan struct{})
+
+	output := new(streamMocks.Output)
+	output.On("Write", mock.Anything, spilled[:2]).Return(nil).Once()
+	output.On("Write", mock.Anything, spilled[2:]).Return(nil).Once().Run(func(args mock.Arguments) {
+		close(replayed)
+	})
+
+	_, run := newSpillingProducerDaemon(t, output, path, 1024)
+
+	done := make(chan error)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	<-replayed
+	cancel()
+	assert.NoError(t, <-done)
+
+	entries, err := os.ReadDir(path)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "the spill file should have been removed after the replay")
+
+	output.AssertExpectations(t)
+}
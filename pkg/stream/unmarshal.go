@@ -21,6 +21,16 @@ var unmarshallers = map[string]UnmarshallerFunc{
 	UnmarshallerSns: SnsUnmarshaller,
 }
 
+func ProvideUnmarshaller(name string) (UnmarshallerFunc, error) {
+	unmarshaller, ok := unmarshallers[name]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown unmarshaller %s", name)
+	}
+
+	return unmarshaller, nil
+}
+
 func MessageUnmarshaller(data *string) (*Message, error) {
 	msg := Message{}
 	err := msg.UnmarshalFromString(*data)
@@ -0,0 +1,215 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	"github.com/justtrackio/gosoline/pkg/uuid"
+)
+
+const maxKeyClaimAttempts = 3
+
+type enqueueOptions struct {
+	delay time.Duration
+	key   string
+}
+
+type EnqueueOption func(opts *enqueueOptions)
+
+// WithDelay postpones the processing of the task by the given duration.
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(opts *enqueueOptions) {
+		opts.delay = delay
+	}
+}
+
+// WithKey makes the task unique: as long as a task with the same key didn't finish, enqueueing another one
+// returns the id of the existing task instead. Requires a status store.
+func WithKey(key string) EnqueueOption {
+	return func(opts *enqueueOptions) {
+		opts.key = key
+	}
+}
+
+// A Queue enqueues tasks to be processed by a worker and provides the status of enqueued tasks.
+//go:generate mockery --name Queue
+type Queue interface {
+	Enqueue(ctx context.Context, taskType string, payload interface{}, opts ...EnqueueOption) (string, error)
+	GetStatus(ctx context.Context, id string) (*TaskStatus, bool, error)
+}
+
+type queue struct {
+	logger   log.Logger
+	clock    clock.Clock
+	uuid     uuid.Uuid
+	producer stream.Producer
+	store    StatusStore
+	settings *Settings
+}
+
+// NewQueue creates a queue with the settings configured at taskqueue.<name>.
+func NewQueue(ctx context.Context, config cfg.Config, logger log.Logger, name string) (Queue, error) {
+	var err error
+	var producer stream.Producer
+	var store StatusStore
+
+	settings := readSettings(config, name)
+	logger = logger.WithChannel("taskqueue")
+
+	if producer, err = stream.NewProducer(ctx, config, logger, settings.Producer); err != nil {
+		return nil, fmt.Errorf("can not create producer %s: %w", settings.Producer, err)
+	}
+
+	if store, err = NewStatusStore(ctx, config, logger, settings.StatusStore); err != nil {
+		return nil, fmt.Errorf("can not create status store of task queue %s: %w", name, err)
+	}
+
+	return NewQueueWithInterfaces(logger, clock.Provider, uuid.New(), producer, store, settings), nil
+}
+
+func NewQueueWithInterfaces(logger log.Logger, clock clock.Clock, uuid uuid.Uuid, producer stream.Producer, store StatusStore, settings *Settings) Queue {
+	return &queue{
+		logger:   logger,
+		clock:    clock,
+		uuid:     uuid,
+		producer: producer,
+		store:    store,
+		settings: settings,
+	}
+}
+
+func (q *queue) Enqueue(ctx context.Context, taskType string, payload interface{}, opts ...EnqueueOption) (string, error) {
+	options := &enqueueOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.key != "" && q.settings.StatusStore == "" {
+		return "", fmt.Errorf("can not enqueue task %s with key %s: unique keys require a status store", taskType, options.key)
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("can not marshal payload of task %s: %w", taskType, err)
+	}
+
+	now := q.clock.Now()
+	task := &Task{
+		Id:         q.uuid.NewV4(),
+		Type:       taskType,
+		Key:        options.key,
+		Payload:    encodedPayload,
+		Attempt:    1,
+		EnqueuedAt: now,
+		NotBefore:  now.Add(options.delay),
+	}
+
+	if task.Key != "" {
+		existingId, err := q.claimKey(ctx, task.Key, task.Id)
+		if err != nil {
+			return "", err
+		}
+
+		if existingId != "" {
+			return existingId, nil
+		}
+	}
+
+	status := &TaskStatus{
+		Id:         task.Id,
+		Type:       task.Type,
+		Key:        task.Key,
+		State:      StatePending,
+		EnqueuedAt: now,
+		UpdatedAt:  now,
+	}
+
+	if err = q.store.Put(ctx, status); err != nil {
+		q.releaseKey(ctx, task)
+
+		return "", fmt.Errorf("can not store status of task %s: %w", task.Id, err)
+	}
+
+	if err = writeTask(ctx, q.producer, task, options.delay); err != nil {
+		q.releaseKey(ctx, task)
+
+		return "", err
+	}
+
+	q.logger.WithContext(ctx).Debug("enqueued task %s of type %s", task.Id, task.Type)
+
+	return task.Id, nil
+}
+
+// claimKey assigns the unique key to the task by a conditional put, so concurrent calls can't enqueue two tasks with
+// the same key. If an unfinished task holds the key already, its id is returned instead.
+func (q *queue) claimKey(ctx context.Context, key string, id string) (string, error) {
+	previousId := ""
+
+	for i := 0; i < maxKeyClaimAttempts; i++ {
+		claimed, err := q.store.PutKey(ctx, key, id, previousId)
+		if err != nil {
+			return "", fmt.Errorf("can not store key of task %s: %w", id, err)
+		}
+
+		if claimed {
+			return "", nil
+		}
+
+		status, found, err := q.store.GetByKey(ctx, key)
+		if err != nil {
+			return "", fmt.Errorf("can not check for existing task with key %s: %w", key, err)
+		}
+
+		if found && !status.IsFinal() {
+			return status.Id, nil
+		}
+
+		// the key was released in the meantime or is left behind by a finished task, so it can be taken over
+		previousId = ""
+		if found {
+			previousId = status.Id
+		}
+	}
+
+	return "", fmt.Errorf("can not claim key %s of task %s: the key changed its owner %d times", key, id, maxKeyClaimAttempts)
+}
+
+// releaseKey frees the key of a task which couldn't be enqueued.
+func (q *queue) releaseKey(ctx context.Context, task *Task) {
+	if task.Key == "" {
+		return
+	}
+
+	if err := q.store.DeleteKey(ctx, task.Key, task.Id); err != nil {
+		q.logger.WithContext(ctx).Error("can not release key %s of task %s: %w", task.Key, task.Id, err)
+	}
+}
+
+func (q *queue) GetStatus(ctx context.Context, id string) (*TaskStatus, bool, error) {
+	return q.store.Get(ctx, id)
+}
+
+// writeTask writes the task to the stream. The delay is passed on to outputs supporting it,
+// longer delays are completed by the worker holding or requeueing the task.
+func writeTask(ctx context.Context, producer stream.Producer, task *Task, delay time.Duration) error {
+	attributes := map[string]interface{}{}
+
+	if seconds := int32(math.Min(delay.Seconds(), sqs.MaxDelaySeconds)); seconds > 0 {
+		attributes[sqs.AttributeSqsDelaySeconds] = seconds
+	}
+
+	if err := producer.WriteOne(ctx, task, attributes); err != nil {
+		return fmt.Errorf("can not write task %s: %w", task.Id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package taskqueue_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/justtrackio/gosoline/pkg/taskqueue"
+	"github.com/justtrackio/gosoline/pkg/taskqueue/mocks"
+	uuidMocks "github.com/justtrackio/gosoline/pkg/uuid/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testPayload struct {
+	UserId int `json:"userId"`
+}
+
+type queueTestCase struct {
+	producer *streamMocks.Producer
+	store    *mocks.StatusStore
+	queue    taskqueue.Queue
+	now      time.Time
+}
+
+func newQueueTestCase(statusStore string) *queueTestCase {
+	now := time.Date(2021, time.March, 3, 10, 0, 0, 0, time.UTC)
+
+	uuid := new(uuidMocks.Uuid)
+	uuid.On("NewV4").Return("4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1")
+
+	tc := &queueTestCase{
+		producer: new(streamMocks.Producer),
+		store:    new(mocks.StatusStore),
+		now:      now,
+	}
+
+	tc.queue = taskqueue.NewQueueWithInterfaces(logMocks.NewLoggerMockedAll(), clock.NewFakeClockAt(now), uuid, tc.producer, tc.store, &taskqueue.Settings{
+		StatusStore: statusStore,
+	})
+
+	return tc
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	tc := newQueueTestCase("tasks")
+
+	expectedTask := &taskqueue.Task{
+		Id:         "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1",
+		Type:       "sendMail",
+		Payload:    json.RawMessage(`{"userId":3}`),
+		Attempt:    1,
+		EnqueuedAt: tc.now,
+		NotBefore:  tc.now,
+	}
+
+	tc.store.On("Put", mock.Anything, &taskqueue.TaskStatus{
+		Id:         "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1",
+		Type:       "sendMail",
+		State:      taskqueue.StatePending,
+		EnqueuedAt: tc.now,
+		UpdatedAt:  tc.now,
+	}).Return(nil).Once()
+	tc.producer.On("WriteOne", mock.Anything, expectedTask, map[string]interface{}{}).Return(nil).Once()
+
+	id, err := tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", id)
+
+	tc.producer.AssertExpectations(t)
+	tc.store.AssertExpectations(t)
+}
+
+func TestQueue_EnqueueDelayed(t *testing.T) {
+	tc := newQueueTestCase("")
+
+	tc.store.On("Put", mock.Anything, mock.AnythingOfType("*taskqueue.TaskStatus")).Return(nil).Once()
+	tc.producer.On("WriteOne", mock.Anything, mock.AnythingOfType("*taskqueue.Task"), map[string]interface{}{
+		sqs.AttributeSqsDelaySeconds: int32(sqs.MaxDelaySeconds),
+	}).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskqueue.Task)
+		assert.Equal(t, tc.now.Add(time.Hour), task.NotBefore)
+	}).Return(nil).Once()
+
+	_, err := tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3}, taskqueue.WithDelay(time.Hour))
+	assert.NoError(t, err)
+
+	tc.producer.AssertExpectations(t)
+}
+
+func TestQueue_EnqueueUniqueKey(t *testing.T) {
+	tc := newQueueTestCase("tasks")
+
+	tc.store.On("PutKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", "").Return(false, nil).Once()
+	tc.store.On("GetByKey", mock.Anything, "user-3").Return(&taskqueue.TaskStatus{
+		Id:    "1f0e4c1a-5cb4-4f8e-8d3a-0c3f1f6e2b7d",
+		State: taskqueue.StateRetrying,
+	}, true, nil).Once()
+
+	id, err := tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3}, taskqueue.WithKey("user-3"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1f0e4c1a-5cb4-4f8e-8d3a-0c3f1f6e2b7d", id, "the pending task should be returned")
+
+	tc.store.On("PutKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", "").Return(false, nil).Once()
+	tc.store.On("GetByKey", mock.Anything, "user-3").Return(&taskqueue.TaskStatus{
+		Id:    "1f0e4c1a-5cb4-4f8e-8d3a-0c3f1f6e2b7d",
+		State: taskqueue.StateSucceeded,
+	}, true, nil).Once()
+	tc.store.On("PutKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", "1f0e4c1a-5cb4-4f8e-8d3a-0c3f1f6e2b7d").Return(true, nil).Once()
+	tc.store.On("Put", mock.Anything, mock.AnythingOfType("*taskqueue.TaskStatus")).Return(nil).Once()
+	tc.producer.On("WriteOne", mock.Anything, mock.AnythingOfType("*taskqueue.Task"), map[string]interface{}{}).Return(nil).Once()
+
+	id, err = tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3}, taskqueue.WithKey("user-3"))
+	assert.NoError(t, err)
+	assert.Equal(t, "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", id, "the key of a finished task should be taken over")
+
+	tc.producer.AssertExpectations(t)
+	tc.store.AssertExpectations(t)
+}
+
+func TestQueue_EnqueueUniqueKeyReleasedOnFailure(t *testing.T) {
+	tc := newQueueTestCase("tasks")
+
+	tc.store.On("PutKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1", "").Return(true, nil).Once()
+	tc.store.On("Put", mock.Anything, mock.AnythingOfType("*taskqueue.TaskStatus")).Return(nil).Once()
+	tc.producer.On("WriteOne", mock.Anything, mock.AnythingOfType("*taskqueue.Task"), map[string]interface{}{}).Return(fmt.Errorf("unavailable")).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	_, err := tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3}, taskqueue.WithKey("user-3"))
+	assert.EqualError(t, err, "can not write task 4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1: unavailable")
+
+	tc.store.AssertExpectations(t)
+}
+
+func TestQueue_EnqueueUniqueKeyWithoutStatusStore(t *testing.T) {
+	tc := newQueueTestCase("")
+
+	_, err := tc.queue.Enqueue(context.Background(), "sendMail", testPayload{UserId: 3}, taskqueue.WithKey("user-3"))
+	assert.EqualError(t, err, "can not enqueue task sendMail with key user-3: unique keys require a status store")
+
+	tc.producer.AssertNotCalled(t, "WriteOne", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetrySettings_Backoff(t *testing.T) {
+	settings := taskqueue.RetrySettings{
+		InitialInterval: 10 * time.Second,
+		Multiplier:      3,
+		MaxInterval:     time.Minute,
+	}
+
+	assert.Equal(t, 10*time.Second, settings.Backoff(1))
+	assert.Equal(t, 30*time.Second, settings.Backoff(2))
+	assert.Equal(t, time.Minute, settings.Backoff(3))
+	assert.Equal(t, time.Minute, settings.Backoff(10))
+}
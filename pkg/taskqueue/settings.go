@@ -0,0 +1,84 @@
+package taskqueue
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+type Settings struct {
+	// Producer is the name of the stream producer used to enqueue tasks, it defaults to the name of the queue.
+	Producer string `cfg:"producer"`
+	// Consumer is the name of the stream consumer used by the worker, it defaults to the name of the queue.
+	Consumer string `cfg:"consumer"`
+	// StatusStore is the name of the kvstore used to track the status of the tasks. Tracking the status and
+	// unique keys are disabled if it is empty.
+	StatusStore string `cfg:"status_store"`
+	// MaxHold is the longest duration a worker waits for a delayed task to become due before putting it back into the queue.
+	// It is capped at half of the visibility timeout of a sqs input, otherwise a held task would be delivered again.
+	MaxHold time.Duration `cfg:"max_hold" default:"15s"`
+	Retry   RetrySettings `cfg:"retry"`
+	// Concurrency limits the number of tasks of a type which are handled at the same time by a worker.
+	Concurrency map[string]int `cfg:"concurrency"`
+	// SkipDelay is the delay after which a task is processed again if the concurrency limit of its type was reached.
+	SkipDelay time.Duration `cfg:"skip_delay" default:"5s"`
+}
+
+type RetrySettings struct {
+	MaxAttempts     int           `cfg:"max_attempts" default:"3"`
+	InitialInterval time.Duration `cfg:"initial_interval" default:"10s"`
+	Multiplier      float64       `cfg:"multiplier" default:"2"`
+	MaxInterval     time.Duration `cfg:"max_interval" default:"15m"`
+}
+
+// Backoff returns the delay before the given attempt is retried.
+func (s RetrySettings) Backoff(attempt int) time.Duration {
+	interval := float64(s.InitialInterval)
+
+	for i := 1; i < attempt; i++ {
+		interval *= s.Multiplier
+
+		if interval >= float64(s.MaxInterval) {
+			return s.MaxInterval
+		}
+	}
+
+	return time.Duration(interval)
+}
+
+func readSettings(config cfg.Config, name string) *Settings {
+	settings := &Settings{}
+	config.UnmarshalKey(fmt.Sprintf("taskqueue.%s", name), settings)
+
+	if settings.Producer == "" {
+		settings.Producer = name
+	}
+
+	if settings.Consumer == "" {
+		settings.Consumer = name
+	}
+
+	return settings
+}
+
+// readWorkerSettings reads the settings of the queue and caps the MaxHold, so a worker holding a task until it is due
+// doesn't exceed the visibility timeout of its input.
+func readWorkerSettings(config cfg.Config, name string) *Settings {
+	settings := readSettings(config, name)
+
+	consumerKey := stream.ConfigurableConsumerKey(settings.Consumer)
+	inputKey := stream.ConfigurableInputKey(config.GetString(fmt.Sprintf("%s.input", consumerKey), "consumer"))
+
+	switch config.GetString(fmt.Sprintf("%s.type", inputKey), "") {
+	case stream.InputTypeSqs, stream.InputTypeSns:
+		visibilityTimeout := time.Duration(config.GetInt(fmt.Sprintf("%s.visibility_timeout", inputKey), 30)) * time.Second
+
+		if limit := visibilityTimeout / 2; settings.MaxHold > limit {
+			settings.MaxHold = limit
+		}
+	}
+
+	return settings
+}
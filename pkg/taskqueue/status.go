@@ -0,0 +1,185 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/kvstore"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/mdl"
+)
+
+// A StatusStore keeps track of the state of every task, so clients are able to poll for the outcome of a task.
+//go:generate mockery --name StatusStore
+type StatusStore interface {
+	Get(ctx context.Context, id string) (*TaskStatus, bool, error)
+	Put(ctx context.Context, status *TaskStatus) error
+	// GetByKey returns the status of the task holding the given unique key. A task which got the key, but didn't
+	// store its status yet, is reported as pending.
+	GetByKey(ctx context.Context, key string) (*TaskStatus, bool, error)
+	// PutKey assigns the unique key to the task with the given id by a conditional put. The key has to be free or still
+	// held by the task with the previous id, otherwise false is returned as another task got the key first.
+	PutKey(ctx context.Context, key string, id string, previousId string) (bool, error)
+	// DeleteKey releases the unique key if it is still held by the task with the given id.
+	DeleteKey(ctx context.Context, key string, id string) error
+}
+
+// taskKey assigns a unique key to the task holding it. The keys are kept in ddb as they require conditional writes.
+type taskKey struct {
+	Key    string `json:"key" ddb:"key=hash"`
+	TaskId string `json:"taskId"`
+}
+
+type kvStatusStore struct {
+	store kvstore.KvStore
+	keys  ddb.Repository
+}
+
+// NewStatusStore creates a status store based on the kvstore configured at kvstore.<name>. The unique keys of the
+// tasks are kept in a ddb table named after the status store. Tracking the status is disabled if no name is given.
+func NewStatusStore(ctx context.Context, config cfg.Config, logger log.Logger, name string) (StatusStore, error) {
+	if name == "" {
+		return NewStatusStoreNoop(), nil
+	}
+
+	store, err := kvstore.ProvideConfigurableKvStore(ctx, config, logger, name)
+	if err != nil {
+		return nil, fmt.Errorf("can not create kvstore %s: %w", name, err)
+	}
+
+	modelId := mdl.ModelId{
+		Name: fmt.Sprintf("taskqueue-keys-%s", name),
+	}
+	modelId.PadFromConfig(config)
+
+	keys, err := ddb.NewRepository(ctx, config, logger, &ddb.Settings{
+		ModelId: modelId,
+		Main: ddb.MainSettings{
+			Model:              taskKey{},
+			ReadCapacityUnits:  5,
+			WriteCapacityUnits: 5,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository for the keys of status store %s: %w", name, err)
+	}
+
+	return NewStatusStoreWithInterfaces(store, keys), nil
+}
+
+func NewStatusStoreWithInterfaces(store kvstore.KvStore, keys ddb.Repository) StatusStore {
+	return &kvStatusStore{
+		store: store,
+		keys:  keys,
+	}
+}
+
+func (s *kvStatusStore) Get(ctx context.Context, id string) (*TaskStatus, bool, error) {
+	status := &TaskStatus{}
+
+	found, err := s.store.Get(ctx, statusKey(id), status)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not get status of task %s: %w", id, err)
+	}
+
+	return status, found, nil
+}
+
+func (s *kvStatusStore) Put(ctx context.Context, status *TaskStatus) error {
+	if err := s.store.Put(ctx, statusKey(status.Id), status); err != nil {
+		return fmt.Errorf("can not put status of task %s: %w", status.Id, err)
+	}
+
+	return nil
+}
+
+func (s *kvStatusStore) GetByKey(ctx context.Context, key string) (*TaskStatus, bool, error) {
+	item := &taskKey{}
+	qb := s.keys.GetItemBuilder().WithHash(key).WithConsistentRead(true)
+
+	res, err := s.keys.GetItem(ctx, qb, item)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not get task with key %s: %w", key, err)
+	}
+
+	if !res.IsFound {
+		return nil, false, nil
+	}
+
+	status, found, err := s.Get(ctx, item.TaskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		status = &TaskStatus{
+			Id:    item.TaskId,
+			Key:   key,
+			State: StatePending,
+		}
+	}
+
+	return status, true, nil
+}
+
+func (s *kvStatusStore) PutKey(ctx context.Context, key string, id string, previousId string) (bool, error) {
+	condition := ddb.AttributeNotExists("key")
+	if previousId != "" {
+		condition = ddb.Eq("taskId", previousId)
+	}
+
+	qb := s.keys.PutItemBuilder().WithCondition(condition)
+
+	res, err := s.keys.PutItem(ctx, qb, &taskKey{
+		Key:    key,
+		TaskId: id,
+	})
+	if err != nil {
+		return false, fmt.Errorf("can not put task with key %s: %w", key, err)
+	}
+
+	return !res.ConditionalCheckFailed, nil
+}
+
+func (s *kvStatusStore) DeleteKey(ctx context.Context, key string, id string) error {
+	qb := s.keys.DeleteItemBuilder().WithHash(key).WithCondition(ddb.Eq("taskId", id))
+
+	// a failed condition means the key was taken over by another task already
+	if _, err := s.keys.DeleteItem(ctx, qb, &taskKey{Key: key, TaskId: id}); err != nil {
+		return fmt.Errorf("can not delete task with key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func statusKey(id string) string {
+	return fmt.Sprintf("status-%s", id)
+}
+
+type noopStatusStore struct{}
+
+func NewStatusStoreNoop() StatusStore {
+	return noopStatusStore{}
+}
+
+func (s noopStatusStore) Get(_ context.Context, _ string) (*TaskStatus, bool, error) {
+	return nil, false, nil
+}
+
+func (s noopStatusStore) Put(_ context.Context, _ *TaskStatus) error {
+	return nil
+}
+
+func (s noopStatusStore) GetByKey(_ context.Context, _ string) (*TaskStatus, bool, error) {
+	return nil, false, nil
+}
+
+func (s noopStatusStore) PutKey(_ context.Context, _ string, _ string, _ string) (bool, error) {
+	return true, nil
+}
+
+func (s noopStatusStore) DeleteKey(_ context.Context, _ string, _ string) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+)
+
+const (
+	StatePending   = "pending"
+	StateRunning   = "running"
+	StateRetrying  = "retrying"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+// A Task is the unit of work transported by the queue. The payload is kept in its json encoded form until
+// the handler of the task type decodes it.
+type Task struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"key,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempt    int             `json:"attempt"`
+	EnqueuedAt time.Time       `json:"enqueuedAt"`
+	NotBefore  time.Time       `json:"notBefore"`
+}
+
+// UnmarshalPayload decodes the payload of the task into the given value.
+func (t *Task) UnmarshalPayload(v interface{}) error {
+	if err := json.Unmarshal(t.Payload, v); err != nil {
+		return fmt.Errorf("can not unmarshal payload of task %s: %w", t.Id, err)
+	}
+
+	return nil
+}
+
+// TaskStatus is the outcome of a task as tracked in the status store.
+type TaskStatus struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"key,omitempty"`
+	State      string          `json:"state"`
+	Attempts   int             `json:"attempts"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	EnqueuedAt time.Time       `json:"enqueuedAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+}
+
+// IsFinal returns true if the task will not be processed again.
+func (s *TaskStatus) IsFinal() bool {
+	return s.State == StateSucceeded || s.State == StateFailed
+}
+
+// A Handler processes the tasks of a single type. The returned result is stored as json in the status of the task.
+// A returned error causes a retry of the task until the maximum number of attempts is reached.
+//go:generate mockery --name Handler
+type Handler interface {
+	Handle(ctx context.Context, task *Task) (interface{}, error)
+}
+
+// HandlerFunc turns a function into a Handler.
+type HandlerFunc func(ctx context.Context, task *Task) (interface{}, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, task *Task) (interface{}, error) {
+	return f(ctx, task)
+}
+
+// A PermanentError marks an error of a handler as not retryable, the task fails immediately.
+type PermanentError struct {
+	Err error
+}
+
+func NewPermanentError(err error) PermanentError {
+	return PermanentError{
+		Err: err,
+	}
+}
+
+func (e PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e PermanentError) Unwrap() error {
+	return e.Err
+}
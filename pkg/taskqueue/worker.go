@@ -0,0 +1,233 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	"github.com/justtrackio/gosoline/pkg/kernel"
+	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/stream"
+)
+
+// HandlerFactory creates the handlers of a worker mapped by the task type they are able to process.
+type HandlerFactory func(ctx context.Context, config cfg.Config, logger log.Logger) (map[string]Handler, error)
+
+// NewWorker creates a consumer module processing the tasks of the queue configured at taskqueue.<name>.
+func NewWorker(name string, factory HandlerFactory) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger log.Logger) (kernel.Module, error) {
+		settings := readWorkerSettings(config, name)
+
+		callbackFactory := func(ctx context.Context, config cfg.Config, logger log.Logger) (stream.ConsumerCallback, error) {
+			return NewWorkerCallback(ctx, config, logger, name, settings, factory)
+		}
+
+		return stream.NewConsumer(settings.Consumer, callbackFactory)(ctx, config, logger)
+	}
+}
+
+type workerCallback struct {
+	logger     log.Logger
+	clock      clock.Clock
+	producer   stream.Producer
+	store      StatusStore
+	handlers   map[string]Handler
+	semaphores map[string]chan struct{}
+	settings   *Settings
+}
+
+func NewWorkerCallback(ctx context.Context, config cfg.Config, logger log.Logger, name string, settings *Settings, factory HandlerFactory) (stream.ConsumerCallback, error) {
+	var err error
+	var producer stream.Producer
+	var store StatusStore
+	var handlers map[string]Handler
+
+	logger = logger.WithChannel("taskqueue")
+
+	if producer, err = stream.NewProducer(ctx, config, logger, settings.Producer); err != nil {
+		return nil, fmt.Errorf("can not create producer %s: %w", settings.Producer, err)
+	}
+
+	if store, err = NewStatusStore(ctx, config, logger, settings.StatusStore); err != nil {
+		return nil, fmt.Errorf("can not create status store of task queue %s: %w", name, err)
+	}
+
+	if handlers, err = factory(ctx, config, logger); err != nil {
+		return nil, fmt.Errorf("can not create handlers of task queue %s: %w", name, err)
+	}
+
+	return NewWorkerCallbackWithInterfaces(logger, clock.Provider, producer, store, handlers, settings), nil
+}
+
+func NewWorkerCallbackWithInterfaces(logger log.Logger, clock clock.Clock, producer stream.Producer, store StatusStore, handlers map[string]Handler, settings *Settings) stream.ConsumerCallback {
+	semaphores := make(map[string]chan struct{})
+
+	for taskType, concurrency := range settings.Concurrency {
+		if concurrency > 0 {
+			semaphores[taskType] = make(chan struct{}, concurrency)
+		}
+	}
+
+	return &workerCallback{
+		logger:     logger,
+		clock:      clock,
+		producer:   producer,
+		store:      store,
+		handlers:   handlers,
+		semaphores: semaphores,
+		settings:   settings,
+	}
+}
+
+func (w *workerCallback) GetModel(_ map[string]interface{}) interface{} {
+	return &Task{}
+}
+
+func (w *workerCallback) Consume(ctx context.Context, model interface{}, _ map[string]interface{}) (bool, error) {
+	task, ok := model.(*Task)
+	if !ok {
+		return false, fmt.Errorf("expected a task but got %T", model)
+	}
+
+	logger := w.logger.WithContext(ctx).WithFields(log.Fields{
+		"task_id":   task.Id,
+		"task_type": task.Type,
+	})
+
+	if wait := task.NotBefore.Sub(w.clock.Now()); wait > 0 {
+		if wait > w.settings.MaxHold {
+			if err := writeTask(ctx, w.producer, task, wait); err != nil {
+				return false, fmt.Errorf("can not requeue delayed task: %w", err)
+			}
+
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-w.clock.After(wait):
+		}
+	}
+
+	handler, ok := w.handlers[task.Type]
+	if !ok {
+		logger.Error("there is no handler for tasks of type %s", task.Type)
+
+		return w.finish(ctx, task, StateFailed, nil, fmt.Errorf("there is no handler for tasks of type %s", task.Type))
+	}
+
+	if semaphore, ok := w.semaphores[task.Type]; ok {
+		select {
+		case semaphore <- struct{}{}:
+		default:
+			// don't block the runner with a saturated type, it can process tasks of other types in the meantime
+			return w.skip(ctx, logger, task)
+		}
+
+		defer func() {
+			<-semaphore
+		}()
+	}
+
+	if err := w.store.Put(ctx, w.newStatus(task, StateRunning)); err != nil {
+		return false, fmt.Errorf("can not mark task as running: %w", err)
+	}
+
+	result, err := handler.Handle(ctx, task)
+
+	if err == nil {
+		return w.finish(ctx, task, StateSucceeded, result, nil)
+	}
+
+	if errors.As(err, &PermanentError{}) || task.Attempt >= w.settings.Retry.MaxAttempts {
+		logger.Warn("task failed after %d attempts: %s", task.Attempt, err.Error())
+
+		return w.finish(ctx, task, StateFailed, nil, err)
+	}
+
+	return w.retry(ctx, logger, task, err)
+}
+
+func (w *workerCallback) retry(ctx context.Context, logger log.Logger, task *Task, cause error) (bool, error) {
+	backoff := w.settings.Retry.Backoff(task.Attempt)
+	logger.Info("retrying task in %s after attempt %d failed: %s", backoff, task.Attempt, cause.Error())
+
+	status := w.newStatus(task, StateRetrying)
+	status.Error = cause.Error()
+
+	if err := w.store.Put(ctx, status); err != nil {
+		return false, fmt.Errorf("can not mark task as retrying: %w", err)
+	}
+
+	retried := *task
+	retried.Attempt++
+	retried.NotBefore = w.clock.Now().Add(backoff)
+
+	if err := writeTask(ctx, w.producer, &retried, backoff); err != nil {
+		return false, fmt.Errorf("can not requeue failed task: %w", err)
+	}
+
+	return true, nil
+}
+
+// finish stores the final state of the task and releases its key. The task is only acknowledged if its state was
+// stored, otherwise it is processed again.
+func (w *workerCallback) finish(ctx context.Context, task *Task, state string, result interface{}, cause error) (bool, error) {
+	var err error
+
+	status := w.newStatus(task, state)
+
+	if cause != nil {
+		status.Error = cause.Error()
+	}
+
+	if result != nil {
+		if status.Result, err = json.Marshal(result); err != nil {
+			return false, fmt.Errorf("can not marshal result of task %s: %w", task.Id, err)
+		}
+	}
+
+	if err = w.store.Put(ctx, status); err != nil {
+		return false, fmt.Errorf("can not mark task %s as %s: %w", task.Id, state, err)
+	}
+
+	if task.Key == "" {
+		return true, nil
+	}
+
+	if err = w.store.DeleteKey(ctx, task.Key, task.Id); err != nil {
+		return false, fmt.Errorf("can not release key of task %s: %w", task.Id, err)
+	}
+
+	return true, nil
+}
+
+// skip puts a task of a saturated type back into the queue to be processed after the skip delay.
+func (w *workerCallback) skip(ctx context.Context, logger log.Logger, task *Task) (bool, error) {
+	logger.Debug("skipping task as the concurrency limit of type %s is reached", task.Type)
+
+	skipped := *task
+	skipped.NotBefore = w.clock.Now().Add(w.settings.SkipDelay)
+
+	if err := writeTask(ctx, w.producer, &skipped, w.settings.SkipDelay); err != nil {
+		return false, fmt.Errorf("can not requeue task of saturated type %s: %w", task.Type, err)
+	}
+
+	return true, nil
+}
+
+func (w *workerCallback) newStatus(task *Task, state string) *TaskStatus {
+	return &TaskStatus{
+		Id:         task.Id,
+		Type:       task.Type,
+		Key:        task.Key,
+		State:      state,
+		Attempts:   task.Attempt,
+		EnqueuedAt: task.EnqueuedAt,
+		UpdatedAt:  w.clock.Now(),
+	}
+}
@@ -0,0 +1,246 @@
+package taskqueue_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/clock"
+	"github.com/justtrackio/gosoline/pkg/cloud/aws/sqs"
+	"github.com/justtrackio/gosoline/pkg/encoding/json"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/stream"
+	streamMocks "github.com/justtrackio/gosoline/pkg/stream/mocks"
+	"github.com/justtrackio/gosoline/pkg/taskqueue"
+	"github.com/justtrackio/gosoline/pkg/taskqueue/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type workerTestCase struct {
+	clock    clock.FakeClock
+	producer *streamMocks.Producer
+	store    *mocks.StatusStore
+	handler  *mocks.Handler
+	callback stream.ConsumerCallback
+	states   []string
+}
+
+func newWorkerTestCase(settings *taskqueue.Settings) *workerTestCase {
+	tc := &workerTestCase{
+		clock:    clock.NewFakeClockAt(time.Date(2021, time.March, 3, 10, 0, 0, 0, time.UTC)),
+		producer: new(streamMocks.Producer),
+		store:    new(mocks.StatusStore),
+		handler:  new(mocks.Handler),
+	}
+
+	tc.store.On("Put", mock.Anything, mock.AnythingOfType("*taskqueue.TaskStatus")).Run(func(args mock.Arguments) {
+		tc.states = append(tc.states, args.Get(1).(*taskqueue.TaskStatus).State)
+	}).Return(nil)
+
+	handlers := map[string]taskqueue.Handler{
+		"sendMail": tc.handler,
+	}
+
+	settings.MaxHold = time.Minute
+	settings.Retry = taskqueue.RetrySettings{
+		MaxAttempts:     2,
+		InitialInterval: 10 * time.Second,
+		Multiplier:      2,
+		MaxInterval:     time.Minute,
+	}
+
+	tc.callback = taskqueue.NewWorkerCallbackWithInterfaces(logMocks.NewLoggerMockedAll(), tc.clock, tc.producer, tc.store, handlers, settings)
+
+	return tc
+}
+
+func (tc *workerTestCase) newTask() *taskqueue.Task {
+	return &taskqueue.Task{
+		Id:         "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1",
+		Type:       "sendMail",
+		Key:        "user-3",
+		Payload:    json.RawMessage(`{"userId":3}`),
+		Attempt:    1,
+		EnqueuedAt: tc.clock.Now(),
+		NotBefore:  tc.clock.Now(),
+	}
+}
+
+func TestWorker_Success(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+	task := tc.newTask()
+
+	tc.handler.On("Handle", mock.Anything, task).Return(func(_ context.Context, task *taskqueue.Task) interface{} {
+		payload := &testPayload{}
+		assert.NoError(t, task.UnmarshalPayload(payload))
+
+		return map[string]int{"sent": payload.UserId}
+	}, nil).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack)
+
+	assert.Equal(t, []string{taskqueue.StateRunning, taskqueue.StateSucceeded}, tc.states)
+	tc.store.AssertCalled(t, "Put", mock.Anything, mock.MatchedBy(func(status *taskqueue.TaskStatus) bool {
+		return status.State == taskqueue.StateSucceeded && string(status.Result) == `{"sent":3}`
+	}))
+	tc.handler.AssertExpectations(t)
+	tc.store.AssertExpectations(t)
+}
+
+func TestWorker_Retry(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+	task := tc.newTask()
+
+	tc.handler.On("Handle", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("smtp unavailable")).Twice()
+	tc.producer.On("WriteOne", mock.Anything, mock.AnythingOfType("*taskqueue.Task"), map[string]interface{}{
+		sqs.AttributeSqsDelaySeconds: int32(10),
+	}).Run(func(args mock.Arguments) {
+		retried := args.Get(1).(*taskqueue.Task)
+		assert.Equal(t, 2, retried.Attempt)
+		assert.Equal(t, tc.clock.Now().Add(10*time.Second), retried.NotBefore)
+
+		task = retried
+	}).Return(nil).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack)
+
+	tc.clock.Advance(10 * time.Second)
+
+	ack, err = tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack)
+
+	assert.Equal(t, []string{taskqueue.StateRunning, taskqueue.StateRetrying, taskqueue.StateRunning, taskqueue.StateFailed}, tc.states)
+	tc.handler.AssertExpectations(t)
+	tc.producer.AssertExpectations(t)
+	tc.store.AssertExpectations(t)
+}
+
+func TestWorker_PermanentError(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+
+	tc.handler.On("Handle", mock.Anything, mock.Anything).Return(nil, taskqueue.NewPermanentError(fmt.Errorf("invalid address"))).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), tc.newTask(), map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack)
+
+	assert.Equal(t, []string{taskqueue.StateRunning, taskqueue.StateFailed}, tc.states)
+	tc.producer.AssertNotCalled(t, "WriteOne", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorker_UnknownType(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+	task := tc.newTask()
+	task.Type = "sendSms"
+
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack)
+
+	assert.Equal(t, []string{taskqueue.StateFailed}, tc.states)
+	tc.handler.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+}
+
+func TestWorker_Delayed(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+
+	task := tc.newTask()
+	task.NotBefore = tc.clock.Now().Add(time.Hour)
+
+	tc.producer.On("WriteOne", mock.Anything, task, map[string]interface{}{
+		sqs.AttributeSqsDelaySeconds: int32(sqs.MaxDelaySeconds),
+	}).Return(nil).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack, "a task due after the max hold should be requeued")
+
+	task = tc.newTask()
+	task.NotBefore = tc.clock.Now().Add(30 * time.Second)
+
+	tc.handler.On("Handle", mock.Anything, task).Return(nil, nil).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil).Once()
+
+	done := make(chan bool)
+	go func() {
+		ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+		assert.NoError(t, err)
+		done <- ack
+	}()
+
+	tc.clock.BlockUntil(1)
+	tc.handler.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+
+	tc.clock.Advance(30 * time.Second)
+	assert.True(t, <-done, "a task due within the max hold should be processed when due")
+
+	tc.handler.AssertExpectations(t)
+	tc.producer.AssertExpectations(t)
+}
+
+func TestWorker_Concurrency(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{
+		Concurrency: map[string]int{
+			"sendMail": 1,
+		},
+		SkipDelay: 5 * time.Second,
+	})
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+
+	tc.handler.On("Handle", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		started <- struct{}{}
+		<-finish
+	}).Return(nil, nil).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(nil)
+	tc.producer.On("WriteOne", mock.Anything, mock.AnythingOfType("*taskqueue.Task"), map[string]interface{}{
+		sqs.AttributeSqsDelaySeconds: int32(5),
+	}).Run(func(args mock.Arguments) {
+		skipped := args.Get(1).(*taskqueue.Task)
+		assert.Equal(t, 1, skipped.Attempt, "skipping a task doesn't count as attempt")
+		assert.Equal(t, tc.clock.Now().Add(5*time.Second), skipped.NotBefore)
+	}).Return(nil).Once()
+
+	done := make(chan bool)
+	go func() {
+		ack, err := tc.callback.Consume(context.Background(), tc.newTask(), map[string]interface{}{})
+		assert.NoError(t, err)
+		done <- ack
+	}()
+	<-started
+
+	ack, err := tc.callback.Consume(context.Background(), tc.newTask(), map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, ack, "a task of a saturated type should be requeued instead of waiting")
+
+	finish <- struct{}{}
+	assert.True(t, <-done)
+
+	tc.handler.AssertExpectations(t)
+	tc.producer.AssertExpectations(t)
+}
+
+func TestWorker_StatusStoreFailure(t *testing.T) {
+	tc := newWorkerTestCase(&taskqueue.Settings{})
+	task := tc.newTask()
+
+	tc.handler.On("Handle", mock.Anything, task).Return(nil, nil).Once()
+	tc.store.On("DeleteKey", mock.Anything, "user-3", "4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1").Return(fmt.Errorf("unavailable")).Once()
+
+	ack, err := tc.callback.Consume(context.Background(), task, map[string]interface{}{})
+	assert.EqualError(t, err, "can not release key of task 4b2e5d4c-5e3b-4f7c-9a32-5a8e38f6b4c1: unavailable")
+	assert.False(t, ack, "a task has to be processed again if its state couldn't be stored")
+}
@@ -0,0 +1,31 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/es"
+)
+
+type elasticsearchComponent struct {
+	baseComponent
+	address string
+	client  *es.ClientV7
+}
+
+func (c *elasticsearchComponent) CfgOptions() []cfg.Option {
+	return []cfg.Option{
+		cfg.WithConfigMap(map[string]interface{}{
+			fmt.Sprintf("es_%s_type", c.name):     "default",
+			fmt.Sprintf("es_%s_endpoint", c.name): c.address,
+		}),
+	}
+}
+
+func (c *elasticsearchComponent) Address() string {
+	return c.address
+}
+
+func (c *elasticsearchComponent) Client() *es.ClientV7 {
+	return c.client
+}
@@ -168,6 +168,10 @@ func (e *Environment) DynamoDb(name string) *DdbComponent {
 	return e.Component(componentDdb, name).(*DdbComponent)
 }
 
+func (e *Environment) Elasticsearch(name string) *elasticsearchComponent {
+	return e.Component(componentElasticsearch, name).(*elasticsearchComponent)
+}
+
 func (e *Environment) Localstack(name string) *localstackComponent {
 	return e.Component(ComponentLocalstack, name).(*localstackComponent)
 }
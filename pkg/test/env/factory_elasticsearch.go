@@ -0,0 +1,119 @@
+package env
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+func init() {
+	componentFactories[componentElasticsearch] = new(elasticsearchFactory)
+}
+
+const componentElasticsearch = "elasticsearch"
+
+type elasticsearchSettings struct {
+	ComponentBaseSettings
+	ComponentContainerSettings
+	Port    int    `cfg:"port" default:"0"`
+	Version string `cfg:"version" default:"7.10.1"`
+}
+
+type elasticsearchFactory struct{}
+
+func (f *elasticsearchFactory) Detect(_ cfg.Config, _ *ComponentsConfigManager) error {
+	return nil
+}
+
+func (f *elasticsearchFactory) GetSettingsSchema() ComponentBaseSettingsAware {
+	return &elasticsearchSettings{}
+}
+
+func (f *elasticsearchFactory) DescribeContainers(settings interface{}) componentContainerDescriptions {
+	return componentContainerDescriptions{
+		"main": {
+			containerConfig: f.configureContainer(settings),
+			healthCheck:     f.healthCheck(),
+		},
+	}
+}
+
+func (f *elasticsearchFactory) configureContainer(settings interface{}) *containerConfig {
+	s := settings.(*elasticsearchSettings)
+
+	return &containerConfig{
+		Repository: "docker.elastic.co/elasticsearch/elasticsearch",
+		Tag:        s.Version,
+		Env: []string{
+			"discovery.type=single-node",
+			"xpack.security.enabled=false",
+			"ES_JAVA_OPTS=-Xms512m -Xmx512m",
+		},
+		PortBindings: portBindings{
+			"9200/tcp": s.Port,
+		},
+		ExpireAfter: s.ExpireAfter,
+	}
+}
+
+func (f *elasticsearchFactory) healthCheck() ComponentHealthCheck {
+	return func(container *container) error {
+		client, err := f.client(container)
+		if err != nil {
+			return err
+		}
+
+		res, err := client.Cluster.Health(client.Cluster.Health.WithWaitForStatus("yellow"))
+		if err != nil {
+			return err
+		}
+
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("elasticsearch did return status '%s'", res.Status())
+		}
+
+		return nil
+	}
+}
+
+func (f *elasticsearchFactory) Component(_ cfg.Config, _ log.Logger, containers map[string]*container, settings interface{}) (Component, error) {
+	s := settings.(*elasticsearchSettings)
+
+	client, err := f.client(containers["main"])
+	if err != nil {
+		return nil, fmt.Errorf("can not create client: %w", err)
+	}
+
+	component := &elasticsearchComponent{
+		baseComponent: baseComponent{
+			name: s.Name,
+		},
+		address: f.address(containers["main"]),
+		client:  client,
+	}
+
+	return component, nil
+}
+
+func (f *elasticsearchFactory) address(container *container) string {
+	binding := container.bindings["9200/tcp"]
+
+	return fmt.Sprintf("http://%s:%s", binding.host, binding.port)
+}
+
+func (f *elasticsearchFactory) client(container *container) (*es.ClientV7, error) {
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{f.address(container)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &es.ClientV7{Client: *client}, nil
+}
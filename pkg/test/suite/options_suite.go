@@ -156,6 +156,12 @@ func WithSubscribers(transformerFactoryMap mdlsub.TransformerMapTypeVersionFacto
 	return WithModuleFactory(subs)
 }
 
+func WithSubscribersAndUpcasters(transformerFactoryMap mdlsub.TransformerMapTypeVersionFactories, upcasterFactoryMap mdlsub.UpcasterMapTypeVersionFactories) Option {
+	subs := mdlsub.NewSubscriberFactoryWithUpcasters(transformerFactoryMap, upcasterFactoryMap)
+
+	return WithModuleFactory(subs)
+}
+
 func WithoutAutoDetectedComponents(components ...string) Option {
 	return func(s *suiteOptions) {
 		s.addEnvOption(env.WithoutAutoDetectedComponents(components...))
@@ -2,6 +2,7 @@ package suite
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/justtrackio/gosoline/pkg/db-repo"
 	"github.com/justtrackio/gosoline/pkg/ddb"
+	"github.com/justtrackio/gosoline/pkg/es"
 	"github.com/justtrackio/gosoline/pkg/kvstore"
 	"github.com/justtrackio/gosoline/pkg/mdl"
 	"github.com/justtrackio/gosoline/pkg/mdlsub"
@@ -146,6 +148,33 @@ func buildTestCaseSubscriber(_ TestingSuite, method reflect.Method) (testCaseRun
 
 				ddbSub.Assert(t, fetcher)
 
+			case mdlsub.OutputTypeEs:
+				esSub, ok := tc.(esSubscriberTestCase)
+
+				if !ok {
+					assert.FailNow(t, "invalid subscription test case", "the test case for the subscription of %s has to be of the es type", tc.GetName())
+					return
+				}
+
+				clientName := config.GetString(fmt.Sprintf("%s.es.client", mdlsub.GetSubscriberConfigKey(tc.GetName())), "default")
+
+				client, err := es.ProvideClient(config, logger, clientName)
+				if err != nil {
+					assert.FailNow(t, err.Error(), "the test case for the subscription of %s can't be initialized", tc.GetName())
+				}
+
+				modelIdTarget := esSub.ModelIdTarget
+				modelIdTarget.PadFromConfig(config)
+
+				fetcher := &EsSubscriberFetcher{
+					t:      t,
+					client: client,
+					index:  es.IndexName(modelIdTarget, 0),
+					name:   tc.GetName(),
+				}
+
+				esSub.Assert(t, fetcher)
+
 			case mdlsub.OutputTypeKvstore:
 				ctx := environment.Context()
 				dbSub, ok := tc.(kvstoreSubscriberTestCase)
@@ -290,6 +319,76 @@ func (f DdbSubscriberFetcher) ByHashAndRange(hash interface{}, rangeValue interf
 	assert.True(f.t, res.IsFound)
 }
 
+func EsTestCase(testCase EsSubscriberTestCase) (SubscriberTestCase, error) {
+	var err error
+	var modelIdSource, modelIdTarget mdl.ModelId
+
+	if modelIdSource, err = mdl.ModelIdFromString(testCase.SourceModelId); err != nil {
+		return nil, fmt.Errorf("invalid source modelId for subscription test case %s: %w", testCase.Name, err)
+	}
+
+	if modelIdTarget, err = mdl.ModelIdFromString(testCase.TargetModelId); err != nil {
+		return nil, fmt.Errorf("invalid target modelId for subscription test case %s: %w", testCase.Name, err)
+	}
+
+	return esSubscriberTestCase{
+		subscriberTestCase: subscriberTestCase{
+			Name:    testCase.Name,
+			ModelId: modelIdSource,
+			Input:   testCase.Input,
+		},
+		ModelIdTarget: modelIdTarget,
+		Assert:        testCase.Assert,
+	}, nil
+}
+
+type EsSubscriberTestCase struct {
+	Name          string
+	SourceModelId string
+	TargetModelId string
+	Input         interface{}
+	Assert        EsSubscriberAssertion
+}
+
+type esSubscriberTestCase struct {
+	subscriberTestCase
+	ModelIdTarget mdl.ModelId
+	Assert        EsSubscriberAssertion
+}
+
+type EsSubscriberAssertion func(t *testing.T, fetcher *EsSubscriberFetcher)
+
+// EsSubscriberFetcher reads the documents from the index of version 0 of the target model, as the input of a test
+// case is published in version 0.
+type EsSubscriberFetcher struct {
+	t      *testing.T
+	client *es.ClientV7
+	index  string
+	name   string
+}
+
+func (f EsSubscriberFetcher) ById(id interface{}, model interface{}) {
+	res, err := f.client.Get(f.index, fmt.Sprint(id))
+	if !assert.NoErrorf(f.t, err, "unexpected error on fetching es subscription %s", f.name) {
+		return
+	}
+
+	defer res.Body.Close()
+
+	if !assert.Falsef(f.t, res.IsError(), "document %v of es subscription %s should be available: %s", id, f.name, res.String()) {
+		return
+	}
+
+	document := &struct {
+		Source interface{} `json:"_source"`
+	}{
+		Source: model,
+	}
+
+	err = json.NewDecoder(res.Body).Decode(document)
+	assert.NoErrorf(f.t, err, "unexpected error on decoding es subscription %s", f.name)
+}
+
 func KvstoreTestCase(testCase KvstoreSubscriberTestCase) (SubscriberTestCase, error) {
 	modelId, err := mdl.ModelIdFromString(testCase.ModelId)
 	if err != nil {
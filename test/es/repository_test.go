@@ -0,0 +1,92 @@
+//go:build integration
+// +build integration
+
+package es_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/es"
+	"github.com/justtrackio/gosoline/pkg/test/suite"
+)
+
+type Item struct {
+	Id          string `json:"id"`
+	Status      string `json:"status"`
+	Description string `json:"description" es:"type=text"`
+	Amount      int    `json:"amount"`
+}
+
+type RepositoryTestSuite struct {
+	suite.Suite
+	repo es.Repository
+}
+
+func (s *RepositoryTestSuite) SetupSuite() []suite.Option {
+	return []suite.Option{
+		suite.WithLogLevel("debug"),
+		suite.WithConfigFile("./config.dist.yml"),
+	}
+}
+
+func (s *RepositoryTestSuite) SetupTest() error {
+	var err error
+
+	s.repo, err = es.NewRepository(s.Env().Context(), s.Env().Config(), s.Env().Logger(), &es.Settings{
+		Model:   &Item{},
+		Refresh: "wait_for",
+	})
+
+	return err
+}
+
+func (s *RepositoryTestSuite) TestSearch() {
+	ctx := context.Background()
+
+	err := s.repo.BulkIndex(ctx, []*Item{
+		{Id: "1", Status: "active", Description: "a red apple", Amount: 3},
+		{Id: "2", Status: "active", Description: "a green pear", Amount: 5},
+		{Id: "3", Status: "deleted", Description: "a red cherry", Amount: 7},
+	})
+	s.NoError(err)
+
+	sb := es.NewSearchBuilder().
+		Query(es.Bool().Filter(es.Term("status", "active")).Must(es.Match("description", "red"))).
+		Aggregation("amount", es.SumAggregation("amount"))
+
+	items := make([]*Item, 0)
+	result, err := s.repo.Search(ctx, sb, &items)
+	s.NoError(err)
+	s.Equal(1, result.Total)
+	s.Equal([]*Item{{Id: "1", Status: "active", Description: "a red apple", Amount: 3}}, items)
+
+	amount := es.MetricAggregationResult{}
+	s.NoError(result.Aggregation("amount", &amount))
+	s.Equal(float64(3), *amount.Value)
+
+	count, err := s.repo.Count(ctx, es.NewSearchBuilder().Query(es.Range("amount").Gte(5)))
+	s.NoError(err)
+	s.Equal(2, count)
+
+	page := es.NewSearchBuilder().OrderBy("amount", "asc").Size(2)
+	items = make([]*Item, 0)
+	result, err = s.repo.Search(ctx, page, &items)
+	s.NoError(err)
+	s.Len(items, 2)
+
+	items = make([]*Item, 0)
+	_, err = s.repo.Search(ctx, page.SearchAfter(result.SearchAfter...), &items)
+	s.NoError(err)
+	s.Equal([]*Item{{Id: "3", Status: "deleted", Description: "a red cherry", Amount: 7}}, items)
+
+	s.NoError(s.repo.Delete(ctx, "3"))
+
+	found, err := s.repo.Get(ctx, "3", &Item{})
+	s.NoError(err)
+	s.False(found)
+}
+
+func TestRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RepositoryTestSuite))
+}
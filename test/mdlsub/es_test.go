@@ -0,0 +1,76 @@
+//go:build integration
+// +build integration
+
+package mdlsub_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/justtrackio/gosoline/pkg/mdlsub"
+	"github.com/justtrackio/gosoline/pkg/test/suite"
+	"github.com/stretchr/testify/assert"
+)
+
+type Item struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (i *Item) GetId() interface{} {
+	return i.Id
+}
+
+type itemTransformer struct{}
+
+func (t itemTransformer) GetInput() interface{} {
+	return &Item{}
+}
+
+func (t itemTransformer) GetOutput() mdlsub.Model {
+	return &Item{}
+}
+
+func (t itemTransformer) Transform(_ context.Context, inp interface{}) (mdlsub.Model, error) {
+	return inp.(*Item), nil
+}
+
+var transformers = mdlsub.TransformerMapTypeVersionFactories{
+	"gosoline.test.publisher.item": {
+		0: mdlsub.NewGenericTransformer(itemTransformer{}),
+	},
+}
+
+type EsSubscriberTestSuite struct {
+	suite.Suite
+}
+
+func (s *EsSubscriberTestSuite) SetupSuite() []suite.Option {
+	return []suite.Option{
+		suite.WithLogLevel("debug"),
+		suite.WithConfigFile("./config.dist.yml"),
+		suite.WithSubscribers(transformers),
+	}
+}
+
+func (s *EsSubscriberTestSuite) TestItem() (suite.SubscriberTestCase, error) {
+	return suite.EsTestCase(suite.EsSubscriberTestCase{
+		Name:          "item",
+		SourceModelId: "gosoline.test.publisher.item",
+		TargetModelId: "gosoline.test.mdlsub-test.item",
+		Input: &Item{
+			Id:   "1",
+			Name: "foo",
+		},
+		Assert: func(t *testing.T, fetcher *suite.EsSubscriberFetcher) {
+			item := &Item{}
+			fetcher.ById("1", item)
+
+			assert.Equal(t, &Item{Id: "1", Name: "foo"}, item)
+		},
+	})
+}
+
+func TestEsSubscriberTestSuite(t *testing.T) {
+	suite.Run(t, new(EsSubscriberTestSuite))
+}